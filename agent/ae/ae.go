@@ -96,6 +96,30 @@ type StateSyncer struct {
 	// syncChangesEvent generates an event based on multiple conditions
 	// when the state machine is performing partial state syncs.
 	syncChangesEvent func() event
+
+	// status records the outcome of recent sync runs for diagnostics.
+	statusLock sync.Mutex
+	status     Status
+}
+
+// Status describes the recent activity of a StateSyncer.
+type Status struct {
+	// LastFullSync is when the last full sync was attempted and
+	// LastFullSyncError is the error it failed with, if any.
+	LastFullSync      time.Time
+	LastFullSyncError string
+
+	// LastPartialSync is when the last partial sync was attempted and
+	// LastPartialSyncError is the error it failed with, if any.
+	LastPartialSync      time.Time
+	LastPartialSyncError string
+
+	// NextFullSync is when the next full sync is scheduled. It is the zero
+	// time while a sync is running.
+	NextFullSync time.Time
+
+	// Paused is true while sync runs are temporarily disabled.
+	Paused bool
 }
 
 const (
@@ -164,7 +188,9 @@ func (s *StateSyncer) nextFSMState(fs fsmState) fsmState {
 			return retryFullSyncState
 		}
 
+		s.setNextFullSync(time.Time{})
 		err := s.State.SyncFull()
+		s.recordSync(true, err)
 		if err != nil {
 			s.Logger.Printf("[ERR] agent: failed to sync remote state: %v", err)
 			return retryFullSyncState
@@ -195,6 +221,7 @@ func (s *StateSyncer) nextFSMState(fs fsmState) fsmState {
 			}
 
 			err := s.State.SyncChanges()
+			s.recordSync(false, err)
 			if err != nil {
 				s.Logger.Printf("[ERR] agent: failed to sync changes: %v", err)
 			}
@@ -242,7 +269,7 @@ func (s *StateSyncer) retrySyncFullEventFn() event {
 
 	// retry full sync after some time
 	// todo(fs): why don't we use s.Interval here?
-	case <-time.After(s.retryFailInterval + s.stagger(s.retryFailInterval)):
+	case <-s.fullSyncTimer(s.retryFailInterval + s.stagger(s.retryFailInterval)):
 		return syncFullTimerEvent
 
 	case <-s.ShutdownCh:
@@ -268,7 +295,7 @@ func (s *StateSyncer) syncChangesEventFn() event {
 		}
 
 	// time for a full sync again
-	case <-time.After(s.Interval + s.stagger(s.Interval)):
+	case <-s.fullSyncTimer(s.Interval + s.stagger(s.Interval)):
 		return syncFullTimerEvent
 
 	// do partial syncs on demand
@@ -280,6 +307,47 @@ func (s *StateSyncer) syncChangesEventFn() event {
 	}
 }
 
+// fullSyncTimer returns a channel that fires after d and records it as the
+// time of the next full sync.
+func (s *StateSyncer) fullSyncTimer(d time.Duration) <-chan time.Time {
+	s.setNextFullSync(time.Now().Add(d))
+	return time.After(d)
+}
+
+func (s *StateSyncer) setNextFullSync(t time.Time) {
+	s.statusLock.Lock()
+	s.status.NextFullSync = t
+	s.statusLock.Unlock()
+}
+
+// recordSync records the outcome of a full or partial sync run.
+func (s *StateSyncer) recordSync(full bool, err error) {
+	var errStr string
+	if err != nil {
+		errStr = err.Error()
+	}
+
+	s.statusLock.Lock()
+	defer s.statusLock.Unlock()
+	if full {
+		s.status.LastFullSync = time.Now()
+		s.status.LastFullSyncError = errStr
+	} else {
+		s.status.LastPartialSync = time.Now()
+		s.status.LastPartialSyncError = errStr
+	}
+}
+
+// Status returns a snapshot of the recent activity of the syncer.
+func (s *StateSyncer) Status() Status {
+	s.statusLock.Lock()
+	status := s.status
+	s.statusLock.Unlock()
+
+	status.Paused = s.Paused()
+	return status
+}
+
 // stubbed out for testing
 var libRandomStagger = lib.RandomStagger
 
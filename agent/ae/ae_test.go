@@ -273,6 +273,50 @@ func TestAE_FSM(t *testing.T) {
 	})
 }
 
+func TestAE_Status(t *testing.T) {
+	l := testSyncer()
+	l.State = &mock{syncFull: func() error { return errors.New("boom") }}
+
+	status := l.Status()
+	if !status.LastFullSync.IsZero() || !status.LastPartialSync.IsZero() {
+		t.Fatalf("bad: %#v", status)
+	}
+
+	// A failed full sync is recorded.
+	l.nextFSMState(fullSyncState)
+	status = l.Status()
+	if status.LastFullSync.IsZero() || status.LastFullSyncError != "boom" {
+		t.Fatalf("bad: %#v", status)
+	}
+
+	// The retry is scheduled.
+	l.retryFailInterval = 10 * time.Millisecond
+	before := time.Now()
+	l.retrySyncFullEvent()
+	status = l.Status()
+	if status.NextFullSync.Before(before) {
+		t.Fatalf("bad: %#v", status)
+	}
+
+	// A successful partial sync clears its error and doesn't touch the full
+	// sync status.
+	l.State = &mock{}
+	l.syncChangesEvent = func() event { return syncChangesNotifEvent }
+	l.nextFSMState(partialSyncState)
+	status = l.Status()
+	if status.LastPartialSync.IsZero() || status.LastPartialSyncError != "" {
+		t.Fatalf("bad: %#v", status)
+	}
+	if status.LastFullSyncError != "boom" {
+		t.Fatalf("bad: %#v", status)
+	}
+
+	l.Pause()
+	if !l.Status().Paused {
+		t.Fatal("should be paused")
+	}
+}
+
 func TestAE_RetrySyncFullEvent(t *testing.T) {
 	t.Run("trigger shutdownEvent", func(t *testing.T) {
 		l := testSyncer()
@@ -157,6 +157,10 @@ type Agent struct {
 	// reap its associated service
 	checkReapAfter map[types.CheckID]time.Duration
 
+	// checkTypes maps the check ID to the check type it was added with, so
+	// that a check replaced by a failed batch registration can be restored
+	checkTypes map[types.CheckID]*structs.CheckType
+
 	// checkMonitors maps the check ID to an associated monitor
 	checkMonitors map[types.CheckID]*checks.CheckMonitor
 
@@ -169,6 +173,15 @@ type Agent struct {
 	// checkGRPCs maps the check ID to an associated GRPC check
 	checkGRPCs map[types.CheckID]*checks.CheckGRPC
 
+	// checkUDPs maps the check ID to an associated UDP check
+	checkUDPs map[types.CheckID]*checks.CheckUDP
+
+	// checkDNSs maps the check ID to an associated DNS check
+	checkDNSs map[types.CheckID]*checks.CheckDNS
+
+	// checkTLSs maps the check ID to an associated TLS handshake check
+	checkTLSs map[types.CheckID]*checks.CheckTLS
+
 	// checkTTLs maps the check ID to an associated check TTL
 	checkTTLs map[types.CheckID]*checks.CheckTTL
 
@@ -228,6 +241,10 @@ type Agent struct {
 	// agent.
 	watchPlans []*watch.Plan
 
+	// serviceDirs keeps the services in the service_watch_dirs in sync
+	// with their files. It is nil if no directories are watched.
+	serviceDirs *serviceDirWatcher
+
 	// tokens holds ACL tokens initially from the configuration, but can
 	// be updated at runtime, so should always be used instead of going to
 	// the configuration directly.
@@ -271,11 +288,15 @@ func New(c *config.RuntimeConfig) (*Agent, error) {
 	a := &Agent{
 		config:          c,
 		checkReapAfter:  make(map[types.CheckID]time.Duration),
+		checkTypes:      make(map[types.CheckID]*structs.CheckType),
 		checkMonitors:   make(map[types.CheckID]*checks.CheckMonitor),
 		checkTTLs:       make(map[types.CheckID]*checks.CheckTTL),
 		checkHTTPs:      make(map[types.CheckID]*checks.CheckHTTP),
 		checkTCPs:       make(map[types.CheckID]*checks.CheckTCP),
 		checkGRPCs:      make(map[types.CheckID]*checks.CheckGRPC),
+		checkUDPs:       make(map[types.CheckID]*checks.CheckUDP),
+		checkDNSs:       make(map[types.CheckID]*checks.CheckDNS),
+		checkTLSs:       make(map[types.CheckID]*checks.CheckTLS),
 		checkDockers:    make(map[types.CheckID]*checks.CheckDocker),
 		checkAliases:    make(map[types.CheckID]*checks.CheckAlias),
 		eventCh:         make(chan serf.UserEvent, 1024),
@@ -441,6 +462,9 @@ func (a *Agent) Start() error {
 	if err := a.loadMetadata(c); err != nil {
 		return err
 	}
+	if err := a.loadServiceWatchDirs(c); err != nil {
+		return err
+	}
 
 	// create the proxy process manager and start it. This is purposely
 	// done here after the local state above is loaded in so we can have
@@ -463,6 +487,7 @@ func (a *Agent) Start() error {
 			Datacenter: a.config.Datacenter,
 			Segment:    a.config.SegmentName,
 		},
+		IntentionDefaultAllow: intentionDefaultAllow(a.config),
 	})
 	if err != nil {
 		return err
@@ -800,7 +825,7 @@ func (a *Agent) reloadWatches(cfg *config.RuntimeConfig) error {
 	for _, params := range cfg.Watches {
 		if handlerType, ok := params["handler_type"]; !ok {
 			params["handler_type"] = "script"
-		} else if handlerType != "http" && handlerType != "script" {
+		} else if handlerType != "http" && handlerType != "script" && handlerType != "file" {
 			return fmt.Errorf("Handler type '%s' not recognized", params["handler_type"])
 		}
 
@@ -844,12 +869,20 @@ func (a *Agent) reloadWatches(cfg *config.RuntimeConfig) error {
 		} else if hasArgs && !ok {
 			return fmt.Errorf("Watch args must be a list of strings")
 		}
-		if hasHandler && hasArgs || hasHandler && wp.HandlerType == "http" || hasArgs && wp.HandlerType == "http" {
+		hasConfigHandler := wp.HandlerType == "http" || wp.HandlerType == "file"
+		if hasHandler && hasArgs || hasHandler && hasConfigHandler || hasArgs && hasConfigHandler {
 			return fmt.Errorf("Only one watch handler allowed")
 		}
-		if !hasHandler && !hasArgs && wp.HandlerType != "http" {
+		if !hasHandler && !hasArgs && !hasConfigHandler {
 			return fmt.Errorf("Must define a watch handler")
 		}
+		if fileConfig, ok := wp.Exempt["file_handler_config"].(*watch.FileHandlerConfig); ok {
+			h, err := makeFileWatchHandler(a.LogOutput, fileConfig)
+			if err != nil {
+				return err
+			}
+			wp.RetryableHandler = h
+		}
 
 		// Store the watch plan
 		watchPlans = append(watchPlans, wp)
@@ -866,12 +899,11 @@ func (a *Agent) reloadWatches(cfg *config.RuntimeConfig) error {
 		a.watchPlans = append(a.watchPlans, wp)
 		go func(wp *watch.Plan) {
 			if h, ok := wp.Exempt["handler"]; ok {
-				wp.Handler = makeWatchHandler(a.LogOutput, h)
+				wp.RetryableHandler = makeWatchHandler(a.LogOutput, h)
 			} else if h, ok := wp.Exempt["args"]; ok {
-				wp.Handler = makeWatchHandler(a.LogOutput, h)
-			} else {
-				httpConfig := wp.Exempt["http_handler_config"].(*watch.HttpHandlerConfig)
-				wp.Handler = makeHTTPWatchHandler(a.LogOutput, httpConfig)
+				wp.RetryableHandler = makeWatchHandler(a.LogOutput, h)
+			} else if httpConfig, ok := wp.Exempt["http_handler_config"].(*watch.HttpHandlerConfig); ok {
+				wp.RetryableHandler = makeHTTPWatchHandler(a.LogOutput, httpConfig)
 			}
 			wp.LogOutput = a.LogOutput
 
@@ -1441,6 +1473,7 @@ func (a *Agent) ShutdownAgent() error {
 	// Stop all the checks
 	a.stateLock.Lock()
 	defer a.stateLock.Unlock()
+	a.unloadServiceWatchDirs()
 	for _, chk := range a.checkMonitors {
 		chk.Stop()
 	}
@@ -1456,6 +1489,15 @@ func (a *Agent) ShutdownAgent() error {
 	for _, chk := range a.checkGRPCs {
 		chk.Stop()
 	}
+	for _, chk := range a.checkUDPs {
+		chk.Stop()
+	}
+	for _, chk := range a.checkDNSs {
+		chk.Stop()
+	}
+	for _, chk := range a.checkTLSs {
+		chk.Stop()
+	}
 	for _, chk := range a.checkDockers {
 		chk.Stop()
 	}
@@ -1896,6 +1938,213 @@ func (a *Agent) AddService(service *structs.NodeService, chkTypes []*structs.Che
 }
 
 func (a *Agent) addServiceLocked(service *structs.NodeService, chkTypes []*structs.CheckType, persist bool, token string, source configSource) error {
+	reg := &serviceRegistration{Service: service, ChkTypes: chkTypes, Token: token}
+	return a.addServicesLocked([]*serviceRegistration{reg}, persist, source)
+}
+
+// serviceRegistration is a service with its health checks and ACL token, as
+// added by addServices.
+type serviceRegistration struct {
+	Service  *structs.NodeService
+	ChkTypes []*structs.CheckType
+	Token    string
+}
+
+// addServices is used to add several service entries at once. The services
+// are validated before any of them is added, and if adding one of them
+// fails, the whole batch is rolled back: services and checks that weren't
+// registered before are removed again, and those that were replaced get
+// their previous definition, check monitors and persisted files back.
+// Services are persisted only after all of them are added to the local state.
+func (a *Agent) addServices(regs []*serviceRegistration, persist bool, source configSource) error {
+	a.stateLock.Lock()
+	defer a.stateLock.Unlock()
+	return a.addServicesLocked(regs, persist, source)
+}
+
+func (a *Agent) addServicesLocked(regs []*serviceRegistration, persist bool, source configSource) error {
+	for _, reg := range regs {
+		if err := a.validateServiceRegistration(reg.Service, reg.ChkTypes); err != nil {
+			return err
+		}
+	}
+
+	// Pause the service syncs during modification
+	a.PauseSync()
+	defer a.ResumeSync()
+
+	// Take a snapshot of the current state of checks (if any), and
+	// restore them before resuming anti-entropy.
+	snap := a.snapshotCheckState()
+	defer a.restoreCheckState(snap)
+
+	// Create the associated health checks
+	checks := make([][]*structs.HealthCheck, len(regs))
+	for i, reg := range regs {
+		checks[i] = a.serviceHealthChecks(reg.Service, reg.ChkTypes)
+	}
+
+	// Record what the batch is about to add or replace so that it can be
+	// rolled back if something fails halfway through the process.
+	snapReg := a.snapshotRegistrations(regs, checks)
+
+	for i, reg := range regs {
+		err := a.State.AddServiceWithChecks(reg.Service, checks[i], reg.Token)
+		if err != nil {
+			a.rollbackRegistrations(snapReg)
+			return err
+		}
+
+		for j := range checks[i] {
+			if err := a.addCheck(checks[i][j], reg.ChkTypes[j], reg.Service, persist, reg.Token, source); err != nil {
+				a.rollbackRegistrations(snapReg)
+				return err
+			}
+		}
+	}
+
+	// Persist the services and checks to files
+	if persist && a.config.DataDir != "" {
+		for i, reg := range regs {
+			for j := range checks[i] {
+				if err := a.persistCheck(checks[i][j], reg.ChkTypes[j]); err != nil {
+					a.rollbackRegistrations(snapReg)
+					return err
+				}
+			}
+
+			if err := a.persistService(reg.Service); err != nil {
+				a.rollbackRegistrations(snapReg)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// registrationSnapshot is the state of the services and checks that a batch
+// of registrations is about to add or replace.
+type registrationSnapshot struct {
+	// newServices and newChecks weren't registered before the batch.
+	newServices []string
+	newChecks   []types.CheckID
+
+	// services and checks are the previous states of those that the batch
+	// replaces, and chkTypes the check types their monitors were started
+	// with.
+	services map[string]*local.ServiceState
+	checks   map[types.CheckID]*local.CheckState
+	chkTypes map[types.CheckID]*structs.CheckType
+
+	// files holds the previous contents of the persisted definitions of
+	// the replaced services and checks, keyed by path. It is nil for the
+	// ones that weren't persisted.
+	files map[string][]byte
+}
+
+// snapshotRegistrations records the state that rollbackRegistrations needs
+// to undo a batch of registrations.
+func (a *Agent) snapshotRegistrations(regs []*serviceRegistration, checks [][]*structs.HealthCheck) *registrationSnapshot {
+	snap := &registrationSnapshot{
+		services: make(map[string]*local.ServiceState),
+		checks:   make(map[types.CheckID]*local.CheckState),
+		chkTypes: make(map[types.CheckID]*structs.CheckType),
+		files:    make(map[string][]byte),
+	}
+
+	for i, reg := range regs {
+		if s := a.State.ServiceState(reg.Service.ID); s == nil {
+			snap.newServices = append(snap.newServices, reg.Service.ID)
+		} else {
+			snap.services[reg.Service.ID] = s
+			a.snapshotFile(snap, filepath.Join(a.config.DataDir, servicesDir, stringHash(reg.Service.ID)))
+		}
+
+		for _, check := range checks[i] {
+			c := a.State.CheckState(check.CheckID)
+			if c == nil {
+				snap.newChecks = append(snap.newChecks, check.CheckID)
+				continue
+			}
+
+			// Copy the check since adding it again fills in some of its
+			// fields.
+			prev := *c.Check
+			c.Check = &prev
+			snap.checks[check.CheckID] = c
+			if chkType, ok := a.checkTypes[check.CheckID]; ok {
+				snap.chkTypes[check.CheckID] = chkType
+			}
+			a.snapshotFile(snap, filepath.Join(a.config.DataDir, checksDir, checkIDHash(check.CheckID)))
+		}
+	}
+
+	return snap
+}
+
+// snapshotFile records the contents of a persisted definition, if any.
+func (a *Agent) snapshotFile(snap *registrationSnapshot, path string) {
+	if a.config.DataDir == "" {
+		return
+	}
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		buf = nil
+	}
+	snap.files[path] = buf
+}
+
+// rollbackRegistrations undoes a batch of registrations that failed part way
+// through. New services and checks are removed, and the ones that were
+// replaced are restored along with their check monitors and persisted files.
+func (a *Agent) rollbackRegistrations(snap *registrationSnapshot) {
+	a.cleanupRegistration(snap.newServices, snap.newChecks)
+
+	for id, s := range snap.services {
+		if err := a.State.AddService(s.Service, s.Token); err != nil {
+			a.logger.Printf("[ERR] consul: service registration: cleanup: failed to restore service %s: %s", id, err)
+		}
+	}
+
+	for id, c := range snap.checks {
+		a.cancelCheckMonitors(id)
+
+		var service *structs.NodeService
+		if c.Check.ServiceID != "" {
+			service = a.State.Service(c.Check.ServiceID)
+		}
+		if chkType, ok := snap.chkTypes[id]; ok {
+			// The check was allowed when it was first added, so it's
+			// restored as a local check to get past the script check
+			// settings for remote ones.
+			if err := a.addCheck(c.Check, chkType, service, false, c.Token, ConfigSourceLocal); err != nil {
+				a.logger.Printf("[ERR] consul: service registration: cleanup: failed to restore check %s: %s", id, err)
+			}
+		}
+		if err := a.State.AddCheck(c.Check, c.Token); err != nil {
+			a.logger.Printf("[ERR] consul: service registration: cleanup: failed to restore check %s: %s", id, err)
+		}
+	}
+
+	for path, buf := range snap.files {
+		var err error
+		if buf == nil {
+			if _, statErr := os.Stat(path); statErr == nil {
+				err = os.Remove(path)
+			}
+		} else {
+			err = file.WriteAtomic(path, buf)
+		}
+		if err != nil {
+			a.logger.Printf("[ERR] consul: service registration: cleanup: failed to restore file %s: %s", path, err)
+		}
+	}
+}
+
+// validateServiceRegistration validates a service and its check types and
+// fills in the defaults of the service.
+func (a *Agent) validateServiceRegistration(service *structs.NodeService, chkTypes []*structs.CheckType) error {
 	if service.Service == "" {
 		return fmt.Errorf("Service name missing")
 	}
@@ -1938,18 +2187,13 @@ func (a *Agent) addServiceLocked(service *structs.NodeService, chkTypes []*struc
 		}
 	}
 
-	// Pause the service syncs during modification
-	a.PauseSync()
-	defer a.ResumeSync()
-
-	// Take a snapshot of the current state of checks (if any), and
-	// restore them before resuming anti-entropy.
-	snap := a.snapshotCheckState()
-	defer a.restoreCheckState(snap)
+	return nil
+}
 
+// serviceHealthChecks returns the health checks for the check types of a
+// service.
+func (a *Agent) serviceHealthChecks(service *structs.NodeService, chkTypes []*structs.CheckType) []*structs.HealthCheck {
 	var checks []*structs.HealthCheck
-
-	// Create an associated health check
 	for i, chkType := range chkTypes {
 		checkID := string(chkType.CheckID)
 		if checkID == "" {
@@ -1978,53 +2222,7 @@ func (a *Agent) addServiceLocked(service *structs.NodeService, chkTypes []*struc
 
 		checks = append(checks, check)
 	}
-
-	// cleanup, store the ids of services and checks that weren't previously
-	// registered so we clean them up if somthing fails halfway through the
-	// process.
-	var cleanupServices []string
-	var cleanupChecks []types.CheckID
-
-	if s := a.State.Service(service.ID); s == nil {
-		cleanupServices = append(cleanupServices, service.ID)
-	}
-
-	for _, check := range checks {
-		if c := a.State.Check(check.CheckID); c == nil {
-			cleanupChecks = append(cleanupChecks, check.CheckID)
-		}
-	}
-
-	err := a.State.AddServiceWithChecks(service, checks, token)
-	if err != nil {
-		a.cleanupRegistration(cleanupServices, cleanupChecks)
-		return err
-	}
-
-	for i := range checks {
-		if err := a.addCheck(checks[i], chkTypes[i], service, persist, token, source); err != nil {
-			a.cleanupRegistration(cleanupServices, cleanupChecks)
-			return err
-		}
-
-		if persist && a.config.DataDir != "" {
-			if err := a.persistCheck(checks[i], chkTypes[i]); err != nil {
-				a.cleanupRegistration(cleanupServices, cleanupChecks)
-				return err
-
-			}
-		}
-	}
-
-	// Persist the service to a file
-	if persist && a.config.DataDir != "" {
-		if err := a.persistService(service); err != nil {
-			a.cleanupRegistration(cleanupServices, cleanupChecks)
-			return err
-		}
-	}
-
-	return nil
+	return checks
 }
 
 // cleanupRegistration is called on  registration error to ensure no there are no
@@ -2203,6 +2401,12 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 
 	// Check if already registered
 	if chkType != nil {
+		// Checks that run on an interval report through a status handler so
+		// their status only changes after the configured number of
+		// consecutive results.
+		statusHandler := checks.NewStatusHandler(a.State, a.logger,
+			chkType.SuccessBeforePassing, chkType.FailuresBeforeCritical)
+
 		switch {
 
 		case chkType.IsTTL():
@@ -2219,10 +2423,14 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 			}
 
 			// Restore persisted state, if any
-			if err := a.loadCheckState(check); err != nil {
+			expires, err := a.loadCheckState(check)
+			if err != nil {
 				a.logger.Printf("[WARN] agent: failed restoring state for check %q: %s",
 					check.CheckID, err)
 			}
+			if !expires.IsZero() {
+				ttl.Restore(check.Output, expires)
+			}
 
 			ttl.Start()
 			a.checkTTLs[check.CheckID] = ttl
@@ -2241,11 +2449,14 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 			tlsClientConfig := a.tlsConfigurator.OutgoingTLSConfigForCheck(chkType.TLSSkipVerify)
 
 			http := &checks.CheckHTTP{
-				Notify:          a.State,
+				Notify:          statusHandler,
 				CheckID:         check.CheckID,
 				HTTP:            chkType.HTTP,
 				Header:          chkType.Header,
 				Method:          chkType.Method,
+				BodyMatch:       chkType.BodyMatch,
+				BodyMatchRegex:  chkType.BodyMatchRegex,
+				JSONAssertions:  chkType.JSONAssertions,
 				Interval:        chkType.Interval,
 				Timeout:         chkType.Timeout,
 				Logger:          a.logger,
@@ -2266,7 +2477,7 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 			}
 
 			tcp := &checks.CheckTCP{
-				Notify:   a.State,
+				Notify:   statusHandler,
 				CheckID:  check.CheckID,
 				TCP:      chkType.TCP,
 				Interval: chkType.Interval,
@@ -2293,7 +2504,7 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 			}
 
 			grpc := &checks.CheckGRPC{
-				Notify:          a.State,
+				Notify:          statusHandler,
 				CheckID:         check.CheckID,
 				GRPC:            chkType.GRPC,
 				Interval:        chkType.Interval,
@@ -2304,6 +2515,80 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 			grpc.Start()
 			a.checkGRPCs[check.CheckID] = grpc
 
+		case chkType.IsUDP():
+			if existing, ok := a.checkUDPs[check.CheckID]; ok {
+				existing.Stop()
+				delete(a.checkUDPs, check.CheckID)
+			}
+			if chkType.Interval < checks.MinInterval {
+				a.logger.Println(fmt.Sprintf("[WARN] agent: check '%s' has interval below minimum of %v",
+					check.CheckID, checks.MinInterval))
+				chkType.Interval = checks.MinInterval
+			}
+
+			udp := &checks.CheckUDP{
+				Notify:   statusHandler,
+				CheckID:  check.CheckID,
+				UDP:      chkType.UDP,
+				Send:     chkType.UDPSend,
+				Expect:   chkType.UDPExpect,
+				Interval: chkType.Interval,
+				Timeout:  chkType.Timeout,
+				Logger:   a.logger,
+			}
+			udp.Start()
+			a.checkUDPs[check.CheckID] = udp
+
+		case chkType.IsDNS():
+			if existing, ok := a.checkDNSs[check.CheckID]; ok {
+				existing.Stop()
+				delete(a.checkDNSs, check.CheckID)
+			}
+			if chkType.Interval < checks.MinInterval {
+				a.logger.Println(fmt.Sprintf("[WARN] agent: check '%s' has interval below minimum of %v",
+					check.CheckID, checks.MinInterval))
+				chkType.Interval = checks.MinInterval
+			}
+
+			dns := &checks.CheckDNS{
+				Notify:     statusHandler,
+				CheckID:    check.CheckID,
+				DNS:        chkType.DNS,
+				Server:     chkType.DNSServer,
+				RecordType: chkType.DNSRecordType,
+				Expect:     chkType.DNSExpect,
+				Interval:   chkType.Interval,
+				Timeout:    chkType.Timeout,
+				Logger:     a.logger,
+			}
+			dns.Start()
+			a.checkDNSs[check.CheckID] = dns
+
+		case chkType.IsTLS():
+			if existing, ok := a.checkTLSs[check.CheckID]; ok {
+				existing.Stop()
+				delete(a.checkTLSs, check.CheckID)
+			}
+			if chkType.Interval < checks.MinInterval {
+				a.logger.Println(fmt.Sprintf("[WARN] agent: check '%s' has interval below minimum of %v",
+					check.CheckID, checks.MinInterval))
+				chkType.Interval = checks.MinInterval
+			}
+
+			tlsCheck := &checks.CheckTLS{
+				Notify:          statusHandler,
+				CheckID:         check.CheckID,
+				TLS:             chkType.TLS,
+				ServerName:      chkType.TLSServerName,
+				ExpiryWarning:   time.Duration(chkType.TLSExpiryWarningDays) * 24 * time.Hour,
+				Interval:        chkType.Interval,
+				Timeout:         chkType.Timeout,
+				Logger:          a.logger,
+				TLSClientConfig: a.tlsConfigurator.OutgoingTLSConfigForCheck(chkType.TLSSkipVerify),
+			}
+			tlsCheck.Start()
+			a.checkTLSs[check.CheckID] = tlsCheck
+
 		case chkType.IsDocker():
 			if existing, ok := a.checkDockers[check.CheckID]; ok {
 				existing.Stop()
@@ -2326,7 +2611,7 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 			}
 
 			dockerCheck := &checks.CheckDocker{
-				Notify:            a.State,
+				Notify:            statusHandler,
 				CheckID:           check.CheckID,
 				DockerContainerID: chkType.DockerContainerID,
 				Shell:             chkType.Shell,
@@ -2353,7 +2638,7 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 			}
 
 			monitor := &checks.CheckMonitor{
-				Notify:     a.State,
+				Notify:     statusHandler,
 				CheckID:    check.CheckID,
 				ScriptArgs: chkType.ScriptArgs,
 				Interval:   chkType.Interval,
@@ -2407,6 +2692,10 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 		} else {
 			delete(a.checkReapAfter, check.CheckID)
 		}
+
+		a.checkTypes[check.CheckID] = chkType
+	} else {
+		delete(a.checkTypes, check.CheckID)
 	}
 
 	return nil
@@ -2792,6 +3081,7 @@ func (a *Agent) verifyProxyToken(token, targetService,
 func (a *Agent) cancelCheckMonitors(checkID types.CheckID) {
 	// Stop any monitors
 	delete(a.checkReapAfter, checkID)
+	delete(a.checkTypes, checkID)
 	if check, ok := a.checkMonitors[checkID]; ok {
 		check.Stop()
 		delete(a.checkMonitors, checkID)
@@ -2808,6 +3098,18 @@ func (a *Agent) cancelCheckMonitors(checkID types.CheckID) {
 		check.Stop()
 		delete(a.checkGRPCs, checkID)
 	}
+	if check, ok := a.checkUDPs[checkID]; ok {
+		check.Stop()
+		delete(a.checkUDPs, checkID)
+	}
+	if check, ok := a.checkDNSs[checkID]; ok {
+		check.Stop()
+		delete(a.checkDNSs, checkID)
+	}
+	if check, ok := a.checkTLSs[checkID]; ok {
+		check.Stop()
+		delete(a.checkTLSs, checkID)
+	}
 	if check, ok := a.checkTTLs[checkID]; ok {
 		check.Stop()
 		delete(a.checkTTLs, checkID)
@@ -2887,35 +3189,38 @@ func (a *Agent) persistCheckState(check *checks.CheckTTL, status, output string)
 	return nil
 }
 
-// loadCheckState is used to restore the persisted state of a check.
-func (a *Agent) loadCheckState(check *structs.HealthCheck) error {
+// loadCheckState is used to restore the persisted state of a check. It
+// returns when the restored state expires, or the zero time if nothing was
+// restored.
+func (a *Agent) loadCheckState(check *structs.HealthCheck) (time.Time, error) {
 	// Try to read the persisted state for this check
 	file := filepath.Join(a.config.DataDir, checkStateDir, checkIDHash(check.CheckID))
 	buf, err := ioutil.ReadFile(file)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil
+			return time.Time{}, nil
 		}
-		return fmt.Errorf("failed reading file %q: %s", file, err)
+		return time.Time{}, fmt.Errorf("failed reading file %q: %s", file, err)
 	}
 
 	// Decode the state data
 	var p persistedCheckState
 	if err := json.Unmarshal(buf, &p); err != nil {
 		a.logger.Printf("[ERR] agent: failed decoding check state: %s", err)
-		return a.purgeCheckState(check.CheckID)
+		return time.Time{}, a.purgeCheckState(check.CheckID)
 	}
 
 	// Check if the state has expired
-	if time.Now().Unix() >= p.Expires {
+	expires := time.Unix(p.Expires, 0)
+	if !time.Now().Before(expires) {
 		a.logger.Printf("[DEBUG] agent: check state expired for %q, not restoring", check.CheckID)
-		return a.purgeCheckState(check.CheckID)
+		return time.Time{}, a.purgeCheckState(check.CheckID)
 	}
 
 	// Restore the fields from the state
 	check.Output = p.Output
 	check.Status = p.Status
-	return nil
+	return expires, nil
 }
 
 // purgeCheckState is used to purge the state of a check from the data dir
@@ -3102,6 +3407,50 @@ func (a *Agent) loadServices(conf *config.RuntimeConfig) error {
 	return nil
 }
 
+// loadServiceWatchDirs registers the services in the service_watch_dirs and
+// starts watching the directories for changes.
+func (a *Agent) loadServiceWatchDirs(conf *config.RuntimeConfig) error {
+	if len(conf.ServiceWatchDirs) == 0 {
+		return nil
+	}
+
+	w, err := newServiceDirWatcher(a, conf.ServiceWatchDirs)
+	if err != nil {
+		return err
+	}
+	a.serviceDirs = w
+	w.syncLocked()
+	go w.run()
+	return nil
+}
+
+// unloadServiceWatchDirs stops watching the service_watch_dirs. The services
+// registered from them are unloaded with the other services.
+func (a *Agent) unloadServiceWatchDirs() {
+	if a.serviceDirs != nil {
+		a.serviceDirs.stop()
+		a.serviceDirs = nil
+	}
+}
+
+// intentionDefaultAllow returns whether connections that match no intention
+// are allowed with the given config. Like ConnectAuthorize, everything is
+// allowed when ACLs are disabled.
+func intentionDefaultAllow(cfg *config.RuntimeConfig) bool {
+	return !cfg.ACLsEnabled || cfg.ACLDefaultPolicy == "allow"
+}
+
+// serviceFileErrors returns the errors of the files in the
+// service_watch_dirs by path.
+func (a *Agent) serviceFileErrors() map[string]string {
+	a.stateLock.Lock()
+	defer a.stateLock.Unlock()
+	if a.serviceDirs == nil {
+		return nil
+	}
+	return a.serviceDirs.fileErrors()
+}
+
 // unloadServices will deregister all services.
 func (a *Agent) unloadServices() error {
 	for id := range a.State.Services() {
@@ -3539,6 +3888,7 @@ func (a *Agent) ReloadConfig(newCfg *config.RuntimeConfig) error {
 
 	// First unload all checks, services, and metadata. This lets us begin the reload
 	// with a clean slate.
+	a.unloadServiceWatchDirs()
 	if err := a.unloadProxies(); err != nil {
 		return fmt.Errorf("Failed unloading proxies: %s", err)
 	}
@@ -3572,6 +3922,9 @@ func (a *Agent) ReloadConfig(newCfg *config.RuntimeConfig) error {
 	if err := a.loadMetadata(newCfg); err != nil {
 		return fmt.Errorf("Failed reloading metadata: %s", err)
 	}
+	if err := a.loadServiceWatchDirs(newCfg); err != nil {
+		return fmt.Errorf("Failed reloading service watch dirs: %s", err)
+	}
 
 	if err := a.reloadWatches(newCfg); err != nil {
 		return fmt.Errorf("Failed reloading watches: %v", err)
@@ -3579,6 +3932,11 @@ func (a *Agent) ReloadConfig(newCfg *config.RuntimeConfig) error {
 
 	a.loadLimits(newCfg)
 
+	// Update the default action of the intentions enforced by proxies
+	if a.proxyConfig != nil {
+		a.proxyConfig.SetIntentionDefaultAllow(intentionDefaultAllow(newCfg))
+	}
+
 	// create the config for the rpc server/client
 	consulCfg, err := a.consulConfig()
 	if err != nil {
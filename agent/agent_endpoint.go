@@ -398,26 +398,154 @@ func (s *HTTPServer) AgentService(resp http.ResponseWriter, req *http.Request) (
 		})
 }
 
+// agentCheck is a check as returned by /v1/agent/checks. It adds a summary
+// of the check's recent status changes to the check record.
+type agentCheck struct {
+	*structs.HealthCheck
+
+	// FlapCount is the number of times the check changed status within
+	// local.CheckFlapWindow.
+	FlapCount int
+}
+
 func (s *HTTPServer) AgentChecks(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	// Fetch the ACL token, if any.
 	var token string
 	s.parseToken(req, &token)
 
-	checks := s.agent.State.Checks()
+	states := s.agent.State.CheckStates()
+	checks := make(map[types.CheckID]*structs.HealthCheck, len(states))
+	for id, c := range states {
+		checks[id] = c.Check
+	}
 	if err := s.agent.filterChecks(token, &checks); err != nil {
 		return nil, err
 	}
 
-	// Use empty list instead of nil
+	now := time.Now()
+	out := make(map[types.CheckID]*agentCheck, len(checks))
 	for id, c := range checks {
+		// Use empty list instead of nil
 		if c.ServiceTags == nil {
 			clone := *c
 			clone.ServiceTags = make([]string, 0)
-			checks[id] = &clone
+			c = &clone
+		}
+		out[id] = &agentCheck{
+			HealthCheck: c,
+			FlapCount:   states[id].FlapCount(now),
 		}
 	}
 
-	return checks, nil
+	return out, nil
+}
+
+// AgentCheckHistory returns the most recent status changes of a check, oldest
+// first. It serves /v1/agent/check/:id/history.
+func (s *HTTPServer) AgentCheckHistory(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/agent/check/")
+	if !strings.HasSuffix(path, "/history") {
+		resp.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(resp, "Invalid path %q", req.URL.Path)
+		return nil, nil
+	}
+	checkID := types.CheckID(strings.TrimSuffix(path, "/history"))
+
+	// Fetch the ACL token, if any.
+	var token string
+	s.parseToken(req, &token)
+
+	state := s.agent.State.CheckState(checkID)
+	if state == nil {
+		resp.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(resp, "Unknown check %q", checkID)
+		return nil, nil
+	}
+
+	// Use the same rules as listing checks.
+	checks := map[types.CheckID]*structs.HealthCheck{checkID: state.Check}
+	if err := s.agent.filterChecks(token, &checks); err != nil {
+		return nil, err
+	}
+	if len(checks) == 0 {
+		return nil, acl.ErrPermissionDenied
+	}
+
+	history := state.History()
+	if history == nil {
+		history = make([]local.CheckHistoryEntry, 0)
+	}
+	return history, nil
+}
+
+// AgentSyncStatus reports how the local services and checks are being synced
+// to the catalog, including the errors of failed attempts that would
+// otherwise only show up in the logs.
+func (s *HTTPServer) AgentSyncStatus(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Fetch the ACL token, if any, and enforce agent policy.
+	var token string
+	s.parseToken(req, &token)
+	rule, err := s.agent.resolveToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if rule != nil && !rule.AgentRead(s.agent.config.NodeName) {
+		return nil, acl.ErrPermissionDenied
+	}
+
+	serviceStates, checkStates := s.agent.State.SyncStates()
+
+	// Use the same rules as listing services and checks.
+	services := make(map[string]*structs.NodeService, len(serviceStates))
+	for id, ss := range serviceStates {
+		services[id] = ss.Service
+	}
+	if err := s.agent.filterServices(token, &services); err != nil {
+		return nil, err
+	}
+	checks := make(map[types.CheckID]*structs.HealthCheck, len(checkStates))
+	for id, cs := range checkStates {
+		checks[id] = cs.Check
+	}
+	if err := s.agent.filterChecks(token, &checks); err != nil {
+		return nil, err
+	}
+
+	status := s.agent.sync.Status()
+	nodeInSync, nodeSyncError := s.agent.State.NodeInfoSyncState()
+	out := &api.AgentSyncStatus{
+		NodeInSync:           nodeInSync && nodeSyncError == "",
+		NodeSyncError:        nodeSyncError,
+		LastFullSync:         status.LastFullSync,
+		LastFullSyncError:    status.LastFullSyncError,
+		LastPartialSync:      status.LastPartialSync,
+		LastPartialSyncError: status.LastPartialSyncError,
+		NextFullSync:         status.NextFullSync,
+		Paused:               status.Paused,
+		Services:             make(map[string]*api.AgentSyncEntry, len(services)),
+		Checks:               make(map[string]*api.AgentSyncEntry, len(checks)),
+		ServiceFileErrors:    s.agent.serviceFileErrors(),
+	}
+
+	// Entries whose sync was blocked by ACLs are marked as in sync locally
+	// to avoid retrying them, so they are reported by their error instead.
+	for id := range services {
+		ss := serviceStates[id]
+		out.Services[id] = &api.AgentSyncEntry{
+			InSync:  ss.InSync && ss.SyncError == "",
+			Deleted: ss.Deleted,
+			Error:   ss.SyncError,
+		}
+	}
+	for id := range checks {
+		cs := checkStates[id]
+		out.Checks[string(id)] = &api.AgentSyncEntry{
+			InSync:  cs.InSync && cs.SyncError == "",
+			Deleted: cs.Deleted,
+			Error:   cs.SyncError,
+		}
+	}
+	return out, nil
 }
 
 func (s *HTTPServer) AgentMembers(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -832,70 +960,138 @@ func (s *HTTPServer) AgentHealthServiceByName(resp http.ResponseWriter, req *htt
 	return result, CodeWithPayloadError{StatusCode: code, Reason: status, ContentType: "application/json"}
 }
 
+// fixupServiceDefinition fixes up the keys of a decoded service definition
+// before it is decoded into a structs.ServiceDefinition.
+func fixupServiceDefinition(raw interface{}) error {
+	rawMap, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	// see https://github.com/hashicorp/consul/pull/3557 why we need this
+	// and why we should get rid of it.
+	config.TranslateKeys(rawMap, map[string]string{
+		"enable_tag_override": "EnableTagOverride",
+		// Managed Proxy Config
+		"exec_mode": "ExecMode",
+		// Proxy Upstreams
+		"destination_name":      "DestinationName",
+		"destination_type":      "DestinationType",
+		"destination_namespace": "DestinationNamespace",
+		"local_bind_port":       "LocalBindPort",
+		"local_bind_address":    "LocalBindAddress",
+		// Proxy Config
+		"destination_service_name": "DestinationServiceName",
+		"destination_service_id":   "DestinationServiceID",
+		"local_service_port":       "LocalServicePort",
+		"local_service_address":    "LocalServiceAddress",
+		// SidecarService
+		"sidecar_service": "SidecarService",
+
+		// DON'T Recurse into these opaque config maps or we might mangle user's
+		// keys. Note empty canonical is a special sentinel to prevent recursion.
+		"Meta": "",
+		// upstreams is an array but this prevents recursion into config field of
+		// any item in the array.
+		"Proxy.Config":                   "",
+		"Proxy.Upstreams.Config":         "",
+		"Connect.Proxy.Config":           "",
+		"Connect.Proxy.Upstreams.Config": "",
+
+		// Same exceptions as above, but for a nested sidecar_service note we use
+		// the canonical form SidecarService since that is translated by the time
+		// the lookup here happens. Note that sidecar service doesn't support
+		// managed proxies (connect.proxy).
+		"Connect.SidecarService.Meta":                   "",
+		"Connect.SidecarService.Proxy.Config":           "",
+		"Connect.SidecarService.Proxy.Upstreams.config": "",
+	})
+
+	for k, v := range rawMap {
+		switch strings.ToLower(k) {
+		case "check":
+			if err := FixupCheckType(v); err != nil {
+				return err
+			}
+		case "checks":
+			chkTypes, ok := v.([]interface{})
+			if !ok {
+				continue
+			}
+			for _, chkType := range chkTypes {
+				if err := FixupCheckType(chkType); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func (s *HTTPServer) AgentRegisterService(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	var args structs.ServiceDefinition
 	// Fixup the type decode of TTL or Interval if a check if provided.
-	decodeCB := func(raw interface{}) error {
-		rawMap, ok := raw.(map[string]interface{})
-		if !ok {
-			return nil
+	if err := decodeBody(req, &args, fixupServiceDefinition); err != nil {
+		resp.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(resp, "Request decode failed: %v", err)
+		return nil, nil
+	}
+
+	// Get the provided token, if any, and vet against any ACL policies.
+	var token string
+	s.parseToken(req, &token)
+	reg, err := s.prepareServiceRegistration(&args, token)
+	if err != nil {
+		if e, ok := err.(BadRequestError); ok {
+			resp.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(resp, e.Reason)
+			return nil, nil
 		}
+		return nil, err
+	}
 
-		// see https://github.com/hashicorp/consul/pull/3557 why we need this
-		// and why we should get rid of it.
-		config.TranslateKeys(rawMap, map[string]string{
-			"enable_tag_override": "EnableTagOverride",
-			// Managed Proxy Config
-			"exec_mode": "ExecMode",
-			// Proxy Upstreams
-			"destination_name":      "DestinationName",
-			"destination_type":      "DestinationType",
-			"destination_namespace": "DestinationNamespace",
-			"local_bind_port":       "LocalBindPort",
-			"local_bind_address":    "LocalBindAddress",
-			// Proxy Config
-			"destination_service_name": "DestinationServiceName",
-			"destination_service_id":   "DestinationServiceID",
-			"local_service_port":       "LocalServicePort",
-			"local_service_address":    "LocalServiceAddress",
-			// SidecarService
-			"sidecar_service": "SidecarService",
-
-			// DON'T Recurse into these opaque config maps or we might mangle user's
-			// keys. Note empty canonical is a special sentinel to prevent recursion.
-			"Meta": "",
-			// upstreams is an array but this prevents recursion into config field of
-			// any item in the array.
-			"Proxy.Config":                   "",
-			"Proxy.Upstreams.Config":         "",
-			"Connect.Proxy.Config":           "",
-			"Connect.Proxy.Upstreams.Config": "",
-
-			// Same exceptions as above, but for a nested sidecar_service note we use
-			// the canonical form SidecarService since that is translated by the time
-			// the lookup here happens. Note that sidecar service doesn't support
-			// managed proxies (connect.proxy).
-			"Connect.SidecarService.Meta":                   "",
-			"Connect.SidecarService.Proxy.Config":           "",
-			"Connect.SidecarService.Proxy.Upstreams.config": "",
-		})
+	// If we have a proxy, verify that we're allowed to add a proxy via the API
+	if reg.proxy != nil && !s.agent.config.ConnectProxyAllowManagedAPIRegistration {
+		return nil, &BadRequestError{
+			Reason: "Managed proxy registration via the API is disallowed."}
+	}
 
-		for k, v := range rawMap {
-			switch strings.ToLower(k) {
-			case "check":
-				if err := FixupCheckType(v); err != nil {
-					return err
-				}
-			case "checks":
-				chkTypes, ok := v.([]interface{})
-				if !ok {
-					continue
-				}
-				for _, chkType := range chkTypes {
-					if err := FixupCheckType(chkType); err != nil {
-						return err
-					}
-				}
+	// Add the service.
+	if err := s.agent.AddService(reg.service.Service, reg.service.ChkTypes, true, token, ConfigSourceRemote); err != nil {
+		return nil, err
+	}
+	// Add proxy (which will add proxy service so do it before we trigger sync)
+	if reg.proxy != nil {
+		if err := s.agent.AddProxy(reg.proxy, true, false, "", ConfigSourceRemote); err != nil {
+			return nil, err
+		}
+	}
+	// Add sidecar.
+	if reg.sidecar != nil {
+		if err := s.agent.AddService(reg.sidecar.Service, reg.sidecar.ChkTypes, true, reg.sidecar.Token, ConfigSourceRemote); err != nil {
+			return nil, err
+		}
+	}
+	s.syncChanges()
+	return nil, nil
+}
+
+// AgentRegisterServices registers several services at once. All of the
+// service definitions are validated before any of them is registered, and
+// if any of them is invalid none of them is registered and the errors are
+// returned per definition. The services are persisted and synced once for
+// the whole batch. If adding a valid batch fails part way, the whole batch is
+// rolled back, see Agent.addServices.
+func (s *HTTPServer) AgentRegisterServices(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args []*structs.ServiceDefinition
+	decodeCB := func(raw interface{}) error {
+		rawDefs, ok := raw.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected a list of service definitions")
+		}
+		for _, rawDef := range rawDefs {
+			if err := fixupServiceDefinition(rawDef); err != nil {
+				return err
 			}
 		}
 		return nil
@@ -906,56 +1102,136 @@ func (s *HTTPServer) AgentRegisterService(resp http.ResponseWriter, req *http.Re
 		return nil, nil
 	}
 
+	var token string
+	s.parseToken(req, &token)
+
+	// Validate all of the definitions before registering any of them.
+	var regs []*serviceRegistration
+	results := make([]*api.AgentServiceRegisterResult, len(args))
+	ids := make(map[string]struct{})
+	ports := make(map[int]string)
+	failed := false
+	for i, def := range args {
+		results[i] = &api.AgentServiceRegisterResult{ID: def.ID}
+		if results[i].ID == "" {
+			results[i].ID = def.Name
+		}
+
+		reg, err := s.prepareServiceRegistration(def, token)
+		if err == nil && reg.proxy != nil {
+			err = BadRequestError{
+				Reason: "Managed proxy registration is not supported in batch registration."}
+		}
+		if err == nil {
+			err = checkBatchServiceIDs(reg, ids, ports)
+		}
+		if err != nil {
+			results[i].Error = err.Error()
+			if e, ok := err.(BadRequestError); ok {
+				results[i].Error = e.Reason
+			}
+			failed = true
+			continue
+		}
+
+		regs = append(regs, reg.service)
+		if reg.sidecar != nil {
+			regs = append(regs, reg.sidecar)
+		}
+	}
+	if failed {
+		return results, CodeWithPayloadError{
+			StatusCode:  http.StatusBadRequest,
+			Reason:      "Invalid service definitions",
+			ContentType: "application/json",
+		}
+	}
+
+	if err := s.agent.addServices(regs, true, ConfigSourceRemote); err != nil {
+		return nil, err
+	}
+	s.syncChanges()
+	return results, nil
+}
+
+// checkBatchServiceIDs makes sure that the services of a definition in a
+// batch don't use the IDs or the sidecar ports of earlier ones, and records
+// them for the following ones.
+func checkBatchServiceIDs(reg *agentServiceRegistration, ids map[string]struct{}, ports map[int]string) error {
+	services := []*serviceRegistration{reg.service}
+	if reg.sidecar != nil {
+		services = append(services, reg.sidecar)
+	}
+	for _, svc := range services {
+		id := svc.Service.ID
+		if id == "" {
+			id = svc.Service.Service
+		}
+		if _, ok := ids[id]; ok {
+			return fmt.Errorf("Duplicate service ID %q", id)
+		}
+		ids[id] = struct{}{}
+	}
+
+	if reg.sidecar != nil {
+		port := reg.sidecar.Service.Port
+		if id, ok := ports[port]; ok && id != reg.sidecar.Service.ID {
+			return fmt.Errorf("Sidecar port %d is already used by %q, "+
+				"set the sidecar port explicitly", port, id)
+		}
+		ports[port] = reg.sidecar.Service.ID
+	}
+	return nil
+}
+
+// agentServiceRegistration is a validated service definition as registered
+// through the agent API.
+type agentServiceRegistration struct {
+	service *serviceRegistration
+	sidecar *serviceRegistration
+	proxy   *structs.ConnectManagedProxy
+}
+
+// prepareServiceRegistration validates a service definition registered
+// through the agent API and vets it against the ACL policies of the token.
+// Invalid definitions are reported with a BadRequestError.
+func (s *HTTPServer) prepareServiceRegistration(args *structs.ServiceDefinition, token string) (*agentServiceRegistration, error) {
 	// Verify the service has a name.
 	if args.Name == "" {
-		resp.WriteHeader(http.StatusBadRequest)
-		fmt.Fprint(resp, "Missing service name")
-		return nil, nil
+		return nil, BadRequestError{Reason: "Missing service name"}
 	}
 
 	// Check the service address here and in the catalog RPC endpoint
 	// since service registration isn't synchronous.
 	if ipaddr.IsAny(args.Address) {
-		resp.WriteHeader(http.StatusBadRequest)
-		fmt.Fprintf(resp, "Invalid service address")
-		return nil, nil
+		return nil, BadRequestError{Reason: "Invalid service address"}
 	}
 
 	// Get the node service.
 	ns := args.NodeService()
 	if ns.Weights != nil {
 		if err := structs.ValidateWeights(ns.Weights); err != nil {
-			resp.WriteHeader(http.StatusBadRequest)
-			fmt.Fprint(resp, fmt.Errorf("Invalid Weights: %v", err))
-			return nil, nil
+			return nil, BadRequestError{Reason: fmt.Sprintf("Invalid Weights: %v", err)}
 		}
 	}
 	if err := structs.ValidateMetadata(ns.Meta, false); err != nil {
-		resp.WriteHeader(http.StatusBadRequest)
-		fmt.Fprint(resp, fmt.Errorf("Invalid Service Meta: %v", err))
-		return nil, nil
+		return nil, BadRequestError{Reason: fmt.Sprintf("Invalid Service Meta: %v", err)}
 	}
 
 	// Run validation. This is the same validation that would happen on
 	// the catalog endpoint so it helps ensure the sync will work properly.
 	if err := ns.Validate(); err != nil {
-		resp.WriteHeader(http.StatusBadRequest)
-		fmt.Fprintf(resp, err.Error())
-		return nil, nil
+		return nil, BadRequestError{Reason: err.Error()}
 	}
 
 	// Verify the check type.
 	chkTypes, err := args.CheckTypes()
 	if err != nil {
-		resp.WriteHeader(http.StatusBadRequest)
-		fmt.Fprint(resp, fmt.Errorf("Invalid check: %v", err))
-		return nil, nil
+		return nil, BadRequestError{Reason: fmt.Sprintf("Invalid check: %v", err)}
 	}
 	for _, check := range chkTypes {
 		if check.Status != "" && !structs.ValidStatus(check.Status) {
-			resp.WriteHeader(http.StatusBadRequest)
-			fmt.Fprint(resp, "Status for checks must 'passing', 'warning', 'critical'")
-			return nil, nil
+			return nil, BadRequestError{Reason: "Status for checks must 'passing', 'warning', 'critical'"}
 		}
 	}
 
@@ -976,13 +1252,15 @@ func (s *HTTPServer) AgentRegisterService(resp http.ResponseWriter, req *http.Re
 		}
 	}
 
-	// Get the provided token, if any, and vet against any ACL policies.
-	var token string
-	s.parseToken(req, &token)
+	// Vet the service against any ACL policies.
 	if err := s.agent.vetServiceRegister(token, ns); err != nil {
 		return nil, err
 	}
 
+	reg := &agentServiceRegistration{
+		service: &serviceRegistration{Service: ns, ChkTypes: chkTypes, Token: token},
+	}
+
 	// See if we have a sidecar to register too
 	sidecar, sidecarChecks, sidecarToken, err := s.agent.sidecarServiceFromNodeService(ns, token)
 	if err != nil {
@@ -1001,40 +1279,16 @@ func (s *HTTPServer) AgentRegisterService(resp http.ResponseWriter, req *http.Re
 		// persist it in the actual state/catalog. SidecarService is meant to be a
 		// registration syntax sugar so don't propagate it any further.
 		ns.Connect.SidecarService = nil
+		reg.sidecar = &serviceRegistration{Service: sidecar, ChkTypes: sidecarChecks, Token: sidecarToken}
 	}
 
 	// Get any proxy registrations
-	proxy, err := args.ConnectManagedProxy()
+	reg.proxy, err = args.ConnectManagedProxy()
 	if err != nil {
-		resp.WriteHeader(http.StatusBadRequest)
-		fmt.Fprintf(resp, err.Error())
-		return nil, nil
+		return nil, BadRequestError{Reason: err.Error()}
 	}
 
-	// If we have a proxy, verify that we're allowed to add a proxy via the API
-	if proxy != nil && !s.agent.config.ConnectProxyAllowManagedAPIRegistration {
-		return nil, &BadRequestError{
-			Reason: "Managed proxy registration via the API is disallowed."}
-	}
-
-	// Add the service.
-	if err := s.agent.AddService(ns, chkTypes, true, token, ConfigSourceRemote); err != nil {
-		return nil, err
-	}
-	// Add proxy (which will add proxy service so do it before we trigger sync)
-	if proxy != nil {
-		if err := s.agent.AddProxy(proxy, true, false, "", ConfigSourceRemote); err != nil {
-			return nil, err
-		}
-	}
-	// Add sidecar.
-	if sidecar != nil {
-		if err := s.agent.AddService(sidecar, sidecarChecks, true, sidecarToken, ConfigSourceRemote); err != nil {
-			return nil, err
-		}
-	}
-	s.syncChanges()
-	return nil, nil
+	return reg, nil
 }
 
 func (s *HTTPServer) AgentDeregisterService(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -620,7 +621,7 @@ func TestAgent_Checks(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Err: %v", err)
 	}
-	val := obj.(map[types.CheckID]*structs.HealthCheck)
+	val := obj.(map[types.CheckID]*agentCheck)
 	if len(val) != 1 {
 		t.Fatalf("bad checks: %v", obj)
 	}
@@ -629,6 +630,98 @@ func TestAgent_Checks(t *testing.T) {
 	}
 }
 
+func TestAgent_CheckHistory(t *testing.T) {
+	t.Parallel()
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	chk1 := &structs.HealthCheck{
+		Node:    a.Config.NodeName,
+		CheckID: "mysql",
+		Name:    "mysql",
+		Status:  api.HealthPassing,
+	}
+	require.NoError(t, a.State.AddCheck(chk1, ""))
+	a.State.UpdateCheck("mysql", api.HealthCritical, "down")
+	a.State.UpdateCheck("mysql", api.HealthPassing, "up")
+
+	t.Run("flap count", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/agent/checks", nil)
+		obj, err := a.srv.AgentChecks(nil, req)
+		require.NoError(t, err)
+		val := obj.(map[types.CheckID]*agentCheck)
+		require.Equal(t, 2, val["mysql"].FlapCount)
+	})
+
+	t.Run("history", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/agent/check/mysql/history", nil)
+		obj, err := a.srv.AgentCheckHistory(nil, req)
+		require.NoError(t, err)
+		history := obj.([]local.CheckHistoryEntry)
+		require.Len(t, history, 3)
+		require.Equal(t, api.HealthCritical, history[1].Status)
+		require.Equal(t, "down", history[1].Output)
+		require.Equal(t, api.HealthPassing, history[2].Status)
+	})
+
+	t.Run("unknown check", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/agent/check/nope/history", nil)
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.AgentCheckHistory(resp, req)
+		require.NoError(t, err)
+		require.Nil(t, obj)
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
+
+func TestAgent_SyncStatus(t *testing.T) {
+	t.Parallel()
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	srv := &structs.NodeService{ID: "mysql", Service: "mysql"}
+	require.NoError(t, a.State.AddService(srv, ""))
+	chk := &structs.HealthCheck{
+		Node:      a.Config.NodeName,
+		CheckID:   "mysql",
+		Name:      "mysql",
+		ServiceID: "mysql",
+		Status:    api.HealthPassing,
+	}
+	require.NoError(t, a.State.AddCheck(chk, ""))
+	require.NoError(t, a.State.SyncFull())
+
+	req, _ := http.NewRequest("GET", "/v1/agent/sync-status", nil)
+	obj, err := a.srv.AgentSyncStatus(nil, req)
+	require.NoError(t, err)
+	status := obj.(*api.AgentSyncStatus)
+	require.True(t, status.NodeInSync)
+	require.Equal(t, &api.AgentSyncEntry{InSync: true}, status.Services["mysql"])
+	require.Equal(t, &api.AgentSyncEntry{InSync: true}, status.Checks["mysql"])
+}
+
+func TestAgent_SyncStatus_ACLDeny(t *testing.T) {
+	t.Parallel()
+	a := NewTestAgent(t, t.Name(), TestACLConfig())
+	defer a.Shutdown()
+
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+	t.Run("no token", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/agent/sync-status", nil)
+		_, err := a.srv.AgentSyncStatus(nil, req)
+		require.True(t, acl.IsErrPermissionDenied(err))
+	})
+
+	t.Run("agent read token", func(t *testing.T) {
+		ro := makeReadOnlyAgentACL(t, a.srv)
+		req, _ := http.NewRequest("GET", fmt.Sprintf("/v1/agent/sync-status?token=%s", ro), nil)
+		_, err := a.srv.AgentSyncStatus(nil, req)
+		require.NoError(t, err)
+	})
+}
+
 func TestAgent_HealthServiceByID(t *testing.T) {
 	t.Parallel()
 	a := NewTestAgent(t, t.Name(), "")
@@ -1087,7 +1180,7 @@ func TestAgent_Checks_ACLFilter(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Err: %v", err)
 		}
-		val := obj.(map[types.CheckID]*structs.HealthCheck)
+		val := obj.(map[types.CheckID]*agentCheck)
 		if len(val) != 0 {
 			t.Fatalf("bad checks: %v", obj)
 		}
@@ -1099,7 +1192,7 @@ func TestAgent_Checks_ACLFilter(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Err: %v", err)
 		}
-		val := obj.(map[types.CheckID]*structs.HealthCheck)
+		val := obj.(map[types.CheckID]*agentCheck)
 		if len(val) != 1 {
 			t.Fatalf("bad checks: %v", obj)
 		}
@@ -1683,6 +1776,79 @@ func TestAgent_RegisterCheck(t *testing.T) {
 	}
 }
 
+func TestAgent_RegisterCheck_Thresholds(t *testing.T) {
+	t.Parallel()
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	// Use the snake case keys from service definitions to make sure they're
+	// translated. The long interval keeps the check from running during the
+	// test.
+	body := `{
+		"name": "test",
+		"tcp": "127.0.0.1:0",
+		"interval": "1h",
+		"success_before_passing": 3,
+		"failures_before_critical": 2
+	}`
+	req, _ := http.NewRequest("PUT", "/v1/agent/check/register", strings.NewReader(body))
+	_, err := a.srv.AgentRegisterCheck(nil, req)
+	require.NoError(t, err)
+
+	a.stateLock.Lock()
+	tcp, ok := a.checkTCPs["test"]
+	a.stateLock.Unlock()
+	require.True(t, ok)
+	tcp.Stop()
+	require.Equal(t, checks.NewStatusHandler(a.State, a.logger, 3, 2), tcp.Notify)
+}
+
+func TestAgent_RegisterCheck_BodyAssertions(t *testing.T) {
+	t.Parallel()
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	body := `{
+		"name": "test",
+		"http": "http://127.0.0.1:0/status",
+		"interval": "1h",
+		"body_match": "ok|degraded",
+		"body_match_regex": true,
+		"json_assertions": [
+			{"path": "status", "value": "ok", "status": "warning"}
+		]
+	}`
+	req, _ := http.NewRequest("PUT", "/v1/agent/check/register", strings.NewReader(body))
+	_, err := a.srv.AgentRegisterCheck(nil, req)
+	require.NoError(t, err)
+
+	a.stateLock.Lock()
+	chk, ok := a.checkHTTPs["test"]
+	a.stateLock.Unlock()
+	require.True(t, ok)
+	require.Equal(t, "ok|degraded", chk.BodyMatch)
+	require.True(t, chk.BodyMatchRegex)
+	require.Equal(t, []structs.CheckJSONAssertion{
+		{Path: "status", Value: "ok", Status: api.HealthWarning},
+	}, chk.JSONAssertions)
+
+	// Invalid assertions are rejected.
+	body = `{
+		"name": "bad",
+		"http": "http://127.0.0.1:0/status",
+		"interval": "1h",
+		"body_match": "(",
+		"body_match_regex": true
+	}`
+	req, _ = http.NewRequest("PUT", "/v1/agent/check/register", strings.NewReader(body))
+	resp := httptest.NewRecorder()
+	_, err = a.srv.AgentRegisterCheck(resp, req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
 // This verifies all the forms of the new args-style check that we need to
 // support as a result of https://github.com/hashicorp/consul/issues/3587.
 func TestAgent_RegisterCheck_Scripts(t *testing.T) {
@@ -2304,6 +2470,82 @@ func TestAgent_RegisterService(t *testing.T) {
 	}
 }
 
+func TestAgent_RegisterServices(t *testing.T) {
+	t.Parallel()
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	args := []*structs.ServiceDefinition{
+		{
+			Name: "web",
+			Port: 8000,
+			Check: structs.CheckType{
+				TTL: 15 * time.Second,
+			},
+		},
+		{
+			ID:   "db1",
+			Name: "db",
+			Port: 5432,
+		},
+	}
+	req, _ := http.NewRequest("PUT", "/v1/agent/services/register?token=abc123", jsonReader(args))
+	obj, err := a.srv.AgentRegisterServices(nil, req)
+	require.NoError(t, err)
+	require.Equal(t, []*api.AgentServiceRegisterResult{
+		{ID: "web"},
+		{ID: "db1"},
+	}, obj)
+
+	// Ensure the services, checks and tokens
+	services := a.State.Services()
+	require.Contains(t, services, "web")
+	require.Contains(t, services, "db1")
+	require.Contains(t, a.State.Checks(), types.CheckID("service:web"))
+	require.Equal(t, "abc123", a.State.ServiceToken("db1"))
+
+	// Ensure the services were persisted
+	for _, id := range []string{"web", "db1"} {
+		file := filepath.Join(a.Config.DataDir, servicesDir, stringHash(id))
+		_, err := os.Stat(file)
+		require.NoError(t, err)
+	}
+}
+
+func TestAgent_RegisterServices_Invalid(t *testing.T) {
+	t.Parallel()
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	args := []*structs.ServiceDefinition{
+		{Name: "web"},
+		{Name: "db", Address: "0.0.0.0"},
+		{Name: "web"},
+		{Name: "api", Check: structs.CheckType{TTL: time.Second, Status: "bogus"}},
+	}
+	req, _ := http.NewRequest("PUT", "/v1/agent/services/register", jsonReader(args))
+	obj, err := a.srv.AgentRegisterServices(nil, req)
+	require.Error(t, err)
+	payloadErr, ok := err.(CodeWithPayloadError)
+	require.True(t, ok)
+	require.Equal(t, http.StatusBadRequest, payloadErr.StatusCode)
+
+	results := obj.([]*api.AgentServiceRegisterResult)
+	require.Len(t, results, 4)
+	require.Empty(t, results[0].Error)
+	require.Equal(t, "Invalid service address", results[1].Error)
+	require.Contains(t, results[2].Error, "Duplicate service ID")
+	require.Contains(t, results[3].Error, "Status for checks")
+
+	// None of the services should be registered
+	services := a.State.Services()
+	require.NotContains(t, services, "web")
+	require.NotContains(t, services, "db")
+	require.NotContains(t, services, "api")
+}
+
 func TestAgent_RegisterService_TranslateKeys(t *testing.T) {
 	t.Parallel()
 	a := NewTestAgent(t, t.Name(), `
@@ -5908,6 +6150,131 @@ func TestAgentConnectAuthorize_deny(t *testing.T) {
 	assert.Contains(obj.Reason, "Matched")
 }
 
+// Test that intentions with L7 permissions deny connections since they can
+// only be enforced per request.
+func TestAgentConnectAuthorize_permissions(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	target := "payments"
+
+	// Create an intention allowing some requests
+	{
+		req := structs.IntentionRequest{
+			Datacenter: "dc1",
+			Op:         structs.IntentionOpCreate,
+			Intention:  structs.TestIntention(t),
+		}
+		req.Intention.SourceNS = structs.IntentionDefaultNamespace
+		req.Intention.SourceName = "billing"
+		req.Intention.DestinationNS = structs.IntentionDefaultNamespace
+		req.Intention.DestinationName = target
+		req.Intention.Action = ""
+		req.Intention.Permissions = []*structs.IntentionPermission{
+			{
+				Action: structs.IntentionActionAllow,
+				HTTP: &structs.IntentionHTTPPermission{
+					PathExact: "/charge",
+					Methods:   []string{"POST"},
+				},
+			},
+		}
+
+		var reply string
+		require.NoError(a.RPC("Intention.Apply", &req, &reply))
+	}
+
+	args := &structs.ConnectAuthorizeRequest{
+		Target:        target,
+		ClientCertURI: connect.TestSpiffeIDService(t, "billing").URI().String(),
+	}
+	req, _ := http.NewRequest("POST", "/v1/agent/connect/authorize", jsonReader(args))
+	resp := httptest.NewRecorder()
+	respRaw, err := a.srv.AgentConnectAuthorize(resp, req)
+	require.NoError(err)
+	require.Equal(200, resp.Code)
+
+	obj := respRaw.(*connectAuthorizeResp)
+	require.False(obj.Authorized)
+	require.Contains(obj.Reason, "L7 intention")
+}
+
+// Test that intentions with a CIDR source match the address of the client.
+func TestAgentConnectAuthorize_cidr(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	target := "db"
+
+	// Allow the whole block but deny a smaller one within it
+	for block, action := range map[string]structs.IntentionAction{
+		"10.0.0.0/8":  structs.IntentionActionAllow,
+		"10.1.0.0/16": structs.IntentionActionDeny,
+	} {
+		req := structs.IntentionRequest{
+			Datacenter: "dc1",
+			Op:         structs.IntentionOpCreate,
+			Intention:  structs.TestIntention(t),
+		}
+		req.Intention.SourceType = structs.IntentionSourceCIDR
+		req.Intention.SourceNS = structs.IntentionDefaultNamespace
+		req.Intention.SourceName = block
+		req.Intention.DestinationNS = structs.IntentionDefaultNamespace
+		req.Intention.DestinationName = target
+		req.Intention.Action = action
+
+		var reply string
+		require.NoError(a.RPC("Intention.Apply", &req, &reply))
+	}
+
+	cases := []struct {
+		addr       string
+		authorized bool
+		reason     string
+	}{
+		{"10.0.0.1", true, "10.0.0.0/8"},
+		{"10.1.0.1", false, "10.1.0.0/16"},
+		{"192.168.0.1", true, "ACLs disabled"},
+		{"", true, "ACLs disabled"},
+	}
+	for _, tc := range cases {
+		args := &structs.ConnectAuthorizeRequest{
+			Target:        target,
+			ClientCertURI: connect.TestSpiffeIDService(t, "web").URI().String(),
+			ClientAddr:    tc.addr,
+		}
+		req, _ := http.NewRequest("POST", "/v1/agent/connect/authorize", jsonReader(args))
+		resp := httptest.NewRecorder()
+		respRaw, err := a.srv.AgentConnectAuthorize(resp, req)
+		require.NoError(err)
+		require.Equal(200, resp.Code)
+
+		obj := respRaw.(*connectAuthorizeResp)
+		require.Equal(tc.authorized, obj.Authorized, tc.addr)
+		require.Contains(obj.Reason, tc.reason, tc.addr)
+	}
+
+	// An invalid address is rejected
+	args := &structs.ConnectAuthorizeRequest{
+		Target:        target,
+		ClientCertURI: connect.TestSpiffeIDService(t, "web").URI().String(),
+		ClientAddr:    "not-an-ip",
+	}
+	req, _ := http.NewRequest("POST", "/v1/agent/connect/authorize", jsonReader(args))
+	resp := httptest.NewRecorder()
+	_, err := a.srv.AgentConnectAuthorize(resp, req)
+	require.Error(err)
+	require.Contains(err.Error(), "ClientAddr")
+}
+
 // Test when there is an intention allowing service with a different trust
 // domain. We allow this because migration between trust domains shouldn't cause
 // an outage even if we have stale info about current trusted domains. It's safe
@@ -553,6 +553,97 @@ func TestAgent_AddServiceNoRemoteExec(t *testing.T) {
 	}
 }
 
+func TestAgent_AddServices_RollsBackReplaced(t *testing.T) {
+	t.Parallel()
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	web := &structs.NodeService{
+		ID:      "web",
+		Service: "web",
+		Port:    8000,
+	}
+	webChk := &structs.CheckType{
+		CheckID: "web-check",
+		TTL:     15 * time.Second,
+	}
+	err := a.AddService(web, []*structs.CheckType{webChk}, true, "web-token", ConfigSourceLocal)
+	require.NoError(t, err)
+
+	svcFile := filepath.Join(a.Config.DataDir, servicesDir, stringHash("web"))
+	svcBuf, err := ioutil.ReadFile(svcFile)
+	require.NoError(t, err)
+	chkFile := filepath.Join(a.Config.DataDir, checksDir, checkIDHash("web-check"))
+	chkBuf, err := ioutil.ReadFile(chkFile)
+	require.NoError(t, err)
+
+	requireRestored := func() {
+		t.Helper()
+		require.Equal(t, 8000, a.State.Service("web").Port)
+		require.Equal(t, "web-token", a.State.ServiceToken("web"))
+		require.NotContains(t, a.State.Services(), "api")
+
+		require.Contains(t, a.checkTTLs, types.CheckID("web-check"))
+		require.NotContains(t, a.checkTCPs, types.CheckID("web-check"))
+		require.Equal(t, webChk, a.checkTypes["web-check"])
+
+		buf, err := ioutil.ReadFile(svcFile)
+		require.NoError(t, err)
+		require.Equal(t, svcBuf, buf)
+		buf, err = ioutil.ReadFile(chkFile)
+		require.NoError(t, err)
+		require.Equal(t, chkBuf, buf)
+	}
+
+	// The replacement for web changes its port and check type.
+	newWeb := func() *serviceRegistration {
+		return &serviceRegistration{
+			Service: &structs.NodeService{
+				ID:      "web",
+				Service: "web",
+				Port:    9000,
+			},
+			ChkTypes: []*structs.CheckType{
+				{
+					CheckID:  "web-check",
+					TCP:      "127.0.0.1:1",
+					Interval: 10 * time.Second,
+				},
+			},
+			Token: "new-token",
+		}
+	}
+
+	// A later service in the batch fails to be added since remote script
+	// checks are disabled.
+	err = a.addServices([]*serviceRegistration{
+		newWeb(),
+		{
+			Service: &structs.NodeService{ID: "api", Service: "api"},
+			ChkTypes: []*structs.CheckType{
+				{
+					ScriptArgs: []string{"true"},
+					Interval:   10 * time.Second,
+				},
+			},
+		},
+	}, true, ConfigSourceRemote)
+	require.Error(t, err)
+	requireRestored()
+
+	// A later service in the batch fails to be persisted since there's a
+	// directory in the way.
+	apiFile := filepath.Join(a.Config.DataDir, servicesDir, stringHash("api"))
+	require.NoError(t, os.MkdirAll(apiFile, 0700))
+	err = a.addServices([]*serviceRegistration{
+		newWeb(),
+		{Service: &structs.NodeService{ID: "api", Service: "api"}},
+	}, true, ConfigSourceRemote)
+	require.Error(t, err)
+	requireRestored()
+}
+
 func TestAgent_RemoveService(t *testing.T) {
 	t.Parallel()
 	a := NewTestAgent(t, t.Name(), "")
@@ -1021,6 +1112,46 @@ func TestAgent_AddCheck_RestoreState(t *testing.T) {
 	}
 }
 
+func TestAgent_AddCheck_RestoreState_Expires(t *testing.T) {
+	t.Parallel()
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+
+	// Persist state that is about to expire.
+	ttl := &checks.CheckTTL{
+		CheckID: "baz",
+		TTL:     2 * time.Second,
+	}
+	if err := a.persistCheckState(ttl, api.HealthPassing, "yup"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Register the check with a much longer TTL.
+	health := &structs.HealthCheck{
+		Node:    "foo",
+		CheckID: "baz",
+		Name:    "baz check 1",
+	}
+	chk := &structs.CheckType{
+		TTL: time.Hour,
+	}
+	if err := a.AddCheck(health, chk, false, "", ConfigSourceLocal); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The restored status should expire at the persisted deadline rather
+	// than a full TTL after the restart.
+	retry.Run(t, func(r *retry.R) {
+		check := a.State.Check("baz")
+		if check.Status != api.HealthCritical {
+			r.Fatalf("bad: %#v", check)
+		}
+		if !strings.Contains(check.Output, "yup") {
+			r.Fatalf("bad: %#v", check)
+		}
+	})
+}
+
 func TestAgent_AddCheck_ExecDisable(t *testing.T) {
 	t.Parallel()
 
@@ -1125,6 +1256,57 @@ func TestAgent_AddCheck_GRPC(t *testing.T) {
 	}
 }
 
+func TestAgent_AddCheck_UDPDNSTLS(t *testing.T) {
+	t.Parallel()
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+
+	add := func(id types.CheckID, chk *structs.CheckType) {
+		t.Helper()
+		health := &structs.HealthCheck{
+			Node:    "foo",
+			CheckID: id,
+			Name:    string(id),
+			Status:  api.HealthCritical,
+		}
+		require.NoError(t, a.AddCheck(health, chk, false, "", ConfigSourceLocal))
+		require.Contains(t, a.State.Checks(), id)
+	}
+
+	add("udp", &structs.CheckType{
+		UDP:      "localhost:12345",
+		UDPSend:  "ping",
+		Interval: 15 * time.Second,
+	})
+	add("dns", &structs.CheckType{
+		DNS:       "web.example.com",
+		DNSServer: "127.0.0.1:8600",
+		Interval:  15 * time.Second,
+	})
+	add("tls", &structs.CheckType{
+		TLS:                  "localhost:443",
+		TLSExpiryWarningDays: 14,
+		Interval:             15 * time.Second,
+	})
+
+	a.stateLock.Lock()
+	require.Contains(t, a.checkUDPs, types.CheckID("udp"))
+	require.Contains(t, a.checkDNSs, types.CheckID("dns"))
+	require.Contains(t, a.checkTLSs, types.CheckID("tls"))
+	require.Equal(t, 14*24*time.Hour, a.checkTLSs["tls"].ExpiryWarning)
+	a.stateLock.Unlock()
+
+	// Removing the checks stops and forgets them.
+	require.NoError(t, a.RemoveCheck("udp", false))
+	require.NoError(t, a.RemoveCheck("dns", false))
+	require.NoError(t, a.RemoveCheck("tls", false))
+	a.stateLock.Lock()
+	require.Empty(t, a.checkUDPs)
+	require.Empty(t, a.checkDNSs)
+	require.Empty(t, a.checkTLSs)
+	a.stateLock.Unlock()
+}
+
 func TestAgent_AddCheck_Alias(t *testing.T) {
 	t.Parallel()
 
@@ -2894,11 +3076,15 @@ func TestAgent_loadCheckState(t *testing.T) {
 		CheckID: "check1",
 		Status:  api.HealthCritical,
 	}
-	if err := a.loadCheckState(health); err != nil {
+	expires, err := a.loadCheckState(health)
+	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
 
 	// Should not have restored the status due to expiration
+	if !expires.IsZero() {
+		t.Fatalf("bad: %v", expires)
+	}
 	if health.Status != api.HealthCritical {
 		t.Fatalf("bad: %#v", health)
 	}
@@ -2920,11 +3106,15 @@ func TestAgent_loadCheckState(t *testing.T) {
 	}
 
 	// Try to load
-	if err := a.loadCheckState(health); err != nil {
+	expires, err = a.loadCheckState(health)
+	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
 
-	// Should have restored
+	// Should have restored along with the deadline
+	if time.Until(expires) > time.Minute || time.Until(expires) < 50*time.Second {
+		t.Fatalf("bad: %v", expires)
+	}
 	if health.Status != api.HealthPassing {
 		t.Fatalf("bad: %#v", health)
 	}
@@ -3016,6 +3206,41 @@ func TestAgent_reloadWatches(t *testing.T) {
 		t.Fatalf("bad: %s", err)
 	}
 
+	// File handlers with delivery options should succeed
+	newConf.Watches = []map[string]interface{}{
+		{
+			"type":         "key",
+			"key":          "asdf",
+			"handler_type": "file",
+			"file_handler_config": map[string]interface{}{
+				"path": filepath.Join(a.Config.DataDir, "asdf.json"),
+			},
+			"delivery_config": map[string]interface{}{
+				"max_retries": -1,
+				"coalesce":    true,
+			},
+		},
+	}
+	if err := a.reloadWatches(&newConf); err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+
+	// File handlers can't be combined with args
+	newConf.Watches = []map[string]interface{}{
+		{
+			"type":         "key",
+			"key":          "asdf",
+			"handler_type": "file",
+			"file_handler_config": map[string]interface{}{
+				"path": filepath.Join(a.Config.DataDir, "asdf.json"),
+			},
+			"args": []interface{}{"ls"},
+		},
+	}
+	if err := a.reloadWatches(&newConf); err == nil || !strings.Contains(err.Error(), "Only one watch handler allowed") {
+		t.Fatalf("bad: %s", err)
+	}
+
 	// Should still succeed with only HTTPS addresses
 	newConf.HTTPSAddrs = newConf.HTTPAddrs
 	newConf.HTTPAddrs = make([]net.Addr, 0)
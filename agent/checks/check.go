@@ -1,7 +1,9 @@
 package checks
 
 import (
+	"bytes"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -10,12 +12,15 @@ import (
 	"net/http"
 	"os"
 	osexec "os/exec"
+	"regexp"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/armon/circbuf"
 	"github.com/hashicorp/consul/agent/exec"
+	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/lib"
 	"github.com/hashicorp/consul/types"
@@ -33,6 +38,10 @@ const (
 	// from being captured
 	BufSize = 4 * 1024 // 4KB
 
+	// MaxAssertionBodySize is the maximum size of an HTTP check's response
+	// body that body matches and JSON assertions are applied to.
+	MaxAssertionBodySize = 1024 * 1024 // 1MB
+
 	// UserAgent is the value of the User-Agent header
 	// for HTTP health checks.
 	UserAgent = "Consul Health Check"
@@ -52,6 +61,57 @@ type CheckNotifier interface {
 	UpdateCheck(checkID types.CheckID, status, output string)
 }
 
+// StatusHandler is a CheckNotifier that wraps another and only passes on a
+// status change once the check has returned it enough times in a row. This
+// stops a single slow or failed response from flapping a check between
+// passing and critical. Warning results count as successes. A StatusHandler
+// keeps the counts for a single check so it must not be shared between checks.
+type StatusHandler struct {
+	inner                  CheckNotifier
+	logger                 *log.Logger
+	successBeforePassing   int
+	successCounter         int
+	failuresBeforeCritical int
+	failuresCounter        int
+}
+
+// NewStatusHandler returns a StatusHandler that notifies inner once a check
+// has had successBeforePassing consecutive successes or failuresBeforeCritical
+// consecutive failures.
+func NewStatusHandler(inner CheckNotifier, logger *log.Logger,
+	successBeforePassing, failuresBeforeCritical int) *StatusHandler {
+	return &StatusHandler{
+		inner:                  inner,
+		logger:                 logger,
+		successBeforePassing:   successBeforePassing,
+		failuresBeforeCritical: failuresBeforeCritical,
+	}
+}
+
+// UpdateCheck implements CheckNotifier.
+func (s *StatusHandler) UpdateCheck(checkID types.CheckID, status, output string) {
+	if status == api.HealthPassing || status == api.HealthWarning {
+		s.successCounter++
+		s.failuresCounter = 0
+		if s.successCounter >= s.successBeforePassing {
+			s.inner.UpdateCheck(checkID, status, output)
+			return
+		}
+		s.logger.Printf("[DEBUG] agent: Check %q was %s but has only succeeded %d of %d times required",
+			checkID, status, s.successCounter, s.successBeforePassing)
+		return
+	}
+
+	s.failuresCounter++
+	s.successCounter = 0
+	if s.failuresCounter >= s.failuresBeforeCritical {
+		s.inner.UpdateCheck(checkID, status, output)
+		return
+	}
+	s.logger.Printf("[DEBUG] agent: Check %q was %s but has only failed %d of %d times required",
+		checkID, status, s.failuresCounter, s.failuresBeforeCritical)
+}
+
 // CheckMonitor is used to periodically invoke a script to
 // determine the health of a given check. It is compatible with
 // nagios plugins and expects the output in the same format.
@@ -216,6 +276,11 @@ type CheckTTL struct {
 
 	timer *time.Timer
 
+	// restoredExpires is when the TTL of a restored status runs out. It is
+	// used in place of TTL for the first timer after Start, and then cleared
+	// so that later restarts of the check get a full TTL.
+	restoredExpires time.Time
+
 	lastOutput     string
 	lastOutputLock sync.RWMutex
 
@@ -230,10 +295,30 @@ func (c *CheckTTL) Start() {
 	defer c.stopLock.Unlock()
 	c.stop = false
 	c.stopCh = make(chan struct{})
-	c.timer = time.NewTimer(c.TTL)
+	ttl := c.TTL
+	if !c.restoredExpires.IsZero() {
+		ttl = time.Until(c.restoredExpires)
+		if ttl < 0 {
+			ttl = 0
+		}
+		c.restoredExpires = time.Time{}
+	}
+	c.timer = time.NewTimer(ttl)
 	go c.run()
 }
 
+// Restore is used to carry over the output and deadline of a status that was
+// set before an agent restart, so the restored status expires when it would
+// have without the restart rather than a full TTL later. It must be called
+// before Start.
+func (c *CheckTTL) Restore(output string, expires time.Time) {
+	c.lastOutputLock.Lock()
+	c.lastOutput = output
+	c.lastOutputLock.Unlock()
+
+	c.restoredExpires = expires
+}
+
 // Stop is used to stop a check ttl.
 func (c *CheckTTL) Stop() {
 	c.stopLock.Lock()
@@ -293,17 +378,24 @@ func (c *CheckTTL) SetStatus(status, output string) {
 // The check is warning if the response code is 429.
 // The check is critical if the response code is anything else
 // or if the request returns an error
+// If BodyMatch or JSONAssertions are set, the body of a passing or warning
+// response must also satisfy them. A failed body match is critical and a
+// failed JSON assertion reports the assertion's status.
 type CheckHTTP struct {
 	Notify          CheckNotifier
 	CheckID         types.CheckID
 	HTTP            string
 	Header          map[string][]string
 	Method          string
+	BodyMatch       string
+	BodyMatchRegex  bool
+	JSONAssertions  []structs.CheckJSONAssertion
 	Interval        time.Duration
 	Timeout         time.Duration
 	Logger          *log.Logger
 	TLSClientConfig *tls.Config
 
+	bodyRegex  *regexp.Regexp
 	httpClient *http.Client
 	stop       bool
 	stopCh     chan struct{}
@@ -341,6 +433,11 @@ func (c *CheckHTTP) Start() {
 		}
 	}
 
+	if c.BodyMatchRegex && c.bodyRegex == nil {
+		// The definition has already been validated so this can't fail.
+		c.bodyRegex = regexp.MustCompile(c.BodyMatch)
+	}
+
 	c.stop = false
 	c.stopCh = make(chan struct{})
 	go c.run()
@@ -412,32 +509,105 @@ func (c *CheckHTTP) check() {
 	}
 	defer resp.Body.Close()
 
-	// Read the response into a circular buffer to limit the size
+	// Read the response into a circular buffer to limit the size. When the
+	// body has to be inspected, a larger copy is kept as well.
 	output, _ := circbuf.NewBuffer(BufSize)
-	if _, err := io.Copy(output, resp.Body); err != nil {
+	var body bytes.Buffer
+	var dst io.Writer = output
+	inspectBody := c.BodyMatch != "" || len(c.JSONAssertions) > 0
+	if inspectBody {
+		dst = io.MultiWriter(output, &limitedWriter{w: &body, n: MaxAssertionBodySize})
+	}
+	if _, err := io.Copy(dst, resp.Body); err != nil {
 		c.Logger.Printf("[WARN] agent: Check %q error while reading body: %s", c.CheckID, err)
 	}
 
 	// Format the response body
 	result := fmt.Sprintf("HTTP %s %s: %s Output: %s", method, c.HTTP, resp.Status, output.String())
 
+	status := api.HealthCritical
 	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
 		// PASSING (2xx)
-		c.Logger.Printf("[DEBUG] agent: Check %q is passing", c.CheckID)
-		c.Notify.UpdateCheck(c.CheckID, api.HealthPassing, result)
-
+		status = api.HealthPassing
 	} else if resp.StatusCode == 429 {
 		// WARNING
 		// 429 Too Many Requests (RFC 6585)
 		// The user has sent too many requests in a given amount of time.
-		c.Logger.Printf("[WARN] agent: Check %q is now warning", c.CheckID)
-		c.Notify.UpdateCheck(c.CheckID, api.HealthWarning, result)
+		status = api.HealthWarning
+	}
 
-	} else {
-		// CRITICAL
+	// Body assertions can only make a passing or warning response worse.
+	if inspectBody && status != api.HealthCritical {
+		if assertStatus, reason := c.checkBody(body.Bytes()); reason != "" {
+			if assertStatus == api.HealthCritical || status == api.HealthPassing {
+				status = assertStatus
+			}
+			result = fmt.Sprintf("HTTP %s %s: %s Assertion failed: %s Output: %s",
+				method, c.HTTP, resp.Status, reason, output.String())
+		}
+	}
+
+	switch status {
+	case api.HealthPassing:
+		c.Logger.Printf("[DEBUG] agent: Check %q is passing", c.CheckID)
+	case api.HealthWarning:
+		c.Logger.Printf("[WARN] agent: Check %q is now warning", c.CheckID)
+	default:
 		c.Logger.Printf("[WARN] agent: Check %q is now critical", c.CheckID)
-		c.Notify.UpdateCheck(c.CheckID, api.HealthCritical, result)
 	}
+	c.Notify.UpdateCheck(c.CheckID, status, result)
+}
+
+// checkBody applies the body match and JSON assertions to a response body.
+// It returns the worst status of the failed assertions along with a short
+// explanation of them, or an empty explanation if they all passed.
+func (c *CheckHTTP) checkBody(body []byte) (string, string) {
+	if c.BodyMatch != "" {
+		matched := false
+		if c.bodyRegex != nil {
+			matched = c.bodyRegex.Match(body)
+		} else {
+			matched = bytes.Contains(body, []byte(c.BodyMatch))
+		}
+		if !matched {
+			return api.HealthCritical, fmt.Sprintf("body does not match %q", c.BodyMatch)
+		}
+	}
+
+	if len(c.JSONAssertions) == 0 {
+		return api.HealthPassing, ""
+	}
+
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return api.HealthCritical, fmt.Sprintf("body is not valid JSON: %s", err)
+	}
+
+	status := api.HealthPassing
+	var reasons []string
+	for _, a := range c.JSONAssertions {
+		reason := ""
+		if v, err := jsonPathLookup(doc, a.Path); err != nil {
+			reason = fmt.Sprintf("%s %s", a.Path, err)
+		} else if got := jsonValueString(v); got != a.Value {
+			reason = fmt.Sprintf("%s is %q, expected %q", a.Path, got, a.Value)
+		}
+		if reason == "" {
+			continue
+		}
+
+		reasons = append(reasons, reason)
+		if a.Status == api.HealthWarning {
+			if status == api.HealthPassing {
+				status = api.HealthWarning
+			}
+		} else {
+			status = api.HealthCritical
+		}
+	}
+	return status, strings.Join(reasons, ", ")
 }
 
 // CheckTCP is used to periodically make an TCP/UDP connection to
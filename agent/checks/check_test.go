@@ -2,6 +2,7 @@ package checks
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -16,6 +17,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/consul/agent/mock"
+	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/consul/types"
@@ -215,6 +217,39 @@ func TestCheckTTL(t *testing.T) {
 	}
 }
 
+func TestCheckTTL_Restore(t *testing.T) {
+	// t.Parallel() // timing test. no parallel
+	notif := mock.NewNotify()
+	check := &CheckTTL{
+		Notify:  notif,
+		CheckID: types.CheckID("foo"),
+		TTL:     time.Minute,
+		Logger:  log.New(ioutil.Discard, uniqueID(), log.LstdFlags),
+	}
+	check.Restore("restored-output", time.Now().Add(100*time.Millisecond))
+	check.Start()
+
+	// The restored deadline is used instead of the full TTL.
+	time.Sleep(200 * time.Millisecond)
+	if notif.State("foo") != api.HealthCritical {
+		t.Fatalf("should be critical %v", notif.StateMap())
+	}
+	if !strings.Contains(notif.Output("foo"), "restored-output") {
+		t.Fatalf("should have retained output %v", notif.OutputMap())
+	}
+
+	// Restarting the check gets the full TTL rather than reusing the
+	// restored deadline that has already passed.
+	check.Stop()
+	check.SetStatus(api.HealthPassing, "test-output")
+	check.Start()
+	defer check.Stop()
+	time.Sleep(100 * time.Millisecond)
+	if notif.State("foo") != api.HealthPassing {
+		t.Fatalf("should be passing %v", notif.StateMap())
+	}
+}
+
 func TestCheckHTTP(t *testing.T) {
 	t.Parallel()
 
@@ -369,6 +404,152 @@ func TestCheckHTTP_disablesKeepAlives(t *testing.T) {
 	}
 }
 
+func TestCheckHTTP_BodyAssertions(t *testing.T) {
+	t.Parallel()
+
+	body := `{"status": "degraded", "version": 3, "checks": {"db": {"ok": true}}, "nodes": [{"name": "a"}, {"name": "b"}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/limited" {
+			w.WriteHeader(429)
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		desc           string
+		path           string
+		bodyMatch      string
+		bodyMatchRegex bool
+		assertions     []structs.CheckJSONAssertion
+		status         string
+		output         string
+	}{
+		{
+			desc:      "substring match",
+			bodyMatch: `"version": 3`,
+			status:    api.HealthPassing,
+		},
+		{
+			desc:      "substring mismatch",
+			bodyMatch: `"status": "ok"`,
+			status:    api.HealthCritical,
+			output:    `Assertion failed: body does not match "\"status\": \"ok\""`,
+		},
+		{
+			desc:           "regex match",
+			bodyMatch:      `"version": \d+`,
+			bodyMatchRegex: true,
+			status:         api.HealthPassing,
+		},
+		{
+			desc:           "regex mismatch",
+			bodyMatch:      `^ok$`,
+			bodyMatchRegex: true,
+			status:         api.HealthCritical,
+		},
+		{
+			desc: "json assertions pass",
+			assertions: []structs.CheckJSONAssertion{
+				{Path: "$.version", Value: "3"},
+				{Path: "checks.db.ok", Value: "true"},
+				{Path: "nodes[1].name", Value: "b"},
+			},
+			status: api.HealthPassing,
+		},
+		{
+			desc: "json assertion warns",
+			assertions: []structs.CheckJSONAssertion{
+				{Path: "status", Value: "ok", Status: api.HealthWarning},
+			},
+			status: api.HealthWarning,
+			output: `Assertion failed: status is "degraded", expected "ok"`,
+		},
+		{
+			desc: "worst json assertion wins",
+			assertions: []structs.CheckJSONAssertion{
+				{Path: "status", Value: "ok", Status: api.HealthWarning},
+				{Path: "checks.cache.ok", Value: "true"},
+			},
+			status: api.HealthCritical,
+			output: `Assertion failed: status is "degraded", expected "ok", checks.cache.ok not found: no key "cache"`,
+		},
+		{
+			desc: "assertions don't improve a warning",
+			path: "/limited",
+			assertions: []structs.CheckJSONAssertion{
+				{Path: "version", Value: "3"},
+			},
+			status: api.HealthWarning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			notif := mock.NewNotify()
+			check := &CheckHTTP{
+				Notify:         notif,
+				CheckID:        types.CheckID("foo"),
+				HTTP:           server.URL + tt.path,
+				BodyMatch:      tt.bodyMatch,
+				BodyMatchRegex: tt.bodyMatchRegex,
+				JSONAssertions: tt.assertions,
+				Interval:       10 * time.Second,
+				Logger:         log.New(ioutil.Discard, uniqueID(), log.LstdFlags),
+			}
+			check.Start()
+			check.Stop()
+			check.check()
+
+			if got, want := notif.State("foo"), tt.status; got != want {
+				t.Fatalf("got status %q want %q: %s", got, want, notif.Output("foo"))
+			}
+			if got := notif.Output("foo"); !strings.Contains(got, tt.output) {
+				t.Fatalf("got output %q want it to contain %q", got, tt.output)
+			}
+		})
+	}
+}
+
+func TestJSONPathLookup(t *testing.T) {
+	t.Parallel()
+
+	var doc interface{}
+	if err := json.Unmarshal([]byte(`{"a": {"b": [1, [2, 3]]}, "c": null}`), &doc); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+		err  string
+	}{
+		{"$", `{"a":{"b":[1,[2,3]]},"c":null}`, ""},
+		{"a.b[0]", "1", ""},
+		{"$.a.b[1][0]", "2", ""},
+		{"c", "null", ""},
+		{"a.b[2]", "", "not found: index 2 out of range"},
+		{"a.x", "", `not found: no key "x"`},
+		{"a.b.c", "", `not found: "c" is not an object`},
+		{"a.b[x]", "", `has an invalid index in "b[x]"`},
+		{"a..b", "", "has an empty key"},
+	}
+	for _, tt := range tests {
+		v, err := jsonPathLookup(doc, tt.path)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("%s: got error %v want %q", tt.path, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: err: %v", tt.path, err)
+		}
+		if got := jsonValueString(v); got != tt.want {
+			t.Fatalf("%s: got %q want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
 func largeBodyHandler(code int) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Body larger than 4k limit
@@ -810,3 +991,62 @@ func TestCheck_Docker(t *testing.T) {
 		})
 	}
 }
+
+func TestStatusHandler(t *testing.T) {
+	t.Parallel()
+
+	type result struct {
+		status string
+		want   string
+	}
+	tests := []struct {
+		name                   string
+		successBeforePassing   int
+		failuresBeforeCritical int
+		results                []result
+	}{
+		{
+			name: "no thresholds",
+			results: []result{
+				{api.HealthPassing, api.HealthPassing},
+				{api.HealthCritical, api.HealthCritical},
+				{api.HealthWarning, api.HealthWarning},
+			},
+		},
+		{
+			name:                   "thresholds",
+			successBeforePassing:   2,
+			failuresBeforeCritical: 3,
+			results: []result{
+				// The first status is only reported after enough results.
+				{api.HealthPassing, ""},
+				{api.HealthPassing, api.HealthPassing},
+				{api.HealthCritical, api.HealthPassing},
+				{api.HealthCritical, api.HealthPassing},
+				// A success resets the failure count.
+				{api.HealthPassing, api.HealthPassing},
+				{api.HealthCritical, api.HealthPassing},
+				{api.HealthCritical, api.HealthPassing},
+				{api.HealthCritical, api.HealthCritical},
+				// Warnings count as successes.
+				{api.HealthWarning, api.HealthCritical},
+				{api.HealthWarning, api.HealthWarning},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notif := mock.NewNotify()
+			logger := log.New(ioutil.Discard, uniqueID(), log.LstdFlags)
+			handler := NewStatusHandler(notif, logger, tt.successBeforePassing,
+				tt.failuresBeforeCritical)
+
+			for i, r := range tt.results {
+				handler.UpdateCheck("foo", r.status, "")
+				if got := notif.State("foo"); got != r.want {
+					t.Fatalf("result %d: got state %q want %q", i, got, r.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package checks
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/lib"
+	"github.com/hashicorp/consul/types"
+	"github.com/miekg/dns"
+)
+
+// CheckDNS is used to periodically resolve a name against a DNS server to
+// determine the health of a given check.
+// The check is passing if the server answers with at least one record of
+// RecordType and, if Expect is set, one of the records matches it.
+// The check is critical if the query fails, the server returns an error code,
+// or there is no matching record.
+type CheckDNS struct {
+	Notify     CheckNotifier
+	CheckID    types.CheckID
+	DNS        string
+	Server     string
+	RecordType string
+	Expect     string
+	Interval   time.Duration
+	Timeout    time.Duration
+	Logger     *log.Logger
+
+	stop     bool
+	stopCh   chan struct{}
+	stopLock sync.Mutex
+}
+
+// Start is used to start a DNS check.
+// The check runs until stop is called
+func (c *CheckDNS) Start() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+	c.stop = false
+	c.stopCh = make(chan struct{})
+	go c.run()
+}
+
+// Stop is used to stop a DNS check.
+func (c *CheckDNS) Stop() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+	if !c.stop {
+		c.stop = true
+		close(c.stopCh)
+	}
+}
+
+// run is invoked by a goroutine to run until Stop() is called
+func (c *CheckDNS) run() {
+	// Get the randomized initial pause time
+	initialPauseTime := lib.RandomStagger(c.Interval)
+	next := time.After(initialPauseTime)
+	for {
+		select {
+		case <-next:
+			c.check()
+			next = time.After(c.Interval)
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+// check is invoked periodically to perform the DNS check
+func (c *CheckDNS) check() {
+	output, err := c.query()
+	if err != nil {
+		c.Logger.Printf("[WARN] agent: Check %q DNS query failed: %s", c.CheckID, err)
+		c.Notify.UpdateCheck(c.CheckID, api.HealthCritical, err.Error())
+		return
+	}
+	c.Logger.Printf("[DEBUG] agent: Check %q is passing", c.CheckID)
+	c.Notify.UpdateCheck(c.CheckID, api.HealthPassing, output)
+}
+
+// query resolves the name and returns the check output if the answer
+// matches, or an error explaining why it doesn't.
+func (c *CheckDNS) query() (string, error) {
+	recordType := strings.ToUpper(c.RecordType)
+	if recordType == "" {
+		recordType = "A"
+	}
+	qtype, ok := dns.StringToType[recordType]
+	if !ok {
+		return "", fmt.Errorf("unknown DNS record type %q", c.RecordType)
+	}
+
+	// Default to the standard DNS port if the server doesn't have one.
+	server := c.Server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	timeout := 10 * time.Second
+	if c.Timeout > 0 {
+		timeout = c.Timeout
+	}
+
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(c.DNS), qtype)
+	client := &dns.Client{Timeout: timeout}
+	r, _, err := client.Exchange(m, server)
+	if err != nil {
+		return "", err
+	}
+	if r.Rcode != dns.RcodeSuccess {
+		return "", fmt.Errorf("DNS query for %s %s returned %s",
+			c.DNS, recordType, dns.RcodeToString[r.Rcode])
+	}
+
+	var values []string
+	for _, rr := range r.Answer {
+		if rr.Header().Rrtype != qtype {
+			continue
+		}
+		values = append(values, dnsRecordValue(rr))
+	}
+	if len(values) == 0 {
+		return "", fmt.Errorf("DNS query for %s %s returned no records", c.DNS, recordType)
+	}
+
+	if c.Expect == "" {
+		return fmt.Sprintf("DNS query for %s %s: %s", c.DNS, recordType,
+			strings.Join(values, ", ")), nil
+	}
+	expect := strings.TrimSuffix(c.Expect, ".")
+	for _, v := range values {
+		if strings.EqualFold(v, expect) {
+			return fmt.Sprintf("DNS query for %s %s: Found %s", c.DNS, recordType, v), nil
+		}
+	}
+	return "", fmt.Errorf("DNS query for %s %s didn't return %q, got: %s",
+		c.DNS, recordType, c.Expect, strings.Join(values, ", "))
+}
+
+// dnsRecordValue returns the value of a record as it would be written in a
+// check definition, without the record header and with names unqualified.
+func dnsRecordValue(rr dns.RR) string {
+	switch v := rr.(type) {
+	case *dns.A:
+		return v.A.String()
+	case *dns.AAAA:
+		return v.AAAA.String()
+	case *dns.CNAME:
+		return strings.TrimSuffix(v.Target, ".")
+	case *dns.NS:
+		return strings.TrimSuffix(v.Ns, ".")
+	case *dns.PTR:
+		return strings.TrimSuffix(v.Ptr, ".")
+	case *dns.MX:
+		return strings.TrimSuffix(v.Mx, ".")
+	case *dns.SRV:
+		return strings.TrimSuffix(v.Target, ".")
+	case *dns.TXT:
+		return strings.Join(v.Txt, "")
+	default:
+		return strings.TrimPrefix(rr.String(), rr.Header().String())
+	}
+}
@@ -0,0 +1,92 @@
+package checks
+
+import (
+	"io/ioutil"
+	"log"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/agent/mock"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/types"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDNSServer answers for web.example.com and returns NXDOMAIN for
+// everything else.
+func mockDNSServer(t *testing.T) (string, func()) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	records := map[uint16][]string{
+		dns.TypeA:     {"web.example.com. 0 IN A 10.0.0.1", "web.example.com. 0 IN A 10.0.0.2"},
+		dns.TypeCNAME: {"web.example.com. 0 IN CNAME lb.example.com."},
+		dns.TypeTXT:   {`web.example.com. 0 IN TXT "status=ok"`},
+	}
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+		q := req.Question[0]
+		if q.Name != "web.example.com." {
+			m.SetRcode(req, dns.RcodeNameError)
+		}
+		for _, rr := range records[q.Qtype] {
+			if q.Name != "web.example.com." {
+				break
+			}
+			r, err := dns.NewRR(rr)
+			if err != nil {
+				panic(err)
+			}
+			m.Answer = append(m.Answer, r)
+		}
+		w.WriteMsg(m)
+	})
+
+	server := &dns.Server{PacketConn: conn, Handler: handler}
+	go server.ActivateAndServe()
+	return conn.LocalAddr().String(), func() { server.Shutdown() }
+}
+
+func TestCheckDNS(t *testing.T) {
+	t.Parallel()
+
+	addr, stop := mockDNSServer(t)
+	defer stop()
+
+	tests := []struct {
+		desc       string
+		name       string
+		recordType string
+		expect     string
+		status     string
+	}{
+		{"any A record", "web.example.com", "", "", api.HealthPassing},
+		{"matching A record", "web.example.com", "A", "10.0.0.2", api.HealthPassing},
+		{"mismatched A record", "web.example.com", "A", "10.0.0.3", api.HealthCritical},
+		{"matching CNAME", "web.example.com", "cname", "lb.example.com.", api.HealthPassing},
+		{"matching TXT", "web.example.com", "TXT", "status=ok", api.HealthPassing},
+		{"no records", "web.example.com", "AAAA", "", api.HealthCritical},
+		{"nxdomain", "db.example.com", "A", "", api.HealthCritical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			notif := mock.NewNotify()
+			check := &CheckDNS{
+				Notify:     notif,
+				CheckID:    types.CheckID("foo"),
+				DNS:        tt.name,
+				Server:     addr,
+				RecordType: tt.recordType,
+				Expect:     tt.expect,
+				Interval:   10 * time.Second,
+				Timeout:    time.Second,
+				Logger:     log.New(ioutil.Discard, uniqueID(), log.LstdFlags),
+			}
+			check.check()
+			require.Equal(t, tt.status, notif.State("foo"), notif.Output("foo"))
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// limitedWriter writes at most n bytes to w and silently discards the rest,
+// so that it can be used with io.MultiWriter without cutting off the other
+// writers.
+type limitedWriter struct {
+	w io.Writer
+	n int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.n > 0 {
+		chunk := p
+		if len(chunk) > l.n {
+			chunk = chunk[:l.n]
+		}
+		n, err := l.w.Write(chunk)
+		l.n -= n
+		if err != nil {
+			return n, err
+		}
+	}
+	return len(p), nil
+}
+
+// jsonPathLookup returns the value at path in a document decoded by
+// encoding/json. Paths are dot separated keys with optional array indexes,
+// such as "checks.db.status" or "$.items[0].state".
+func jsonPathLookup(doc interface{}, path string) (interface{}, error) {
+	p := strings.TrimPrefix(path, "$")
+	p = strings.TrimPrefix(p, ".")
+	if p == "" {
+		return doc, nil
+	}
+
+	cur := doc
+	for _, segment := range strings.Split(p, ".") {
+		key, indexes, err := parseJSONPathSegment(segment)
+		if err != nil {
+			return nil, err
+		}
+
+		if key != "" {
+			obj, ok := cur.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("not found: %q is not an object", key)
+			}
+			if cur, ok = obj[key]; !ok {
+				return nil, fmt.Errorf("not found: no key %q", key)
+			}
+		}
+
+		for _, idx := range indexes {
+			arr, ok := cur.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("not found: index %d is not into an array", idx)
+			}
+			if idx < 0 || idx >= len(arr) {
+				return nil, fmt.Errorf("not found: index %d out of range", idx)
+			}
+			cur = arr[idx]
+		}
+	}
+	return cur, nil
+}
+
+// parseJSONPathSegment splits a segment like "items[0][1]" into its key and
+// array indexes.
+func parseJSONPathSegment(segment string) (string, []int, error) {
+	open := strings.Index(segment, "[")
+	if open == -1 {
+		if segment == "" {
+			return "", nil, fmt.Errorf("has an empty key")
+		}
+		return segment, nil, nil
+	}
+
+	key, rest := segment[:open], segment[open:]
+	var indexes []int
+	for rest != "" {
+		end := strings.Index(rest, "]")
+		if rest[0] != '[' || end == -1 {
+			return "", nil, fmt.Errorf("has an invalid index in %q", segment)
+		}
+		idx, err := strconv.Atoi(rest[1:end])
+		if err != nil {
+			return "", nil, fmt.Errorf("has an invalid index in %q", segment)
+		}
+		indexes = append(indexes, idx)
+		rest = rest[end+1:]
+	}
+	return key, indexes, nil
+}
+
+// jsonValueString returns the string a JSON assertion's value is compared
+// against. Strings are returned as is and everything else is JSON encoded.
+func jsonValueString(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case json.Number:
+		return x.String()
+	default:
+		b, err := json.Marshal(x)
+		if err != nil {
+			return fmt.Sprintf("%v", x)
+		}
+		return string(b)
+	}
+}
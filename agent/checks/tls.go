@@ -0,0 +1,145 @@
+package checks
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/lib"
+	"github.com/hashicorp/consul/types"
+)
+
+// CheckTLS is used to periodically complete a TLS handshake to determine the
+// health of a given check.
+// The check is passing if the handshake succeeds and the certificate is
+// valid for longer than ExpiryWarning.
+// The check is warning if the certificate expires within ExpiryWarning.
+// The check is critical if the handshake fails, including when the
+// certificate chain can't be verified, or the certificate has expired.
+type CheckTLS struct {
+	Notify          CheckNotifier
+	CheckID         types.CheckID
+	TLS             string
+	ServerName      string
+	ExpiryWarning   time.Duration
+	Interval        time.Duration
+	Timeout         time.Duration
+	TLSClientConfig *tls.Config
+	Logger          *log.Logger
+
+	// now is used in place of time.Now so tests can control the expiry
+	// calculation.
+	now func() time.Time
+
+	tlsConfig *tls.Config
+	stop      bool
+	stopCh    chan struct{}
+	stopLock  sync.Mutex
+}
+
+// Start is used to start a TLS check.
+// The check runs until stop is called
+func (c *CheckTLS) Start() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+
+	if c.now == nil {
+		c.now = time.Now
+	}
+
+	// Verify against the name being checked rather than whatever the
+	// client config was set up for.
+	if c.TLSClientConfig != nil {
+		c.tlsConfig = c.TLSClientConfig.Clone()
+	} else {
+		c.tlsConfig = &tls.Config{}
+	}
+	c.tlsConfig.ServerName = c.ServerName
+	if c.tlsConfig.ServerName == "" {
+		host, _, err := net.SplitHostPort(c.TLS)
+		if err != nil {
+			host = c.TLS
+		}
+		c.tlsConfig.ServerName = host
+	}
+
+	c.stop = false
+	c.stopCh = make(chan struct{})
+	go c.run()
+}
+
+// Stop is used to stop a TLS check.
+func (c *CheckTLS) Stop() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+	if !c.stop {
+		c.stop = true
+		close(c.stopCh)
+	}
+}
+
+// run is invoked by a goroutine to run until Stop() is called
+func (c *CheckTLS) run() {
+	// Get the randomized initial pause time
+	initialPauseTime := lib.RandomStagger(c.Interval)
+	next := time.After(initialPauseTime)
+	for {
+		select {
+		case <-next:
+			c.check()
+			next = time.After(c.Interval)
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+// check is invoked periodically to perform the TLS check
+func (c *CheckTLS) check() {
+	timeout := 10 * time.Second
+	if c.Timeout > 0 {
+		timeout = c.Timeout
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", c.TLS, c.tlsConfig)
+	if err != nil {
+		c.Logger.Printf("[WARN] agent: Check %q TLS handshake failed: %s", c.CheckID, err)
+		c.Notify.UpdateCheck(c.CheckID, api.HealthCritical, err.Error())
+		return
+	}
+	state := conn.ConnectionState()
+	conn.Close()
+
+	if len(state.PeerCertificates) == 0 {
+		c.Notify.UpdateCheck(c.CheckID, api.HealthCritical,
+			fmt.Sprintf("TLS handshake %s: No certificate presented", c.TLS))
+		return
+	}
+
+	// Expiry is only checked here when verification is skipped since the
+	// handshake fails on expired certificates otherwise.
+	notAfter := state.PeerCertificates[0].NotAfter
+	remaining := notAfter.Sub(c.now())
+	switch {
+	case remaining <= 0:
+		c.Logger.Printf("[WARN] agent: Check %q certificate has expired", c.CheckID)
+		c.Notify.UpdateCheck(c.CheckID, api.HealthCritical,
+			fmt.Sprintf("TLS handshake %s: Certificate expired at %s", c.TLS, notAfter.UTC()))
+
+	case remaining < c.ExpiryWarning:
+		c.Logger.Printf("[WARN] agent: Check %q certificate expires in %s", c.CheckID, remaining)
+		c.Notify.UpdateCheck(c.CheckID, api.HealthWarning,
+			fmt.Sprintf("TLS handshake %s: Certificate expires in %d days at %s",
+				c.TLS, int(remaining.Hours()/24), notAfter.UTC()))
+
+	default:
+		c.Logger.Printf("[DEBUG] agent: Check %q is passing", c.CheckID)
+		c.Notify.UpdateCheck(c.CheckID, api.HealthPassing,
+			fmt.Sprintf("TLS handshake %s: Success, certificate expires at %s", c.TLS, notAfter.UTC()))
+	}
+}
@@ -0,0 +1,95 @@
+package checks
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/agent/mock"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTLS(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	notAfter := server.Certificate().NotAfter
+	roots := server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+	tests := []struct {
+		desc          string
+		config        *tls.Config
+		serverName    string
+		now           time.Time
+		expiryWarning time.Duration
+		status        string
+	}{
+		{
+			desc:   "trusted",
+			config: &tls.Config{RootCAs: roots},
+			now:    time.Now(),
+			status: api.HealthPassing,
+		},
+		{
+			desc:          "trusted outside the warning window",
+			config:        &tls.Config{RootCAs: roots},
+			now:           notAfter.Add(-30 * 24 * time.Hour),
+			expiryWarning: 10 * 24 * time.Hour,
+			status:        api.HealthPassing,
+		},
+		{
+			desc:          "expiring soon",
+			config:        &tls.Config{RootCAs: roots},
+			now:           notAfter.Add(-5 * 24 * time.Hour),
+			expiryWarning: 10 * 24 * time.Hour,
+			status:        api.HealthWarning,
+		},
+		{
+			desc:   "expired without verification",
+			config: &tls.Config{InsecureSkipVerify: true},
+			now:    notAfter.Add(time.Hour),
+			status: api.HealthCritical,
+		},
+		{
+			desc:   "untrusted chain",
+			config: &tls.Config{},
+			now:    time.Now(),
+			status: api.HealthCritical,
+		},
+		{
+			desc:       "name mismatch",
+			config:     &tls.Config{RootCAs: roots},
+			serverName: "consul.example.org",
+			now:        time.Now(),
+			status:     api.HealthCritical,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			notif := mock.NewNotify()
+			check := &CheckTLS{
+				Notify:          notif,
+				CheckID:         types.CheckID("foo"),
+				TLS:             server.Listener.Addr().String(),
+				ServerName:      tt.serverName,
+				ExpiryWarning:   tt.expiryWarning,
+				Interval:        10 * time.Second,
+				Timeout:         time.Second,
+				TLSClientConfig: tt.config,
+				Logger:          log.New(ioutil.Discard, uniqueID(), log.LstdFlags),
+				now:             func() time.Time { return tt.now },
+			}
+			check.Start()
+			check.Stop()
+			check.check()
+			require.Equal(t, tt.status, notif.State("foo"), notif.Output("foo"))
+		})
+	}
+}
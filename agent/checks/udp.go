@@ -0,0 +1,117 @@
+package checks
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/lib"
+	"github.com/hashicorp/consul/types"
+)
+
+// CheckUDP is used to periodically send a UDP datagram to determine the
+// health of a given check.
+// The check is passing if a response is received and, if Expect is set,
+// the response contains it.
+// The check is critical if no response arrives before the timeout or the
+// response doesn't contain Expect.
+type CheckUDP struct {
+	Notify   CheckNotifier
+	CheckID  types.CheckID
+	UDP      string
+	Send     string
+	Expect   string
+	Interval time.Duration
+	Timeout  time.Duration
+	Logger   *log.Logger
+
+	stop     bool
+	stopCh   chan struct{}
+	stopLock sync.Mutex
+}
+
+// Start is used to start a UDP check.
+// The check runs until stop is called
+func (c *CheckUDP) Start() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+	c.stop = false
+	c.stopCh = make(chan struct{})
+	go c.run()
+}
+
+// Stop is used to stop a UDP check.
+func (c *CheckUDP) Stop() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+	if !c.stop {
+		c.stop = true
+		close(c.stopCh)
+	}
+}
+
+// run is invoked by a goroutine to run until Stop() is called
+func (c *CheckUDP) run() {
+	// Get the randomized initial pause time
+	initialPauseTime := lib.RandomStagger(c.Interval)
+	next := time.After(initialPauseTime)
+	for {
+		select {
+		case <-next:
+			c.check()
+			next = time.After(c.Interval)
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+// check is invoked periodically to perform the UDP check
+func (c *CheckUDP) check() {
+	timeout := 10 * time.Second
+	if c.Timeout > 0 {
+		timeout = c.Timeout
+	}
+
+	resp, err := c.exchange(timeout)
+	if err != nil {
+		c.Logger.Printf("[WARN] agent: Check %q UDP exchange failed: %s", c.CheckID, err)
+		c.Notify.UpdateCheck(c.CheckID, api.HealthCritical, err.Error())
+		return
+	}
+	if c.Expect != "" && !strings.Contains(resp, c.Expect) {
+		c.Logger.Printf("[WARN] agent: Check %q UDP response didn't contain %q", c.CheckID, c.Expect)
+		c.Notify.UpdateCheck(c.CheckID, api.HealthCritical,
+			fmt.Sprintf("UDP response from %s didn't contain %q", c.UDP, c.Expect))
+		return
+	}
+	c.Logger.Printf("[DEBUG] agent: Check %q is passing", c.CheckID)
+	c.Notify.UpdateCheck(c.CheckID, api.HealthPassing, fmt.Sprintf("UDP exchange %s: Success", c.UDP))
+}
+
+// exchange sends the payload and waits for a single response datagram.
+func (c *CheckUDP) exchange(timeout time.Duration) (string, error) {
+	conn, err := net.DialTimeout("udp", c.UDP, timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return "", err
+	}
+	if _, err := conn.Write([]byte(c.Send)); err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, BufSize)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	return string(buf[:n]), nil
+}
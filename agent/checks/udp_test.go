@@ -0,0 +1,73 @@
+package checks
+
+import (
+	"io/ioutil"
+	"log"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/agent/mock"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/types"
+	"github.com/stretchr/testify/require"
+)
+
+// mockUDPServer echoes back every datagram it receives prefixed with "pong ".
+func mockUDPServer(t *testing.T) net.PacketConn {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			conn.WriteTo(append([]byte("pong "), buf[:n]...), addr)
+		}
+	}()
+	return conn
+}
+
+func TestCheckUDP(t *testing.T) {
+	t.Parallel()
+
+	server := mockUDPServer(t)
+	defer server.Close()
+
+	// Nothing is listening on this socket once it's closed.
+	dead, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	deadAddr := dead.LocalAddr().String()
+	dead.Close()
+
+	tests := []struct {
+		desc   string
+		addr   string
+		expect string
+		status string
+	}{
+		{"any response", server.LocalAddr().String(), "", api.HealthPassing},
+		{"expected response", server.LocalAddr().String(), "pong ping", api.HealthPassing},
+		{"unexpected response", server.LocalAddr().String(), "nope", api.HealthCritical},
+		{"no response", deadAddr, "", api.HealthCritical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			notif := mock.NewNotify()
+			check := &CheckUDP{
+				Notify:   notif,
+				CheckID:  types.CheckID("foo"),
+				UDP:      tt.addr,
+				Send:     "ping",
+				Expect:   tt.expect,
+				Interval: 10 * time.Second,
+				Timeout:  500 * time.Millisecond,
+				Logger:   log.New(ioutil.Discard, uniqueID(), log.LstdFlags),
+			}
+			check.check()
+			require.Equal(t, tt.status, notif.State("foo"), notif.Output("foo"))
+		})
+	}
+}
@@ -28,6 +28,18 @@ func FixupCheckType(raw interface{}) error {
 		"docker_container_id":               "DockerContainerID",
 		"tls_skip_verify":                   "TLSSkipVerify",
 		"service_id":                        "ServiceID",
+		"success_before_passing":            "SuccessBeforePassing",
+		"failures_before_critical":          "FailuresBeforeCritical",
+		"body_match":                        "BodyMatch",
+		"body_match_regex":                  "BodyMatchRegex",
+		"json_assertions":                   "JSONAssertions",
+		"udp_send":                          "UDPSend",
+		"udp_expect":                        "UDPExpect",
+		"dns_server":                        "DNSServer",
+		"dns_record_type":                   "DNSRecordType",
+		"dns_expect":                        "DNSExpect",
+		"tls_server_name":                   "TLSServerName",
+		"tls_expiry_warning_days":           "TLSExpiryWarningDays",
 	})
 
 	parseDuration := func(v interface{}) (time.Duration, error) {
@@ -846,6 +846,7 @@ func (b *Builder) Build() (rt RuntimeConfig, err error) {
 		ServerName:                              b.stringVal(c.ServerName),
 		ServerPort:                              serverPort,
 		Services:                                services,
+		ServiceWatchDirs:                        c.ServiceWatchDirs,
 		SessionTTLMin:                           b.durationVal("session_ttl_min", c.SessionTTLMin),
 		SkipLeaveOnInt:                          skipLeaveOnInt,
 		StartJoinAddrsLAN:                       b.expandAllOptionalAddrs("start_join", c.StartJoinAddrsLAN),
@@ -1139,7 +1140,20 @@ func (b *Builder) checkVal(v *CheckDefinition) *structs.CheckDefinition {
 		HTTP:                           b.stringVal(v.HTTP),
 		Header:                         v.Header,
 		Method:                         b.stringVal(v.Method),
+		BodyMatch:                      b.stringVal(v.BodyMatch),
+		BodyMatchRegex:                 b.boolVal(v.BodyMatchRegex),
+		JSONAssertions:                 b.checkJSONAssertionsVal(v.JSONAssertions),
 		TCP:                            b.stringVal(v.TCP),
+		UDP:                            b.stringVal(v.UDP),
+		UDPSend:                        b.stringVal(v.UDPSend),
+		UDPExpect:                      b.stringVal(v.UDPExpect),
+		DNS:                            b.stringVal(v.DNS),
+		DNSServer:                      b.stringVal(v.DNSServer),
+		DNSRecordType:                  b.stringVal(v.DNSRecordType),
+		DNSExpect:                      b.stringVal(v.DNSExpect),
+		TLS:                            b.stringVal(v.TLS),
+		TLSServerName:                  b.stringVal(v.TLSServerName),
+		TLSExpiryWarningDays:           b.intVal(v.TLSExpiryWarningDays),
 		Interval:                       b.durationVal(fmt.Sprintf("check[%s].interval", id), v.Interval),
 		DockerContainerID:              b.stringVal(v.DockerContainerID),
 		Shell:                          b.stringVal(v.Shell),
@@ -1150,10 +1164,27 @@ func (b *Builder) checkVal(v *CheckDefinition) *structs.CheckDefinition {
 		AliasService:                   b.stringVal(v.AliasService),
 		Timeout:                        b.durationVal(fmt.Sprintf("check[%s].timeout", id), v.Timeout),
 		TTL:                            b.durationVal(fmt.Sprintf("check[%s].ttl", id), v.TTL),
+		SuccessBeforePassing:           b.intVal(v.SuccessBeforePassing),
+		FailuresBeforeCritical:         b.intVal(v.FailuresBeforeCritical),
 		DeregisterCriticalServiceAfter: b.durationVal(fmt.Sprintf("check[%s].deregister_critical_service_after", id), v.DeregisterCriticalServiceAfter),
 	}
 }
 
+func (b *Builder) checkJSONAssertionsVal(v []JSONAssertion) []structs.CheckJSONAssertion {
+	if len(v) == 0 {
+		return nil
+	}
+	assertions := make([]structs.CheckJSONAssertion, len(v))
+	for i, a := range v {
+		assertions[i] = structs.CheckJSONAssertion{
+			Path:   b.stringVal(a.Path),
+			Value:  b.stringVal(a.Value),
+			Status: b.stringVal(a.Status),
+		}
+	}
+	return assertions
+}
+
 func (b *Builder) serviceVal(v *ServiceDefinition) *structs.ServiceDefinition {
 	if v == nil {
 		return nil
@@ -3,8 +3,10 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 
+	"github.com/hashicorp/consul/agent/structs"
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/hcl"
 	"github.com/mitchellh/mapstructure"
@@ -97,6 +99,18 @@ func Parse(data string, format string) (c Config, err error) {
 		"services.connect.sidecar_service.checks",
 		"service.connect.sidecar_service.proxy.upstreams",
 		"services.connect.sidecar_service.proxy.upstreams",
+
+		// JSON assertions on HTTP checks wherever checks can be defined.
+		"check.json_assertions",
+		"checks.json_assertions",
+		"service.check.json_assertions",
+		"service.checks.json_assertions",
+		"services.check.json_assertions",
+		"services.checks.json_assertions",
+		"service.connect.sidecar_service.check.json_assertions",
+		"service.connect.sidecar_service.checks.json_assertions",
+		"services.connect.sidecar_service.check.json_assertions",
+		"services.connect.sidecar_service.checks.json_assertions",
 	})
 
 	// There is a difference of representation of some fields depending on
@@ -137,6 +151,41 @@ func Parse(data string, format string) (c Config, err error) {
 	return
 }
 
+// ParseServices parses a service definition file in either JSON or HCL
+// format. The file may only contain 'service' and 'services' stanzas.
+func ParseServices(data string, format string) ([]*structs.ServiceDefinition, error) {
+	c, err := Parse(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	var services []ServiceDefinition
+	if c.Service != nil {
+		services = append(services, *c.Service)
+	}
+	services = append(services, c.Services...)
+
+	c.Service, c.Services = nil, nil
+	if !reflect.DeepEqual(c, Config{}) {
+		return nil, fmt.Errorf("only service definitions are allowed")
+	}
+
+	b := &Builder{}
+	var defs []*structs.ServiceDefinition
+	for i := range services {
+		defs = append(defs, b.serviceVal(&services[i]))
+	}
+	if b.err != nil {
+		return nil, b.err
+	}
+	for _, def := range defs {
+		if err := def.Validate(); err != nil {
+			return nil, fmt.Errorf("service %q: %s", def.Name, err)
+		}
+	}
+	return defs, nil
+}
+
 // Config defines the format of a configuration file in either JSON or
 // HCL format.
 //
@@ -246,6 +295,7 @@ type Config struct {
 	ServerMode                       *bool                    `json:"server,omitempty" hcl:"server" mapstructure:"server"`
 	ServerName                       *string                  `json:"server_name,omitempty" hcl:"server_name" mapstructure:"server_name"`
 	Service                          *ServiceDefinition       `json:"service,omitempty" hcl:"service" mapstructure:"service"`
+	ServiceWatchDirs                 []string                 `json:"service_watch_dirs,omitempty" hcl:"service_watch_dirs" mapstructure:"service_watch_dirs"`
 	Services                         []ServiceDefinition      `json:"services,omitempty" hcl:"services" mapstructure:"services"`
 	SessionTTLMin                    *string                  `json:"session_ttl_min,omitempty" hcl:"session_ttl_min" mapstructure:"session_ttl_min"`
 	SkipLeaveOnInt                   *bool                    `json:"skip_leave_on_interrupt,omitempty" hcl:"skip_leave_on_interrupt" mapstructure:"skip_leave_on_interrupt"`
@@ -387,7 +437,20 @@ type CheckDefinition struct {
 	HTTP                           *string             `json:"http,omitempty" hcl:"http" mapstructure:"http"`
 	Header                         map[string][]string `json:"header,omitempty" hcl:"header" mapstructure:"header"`
 	Method                         *string             `json:"method,omitempty" hcl:"method" mapstructure:"method"`
+	BodyMatch                      *string             `json:"body_match,omitempty" hcl:"body_match" mapstructure:"body_match"`
+	BodyMatchRegex                 *bool               `json:"body_match_regex,omitempty" hcl:"body_match_regex" mapstructure:"body_match_regex"`
+	JSONAssertions                 []JSONAssertion     `json:"json_assertions,omitempty" hcl:"json_assertions" mapstructure:"json_assertions"`
 	TCP                            *string             `json:"tcp,omitempty" hcl:"tcp" mapstructure:"tcp"`
+	UDP                            *string             `json:"udp,omitempty" hcl:"udp" mapstructure:"udp"`
+	UDPSend                        *string             `json:"udp_send,omitempty" hcl:"udp_send" mapstructure:"udp_send"`
+	UDPExpect                      *string             `json:"udp_expect,omitempty" hcl:"udp_expect" mapstructure:"udp_expect"`
+	DNS                            *string             `json:"dns,omitempty" hcl:"dns" mapstructure:"dns"`
+	DNSServer                      *string             `json:"dns_server,omitempty" hcl:"dns_server" mapstructure:"dns_server"`
+	DNSRecordType                  *string             `json:"dns_record_type,omitempty" hcl:"dns_record_type" mapstructure:"dns_record_type"`
+	DNSExpect                      *string             `json:"dns_expect,omitempty" hcl:"dns_expect" mapstructure:"dns_expect"`
+	TLS                            *string             `json:"tls,omitempty" hcl:"tls" mapstructure:"tls"`
+	TLSServerName                  *string             `json:"tls_server_name,omitempty" hcl:"tls_server_name" mapstructure:"tls_server_name"`
+	TLSExpiryWarningDays           *int                `json:"tls_expiry_warning_days,omitempty" hcl:"tls_expiry_warning_days" mapstructure:"tls_expiry_warning_days"`
 	Interval                       *string             `json:"interval,omitempty" hcl:"interval" mapstructure:"interval"`
 	DockerContainerID              *string             `json:"docker_container_id,omitempty" hcl:"docker_container_id" mapstructure:"docker_container_id"`
 	Shell                          *string             `json:"shell,omitempty" hcl:"shell" mapstructure:"shell"`
@@ -398,9 +461,18 @@ type CheckDefinition struct {
 	AliasService                   *string             `json:"alias_service,omitempty" hcl:"alias_service" mapstructure:"alias_service"`
 	Timeout                        *string             `json:"timeout,omitempty" hcl:"timeout" mapstructure:"timeout"`
 	TTL                            *string             `json:"ttl,omitempty" hcl:"ttl" mapstructure:"ttl"`
+	SuccessBeforePassing           *int                `json:"success_before_passing,omitempty" hcl:"success_before_passing" mapstructure:"success_before_passing"`
+	FailuresBeforeCritical         *int                `json:"failures_before_critical,omitempty" hcl:"failures_before_critical" mapstructure:"failures_before_critical"`
 	DeregisterCriticalServiceAfter *string             `json:"deregister_critical_service_after,omitempty" hcl:"deregister_critical_service_after" mapstructure:"deregister_critical_service_after"`
 }
 
+// JSONAssertion is an assertion on the JSON body returned to an HTTP check.
+type JSONAssertion struct {
+	Path   *string `json:"path,omitempty" hcl:"path" mapstructure:"path"`
+	Value  *string `json:"value,omitempty" hcl:"value" mapstructure:"value"`
+	Status *string `json:"status,omitempty" hcl:"status" mapstructure:"status"`
+}
+
 // ServiceConnect is the connect block within a service registration
 type ServiceConnect struct {
 	// Native is true when this service can natively understand Connect.
@@ -192,7 +192,6 @@ type RuntimeConfig struct {
 
 	// AutopilotRedundancyZoneTag is the Meta tag to use for separating servers
 	// into zones for redundancy. If left blank, this feature will be disabled.
-	// (Enterprise-only)
 	//
 	// hcl: autopilot { redundancy_zone_tag = string }
 	AutopilotRedundancyZoneTag string
@@ -1245,6 +1244,14 @@ type RuntimeConfig struct {
 	// hcl: ports { server = int }
 	ServerPort int
 
+	// ServiceWatchDirs are directories of service definition files which
+	// the agent watches. The services and checks registered from these
+	// directories are kept in sync with the files in them, so that services
+	// are added, updated and removed along with their files.
+	//
+	// hcl: service_watch_dirs = []string
+	ServiceWatchDirs []string
+
 	// Services contains the provided service definitions:
 	//
 	// hcl: services = [
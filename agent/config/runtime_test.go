@@ -2948,6 +2948,9 @@ func TestFullConfig(t *testing.T) {
 					"f3r6xFtM": [ "RyuIdDWv", "QbxEcIUM" ]
 				},
 				"method": "Dou0nGT5",
+				"body_match": "KkOo01rE",
+				"body_match_regex": true,
+				"json_assertions": [{ "path": "P45I6HlP", "value": "5N8Gu9RH", "status": "ECs8ROet" }],
 				"tcp": "JY6fTTcw",
 				"interval": "18714s",
 				"docker_container_id": "qF66POS9",
@@ -2955,6 +2958,18 @@ func TestFullConfig(t *testing.T) {
 				"tls_skip_verify": true,
 				"timeout": "5954s",
 				"ttl": "30044s",
+				"success_before_passing": 2609,
+				"failures_before_critical": 62548,
+				"udp": "e7njtS5p",
+				"udp_send": "FbUcguGv",
+				"udp_expect": "1dHS84Ex",
+				"dns": "MaIWYiHa",
+				"dns_server": "FUX1m1s9",
+				"dns_record_type": "7mCfvhcH",
+				"dns_expect": "hUkCbBd6",
+				"tls": "cJwokDtU",
+				"tls_server_name": "7FkI00Vt",
+				"tls_expiry_warning_days": 75517,
 				"deregister_critical_service_after": "13209s"
 			},
 			"checks": [
@@ -2972,6 +2987,9 @@ func TestFullConfig(t *testing.T) {
 						"Ui0nU99X": [ "LMccm3Qe", "k5H5RggQ" ]
 					},
 					"method": "aldrIQ4l",
+					"body_match": "AtHF5dVM",
+					"body_match_regex": true,
+					"json_assertions": [{ "path": "3VRB02r7", "value": "uJWRph3d", "status": "u4sn5eVx" }],
 					"tcp": "RJQND605",
 					"interval": "22164s",
 					"docker_container_id": "ipgdFtjd",
@@ -2979,6 +2997,18 @@ func TestFullConfig(t *testing.T) {
 					"tls_skip_verify": true,
 					"timeout": "1813s",
 					"ttl": "21743s",
+					"success_before_passing": 15732,
+					"failures_before_critical": 52489,
+					"udp": "KKfXcLso",
+					"udp_send": "svfpQz1s",
+					"udp_expect": "BSeYEa0l",
+					"dns": "CkSf3diQ",
+					"dns_server": "qdJtjydm",
+					"dns_record_type": "LcFaG8HS",
+					"dns_expect": "u8xFE973",
+					"tls": "pNuHKGoo",
+					"tls_server_name": "yyLUxm12",
+					"tls_expiry_warning_days": 67824,
 					"deregister_critical_service_after": "14232s"
 				},
 				{
@@ -2995,6 +3025,9 @@ func TestFullConfig(t *testing.T) {
 						"qxvdnSE9": [ "6wBPUYdF", "YYh8wtSZ" ]
 					},
 					"method": "gLrztrNw",
+					"body_match": "hPuXpGru",
+					"body_match_regex": true,
+					"json_assertions": [{ "path": "awHQtJzy", "value": "lRh4cx3O", "status": "VRJ2hejf" }],
 					"tcp": "4jG5casb",
 					"interval": "28767s",
 					"docker_container_id": "THW6u7rL",
@@ -3002,6 +3035,18 @@ func TestFullConfig(t *testing.T) {
 					"tls_skip_verify": true,
 					"timeout": "18506s",
 					"ttl": "31006s",
+					"success_before_passing": 19462,
+					"failures_before_critical": 90635,
+					"udp": "iMR0wPxi",
+					"udp_send": "Np9aFbg8",
+					"udp_expect": "aL2kX2uX",
+					"dns": "9A7oDzfu",
+					"dns_server": "Bk8xtf6T",
+					"dns_record_type": "GXniXSLq",
+					"dns_expect": "ER6xyf0X",
+					"tls": "cM93l4ad",
+					"tls_server_name": "4MUmRHXu",
+					"tls_expiry_warning_days": 50461,
 					"deregister_critical_service_after": "2366s"
 				}
 			],
@@ -3155,6 +3200,7 @@ func TestFullConfig(t *testing.T) {
 			"serf_wan": "67.88.33.19",
 			"server": true,
 			"server_name": "Oerr9n1G",
+			"service_watch_dirs": [ "/etc/consul/services.d" ],
 			"service": {
 				"id": "dLOXpSCI",
 				"name": "o1ynPkp0",
@@ -3182,6 +3228,9 @@ func TestFullConfig(t *testing.T) {
 						"l4HwQ112": ["fk56MNlo", "dhLK56aZ"]
 					},
 					"method": "9afLm3Mj",
+					"body_match": "hZuadArw",
+					"body_match_regex": true,
+					"json_assertions": [{ "path": "m3mRPyDM", "value": "EYwLIDY3", "status": "tFeaEyyW" }],
 					"tcp": "fjiLFqVd",
 					"interval": "23926s",
 					"docker_container_id": "dO5TtRHk",
@@ -3189,6 +3238,18 @@ func TestFullConfig(t *testing.T) {
 					"tls_skip_verify": true,
 					"timeout": "38483s",
 					"ttl": "10943s",
+					"success_before_passing": 6671,
+					"failures_before_critical": 19178,
+					"udp": "ohzum9Pj",
+					"udp_send": "oNCdCR5b",
+					"udp_expect": "aD12qbJe",
+					"dns": "iwfU4hGo",
+					"dns_server": "R3vWOetL",
+					"dns_record_type": "loGZegl9",
+					"dns_expect": "bbpuxTPG",
+					"tls": "wlNaNSxa",
+					"tls_server_name": "bJGbW8lg",
+					"tls_expiry_warning_days": 12435,
 					"deregister_critical_service_after": "68787s"
 				},
 				"checks": [
@@ -3204,6 +3265,9 @@ func TestFullConfig(t *testing.T) {
 							"SHOVq1Vv": [ "jntFhyym", "GYJh32pp" ]
 						},
 						"method": "T66MFBfR",
+						"body_match": "XxhQ3pa7",
+						"body_match_regex": true,
+						"json_assertions": [{ "path": "IOCUu341", "value": "S3k6FUNE", "status": "cSf4M7Us" }],
 						"tcp": "bNnNfx2A",
 						"interval": "22224s",
 						"docker_container_id": "ipgdFtjd",
@@ -3211,6 +3275,18 @@ func TestFullConfig(t *testing.T) {
 						"tls_skip_verify": true,
 						"timeout": "18913s",
 						"ttl": "44743s",
+						"success_before_passing": 15738,
+						"failures_before_critical": 71151,
+						"udp": "RUO6oyKE",
+						"udp_send": "dOPH4iCr",
+						"udp_expect": "Tn3mtfQz",
+						"dns": "Wo12cuC9",
+						"dns_server": "JZ4rPj1K",
+						"dns_record_type": "nEtI8hbH",
+						"dns_expect": "xWXCiq97",
+						"tls": "ts9SSpaS",
+						"tls_server_name": "CjjdYoAp",
+						"tls_expiry_warning_days": 12211,
 						"deregister_critical_service_after": "8482s"
 					},
 					{
@@ -3225,6 +3301,9 @@ func TestFullConfig(t *testing.T) {
 							"p2UI34Qz": [ "UsG1D0Qh", "NHhRiB6s" ]
 						},
 						"method": "ciYHWors",
+						"body_match": "DrB8GAqE",
+						"body_match_regex": true,
+						"json_assertions": [{ "path": "4N9WEybW", "value": "zuZ9ukg9", "status": "l6TuN8s6" }],
 						"tcp": "FfvCwlqH",
 						"interval": "12356s",
 						"docker_container_id": "HBndBU6R",
@@ -3232,6 +3311,18 @@ func TestFullConfig(t *testing.T) {
 						"tls_skip_verify": true,
 						"timeout": "38282s",
 						"ttl": "1181s",
+						"success_before_passing": 31382,
+						"failures_before_critical": 94259,
+						"udp": "N8aZ0MRK",
+						"udp_send": "XesityH5",
+						"udp_expect": "FxEKbK5Z",
+						"dns": "F7zq3T35",
+						"dns_server": "Uj6wwHg5",
+						"dns_record_type": "wyMmYRVg",
+						"dns_expect": "OMbQxKLa",
+						"tls": "2IKrreoN",
+						"tls_server_name": "41CsAQbO",
+						"tls_expiry_warning_days": 16981,
 						"deregister_critical_service_after": "4992s"
 					}
 				],
@@ -3260,6 +3351,9 @@ func TestFullConfig(t *testing.T) {
 							"cVFpko4u": ["gGqdEB6k", "9LsRo22u"]
 						},
 						"method": "X5DrovFc",
+						"body_match": "NlWU9w1B",
+						"body_match_regex": true,
+						"json_assertions": [{ "path": "B7Zv7P3x", "value": "W3LQGc2V", "status": "O5UaoEfZ" }],
 						"tcp": "ICbxkpSF",
 						"interval": "24392s",
 						"docker_container_id": "ZKXr68Yb",
@@ -3267,6 +3361,18 @@ func TestFullConfig(t *testing.T) {
 						"tls_skip_verify": true,
 						"timeout": "38333s",
 						"ttl": "57201s",
+						"success_before_passing": 19296,
+						"failures_before_critical": 20320,
+						"udp": "Rz3xSdfJ",
+						"udp_send": "NbwAmRSl",
+						"udp_expect": "hOV84e02",
+						"dns": "ITkzYX0f",
+						"dns_server": "Uk1JNTkl",
+						"dns_record_type": "iACJIyll",
+						"dns_expect": "sOjCMJ38",
+						"tls": "bhZ1lU4r",
+						"tls_server_name": "vhHV62jw",
+						"tls_expiry_warning_days": 64494,
 						"deregister_critical_service_after": "44214s"
 					},
 					"connect": {
@@ -3298,6 +3404,9 @@ func TestFullConfig(t *testing.T) {
 								"1UJXjVrT": [ "OJgxzTfk", "xZZrFsq7" ]
 							},
 							"method": "5wkAxCUE",
+							"body_match": "r2LfKhm3",
+							"body_match_regex": true,
+							"json_assertions": [{ "path": "mm8Mee7R", "value": "XaKBHRxa", "status": "28jAb6f0" }],
 							"tcp": "MN3oA9D2",
 							"interval": "32718s",
 							"docker_container_id": "cU15LMet",
@@ -3305,6 +3414,18 @@ func TestFullConfig(t *testing.T) {
 							"tls_skip_verify": true,
 							"timeout": "34738s",
 							"ttl": "22773s",
+							"success_before_passing": 98079,
+							"failures_before_critical": 5340,
+							"udp": "i8JBQfwA",
+							"udp_send": "TgojDoQW",
+							"udp_expect": "DhJmsDYn",
+							"dns": "vpqVcoAJ",
+							"dns_server": "uf6HDSfC",
+							"dns_record_type": "WmfbrwAZ",
+							"dns_expect": "gXB9GneE",
+							"tls": "Q9cSyCA1",
+							"tls_server_name": "38c8m8TX",
+							"tls_expiry_warning_days": 41856,
 							"deregister_critical_service_after": "84282s"
 						},
 						{
@@ -3319,6 +3440,9 @@ func TestFullConfig(t *testing.T) {
 								"vr7wY7CS": [ "EtCoNPPL", "9vAarJ5s" ]
 							},
 							"method": "wzByP903",
+							"body_match": "2iDcXbLX",
+							"body_match_regex": true,
+							"json_assertions": [{ "path": "nYJoRhvx", "value": "fZ5VF3t9", "status": "bWQ15bZa" }],
 							"tcp": "2exjZIGE",
 							"interval": "5656s",
 							"docker_container_id": "5tDBWpfA",
@@ -3326,6 +3450,18 @@ func TestFullConfig(t *testing.T) {
 							"tls_skip_verify": true,
 							"timeout": "4868s",
 							"ttl": "11222s",
+							"success_before_passing": 87791,
+							"failures_before_critical": 8945,
+							"udp": "8kWNuDi1",
+							"udp_send": "FCS1rXx2",
+							"udp_expect": "ftcRq62Q",
+							"dns": "dFjfzn98",
+							"dns_server": "nOctdCwe",
+							"dns_record_type": "aeQ8dmmE",
+							"dns_expect": "eerfMkym",
+							"tls": "4oGOCx7n",
+							"tls_server_name": "Qa3Z2D8t",
+							"tls_expiry_warning_days": 94002,
 							"deregister_critical_service_after": "68482s"
 						}
 					],
@@ -3500,6 +3636,9 @@ func TestFullConfig(t *testing.T) {
 					f3r6xFtM = [ "RyuIdDWv", "QbxEcIUM" ]
 				}
 				method = "Dou0nGT5"
+				body_match = "KkOo01rE"
+				body_match_regex = true
+				json_assertions = [{ path = "P45I6HlP" value = "5N8Gu9RH" status = "ECs8ROet" }]
 				tcp = "JY6fTTcw"
 				interval = "18714s"
 				docker_container_id = "qF66POS9"
@@ -3507,6 +3646,18 @@ func TestFullConfig(t *testing.T) {
 				tls_skip_verify = true
 				timeout = "5954s"
 				ttl = "30044s"
+				success_before_passing = 2609
+				failures_before_critical = 62548
+				udp = "e7njtS5p"
+				udp_send = "FbUcguGv"
+				udp_expect = "1dHS84Ex"
+				dns = "MaIWYiHa"
+				dns_server = "FUX1m1s9"
+				dns_record_type = "7mCfvhcH"
+				dns_expect = "hUkCbBd6"
+				tls = "cJwokDtU"
+				tls_server_name = "7FkI00Vt"
+				tls_expiry_warning_days = 75517
 				deregister_critical_service_after = "13209s"
 			},
 			checks = [
@@ -3524,6 +3675,9 @@ func TestFullConfig(t *testing.T) {
 						"Ui0nU99X" = [ "LMccm3Qe", "k5H5RggQ" ]
 					}
 					method = "aldrIQ4l"
+					body_match = "AtHF5dVM"
+					body_match_regex = true
+					json_assertions = [{ path = "3VRB02r7" value = "uJWRph3d" status = "u4sn5eVx" }]
 					tcp = "RJQND605"
 					interval = "22164s"
 					docker_container_id = "ipgdFtjd"
@@ -3531,6 +3685,18 @@ func TestFullConfig(t *testing.T) {
 					tls_skip_verify = true
 					timeout = "1813s"
 					ttl = "21743s"
+					success_before_passing = 15732
+					failures_before_critical = 52489
+					udp = "KKfXcLso"
+					udp_send = "svfpQz1s"
+					udp_expect = "BSeYEa0l"
+					dns = "CkSf3diQ"
+					dns_server = "qdJtjydm"
+					dns_record_type = "LcFaG8HS"
+					dns_expect = "u8xFE973"
+					tls = "pNuHKGoo"
+					tls_server_name = "yyLUxm12"
+					tls_expiry_warning_days = 67824
 					deregister_critical_service_after = "14232s"
 				},
 				{
@@ -3547,6 +3713,9 @@ func TestFullConfig(t *testing.T) {
 						"qxvdnSE9" = [ "6wBPUYdF", "YYh8wtSZ" ]
 					}
 					method = "gLrztrNw"
+					body_match = "hPuXpGru"
+					body_match_regex = true
+					json_assertions = [{ path = "awHQtJzy" value = "lRh4cx3O" status = "VRJ2hejf" }]
 					tcp = "4jG5casb"
 					interval = "28767s"
 					docker_container_id = "THW6u7rL"
@@ -3554,6 +3723,18 @@ func TestFullConfig(t *testing.T) {
 					tls_skip_verify = true
 					timeout = "18506s"
 					ttl = "31006s"
+					success_before_passing = 19462
+					failures_before_critical = 90635
+					udp = "iMR0wPxi"
+					udp_send = "Np9aFbg8"
+					udp_expect = "aL2kX2uX"
+					dns = "9A7oDzfu"
+					dns_server = "Bk8xtf6T"
+					dns_record_type = "GXniXSLq"
+					dns_expect = "ER6xyf0X"
+					tls = "cM93l4ad"
+					tls_server_name = "4MUmRHXu"
+					tls_expiry_warning_days = 50461
 					deregister_critical_service_after = "2366s"
 				}
 			]
@@ -3711,6 +3892,7 @@ func TestFullConfig(t *testing.T) {
 			serf_wan = "67.88.33.19"
 			server = true
 			server_name = "Oerr9n1G"
+			service_watch_dirs = [ "/etc/consul/services.d" ]
 			service = {
 				id = "dLOXpSCI"
 				name = "o1ynPkp0"
@@ -3738,6 +3920,9 @@ func TestFullConfig(t *testing.T) {
 						l4HwQ112 = [ "fk56MNlo", "dhLK56aZ" ]
 					}
 					method = "9afLm3Mj"
+					body_match = "hZuadArw"
+					body_match_regex = true
+					json_assertions = [{ path = "m3mRPyDM" value = "EYwLIDY3" status = "tFeaEyyW" }]
 					tcp = "fjiLFqVd"
 					interval = "23926s"
 					docker_container_id = "dO5TtRHk"
@@ -3745,6 +3930,18 @@ func TestFullConfig(t *testing.T) {
 					tls_skip_verify = true
 					timeout = "38483s"
 					ttl = "10943s"
+					success_before_passing = 6671
+					failures_before_critical = 19178
+					udp = "ohzum9Pj"
+					udp_send = "oNCdCR5b"
+					udp_expect = "aD12qbJe"
+					dns = "iwfU4hGo"
+					dns_server = "R3vWOetL"
+					dns_record_type = "loGZegl9"
+					dns_expect = "bbpuxTPG"
+					tls = "wlNaNSxa"
+					tls_server_name = "bJGbW8lg"
+					tls_expiry_warning_days = 12435
 					deregister_critical_service_after = "68787s"
 				}
 				checks = [
@@ -3760,6 +3957,9 @@ func TestFullConfig(t *testing.T) {
 							"SHOVq1Vv" = [ "jntFhyym", "GYJh32pp" ]
 						}
 						method = "T66MFBfR"
+						body_match = "XxhQ3pa7"
+						body_match_regex = true
+						json_assertions = [{ path = "IOCUu341" value = "S3k6FUNE" status = "cSf4M7Us" }]
 						tcp = "bNnNfx2A"
 						interval = "22224s"
 						docker_container_id = "ipgdFtjd"
@@ -3767,6 +3967,18 @@ func TestFullConfig(t *testing.T) {
 						tls_skip_verify = true
 						timeout = "18913s"
 						ttl = "44743s"
+						success_before_passing = 15738
+						failures_before_critical = 71151
+						udp = "RUO6oyKE"
+						udp_send = "dOPH4iCr"
+						udp_expect = "Tn3mtfQz"
+						dns = "Wo12cuC9"
+						dns_server = "JZ4rPj1K"
+						dns_record_type = "nEtI8hbH"
+						dns_expect = "xWXCiq97"
+						tls = "ts9SSpaS"
+						tls_server_name = "CjjdYoAp"
+						tls_expiry_warning_days = 12211
 						deregister_critical_service_after = "8482s"
 					},
 					{
@@ -3781,6 +3993,9 @@ func TestFullConfig(t *testing.T) {
 							"p2UI34Qz" = [ "UsG1D0Qh", "NHhRiB6s" ]
 						}
 						method = "ciYHWors"
+						body_match = "DrB8GAqE"
+						body_match_regex = true
+						json_assertions = [{ path = "4N9WEybW" value = "zuZ9ukg9" status = "l6TuN8s6" }]
 						tcp = "FfvCwlqH"
 						interval = "12356s"
 						docker_container_id = "HBndBU6R"
@@ -3788,6 +4003,18 @@ func TestFullConfig(t *testing.T) {
 						tls_skip_verify = true
 						timeout = "38282s"
 						ttl = "1181s"
+						success_before_passing = 31382
+						failures_before_critical = 94259
+						udp = "N8aZ0MRK"
+						udp_send = "XesityH5"
+						udp_expect = "FxEKbK5Z"
+						dns = "F7zq3T35"
+						dns_server = "Uj6wwHg5"
+						dns_record_type = "wyMmYRVg"
+						dns_expect = "OMbQxKLa"
+						tls = "2IKrreoN"
+						tls_server_name = "41CsAQbO"
+						tls_expiry_warning_days = 16981
 						deregister_critical_service_after = "4992s"
 					}
 				]
@@ -3816,6 +4043,9 @@ func TestFullConfig(t *testing.T) {
 							cVFpko4u = [ "gGqdEB6k", "9LsRo22u" ]
 						}
 						method = "X5DrovFc"
+						body_match = "NlWU9w1B"
+						body_match_regex = true
+						json_assertions = [{ path = "B7Zv7P3x" value = "W3LQGc2V" status = "O5UaoEfZ" }]
 						tcp = "ICbxkpSF"
 						interval = "24392s"
 						docker_container_id = "ZKXr68Yb"
@@ -3823,6 +4053,18 @@ func TestFullConfig(t *testing.T) {
 						tls_skip_verify = true
 						timeout = "38333s"
 						ttl = "57201s"
+						success_before_passing = 19296
+						failures_before_critical = 20320
+						udp = "Rz3xSdfJ"
+						udp_send = "NbwAmRSl"
+						udp_expect = "hOV84e02"
+						dns = "ITkzYX0f"
+						dns_server = "Uk1JNTkl"
+						dns_record_type = "iACJIyll"
+						dns_expect = "sOjCMJ38"
+						tls = "bhZ1lU4r"
+						tls_server_name = "vhHV62jw"
+						tls_expiry_warning_days = 64494
 						deregister_critical_service_after = "44214s"
 					}
 					connect {
@@ -3854,6 +4096,9 @@ func TestFullConfig(t *testing.T) {
 								"1UJXjVrT" = [ "OJgxzTfk", "xZZrFsq7" ]
 							}
 							method = "5wkAxCUE"
+							body_match = "r2LfKhm3"
+							body_match_regex = true
+							json_assertions = [{ path = "mm8Mee7R" value = "XaKBHRxa" status = "28jAb6f0" }]
 							tcp = "MN3oA9D2"
 							interval = "32718s"
 							docker_container_id = "cU15LMet"
@@ -3861,6 +4106,18 @@ func TestFullConfig(t *testing.T) {
 							tls_skip_verify = true
 							timeout = "34738s"
 							ttl = "22773s"
+							success_before_passing = 98079
+							failures_before_critical = 5340
+							udp = "i8JBQfwA"
+							udp_send = "TgojDoQW"
+							udp_expect = "DhJmsDYn"
+							dns = "vpqVcoAJ"
+							dns_server = "uf6HDSfC"
+							dns_record_type = "WmfbrwAZ"
+							dns_expect = "gXB9GneE"
+							tls = "Q9cSyCA1"
+							tls_server_name = "38c8m8TX"
+							tls_expiry_warning_days = 41856
 							deregister_critical_service_after = "84282s"
 						},
 						{
@@ -3875,6 +4132,9 @@ func TestFullConfig(t *testing.T) {
 								"vr7wY7CS" = [ "EtCoNPPL", "9vAarJ5s" ]
 							}
 							method = "wzByP903"
+							body_match = "2iDcXbLX"
+							body_match_regex = true
+							json_assertions = [{ path = "nYJoRhvx" value = "fZ5VF3t9" status = "bWQ15bZa" }]
 							tcp = "2exjZIGE"
 							interval = "5656s"
 							docker_container_id = "5tDBWpfA"
@@ -3882,6 +4142,18 @@ func TestFullConfig(t *testing.T) {
 							tls_skip_verify = true
 							timeout = "4868s"
 							ttl = "11222s"
+							success_before_passing = 87791
+							failures_before_critical = 8945
+							udp = "8kWNuDi1"
+							udp_send = "FCS1rXx2"
+							udp_expect = "ftcRq62Q"
+							dns = "dFjfzn98"
+							dns_server = "nOctdCwe"
+							dns_record_type = "aeQ8dmmE"
+							dns_expect = "eerfMkym"
+							tls = "4oGOCx7n"
+							tls_server_name = "Qa3Z2D8t"
+							tls_expiry_warning_days = 94002
 							deregister_critical_service_after = "68482s"
 						}
 					]
@@ -4159,6 +4431,9 @@ func TestFullConfig(t *testing.T) {
 					"Ui0nU99X": []string{"LMccm3Qe", "k5H5RggQ"},
 				},
 				Method:                         "aldrIQ4l",
+				BodyMatch:                      "AtHF5dVM",
+				BodyMatchRegex:                 true,
+				JSONAssertions:                 []structs.CheckJSONAssertion{{Path: "3VRB02r7", Value: "uJWRph3d", Status: "u4sn5eVx"}},
 				TCP:                            "RJQND605",
 				Interval:                       22164 * time.Second,
 				DockerContainerID:              "ipgdFtjd",
@@ -4166,6 +4441,18 @@ func TestFullConfig(t *testing.T) {
 				TLSSkipVerify:                  true,
 				Timeout:                        1813 * time.Second,
 				TTL:                            21743 * time.Second,
+				SuccessBeforePassing:           15732,
+				FailuresBeforeCritical:         52489,
+				UDP:                            "KKfXcLso",
+				UDPSend:                        "svfpQz1s",
+				UDPExpect:                      "BSeYEa0l",
+				DNS:                            "CkSf3diQ",
+				DNSServer:                      "qdJtjydm",
+				DNSRecordType:                  "LcFaG8HS",
+				DNSExpect:                      "u8xFE973",
+				TLS:                            "pNuHKGoo",
+				TLSServerName:                  "yyLUxm12",
+				TLSExpiryWarningDays:           67824,
 				DeregisterCriticalServiceAfter: 14232 * time.Second,
 			},
 			&structs.CheckDefinition{
@@ -4182,6 +4469,9 @@ func TestFullConfig(t *testing.T) {
 					"qxvdnSE9": []string{"6wBPUYdF", "YYh8wtSZ"},
 				},
 				Method:                         "gLrztrNw",
+				BodyMatch:                      "hPuXpGru",
+				BodyMatchRegex:                 true,
+				JSONAssertions:                 []structs.CheckJSONAssertion{{Path: "awHQtJzy", Value: "lRh4cx3O", Status: "VRJ2hejf"}},
 				TCP:                            "4jG5casb",
 				Interval:                       28767 * time.Second,
 				DockerContainerID:              "THW6u7rL",
@@ -4189,6 +4479,18 @@ func TestFullConfig(t *testing.T) {
 				TLSSkipVerify:                  true,
 				Timeout:                        18506 * time.Second,
 				TTL:                            31006 * time.Second,
+				SuccessBeforePassing:           19462,
+				FailuresBeforeCritical:         90635,
+				UDP:                            "iMR0wPxi",
+				UDPSend:                        "Np9aFbg8",
+				UDPExpect:                      "aL2kX2uX",
+				DNS:                            "9A7oDzfu",
+				DNSServer:                      "Bk8xtf6T",
+				DNSRecordType:                  "GXniXSLq",
+				DNSExpect:                      "ER6xyf0X",
+				TLS:                            "cM93l4ad",
+				TLSServerName:                  "4MUmRHXu",
+				TLSExpiryWarningDays:           50461,
 				DeregisterCriticalServiceAfter: 2366 * time.Second,
 			},
 			&structs.CheckDefinition{
@@ -4205,6 +4507,9 @@ func TestFullConfig(t *testing.T) {
 					"f3r6xFtM": {"RyuIdDWv", "QbxEcIUM"},
 				},
 				Method:                         "Dou0nGT5",
+				BodyMatch:                      "KkOo01rE",
+				BodyMatchRegex:                 true,
+				JSONAssertions:                 []structs.CheckJSONAssertion{{Path: "P45I6HlP", Value: "5N8Gu9RH", Status: "ECs8ROet"}},
 				TCP:                            "JY6fTTcw",
 				Interval:                       18714 * time.Second,
 				DockerContainerID:              "qF66POS9",
@@ -4212,6 +4517,18 @@ func TestFullConfig(t *testing.T) {
 				TLSSkipVerify:                  true,
 				Timeout:                        5954 * time.Second,
 				TTL:                            30044 * time.Second,
+				SuccessBeforePassing:           2609,
+				FailuresBeforeCritical:         62548,
+				UDP:                            "e7njtS5p",
+				UDPSend:                        "FbUcguGv",
+				UDPExpect:                      "1dHS84Ex",
+				DNS:                            "MaIWYiHa",
+				DNSServer:                      "FUX1m1s9",
+				DNSRecordType:                  "7mCfvhcH",
+				DNSExpect:                      "hUkCbBd6",
+				TLS:                            "cJwokDtU",
+				TLSServerName:                  "7FkI00Vt",
+				TLSExpiryWarningDays:           75517,
 				DeregisterCriticalServiceAfter: 13209 * time.Second,
 			},
 		},
@@ -4330,11 +4647,12 @@ func TestFullConfig(t *testing.T) {
 				RPCListener: true,
 			},
 		},
-		SerfPortLAN: 8301,
-		SerfPortWAN: 8302,
-		ServerMode:  true,
-		ServerName:  "Oerr9n1G",
-		ServerPort:  3757,
+		SerfPortLAN:      8301,
+		SerfPortWAN:      8302,
+		ServerMode:       true,
+		ServerName:       "Oerr9n1G",
+		ServerPort:       3757,
+		ServiceWatchDirs: []string{"/etc/consul/services.d"},
 		Services: []*structs.ServiceDefinition{
 			{
 				ID:      "wI1dzxS4",
@@ -4361,6 +4679,9 @@ func TestFullConfig(t *testing.T) {
 							"cVFpko4u": {"gGqdEB6k", "9LsRo22u"},
 						},
 						Method:                         "X5DrovFc",
+						BodyMatch:                      "NlWU9w1B",
+						BodyMatchRegex:                 true,
+						JSONAssertions:                 []structs.CheckJSONAssertion{{Path: "B7Zv7P3x", Value: "W3LQGc2V", Status: "O5UaoEfZ"}},
 						TCP:                            "ICbxkpSF",
 						Interval:                       24392 * time.Second,
 						DockerContainerID:              "ZKXr68Yb",
@@ -4368,6 +4689,18 @@ func TestFullConfig(t *testing.T) {
 						TLSSkipVerify:                  true,
 						Timeout:                        38333 * time.Second,
 						TTL:                            57201 * time.Second,
+						SuccessBeforePassing:           19296,
+						FailuresBeforeCritical:         20320,
+						UDP:                            "Rz3xSdfJ",
+						UDPSend:                        "NbwAmRSl",
+						UDPExpect:                      "hOV84e02",
+						DNS:                            "ITkzYX0f",
+						DNSServer:                      "Uk1JNTkl",
+						DNSRecordType:                  "iACJIyll",
+						DNSExpect:                      "sOjCMJ38",
+						TLS:                            "bhZ1lU4r",
+						TLSServerName:                  "vhHV62jw",
+						TLSExpiryWarningDays:           64494,
 						DeregisterCriticalServiceAfter: 44214 * time.Second,
 					},
 				},
@@ -4409,6 +4742,9 @@ func TestFullConfig(t *testing.T) {
 							"1UJXjVrT": {"OJgxzTfk", "xZZrFsq7"},
 						},
 						Method:                         "5wkAxCUE",
+						BodyMatch:                      "r2LfKhm3",
+						BodyMatchRegex:                 true,
+						JSONAssertions:                 []structs.CheckJSONAssertion{{Path: "mm8Mee7R", Value: "XaKBHRxa", Status: "28jAb6f0"}},
 						TCP:                            "MN3oA9D2",
 						Interval:                       32718 * time.Second,
 						DockerContainerID:              "cU15LMet",
@@ -4416,6 +4752,18 @@ func TestFullConfig(t *testing.T) {
 						TLSSkipVerify:                  true,
 						Timeout:                        34738 * time.Second,
 						TTL:                            22773 * time.Second,
+						SuccessBeforePassing:           98079,
+						FailuresBeforeCritical:         5340,
+						UDP:                            "i8JBQfwA",
+						UDPSend:                        "TgojDoQW",
+						UDPExpect:                      "DhJmsDYn",
+						DNS:                            "vpqVcoAJ",
+						DNSServer:                      "uf6HDSfC",
+						DNSRecordType:                  "WmfbrwAZ",
+						DNSExpect:                      "gXB9GneE",
+						TLS:                            "Q9cSyCA1",
+						TLSServerName:                  "38c8m8TX",
+						TLSExpiryWarningDays:           41856,
 						DeregisterCriticalServiceAfter: 84282 * time.Second,
 					},
 					&structs.CheckType{
@@ -4430,6 +4778,9 @@ func TestFullConfig(t *testing.T) {
 							"vr7wY7CS": {"EtCoNPPL", "9vAarJ5s"},
 						},
 						Method:                         "wzByP903",
+						BodyMatch:                      "2iDcXbLX",
+						BodyMatchRegex:                 true,
+						JSONAssertions:                 []structs.CheckJSONAssertion{{Path: "nYJoRhvx", Value: "fZ5VF3t9", Status: "bWQ15bZa"}},
 						TCP:                            "2exjZIGE",
 						Interval:                       5656 * time.Second,
 						DockerContainerID:              "5tDBWpfA",
@@ -4437,6 +4788,18 @@ func TestFullConfig(t *testing.T) {
 						TLSSkipVerify:                  true,
 						Timeout:                        4868 * time.Second,
 						TTL:                            11222 * time.Second,
+						SuccessBeforePassing:           87791,
+						FailuresBeforeCritical:         8945,
+						UDP:                            "8kWNuDi1",
+						UDPSend:                        "FCS1rXx2",
+						UDPExpect:                      "ftcRq62Q",
+						DNS:                            "dFjfzn98",
+						DNSServer:                      "nOctdCwe",
+						DNSRecordType:                  "aeQ8dmmE",
+						DNSExpect:                      "eerfMkym",
+						TLS:                            "4oGOCx7n",
+						TLSServerName:                  "Qa3Z2D8t",
+						TLSExpiryWarningDays:           94002,
 						DeregisterCriticalServiceAfter: 68482 * time.Second,
 					},
 				},
@@ -4515,6 +4878,9 @@ func TestFullConfig(t *testing.T) {
 							"SHOVq1Vv": {"jntFhyym", "GYJh32pp"},
 						},
 						Method:                         "T66MFBfR",
+						BodyMatch:                      "XxhQ3pa7",
+						BodyMatchRegex:                 true,
+						JSONAssertions:                 []structs.CheckJSONAssertion{{Path: "IOCUu341", Value: "S3k6FUNE", Status: "cSf4M7Us"}},
 						TCP:                            "bNnNfx2A",
 						Interval:                       22224 * time.Second,
 						DockerContainerID:              "ipgdFtjd",
@@ -4522,6 +4888,18 @@ func TestFullConfig(t *testing.T) {
 						TLSSkipVerify:                  true,
 						Timeout:                        18913 * time.Second,
 						TTL:                            44743 * time.Second,
+						SuccessBeforePassing:           15738,
+						FailuresBeforeCritical:         71151,
+						UDP:                            "RUO6oyKE",
+						UDPSend:                        "dOPH4iCr",
+						UDPExpect:                      "Tn3mtfQz",
+						DNS:                            "Wo12cuC9",
+						DNSServer:                      "JZ4rPj1K",
+						DNSRecordType:                  "nEtI8hbH",
+						DNSExpect:                      "xWXCiq97",
+						TLS:                            "ts9SSpaS",
+						TLSServerName:                  "CjjdYoAp",
+						TLSExpiryWarningDays:           12211,
 						DeregisterCriticalServiceAfter: 8482 * time.Second,
 					},
 					&structs.CheckType{
@@ -4536,6 +4914,9 @@ func TestFullConfig(t *testing.T) {
 							"p2UI34Qz": {"UsG1D0Qh", "NHhRiB6s"},
 						},
 						Method:                         "ciYHWors",
+						BodyMatch:                      "DrB8GAqE",
+						BodyMatchRegex:                 true,
+						JSONAssertions:                 []structs.CheckJSONAssertion{{Path: "4N9WEybW", Value: "zuZ9ukg9", Status: "l6TuN8s6"}},
 						TCP:                            "FfvCwlqH",
 						Interval:                       12356 * time.Second,
 						DockerContainerID:              "HBndBU6R",
@@ -4543,6 +4924,18 @@ func TestFullConfig(t *testing.T) {
 						TLSSkipVerify:                  true,
 						Timeout:                        38282 * time.Second,
 						TTL:                            1181 * time.Second,
+						SuccessBeforePassing:           31382,
+						FailuresBeforeCritical:         94259,
+						UDP:                            "N8aZ0MRK",
+						UDPSend:                        "XesityH5",
+						UDPExpect:                      "FxEKbK5Z",
+						DNS:                            "F7zq3T35",
+						DNSServer:                      "Uj6wwHg5",
+						DNSRecordType:                  "wyMmYRVg",
+						DNSExpect:                      "OMbQxKLa",
+						TLS:                            "2IKrreoN",
+						TLSServerName:                  "41CsAQbO",
+						TLSExpiryWarningDays:           16981,
 						DeregisterCriticalServiceAfter: 4992 * time.Second,
 					},
 					&structs.CheckType{
@@ -4557,6 +4950,9 @@ func TestFullConfig(t *testing.T) {
 							"l4HwQ112": {"fk56MNlo", "dhLK56aZ"},
 						},
 						Method:                         "9afLm3Mj",
+						BodyMatch:                      "hZuadArw",
+						BodyMatchRegex:                 true,
+						JSONAssertions:                 []structs.CheckJSONAssertion{{Path: "m3mRPyDM", Value: "EYwLIDY3", Status: "tFeaEyyW"}},
 						TCP:                            "fjiLFqVd",
 						Interval:                       23926 * time.Second,
 						DockerContainerID:              "dO5TtRHk",
@@ -4564,6 +4960,18 @@ func TestFullConfig(t *testing.T) {
 						TLSSkipVerify:                  true,
 						Timeout:                        38483 * time.Second,
 						TTL:                            10943 * time.Second,
+						SuccessBeforePassing:           6671,
+						FailuresBeforeCritical:         19178,
+						UDP:                            "ohzum9Pj",
+						UDPSend:                        "oNCdCR5b",
+						UDPExpect:                      "aD12qbJe",
+						DNS:                            "iwfU4hGo",
+						DNSServer:                      "R3vWOetL",
+						DNSRecordType:                  "loGZegl9",
+						DNSExpect:                      "bbpuxTPG",
+						TLS:                            "wlNaNSxa",
+						TLSServerName:                  "bJGbW8lg",
+						TLSExpiryWarningDays:           12435,
 						DeregisterCriticalServiceAfter: 68787 * time.Second,
 					},
 				},
@@ -4971,14 +5379,22 @@ func TestSanitize(t *testing.T) {
 		"Checks": [{
 			"AliasNode": "",
 			"AliasService": "",
+			"BodyMatch": "",
+			"BodyMatchRegex": false,
+			"DNS": "",
+			"DNSExpect": "",
+			"DNSRecordType": "",
+			"DNSServer": "",
 			"DeregisterCriticalServiceAfter": "0s",
 			"DockerContainerID": "",
+			"FailuresBeforeCritical": 0,
 			"GRPC": "",
 			"GRPCUseTLS": false,
 			"HTTP": "",
 			"Header": {},
 			"ID": "",
 			"Interval": "0s",
+			"JSONAssertions": [],
 			"Method": "",
 			"Name": "zoo",
 			"Notes": "",
@@ -4986,10 +5402,17 @@ func TestSanitize(t *testing.T) {
 			"ServiceID": "",
 			"Shell": "",
 			"Status": "",
+			"SuccessBeforePassing": 0,
 			"TCP": "",
+			"TLS": "",
+			"TLSExpiryWarningDays": 0,
+			"TLSServerName": "",
 			"TLSSkipVerify": false,
 			"TTL": "0s",
 			"Timeout": "0s",
+			"UDP": "",
+			"UDPExpect": "",
+			"UDPSend": "",
 			"Token": "hidden"
 		}],
 		"ClientAddrs": [],
@@ -5134,29 +5557,45 @@ func TestSanitize(t *testing.T) {
 		"ServerMode": false,
 		"ServerName": "",
 		"ServerPort": 0,
+		"ServiceWatchDirs": [],
 		"Services": [{
 			"Address": "",
 			"Check": {
 				"AliasNode": "",
 				"AliasService": "",
+				"BodyMatch": "",
+				"BodyMatchRegex": false,
 				"CheckID": "",
+				"DNS": "",
+				"DNSExpect": "",
+				"DNSRecordType": "",
+				"DNSServer": "",
 				"DeregisterCriticalServiceAfter": "0s",
 				"DockerContainerID": "",
+				"FailuresBeforeCritical": 0,
 				"GRPC": "",
 				"GRPCUseTLS": false,
 				"HTTP": "",
 				"Header": {},
 				"Interval": "0s",
+				"JSONAssertions": [],
 				"Method": "",
 				"Name": "blurb",
 				"Notes": "",
 				"ScriptArgs": [],
 				"Shell": "",
 				"Status": "",
+				"SuccessBeforePassing": 0,
 				"TCP": "",
+				"TLS": "",
+				"TLSExpiryWarningDays": 0,
+				"TLSServerName": "",
 				"TLSSkipVerify": false,
 				"TTL": "0s",
-				"Timeout": "0s"
+				"Timeout": "0s",
+				"UDP": "",
+				"UDPExpect": "",
+				"UDPSend": ""
 			},
 			"Checks": [],
 			"Connect": null,
@@ -27,6 +27,11 @@ func (id *SpiffeIDService) URI() *url.URL {
 
 // CertURI impl.
 func (id *SpiffeIDService) Authorize(ixn *structs.Intention) (bool, bool) {
+	if ixn.SourceType != "" && ixn.SourceType != structs.IntentionSourceConsul {
+		// Intentions for other source types never match a service
+		return false, false
+	}
+
 	if ixn.SourceNS != structs.IntentionWildcard && ixn.SourceNS != id.Namespace {
 		// Non-matching namespace
 		return false, false
@@ -37,6 +42,12 @@ func (id *SpiffeIDService) Authorize(ixn *structs.Intention) (bool, bool) {
 		return false, false
 	}
 
+	// Intentions with L7 permissions can't be decided for a whole connection,
+	// so they deny it. Proxies that speak HTTP enforce them per request.
+	if ixn.HasPermissions() {
+		return false, true
+	}
+
 	// Match, return allow value
 	return ixn.Action == structs.IntentionActionAllow, true
 }
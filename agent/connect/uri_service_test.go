@@ -45,6 +45,19 @@ func TestSpiffeIDServiceAuthorize(t *testing.T) {
 			false,
 		},
 
+		{
+			"cidr source",
+			serviceWeb,
+			&structs.Intention{
+				SourceNS:   ns,
+				SourceName: "10.0.0.0/8",
+				SourceType: structs.IntentionSourceCIDR,
+				Action:     structs.IntentionActionAllow,
+			},
+			false,
+			false,
+		},
+
 		{
 			"exact source, allow",
 			serviceWeb,
@@ -92,6 +105,23 @@ func TestSpiffeIDServiceAuthorize(t *testing.T) {
 			true,
 			true,
 		},
+
+		{
+			"exact source, L7 permissions",
+			serviceWeb,
+			&structs.Intention{
+				SourceNS:   serviceWeb.Namespace,
+				SourceName: serviceWeb.Service,
+				Permissions: []*structs.IntentionPermission{
+					{
+						Action: structs.IntentionActionAllow,
+						HTTP:   &structs.IntentionHTTPPermission{PathPrefix: "/"},
+					},
+				},
+			},
+			false,
+			true,
+		},
 	}
 
 	for _, tc := range cases {
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"fmt"
+	"net"
 
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/cache"
@@ -50,6 +51,13 @@ func (a *Agent) ConnectAuthorize(token string,
 		return returnErr(BadRequestError{"ClientCertURI not a valid Service identifier"})
 	}
 
+	var clientIP net.IP
+	if req.ClientAddr != "" {
+		if clientIP = net.ParseIP(req.ClientAddr); clientIP == nil {
+			return returnErr(BadRequestError{"ClientAddr not a valid IP address"})
+		}
+	}
+
 	// We need to verify service:write permissions for the given token.
 	// We do this manually here since the RPC request below only verifies
 	// service:read.
@@ -96,8 +104,15 @@ func (a *Agent) ConnectAuthorize(token string,
 
 	// Test the authorization for each match
 	for _, ixn := range reply.Matches[0] {
-		if auth, ok := uriService.Authorize(ixn); ok {
+		auth, ok := uriService.Authorize(ixn)
+		if !ok {
+			auth, ok = ixn.AuthorizeSourceAddr(clientIP)
+		}
+		if ok {
 			reason = fmt.Sprintf("Matched intention: %s", ixn.String())
+			if ixn.HasPermissions() {
+				reason = fmt.Sprintf("Matched L7 intention that can only be enforced per request: %s", ixn.String())
+			}
 			return auth, reason, &meta, nil
 		}
 	}
@@ -22,6 +22,21 @@ func (d *AutopilotDelegate) AutopilotConfig() *autopilot.Config {
 	return d.server.getOrCreateAutopilotConfig()
 }
 
+func (d *AutopilotDelegate) DemoteVoters(conf *autopilot.Config, health autopilot.OperatorHealthReply) ([]raft.Server, error) {
+	// Voters are only ever demoted to keep redundancy zones down to a
+	// single voter.
+	if conf.RedundancyZoneTag == "" {
+		return nil, nil
+	}
+
+	future := d.server.raft.GetConfiguration()
+	if err := future.Error(); err != nil {
+		return nil, fmt.Errorf("failed to get raft configuration: %v", err)
+	}
+
+	return autopilot.DemoteZoneVoters(health, future.Configuration().Servers), nil
+}
+
 func (d *AutopilotDelegate) FetchStats(ctx context.Context, servers []serf.Member) map[string]*autopilot.ServerStats {
 	return d.server.statsFetcher.Fetch(ctx, servers)
 }
@@ -49,6 +64,16 @@ func (d *AutopilotDelegate) IsServer(m serf.Member) (*autopilot.ServerInfo, erro
 		Build:  *buildVersion,
 		Status: m.Status,
 	}
+
+	// The node meta isn't gossiped, so pull it from the catalog. This will
+	// be empty until the server's agent has synced its node info.
+	_, node, err := d.server.fsm.State().GetNode(m.Name)
+	if err != nil {
+		return nil, err
+	}
+	if node != nil {
+		server.Meta = node.Meta
+	}
 	return server, nil
 }
 
@@ -70,7 +95,11 @@ func (d *AutopilotDelegate) PromoteNonVoters(conf *autopilot.Config, health auto
 		return nil, fmt.Errorf("failed to get raft configuration: %v", err)
 	}
 
-	return autopilot.PromoteStableServers(conf, health, future.Configuration().Servers), nil
+	servers := future.Configuration().Servers
+	if conf.RedundancyZoneTag != "" {
+		return autopilot.PromoteStableServersByZone(conf, health, servers), nil
+	}
+	return autopilot.PromoteStableServers(conf, health, servers), nil
 }
 
 func (d *AutopilotDelegate) Raft() *raft.Raft {
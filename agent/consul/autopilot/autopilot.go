@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -17,6 +18,7 @@ import (
 // Delegate is the interface for the Autopilot mechanism
 type Delegate interface {
 	AutopilotConfig() *Config
+	DemoteVoters(*Config, OperatorHealthReply) ([]raft.Server, error)
 	FetchStats(context.Context, []serf.Member) map[string]*ServerStats
 	IsServer(serf.Member) (*ServerInfo, error)
 	NotifyHealth(OperatorHealthReply)
@@ -51,6 +53,7 @@ type ServerInfo struct {
 	Addr   net.Addr
 	Build  version.Version
 	Status serf.MemberStatus
+	Meta   map[string]string
 }
 
 func NewAutopilot(logger *log.Logger, delegate Delegate, interval, healthInterval time.Duration) *Autopilot {
@@ -144,6 +147,14 @@ func (a *Autopilot) promoteServers() error {
 		if err := a.handlePromotions(promotions); err != nil {
 			return fmt.Errorf("error handling promotions: %s", err)
 		}
+
+		demotions, err := a.delegate.DemoteVoters(conf, a.GetClusterHealth())
+		if err != nil {
+			return fmt.Errorf("error checking for voters to demote: %s", err)
+		}
+		if err := a.handleDemotions(demotions); err != nil {
+			return fmt.Errorf("error handling demotions: %s", err)
+		}
 	}
 
 	return nil
@@ -328,6 +339,19 @@ func (a *Autopilot) handlePromotions(promotions []raft.Server) error {
 	return nil
 }
 
+// handleDemotions attempts to apply desired server demotions to the Raft
+// configuration. Demoted servers stay in the cluster as non-voters.
+func (a *Autopilot) handleDemotions(demotions []raft.Server) error {
+	for _, server := range demotions {
+		a.logger.Printf("[INFO] autopilot: Demoting %s to non-voter", fmtServer(server))
+		demoteFuture := a.delegate.Raft().DemoteVoter(server.ID, 0, 0)
+		if err := demoteFuture.Error(); err != nil {
+			return fmt.Errorf("failed to demote raft peer: %v", err)
+		}
+	}
+	return nil
+}
+
 // serverHealthLoop monitors the health of the servers in the cluster
 func (a *Autopilot) serverHealthLoop() {
 	defer a.waitGroup.Done()
@@ -427,6 +451,9 @@ func (a *Autopilot) updateClusterHealth() error {
 			health.Name = parts.Name
 			health.SerfStatus = parts.Status
 			health.Version = parts.Build.String()
+			if autopilotConf.RedundancyZoneTag != "" {
+				health.RedundancyZone = parts.Meta[autopilotConf.RedundancyZoneTag]
+			}
 			if stats, ok := fetchedStats[string(server.ID)]; ok {
 				if err := a.updateServerHealth(&health, parts, stats, autopilotConf, targetLastIndex); err != nil {
 					a.logger.Printf("[WARN] autopilot: Error updating server %s health: %s", fmtServer(server), err)
@@ -456,6 +483,8 @@ func (a *Autopilot) updateClusterHealth() error {
 		clusterHealth.FailureTolerance = healthyVoterCount - requiredQuorum
 	}
 
+	clusterHealth.RedundancyZones = zoneHealth(clusterHealth.Servers)
+
 	a.delegate.NotifyHealth(clusterHealth)
 
 	a.clusterHealthLock.Lock()
@@ -500,6 +529,41 @@ func (a *Autopilot) updateServerHealth(health *ServerHealth,
 	return nil
 }
 
+// zoneHealth summarizes the health of each redundancy zone from the health of
+// its servers. The zones are returned sorted by name.
+func zoneHealth(servers []ServerHealth) []ZoneHealth {
+	zones := make(map[string]*ZoneHealth)
+	for _, server := range servers {
+		if server.RedundancyZone == "" {
+			continue
+		}
+
+		zone, ok := zones[server.RedundancyZone]
+		if !ok {
+			zone = &ZoneHealth{Name: server.RedundancyZone}
+			zones[server.RedundancyZone] = zone
+		}
+		zone.Servers = append(zone.Servers, server.ID)
+		if server.Voter {
+			zone.Voters = append(zone.Voters, server.ID)
+			if server.Healthy {
+				zone.Healthy = true
+			}
+		} else if server.Healthy {
+			zone.FailureTolerance++
+		}
+	}
+
+	var out []ZoneHealth
+	for _, zone := range zones {
+		out = append(out, *zone)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
 func (a *Autopilot) GetClusterHealth() OperatorHealthReply {
 	a.clusterHealthLock.RLock()
 	defer a.clusterHealthLock.RUnlock()
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/serf/serf"
+	"github.com/pascaldekloe/goe/verify"
 )
 
 func TestMinRaftProtocol(t *testing.T) {
@@ -84,3 +85,30 @@ func TestMinRaftProtocol(t *testing.T) {
 		}
 	}
 }
+
+func TestZoneHealth(t *testing.T) {
+	t.Parallel()
+	servers := []ServerHealth{
+		{ID: "a", Healthy: true, Voter: true, RedundancyZone: "west"},
+		{ID: "b", Healthy: true, Voter: false, RedundancyZone: "west"},
+		{ID: "c", Healthy: false, Voter: true, RedundancyZone: "east"},
+		{ID: "d", Healthy: false, Voter: false, RedundancyZone: "east"},
+		{ID: "e", Healthy: true, Voter: true},
+	}
+
+	expected := []ZoneHealth{
+		{
+			Name:    "east",
+			Servers: []string{"c", "d"},
+			Voters:  []string{"c"},
+		},
+		{
+			Name:             "west",
+			Servers:          []string{"a", "b"},
+			Voters:           []string{"a"},
+			Healthy:          true,
+			FailureTolerance: 1,
+		},
+	}
+	verify.Values(t, "", zoneHealth(servers), expected)
+}
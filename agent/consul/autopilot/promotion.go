@@ -24,3 +24,112 @@ func PromoteStableServers(autopilotConfig *Config, health OperatorHealthReply, s
 
 	return promotions
 }
+
+// PromoteStableServersByZone is an autopilot promotion policy for redundancy
+// zones. It behaves like PromoteStableServers for servers that aren't in a
+// zone, but only promotes a stable server in a zone if that zone doesn't
+// already have a healthy voter, and then only one server per zone.
+func PromoteStableServersByZone(autopilotConfig *Config, health OperatorHealthReply, servers []raft.Server) []raft.Server {
+	// Find the zones that already have a healthy voter.
+	covered := make(map[string]bool)
+	for _, server := range servers {
+		health := health.ServerHealth(string(server.ID))
+		if health == nil || health.RedundancyZone == "" {
+			continue
+		}
+		if IsPotentialVoter(server.Suffrage) && health.Healthy {
+			covered[health.RedundancyZone] = true
+		}
+	}
+
+	// Find any non-voters eligible for promotion.
+	now := time.Now()
+	var promotions []raft.Server
+	for _, server := range servers {
+		if IsPotentialVoter(server.Suffrage) {
+			continue
+		}
+
+		health := health.ServerHealth(string(server.ID))
+		if !health.IsStable(now, autopilotConfig) {
+			continue
+		}
+		if zone := health.RedundancyZone; zone != "" {
+			if covered[zone] {
+				continue
+			}
+			covered[zone] = true
+		}
+		promotions = append(promotions, server)
+	}
+
+	return promotions
+}
+
+// DemoteZoneVoters returns the voters that should be demoted so that each
+// redundancy zone is left with a single voter. This is how a zone goes back
+// to having one voter after one of its non-voters was promoted to replace a
+// failed voter. The leader is never demoted, and a healthy voter is kept in
+// preference to an unhealthy one. If all the voters are in a single zone
+// then nothing is demoted, since that would shrink the cluster to one voter.
+func DemoteZoneVoters(health OperatorHealthReply, servers []raft.Server) []raft.Server {
+	// Group the voters by zone, keeping track of the zone order so the
+	// result is deterministic.
+	var zones []string
+	zoneVoters := make(map[string][]raft.Server)
+	for _, server := range servers {
+		if !IsPotentialVoter(server.Suffrage) {
+			continue
+		}
+		health := health.ServerHealth(string(server.ID))
+		if health == nil || health.RedundancyZone == "" {
+			continue
+		}
+		zone := health.RedundancyZone
+		if _, ok := zoneVoters[zone]; !ok {
+			zones = append(zones, zone)
+		}
+		zoneVoters[zone] = append(zoneVoters[zone], server)
+	}
+
+	// With only one zone there's no redundancy to preserve, and demoting
+	// down to a single voter would leave the cluster unable to tolerate
+	// any failure.
+	if len(zones) < 2 {
+		return nil
+	}
+
+	var demotions []raft.Server
+	for _, zone := range zones {
+		voters := zoneVoters[zone]
+		if len(voters) < 2 {
+			continue
+		}
+
+		// Pick the voter to keep, falling back to the first healthy one
+		// if the leader isn't in this zone. If none of them are healthy
+		// then there's nothing better to switch to, so leave them alone.
+		keep := -1
+		for i, server := range voters {
+			health := health.ServerHealth(string(server.ID))
+			if health.Leader {
+				keep = i
+				break
+			}
+			if keep == -1 && health.Healthy {
+				keep = i
+			}
+		}
+		if keep == -1 {
+			continue
+		}
+
+		for i, server := range voters {
+			if i != keep {
+				demotions = append(demotions, server)
+			}
+		}
+	}
+
+	return demotions
+}
@@ -100,3 +100,208 @@ func TestPromotion(t *testing.T) {
 		verify.Values(t, tc.name, tc.promotions, promotions)
 	}
 }
+
+func TestPromotion_RedundancyZones(t *testing.T) {
+	config := &Config{
+		LastContactThreshold:    5 * time.Second,
+		MaxTrailingLogs:         100,
+		ServerStabilizationTime: 3 * time.Second,
+		RedundancyZoneTag:       "zone",
+	}
+	stable := time.Now().Add(-10 * time.Second)
+
+	cases := []struct {
+		name       string
+		health     OperatorHealthReply
+		servers    []raft.Server
+		promotions []raft.Server
+	}{
+		{
+			name: "zone with a healthy voter, no promotions",
+			health: OperatorHealthReply{
+				Servers: []ServerHealth{
+					{ID: "a", Healthy: true, StableSince: stable, RedundancyZone: "east"},
+					{ID: "b", Healthy: true, StableSince: stable, RedundancyZone: "east"},
+				},
+			},
+			servers: []raft.Server{
+				{ID: "a", Suffrage: raft.Voter},
+				{ID: "b", Suffrage: raft.Nonvoter},
+			},
+			promotions: []raft.Server{},
+		},
+		{
+			name: "zone without a voter, promote only one",
+			health: OperatorHealthReply{
+				Servers: []ServerHealth{
+					{ID: "a", Healthy: true, StableSince: stable, RedundancyZone: "east"},
+					{ID: "b", Healthy: true, StableSince: stable, RedundancyZone: "west"},
+					{ID: "c", Healthy: true, StableSince: stable, RedundancyZone: "west"},
+				},
+			},
+			servers: []raft.Server{
+				{ID: "a", Suffrage: raft.Voter},
+				{ID: "b", Suffrage: raft.Nonvoter},
+				{ID: "c", Suffrage: raft.Nonvoter},
+			},
+			promotions: []raft.Server{
+				{ID: "b", Suffrage: raft.Nonvoter},
+			},
+		},
+		{
+			name: "zone voter failed, promote a standby",
+			health: OperatorHealthReply{
+				Servers: []ServerHealth{
+					{ID: "a", Healthy: false, StableSince: stable, RedundancyZone: "east"},
+					{ID: "b", Healthy: false, StableSince: stable, RedundancyZone: "east"},
+					{ID: "c", Healthy: true, StableSince: stable, RedundancyZone: "east"},
+				},
+			},
+			servers: []raft.Server{
+				{ID: "a", Suffrage: raft.Voter},
+				{ID: "b", Suffrage: raft.Nonvoter},
+				{ID: "c", Suffrage: raft.Nonvoter},
+			},
+			promotions: []raft.Server{
+				{ID: "c", Suffrage: raft.Nonvoter},
+			},
+		},
+		{
+			name: "servers without a zone are promoted as usual",
+			health: OperatorHealthReply{
+				Servers: []ServerHealth{
+					{ID: "a", Healthy: true, StableSince: stable, RedundancyZone: "east"},
+					{ID: "b", Healthy: true, StableSince: stable},
+					{ID: "c", Healthy: true, StableSince: stable},
+				},
+			},
+			servers: []raft.Server{
+				{ID: "a", Suffrage: raft.Voter},
+				{ID: "b", Suffrage: raft.Nonvoter},
+				{ID: "c", Suffrage: raft.Nonvoter},
+			},
+			promotions: []raft.Server{
+				{ID: "b", Suffrage: raft.Nonvoter},
+				{ID: "c", Suffrage: raft.Nonvoter},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		promotions := PromoteStableServersByZone(config, tc.health, tc.servers)
+		verify.Values(t, tc.name, tc.promotions, promotions)
+	}
+}
+
+func TestDemoteZoneVoters(t *testing.T) {
+	cases := []struct {
+		name      string
+		health    OperatorHealthReply
+		servers   []raft.Server
+		demotions []raft.Server
+	}{
+		{
+			name: "one voter per zone, no demotions",
+			health: OperatorHealthReply{
+				Servers: []ServerHealth{
+					{ID: "a", Healthy: true, RedundancyZone: "east"},
+					{ID: "b", Healthy: true, RedundancyZone: "east"},
+					{ID: "c", Healthy: true, RedundancyZone: "west"},
+				},
+			},
+			servers: []raft.Server{
+				{ID: "a", Suffrage: raft.Voter},
+				{ID: "b", Suffrage: raft.Nonvoter},
+				{ID: "c", Suffrage: raft.Voter},
+			},
+			demotions: []raft.Server{},
+		},
+		{
+			name: "failed voter is demoted after its standby was promoted",
+			health: OperatorHealthReply{
+				Servers: []ServerHealth{
+					{ID: "a", Healthy: false, RedundancyZone: "east"},
+					{ID: "b", Healthy: true, RedundancyZone: "east"},
+					{ID: "c", Healthy: true, RedundancyZone: "west"},
+				},
+			},
+			servers: []raft.Server{
+				{ID: "a", Suffrage: raft.Voter},
+				{ID: "b", Suffrage: raft.Voter},
+				{ID: "c", Suffrage: raft.Voter},
+			},
+			demotions: []raft.Server{
+				{ID: "a", Suffrage: raft.Voter},
+			},
+		},
+		{
+			name: "leader is never demoted",
+			health: OperatorHealthReply{
+				Servers: []ServerHealth{
+					{ID: "a", Healthy: true, RedundancyZone: "east"},
+					{ID: "b", Healthy: true, Leader: true, RedundancyZone: "east"},
+					{ID: "c", Healthy: true, RedundancyZone: "west"},
+				},
+			},
+			servers: []raft.Server{
+				{ID: "a", Suffrage: raft.Voter},
+				{ID: "b", Suffrage: raft.Voter},
+				{ID: "c", Suffrage: raft.Voter},
+			},
+			demotions: []raft.Server{
+				{ID: "a", Suffrage: raft.Voter},
+			},
+		},
+		{
+			name: "no healthy voters in the zone, leave it alone",
+			health: OperatorHealthReply{
+				Servers: []ServerHealth{
+					{ID: "a", Healthy: false, RedundancyZone: "east"},
+					{ID: "b", Healthy: false, RedundancyZone: "east"},
+					{ID: "c", Healthy: true, RedundancyZone: "west"},
+				},
+			},
+			servers: []raft.Server{
+				{ID: "a", Suffrage: raft.Voter},
+				{ID: "b", Suffrage: raft.Voter},
+				{ID: "c", Suffrage: raft.Voter},
+			},
+			demotions: []raft.Server{},
+		},
+		{
+			name: "voters without a zone are left alone",
+			health: OperatorHealthReply{
+				Servers: []ServerHealth{
+					{ID: "a", Healthy: true},
+					{ID: "b", Healthy: true},
+				},
+			},
+			servers: []raft.Server{
+				{ID: "a", Suffrage: raft.Voter},
+				{ID: "b", Suffrage: raft.Voter},
+			},
+			demotions: []raft.Server{},
+		},
+		{
+			name: "voters all in one zone are left alone",
+			health: OperatorHealthReply{
+				Servers: []ServerHealth{
+					{ID: "a", Healthy: true, Leader: true, RedundancyZone: "east"},
+					{ID: "b", Healthy: true, RedundancyZone: "east"},
+					{ID: "c", Healthy: true, RedundancyZone: "east"},
+				},
+			},
+			servers: []raft.Server{
+				{ID: "a", Suffrage: raft.Voter},
+				{ID: "b", Suffrage: raft.Voter},
+				{ID: "c", Suffrage: raft.Voter},
+			},
+			demotions: []raft.Server{},
+		},
+	}
+
+	for _, tc := range cases {
+		demotions := DemoteZoneVoters(tc.health, tc.servers)
+		verify.Values(t, tc.name, tc.demotions, demotions)
+	}
+}
@@ -25,8 +25,10 @@ type Config struct {
 	// applicable with Raft protocol version 3 or higher.
 	ServerStabilizationTime time.Duration
 
-	// (Enterprise-only) RedundancyZoneTag is the node tag to use for separating
-	// servers into zones for redundancy. If left blank, this feature will be disabled.
+	// RedundancyZoneTag is the node meta key to use for separating servers
+	// into zones for redundancy. Only one server in each zone is a voter, and
+	// the rest are kept as non-voters that are promoted if that voter fails.
+	// If left blank, this feature will be disabled.
 	RedundancyZoneTag string
 
 	// (Enterprise-only) DisableUpgradeMigration will disable Autopilot's upgrade migration
@@ -81,6 +83,10 @@ type ServerHealth struct {
 
 	// StableSince is the last time this server's Healthy value changed.
 	StableSince time.Time
+
+	// RedundancyZone is the redundancy zone this server belongs to, if
+	// redundancy zones are enabled.
+	RedundancyZone string
 }
 
 // IsHealthy determines whether this ServerHealth is considered healthy
@@ -146,6 +152,29 @@ type OperatorHealthReply struct {
 
 	// Servers holds the health of each server.
 	Servers []ServerHealth
+
+	// RedundancyZones holds the health of each redundancy zone, if
+	// redundancy zones are enabled.
+	RedundancyZones []ZoneHealth
+}
+
+// ZoneHealth is the health of a redundancy zone.
+type ZoneHealth struct {
+	// Name is the name of the zone, taken from the configured node meta key.
+	Name string
+
+	// Servers holds the IDs of the servers in the zone.
+	Servers []string
+
+	// Voters holds the IDs of the voting servers in the zone.
+	Voters []string
+
+	// Healthy is true if the zone has a healthy voter.
+	Healthy bool
+
+	// FailureTolerance is the number of healthy non-voters in the zone that
+	// are available to replace its voter.
+	FailureTolerance int
 }
 
 func (o *OperatorHealthReply) ServerHealth(id string) *ServerHealth {
@@ -5,8 +5,10 @@ import (
 	"testing"
 	"time"
 
-	"github.com/hashicorp/consul/testrpc"
+	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/hashicorp/raft"
 	"github.com/hashicorp/serf/serf"
 )
@@ -369,3 +371,91 @@ func TestAutopilot_PromoteNonVoter(t *testing.T) {
 		}
 	})
 }
+
+func TestAutopilot_RedundancyZones(t *testing.T) {
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.Datacenter = "dc1"
+		c.Bootstrap = true
+		c.RaftConfig.ProtocolVersion = 3
+		c.AutopilotConfig.ServerStabilizationTime = 200 * time.Millisecond
+		c.AutopilotConfig.RedundancyZoneTag = "zone"
+		c.ServerHealthInterval = 100 * time.Millisecond
+		c.AutopilotInterval = 100 * time.Millisecond
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	conf := func(c *Config) {
+		c.Datacenter = "dc1"
+		c.Bootstrap = false
+		c.RaftConfig.ProtocolVersion = 3
+	}
+	dir2, s2 := testServerWithConfig(t, conf)
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+
+	dir3, s3 := testServerWithConfig(t, conf)
+	defer os.RemoveAll(dir3)
+	defer s3.Shutdown()
+
+	// Put the servers into zones before they join, the same way their
+	// agents would sync their node meta.
+	zones := map[*Server]string{s1: "east", s2: "east", s3: "west"}
+	for s, zone := range zones {
+		req := structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       s.config.NodeName,
+			Address:    "127.0.0.1",
+			NodeMeta:   map[string]string{"zone": zone},
+		}
+		var out struct{}
+		if err := msgpackrpc.CallWithCodec(codec, "Catalog.Register", &req, &out); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+
+	joinLAN(t, s2, s1)
+	joinLAN(t, s3, s1)
+
+	// The west server should be promoted since it's alone in its zone.
+	suffrage := func(r *retry.R, s *Server) raft.ServerSuffrage {
+		future := s1.raft.GetConfiguration()
+		if err := future.Error(); err != nil {
+			r.Fatal(err)
+		}
+		for _, server := range future.Configuration().Servers {
+			if server.ID == raft.ServerID(s.config.NodeID) {
+				return server.Suffrage
+			}
+		}
+		r.Fatalf("server %q not found", s.config.NodeName)
+		return raft.Nonvoter
+	}
+	retry.Run(t, func(r *retry.R) {
+		if got := suffrage(r, s3); got != raft.Voter {
+			r.Fatalf("bad: %v", got)
+		}
+	})
+
+	// The second east server should be kept as a hot standby.
+	retry.Run(t, func(r *retry.R) {
+		health := s1.autopilot.GetClusterHealth()
+		if len(health.RedundancyZones) != 2 {
+			r.Fatalf("bad: %v", health.RedundancyZones)
+		}
+		east := health.RedundancyZones[0]
+		if east.Name != "east" || len(east.Servers) != 2 || len(east.Voters) != 1 ||
+			!east.Healthy || east.FailureTolerance != 1 {
+			r.Fatalf("bad: %#v", east)
+		}
+	})
+	retry.Run(t, func(r *retry.R) {
+		if got := suffrage(r, s2); got != raft.Nonvoter {
+			r.Fatalf("bad: %v", got)
+		}
+	})
+}
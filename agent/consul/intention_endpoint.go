@@ -3,6 +3,8 @@ package consul
 import (
 	"errors"
 	"fmt"
+	"net"
+	"reflect"
 	"time"
 
 	"github.com/armon/go-metrics"
@@ -55,23 +57,10 @@ func (s *Intention) Apply(
 			return fmt.Errorf("ID must be empty when creating a new intention")
 		}
 
-		state := s.srv.fsm.State()
-		for {
-			var err error
-			args.Intention.ID, err = uuid.GenerateUUID()
-			if err != nil {
-				s.srv.logger.Printf("[ERR] consul.intention: UUID generation failed: %v", err)
-				return err
-			}
-
-			_, ixn, err := state.IntentionGet(nil, args.Intention.ID)
-			if err != nil {
-				s.srv.logger.Printf("[ERR] consul.intention: intention lookup failed: %v", err)
-				return err
-			}
-			if ixn == nil {
-				break
-			}
+		var err error
+		args.Intention.ID, err = s.generateID(nil)
+		if err != nil {
+			return err
 		}
 
 		// Set the created at
@@ -154,6 +143,236 @@ func (s *Intention) Apply(
 	return nil
 }
 
+// generateID returns a new intention ID that is neither in use nor in the
+// given set of reserved IDs.
+func (s *Intention) generateID(reserved map[string]struct{}) (string, error) {
+	state := s.srv.fsm.State()
+	for {
+		id, err := uuid.GenerateUUID()
+		if err != nil {
+			s.srv.logger.Printf("[ERR] consul.intention: UUID generation failed: %v", err)
+			return "", err
+		}
+		if _, ok := reserved[id]; ok {
+			continue
+		}
+
+		_, ixn, err := state.IntentionGet(nil, id)
+		if err != nil {
+			s.srv.logger.Printf("[ERR] consul.intention: intention lookup failed: %v", err)
+			return "", err
+		}
+		if ixn == nil {
+			return id, nil
+		}
+	}
+}
+
+// Reconcile makes the intentions match the desired set of the request. The
+// existing intentions are matched with the desired ones by source and
+// destination, and all the needed creates, updates and deletes are applied
+// in a single transaction.
+func (s *Intention) Reconcile(
+	args *structs.IntentionReconcileRequest,
+	reply *structs.IntentionReconcileResponse) error {
+
+	// Forward this request to the primary DC if we're a secondary that's replicating intentions.
+	if s.srv.intentionReplicationEnabled() {
+		args.Datacenter = s.srv.config.PrimaryDatacenter
+	}
+
+	if done, err := s.srv.forward("Intention.Reconcile", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"intention", "reconcile"}, time.Now())
+
+	// Get the ACL token for the request for the checks below.
+	rule, err := s.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+
+	// Default and validate the desired intentions the same way as Apply.
+	now := time.Now().UTC()
+	desired := make(map[string]*structs.Intention)
+	for _, ixn := range args.Intentions {
+		if ixn == nil {
+			return fmt.Errorf("Intentions must not be null")
+		}
+		if ixn.SourceType == "" {
+			ixn.SourceType = structs.IntentionSourceConsul
+		}
+		if ixn.SourceNS == "" {
+			ixn.SourceNS = structs.IntentionDefaultNamespace
+		}
+		if ixn.DestinationNS == "" {
+			ixn.DestinationNS = structs.IntentionDefaultNamespace
+		}
+		if ixn.Meta == nil {
+			ixn.Meta = make(map[string]string)
+		}
+		ixn.UpdatePrecedence()
+
+		key := intentionKey(ixn)
+		if ixn.ID != "" {
+			return fmt.Errorf("ID must be empty for intention %s", key)
+		}
+		if err := ixn.Validate(); err != nil {
+			return fmt.Errorf("Invalid intention %s: %v", key, err)
+		}
+		if _, ok := desired[key]; ok {
+			return fmt.Errorf("Duplicate intention %s", key)
+		}
+		desired[key] = ixn
+	}
+
+	// Plan the changes against the current intentions.
+	index, existing, err := s.srv.fsm.State().Intentions(nil)
+	if err != nil {
+		return err
+	}
+	if args.CAS != 0 && args.CAS != index {
+		return fmt.Errorf("Intentions changed since index %d, the current index is %d", args.CAS, index)
+	}
+
+	var deletes, updates, creates []*structs.IntentionChange
+	var unmanaged structs.Intentions
+	ids := make(map[string]struct{})
+	for _, ixn := range existing {
+		ids[ixn.ID] = struct{}{}
+		want, ok := desired[intentionKey(ixn)]
+		if !ok {
+			if args.Prune {
+				deletes = append(deletes, &structs.IntentionChange{
+					Op:        structs.IntentionOpDelete,
+					Intention: ixn,
+				})
+			} else {
+				unmanaged = append(unmanaged, ixn)
+			}
+			continue
+		}
+
+		want.ID = ixn.ID
+		if intentionConfigEqual(ixn, want) {
+			continue
+		}
+		want.CreatedAt = ixn.CreatedAt
+		want.UpdatedAt = now
+		updates = append(updates, &structs.IntentionChange{
+			Op:        structs.IntentionOpUpdate,
+			Intention: want,
+			Previous:  ixn,
+		})
+	}
+	for _, ixn := range args.Intentions {
+		// The desired intentions that match an existing one have its ID by
+		// now, so the rest are new.
+		if ixn.ID != "" {
+			continue
+		}
+		if ixn.ID, err = s.generateID(ids); err != nil {
+			return err
+		}
+		ids[ixn.ID] = struct{}{}
+		ixn.CreatedAt = now
+		ixn.UpdatedAt = now
+		creates = append(creates, &structs.IntentionChange{
+			Op:        structs.IntentionOpCreate,
+			Intention: ixn,
+		})
+	}
+
+	// Deletes go first so that they can't conflict with the other changes.
+	changes := append(append(deletes, updates...), creates...)
+
+	// Perform the ACL checks. Every change requires write access to the
+	// destination and unmanaged intentions are only reported if readable.
+	if rule != nil {
+		for _, change := range changes {
+			if prefix, ok := change.Intention.GetACLPrefix(); ok && !rule.IntentionWrite(prefix) {
+				s.srv.logger.Printf("[WARN] consul.intention: Operation on intention '%s' denied due to ACLs", intentionKey(change.Intention))
+				return acl.ErrPermissionDenied
+			}
+		}
+
+		var readable structs.Intentions
+		for _, ixn := range unmanaged {
+			if prefix, ok := ixn.GetACLPrefix(); ok && !rule.IntentionRead(prefix) {
+				continue
+			}
+			readable = append(readable, ixn)
+		}
+		unmanaged = readable
+	}
+
+	reply.Changes = changes
+	reply.Unmanaged = unmanaged
+	reply.Index = index
+	if args.DryRun || len(changes) == 0 {
+		return nil
+	}
+
+	// Commit. The check above was made against the local state, so the index
+	// is checked again within the transaction in case a concurrent write was
+	// applied in the meantime.
+	txn := &structs.TxnRequest{Datacenter: args.Datacenter}
+	if args.CAS != 0 {
+		txn.Ops = append(txn.Ops, &structs.TxnOp{
+			Intention: &structs.TxnIntentionOp{
+				Datacenter: args.Datacenter,
+				Op:         structs.IntentionOpCheckIndex,
+				Intention: &structs.Intention{
+					RaftIndex: structs.RaftIndex{ModifyIndex: args.CAS},
+				},
+			},
+		})
+	}
+	for _, change := range changes {
+		txn.Ops = append(txn.Ops, &structs.TxnOp{
+			Intention: &structs.TxnIntentionOp{
+				Datacenter: args.Datacenter,
+				Op:         change.Op,
+				Intention:  change.Intention,
+			},
+		})
+	}
+	resp, err := s.srv.raftApply(structs.TxnRequestType, txn)
+	if err != nil {
+		s.srv.logger.Printf("[ERR] consul.intention: Reconcile failed %v", err)
+		return err
+	}
+	if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+	if txnResp, ok := resp.(structs.TxnResponse); ok && len(txnResp.Errors) > 0 {
+		return txnResp.Error()
+	}
+
+	return nil
+}
+
+// intentionKey returns the source and destination of an intention, which
+// identify it.
+func intentionKey(ixn *structs.Intention) string {
+	return fmt.Sprintf("%s/%s => %s/%s",
+		ixn.SourceNS, ixn.SourceName, ixn.DestinationNS, ixn.DestinationName)
+}
+
+// intentionConfigEqual returns true if the user-defined fields of the two
+// intentions are equal.
+func intentionConfigEqual(a, b *structs.Intention) bool {
+	return a.Description == b.Description &&
+		a.SourceType == b.SourceType &&
+		a.Action == b.Action &&
+		a.DefaultAddr == b.DefaultAddr &&
+		a.DefaultPort == b.DefaultPort &&
+		len(a.Meta) == len(b.Meta) &&
+		(len(a.Meta) == 0 || reflect.DeepEqual(a.Meta, b.Meta)) &&
+		len(a.Permissions) == len(b.Permissions) &&
+		(len(a.Permissions) == 0 || reflect.DeepEqual(a.Permissions, b.Permissions))
+}
+
 // Get returns a single intention by ID.
 func (s *Intention) Get(
 	args *structs.IntentionQueryRequest,
@@ -283,17 +502,10 @@ func (s *Intention) Check(
 		return errors.New("Check must be specified on args")
 	}
 
-	// Build the URI
-	var uri connect.CertURI
-	switch query.SourceType {
-	case structs.IntentionSourceConsul:
-		uri = &connect.SpiffeIDService{
-			Namespace: query.SourceNS,
-			Service:   query.SourceName,
-		}
-
-	default:
-		return fmt.Errorf("unsupported SourceType: %q", query.SourceType)
+	// Build the matcher for the source
+	authorize, err := intentionSourceAuthorizer(query)
+	if err != nil {
+		return err
 	}
 
 	// Get the ACL token for the request for the checks below.
@@ -335,7 +547,7 @@ func (s *Intention) Check(
 
 	// Check the authorization for each match
 	for _, ixn := range matches[0] {
-		if auth, ok := uri.Authorize(ixn); ok {
+		if auth, ok := authorize(ixn); ok {
 			reply.Allowed = auth
 			return nil
 		}
@@ -362,3 +574,155 @@ func (s *Intention) Check(
 
 	return nil
 }
+
+// Explain tests a source/destination like Check and explains the decision:
+// the intention that decided or the default behavior configured by ACLs, and
+// all intentions for the destination in the order they are evaluated,
+// including those that can never apply because they are shadowed by an
+// intention with a higher precedence.
+func (s *Intention) Explain(
+	args *structs.IntentionQueryRequest,
+	reply *structs.IntentionQueryExplainResponse) error {
+	// Forward maybe
+	if done, err := s.srv.forward("Intention.Explain", args, args, reply); done {
+		return err
+	}
+
+	// Get the test args, and defensively guard against nil
+	query := args.Check
+	if query == nil {
+		return errors.New("Check must be specified on args")
+	}
+
+	// Build the matcher for the source
+	authorize, err := intentionSourceAuthorizer(query)
+	if err != nil {
+		return err
+	}
+
+	// Get the ACL token for the request for the checks below.
+	rule, err := s.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+
+	// Unlike Check, Explain returns the intentions so it requires
+	// IntentionRead.
+	if prefix, ok := query.GetACLPrefix(); ok {
+		if rule != nil && !rule.IntentionRead(prefix) {
+			s.srv.logger.Printf("[WARN] consul.intention: explain on intention '%s' denied due to ACLs", prefix)
+			return acl.ErrPermissionDenied
+		}
+	}
+
+	// Get the default behavior the same way as Check.
+	defaultRule, err := s.srv.ResolveToken("")
+	if err != nil {
+		return err
+	}
+	defaultAllow := true
+	if defaultRule != nil {
+		defaultAllow = defaultRule.IntentionDefaultAllow()
+	}
+
+	return s.srv.blockingQuery(
+		&args.QueryOptions,
+		&reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			index, matches, err := state.IntentionMatch(ws, &structs.IntentionQueryMatch{
+				Type: structs.IntentionMatchDestination,
+				Entries: []structs.IntentionMatchEntry{
+					structs.IntentionMatchEntry{
+						Namespace: query.DestinationNS,
+						Name:      query.DestinationName,
+					},
+				},
+			})
+			if err != nil {
+				return err
+			}
+			if len(matches) != 1 {
+				return errors.New("internal error loading matches")
+			}
+
+			reply.Index = index
+			reply.DefaultAllow = defaultAllow
+			reply.Matched = nil
+			reply.Intentions = make([]*structs.IntentionExplainEntry, 0, len(matches[0]))
+			for i, ixn := range matches[0] {
+				entry := &structs.IntentionExplainEntry{Intention: ixn}
+				if auth, ok := authorize(ixn); ok {
+					entry.SourceMatch = true
+					if reply.Matched == nil {
+						reply.Matched = ixn
+						reply.Allowed = auth
+					}
+				}
+				for _, other := range matches[0][:i] {
+					if intentionSourceCovers(other, ixn) {
+						entry.ShadowedBy = other.ID
+						break
+					}
+				}
+				reply.Intentions = append(reply.Intentions, entry)
+			}
+
+			switch {
+			case reply.Matched != nil && reply.Matched.HasPermissions():
+				reply.Reason = fmt.Sprintf("Matched L7 intention that can only be enforced per request: %s", reply.Matched.String())
+			case reply.Matched != nil:
+				reply.Reason = fmt.Sprintf("Matched intention: %s", reply.Matched.String())
+			case defaultRule == nil:
+				reply.Allowed = true
+				reply.Reason = "ACLs disabled, access is allowed by default"
+			default:
+				reply.Allowed = defaultAllow
+				reply.Reason = "Default behavior configured by ACLs"
+			}
+			return nil
+		},
+	)
+}
+
+// intentionSourceAuthorizer returns the func that tests whether an intention
+// applies to the source of a check, and if so whether it allows it. Consul
+// sources are matched by service and CIDR sources by the IP address given as
+// the source name.
+func intentionSourceAuthorizer(query *structs.IntentionQueryCheck) (func(*structs.Intention) (bool, bool), error) {
+	switch query.SourceType {
+	case structs.IntentionSourceConsul:
+		uri := &connect.SpiffeIDService{
+			Namespace: query.SourceNS,
+			Service:   query.SourceName,
+		}
+		return uri.Authorize, nil
+
+	case structs.IntentionSourceCIDR:
+		ip := net.ParseIP(query.SourceName)
+		if ip == nil {
+			return nil, fmt.Errorf("SourceName must be an IP address for the %q SourceType",
+				structs.IntentionSourceCIDR)
+		}
+		return func(ixn *structs.Intention) (bool, bool) {
+			return ixn.AuthorizeSourceAddr(ip)
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported SourceType: %q", query.SourceType)
+	}
+}
+
+// intentionSourceCovers returns true if every source that matches b also
+// matches a.
+func intentionSourceCovers(a, b *structs.Intention) bool {
+	if a.SourceType != b.SourceType {
+		return false
+	}
+	if a.SourceType == structs.IntentionSourceCIDR {
+		return a.SourceCIDRCovers(b)
+	}
+	if a.SourceNS != structs.IntentionWildcard && a.SourceNS != b.SourceNS {
+		return false
+	}
+	return a.SourceName == structs.IntentionWildcard || a.SourceName == b.SourceName
+}
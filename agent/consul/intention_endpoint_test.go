@@ -1276,3 +1276,563 @@ service "bar" {
 		require.False(resp.Allowed)
 	}
 }
+
+// Test the Explain method explains matches and shadowed intentions.
+func TestIntentionExplain_match(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ACLDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLMasterToken = "root"
+		c.ACLDefaultPolicy = "deny"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	// Create some intentions
+	ids := make(map[string]string)
+	{
+		insert := [][]string{
+			{"foo", "*", "allow"},
+			{"foo", "web", "deny"}, // higher precedence than foo/*
+			{"*", "*", "deny"},
+			{"bar", "*", "allow"}, // doesn't match foo sources
+		}
+
+		for _, v := range insert {
+			ixn := structs.IntentionRequest{
+				Datacenter: "dc1",
+				Op:         structs.IntentionOpCreate,
+				Intention: &structs.Intention{
+					SourceNS:        v[0],
+					SourceName:      v[1],
+					DestinationNS:   "foo",
+					DestinationName: "db",
+					Action:          structs.IntentionAction(v[2]),
+				},
+			}
+			ixn.WriteRequest.Token = "root"
+
+			// Create
+			var reply string
+			require.Nil(msgpackrpc.CallWithCodec(codec, "Intention.Apply", &ixn, &reply))
+			ids[v[0]+"/"+v[1]] = reply
+		}
+	}
+
+	explain := func(srcNS, src string) *structs.IntentionQueryExplainResponse {
+		req := &structs.IntentionQueryRequest{
+			Datacenter: "dc1",
+			Check: &structs.IntentionQueryCheck{
+				SourceNS:        srcNS,
+				SourceName:      src,
+				DestinationNS:   "foo",
+				DestinationName: "db",
+				SourceType:      structs.IntentionSourceConsul,
+			},
+		}
+		req.Token = "root"
+		var resp structs.IntentionQueryExplainResponse
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Intention.Explain", req, &resp))
+		return &resp
+	}
+
+	// The exact intention matches first and the wildcard ones are listed
+	// after it.
+	resp := explain("foo", "web")
+	require.False(resp.Allowed)
+	require.False(resp.DefaultAllow)
+	require.Equal(ids["foo/web"], resp.Matched.ID)
+	require.Contains(resp.Reason, "Matched intention")
+
+	var order []string
+	var matches []bool
+	for _, e := range resp.Intentions {
+		order = append(order, e.Intention.SourceNS+"/"+e.Intention.SourceName)
+		matches = append(matches, e.SourceMatch)
+		require.Empty(e.ShadowedBy)
+	}
+	require.Equal([]string{"foo/web", "bar/*", "foo/*", "*/*"}, order)
+	require.Equal([]bool{true, false, true, true}, matches)
+
+	// A wildcard match
+	resp = explain("foo", "api")
+	require.True(resp.Allowed)
+	require.Equal(ids["foo/*"], resp.Matched.ID)
+
+	// Other namespaces only match the catch-all
+	resp = explain("baz", "api")
+	require.False(resp.Allowed)
+	require.Equal(ids["*/*"], resp.Matched.ID)
+}
+
+// Test the Explain method reports shadowed intentions and the default.
+func TestIntentionExplain_shadowedDefault(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	// foo/* => foo/db has a higher precedence than foo/web => foo/* so the
+	// latter never applies to foo/db.
+	var ids []string
+	{
+		insert := [][]string{
+			{"foo", "*", "foo", "db"},
+			{"foo", "web", "foo", "*"},
+		}
+
+		for _, v := range insert {
+			ixn := structs.IntentionRequest{
+				Datacenter: "dc1",
+				Op:         structs.IntentionOpCreate,
+				Intention: &structs.Intention{
+					SourceNS:        v[0],
+					SourceName:      v[1],
+					DestinationNS:   v[2],
+					DestinationName: v[3],
+					Action:          structs.IntentionActionDeny,
+				},
+			}
+
+			// Create
+			var reply string
+			require.Nil(msgpackrpc.CallWithCodec(codec, "Intention.Apply", &ixn, &reply))
+			ids = append(ids, reply)
+		}
+	}
+
+	req := &structs.IntentionQueryRequest{
+		Datacenter: "dc1",
+		Check: &structs.IntentionQueryCheck{
+			SourceNS:        "bar",
+			SourceName:      "api",
+			DestinationNS:   "foo",
+			DestinationName: "db",
+			SourceType:      structs.IntentionSourceConsul,
+		},
+	}
+	var resp structs.IntentionQueryExplainResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Intention.Explain", req, &resp))
+	require.True(resp.Allowed)
+	require.True(resp.DefaultAllow)
+	require.Nil(resp.Matched)
+	require.Contains(resp.Reason, "ACLs disabled")
+	require.Len(resp.Intentions, 2)
+	require.Equal(ids[0], resp.Intentions[0].Intention.ID)
+	require.Empty(resp.Intentions[0].ShadowedBy)
+	require.Equal(ids[1], resp.Intentions[1].Intention.ID)
+	require.Equal(ids[0], resp.Intentions[1].ShadowedBy)
+}
+
+// Test that Check and Explain match CIDR sources by IP address and that a
+// larger block shadows the smaller blocks within it.
+func TestIntentionCheck_cidr(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	// 10.0.0.0/8 => db has a higher precedence than 10.1.0.0/16 => * so the
+	// latter never applies to db.
+	var ids []string
+	{
+		insert := [][]string{
+			{"10.0.0.0/8", "db", "deny"},
+			{"10.1.0.0/16", "*", "allow"},
+		}
+
+		for _, v := range insert {
+			ixn := structs.IntentionRequest{
+				Datacenter: "dc1",
+				Op:         structs.IntentionOpCreate,
+				Intention: &structs.Intention{
+					SourceNS:        structs.IntentionDefaultNamespace,
+					SourceName:      v[0],
+					DestinationNS:   structs.IntentionDefaultNamespace,
+					DestinationName: v[1],
+					SourceType:      structs.IntentionSourceCIDR,
+					Action:          structs.IntentionAction(v[2]),
+				},
+			}
+
+			// Create
+			var reply string
+			require.Nil(msgpackrpc.CallWithCodec(codec, "Intention.Apply", &ixn, &reply))
+			ids = append(ids, reply)
+		}
+	}
+
+	request := func(src string) *structs.IntentionQueryRequest {
+		return &structs.IntentionQueryRequest{
+			Datacenter: "dc1",
+			Check: &structs.IntentionQueryCheck{
+				SourceName:      src,
+				DestinationNS:   structs.IntentionDefaultNamespace,
+				DestinationName: "db",
+				SourceType:      structs.IntentionSourceCIDR,
+			},
+		}
+	}
+
+	// An address in the blocks is decided by the intention with the
+	// highest precedence.
+	var checkResp structs.IntentionQueryCheckResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Intention.Check", request("10.1.2.3"), &checkResp))
+	require.False(checkResp.Allowed)
+
+	var resp structs.IntentionQueryExplainResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Intention.Explain", request("10.1.2.3"), &resp))
+	require.False(resp.Allowed)
+	require.Equal(ids[0], resp.Matched.ID)
+	require.Len(resp.Intentions, 2)
+	require.True(resp.Intentions[0].SourceMatch)
+	require.Empty(resp.Intentions[0].ShadowedBy)
+	require.True(resp.Intentions[1].SourceMatch)
+	require.Equal(ids[0], resp.Intentions[1].ShadowedBy)
+
+	// Addresses outside the blocks get the default.
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Intention.Check", request("192.168.0.1"), &checkResp))
+	require.True(checkResp.Allowed)
+
+	// The source must be an address.
+	err := msgpackrpc.CallWithCodec(codec, "Intention.Check", request("10.0.0.0/8"), &checkResp)
+	require.Error(err)
+	require.Contains(err.Error(), "must be an IP address")
+}
+
+// Test the Explain method requires intention read permissions.
+func TestIntentionExplain_aclDeny(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ACLDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLMasterToken = "root"
+		c.ACLDefaultPolicy = "deny"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	// Create an ACL with service read permissions but no intention read
+	// permissions. This is enough for Check but not for Explain.
+	var token string
+	{
+		var rules = `
+service "bar" {
+	policy = "read"
+	intentions = "deny"
+}`
+
+		req := structs.ACLRequest{
+			Datacenter: "dc1",
+			Op:         structs.ACLSet,
+			ACL: structs.ACL{
+				Name:  "User token",
+				Type:  structs.ACLTokenTypeClient,
+				Rules: rules,
+			},
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+		require.Nil(msgpackrpc.CallWithCodec(codec, "ACL.Apply", &req, &token))
+	}
+
+	req := &structs.IntentionQueryRequest{
+		Datacenter: "dc1",
+		Check: &structs.IntentionQueryCheck{
+			SourceNS:        "foo",
+			SourceName:      "qux",
+			DestinationNS:   "foo",
+			DestinationName: "bar",
+			SourceType:      structs.IntentionSourceConsul,
+		},
+	}
+	req.Token = token
+	var resp structs.IntentionQueryExplainResponse
+	err := msgpackrpc.CallWithCodec(codec, "Intention.Explain", req, &resp)
+	require.True(acl.IsErrPermissionDenied(err))
+}
+
+// Test reconciling the intentions with a desired set.
+func TestIntentionReconcile(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	// Create some intentions
+	ids := make(map[string]string)
+	for _, v := range [][]string{
+		{"web", "db", "allow"},
+		{"api", "db", "allow"},
+		{"*", "db", "deny"},
+	} {
+		ixn := structs.IntentionRequest{
+			Datacenter: "dc1",
+			Op:         structs.IntentionOpCreate,
+			Intention: &structs.Intention{
+				SourceName:      v[0],
+				DestinationName: v[1],
+				Action:          structs.IntentionAction(v[2]),
+			},
+		}
+		var reply string
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Intention.Apply", &ixn, &reply))
+		ids[v[0]] = reply
+	}
+
+	desired := func() structs.Intentions {
+		return structs.Intentions{
+			{SourceName: "web", DestinationName: "db", Action: structs.IntentionActionAllow},
+			{SourceName: "api", DestinationName: "db", Action: structs.IntentionActionDeny},
+			{SourceName: "billing", DestinationName: "db", Action: structs.IntentionActionAllow},
+		}
+	}
+	ops := func(resp *structs.IntentionReconcileResponse) []string {
+		var result []string
+		for _, c := range resp.Changes {
+			result = append(result, string(c.Op)+" "+c.Intention.SourceName)
+		}
+		return result
+	}
+
+	// A dry run plans without changing anything.
+	req := &structs.IntentionReconcileRequest{
+		Datacenter: "dc1",
+		Intentions: desired(),
+		Prune:      true,
+		DryRun:     true,
+	}
+	resp := &structs.IntentionReconcileResponse{}
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Intention.Reconcile", req, resp))
+	require.Equal([]string{"delete *", "update api", "create billing"}, ops(resp))
+	require.Equal(ids["api"], resp.Changes[1].Intention.ID)
+	require.Equal(structs.IntentionActionAllow, resp.Changes[1].Previous.Action)
+	require.Empty(resp.Unmanaged)
+	planIndex := resp.Index
+
+	index, ixns, err := s1.fsm.State().Intentions(nil)
+	require.Nil(err)
+	require.Equal(planIndex, index)
+	require.Len(ixns, 3)
+
+	// Without pruning the other intentions are unmanaged.
+	req = &structs.IntentionReconcileRequest{
+		Datacenter: "dc1",
+		Intentions: desired(),
+		DryRun:     true,
+	}
+	resp = &structs.IntentionReconcileResponse{}
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Intention.Reconcile", req, resp))
+	require.Equal([]string{"update api", "create billing"}, ops(resp))
+	require.Len(resp.Unmanaged, 1)
+	require.Equal(ids["*"], resp.Unmanaged[0].ID)
+
+	// Apply the plan.
+	req = &structs.IntentionReconcileRequest{
+		Datacenter: "dc1",
+		Intentions: desired(),
+		Prune:      true,
+		CAS:        planIndex,
+	}
+	resp = &structs.IntentionReconcileResponse{}
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Intention.Reconcile", req, resp))
+
+	_, ixns, err = s1.fsm.State().Intentions(nil)
+	require.Nil(err)
+	actual := make(map[string]*structs.Intention)
+	for _, ixn := range ixns {
+		actual[ixn.SourceName] = ixn
+	}
+	require.Len(actual, 3)
+	require.Equal(ids["web"], actual["web"].ID)
+	require.Equal(ids["api"], actual["api"].ID)
+	require.Equal(structs.IntentionActionDeny, actual["api"].Action)
+	require.Equal(structs.IntentionActionAllow, actual["billing"].Action)
+	require.NotEmpty(actual["billing"].ID)
+
+	// Nothing left to do.
+	req = &structs.IntentionReconcileRequest{
+		Datacenter: "dc1",
+		Intentions: desired(),
+		Prune:      true,
+	}
+	resp = &structs.IntentionReconcileResponse{}
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Intention.Reconcile", req, resp))
+	require.Empty(resp.Changes)
+
+	// The old plan is stale now.
+	req = &structs.IntentionReconcileRequest{
+		Datacenter: "dc1",
+		Intentions: desired(),
+		CAS:        planIndex,
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Intention.Reconcile", req, &structs.IntentionReconcileResponse{})
+	require.NotNil(err)
+	require.Contains(err.Error(), "Intentions changed")
+}
+
+// Test that invalid desired intentions are rejected.
+func TestIntentionReconcile_invalid(t *testing.T) {
+	t.Parallel()
+
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	cases := map[string]struct {
+		ixns structs.Intentions
+		err  string
+	}{
+		"duplicate": {
+			structs.Intentions{
+				{SourceName: "web", DestinationName: "db", Action: structs.IntentionActionAllow},
+				{SourceName: "web", DestinationName: "db", Action: structs.IntentionActionDeny},
+			},
+			"Duplicate intention",
+		},
+		"ID": {
+			structs.Intentions{
+				{ID: generateUUID(), SourceName: "web", DestinationName: "db", Action: structs.IntentionActionAllow},
+			},
+			"ID must be empty",
+		},
+		"invalid": {
+			structs.Intentions{
+				{SourceName: "web", DestinationName: "db", Action: "wat"},
+			},
+			"Invalid intention",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := &structs.IntentionReconcileRequest{
+				Datacenter: "dc1",
+				Intentions: tc.ixns,
+			}
+			var resp structs.IntentionReconcileResponse
+			err := msgpackrpc.CallWithCodec(codec, "Intention.Reconcile", req, &resp)
+			require.NotNil(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+
+	_, ixns, err := s1.fsm.State().Intentions(nil)
+	require.Nil(t, err)
+	require.Empty(t, ixns)
+}
+
+// Test that reconciling requires write access to every changed intention.
+func TestIntentionReconcile_acl(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ACLDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLMasterToken = "root"
+		c.ACLDefaultPolicy = "deny"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	// Create an ACL with write permissions for the db intentions.
+	var token string
+	{
+		var rules = `
+service "db" {
+	policy = "deny"
+	intentions = "write"
+}`
+
+		req := structs.ACLRequest{
+			Datacenter: "dc1",
+			Op:         structs.ACLSet,
+			ACL: structs.ACL{
+				Name:  "User token",
+				Type:  structs.ACLTokenTypeClient,
+				Rules: rules,
+			},
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+		require.Nil(msgpackrpc.CallWithCodec(codec, "ACL.Apply", &req, &token))
+	}
+
+	// Create an intention the token can't see.
+	{
+		ixn := structs.IntentionRequest{
+			Datacenter: "dc1",
+			Op:         structs.IntentionOpCreate,
+			Intention: &structs.Intention{
+				SourceName:      "web",
+				DestinationName: "api",
+				Action:          structs.IntentionActionAllow,
+			},
+		}
+		ixn.WriteRequest.Token = "root"
+		var reply string
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Intention.Apply", &ixn, &reply))
+	}
+
+	// The db intentions can be managed and the other one is hidden.
+	req := &structs.IntentionReconcileRequest{
+		Datacenter: "dc1",
+		Intentions: structs.Intentions{
+			{SourceName: "web", DestinationName: "db", Action: structs.IntentionActionAllow},
+		},
+	}
+	req.Token = token
+	var resp structs.IntentionReconcileResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Intention.Reconcile", req, &resp))
+	require.Len(resp.Changes, 1)
+	require.Empty(resp.Unmanaged)
+
+	// Pruning the other intention is denied.
+	req = &structs.IntentionReconcileRequest{
+		Datacenter: "dc1",
+		Intentions: structs.Intentions{
+			{SourceName: "web", DestinationName: "db", Action: structs.IntentionActionAllow},
+		},
+		Prune: true,
+	}
+	req.Token = token
+	err := msgpackrpc.CallWithCodec(codec, "Intention.Reconcile", req, &structs.IntentionReconcileResponse{})
+	require.True(acl.IsErrPermissionDenied(err))
+}
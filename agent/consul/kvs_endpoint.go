@@ -40,7 +40,7 @@ func kvsPreApply(srv *Server, rule acl.Authorizer, op api.KVOp, dirEnt *structs.
 		case api.KVGet, api.KVGetTree:
 			// Filtering for GETs is done on the output side.
 
-		case api.KVCheckSession, api.KVCheckIndex:
+		case api.KVCheckSession, api.KVCheckIndex, api.KVCheckFence:
 			// These could reveal information based on the outcome
 			// of the transaction, and they operate on individual
 			// keys so we check them here.
@@ -191,6 +191,65 @@ func (k *KVS) Get(args *structs.KeyRequest, reply *structs.IndexedDirEntries) er
 		})
 }
 
+// LockInfo is used to describe the lock on a key. The key doesn't have to
+// exist, since a lock-delay can be in effect for a key that was deleted when
+// the session that held it was invalidated.
+func (k *KVS) LockInfo(args *structs.KeyRequest, reply *structs.IndexedKeyLockInfo) error {
+	if done, err := k.srv.forward("KVS.LockInfo", args, args, reply); done {
+		return err
+	}
+
+	aclRule, err := k.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+	if aclRule != nil && !aclRule.KeyRead(args.Key) {
+		return acl.ErrPermissionDenied
+	}
+
+	return k.srv.blockingQuery(
+		&args.QueryOptions,
+		&reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			index, ent, err := state.KVSGet(ws, args.Key)
+			if err != nil {
+				return err
+			}
+
+			// Must provide non-zero index to prevent blocking
+			// Index 1 is impossible anyways (due to Raft internals)
+			if index == 0 {
+				reply.Index = 1
+			} else {
+				reply.Index = index
+			}
+
+			info := &structs.KeyLockInfo{Key: args.Key}
+			if ent != nil {
+				info.Session = ent.Session
+				info.LockIndex = ent.LockIndex
+			}
+			if info.Session != "" {
+				_, session, err := state.SessionGet(ws, info.Session)
+				if err != nil {
+					return err
+				}
+				if session != nil && (aclRule == nil || aclRule.SessionRead(session.Node)) {
+					info.Node = session.Node
+				}
+			}
+
+			// The lock-delay is based on wall-time, so this is only an
+			// estimate when read from a server other than the leader.
+			if expires := state.KVSLockDelay(args.Key); expires.After(time.Now()) {
+				info.LockDelay = time.Until(expires)
+			}
+
+			reply.Info = info
+			return nil
+		})
+}
+
 // List is used to list all keys with a given prefix.
 func (k *KVS) List(args *structs.KeyRequest, reply *structs.IndexedDirEntries) error {
 	if done, err := k.srv.forward("KVS.List", args, args, reply); done {
@@ -2,6 +2,7 @@ package consul
 
 import (
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -826,6 +827,72 @@ func TestKVS_Apply_LockDelay(t *testing.T) {
 	}
 }
 
+func TestKVS_LockInfo(t *testing.T) {
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	state := s1.fsm.State()
+	if err := state.EnsureNode(1, &structs.Node{Node: "foo", Address: "127.0.0.1"}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	session := &structs.Session{
+		ID:        generateUUID(),
+		Node:      "foo",
+		LockDelay: time.Minute,
+	}
+	if err := state.SessionCreate(2, session); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok, err := state.KVSLock(3, &structs.DirEntry{Key: "test", Session: session.ID}); err != nil || !ok {
+		t.Fatalf("err: %v", err)
+	}
+
+	lockInfo := func() *structs.KeyLockInfo {
+		arg := structs.KeyRequest{
+			Datacenter: "dc1",
+			Key:        "test",
+		}
+		var out structs.IndexedKeyLockInfo
+		if err := msgpackrpc.CallWithCodec(codec, "KVS.LockInfo", &arg, &out); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if out.Index == 0 {
+			t.Fatalf("bad: %v", out)
+		}
+		return out.Info
+	}
+
+	// The holder of the lock is shown.
+	info := lockInfo()
+	expected := &structs.KeyLockInfo{
+		Key:       "test",
+		Session:   session.ID,
+		Node:      "foo",
+		LockIndex: 1,
+	}
+	if !reflect.DeepEqual(info, expected) {
+		t.Fatalf("bad: %#v", info)
+	}
+
+	// Once the session is invalidated, the lock-delay is shown.
+	if err := state.SessionDestroy(4, session.ID); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	info = lockInfo()
+	if info.Session != "" || info.Node != "" || info.LockIndex != 1 {
+		t.Fatalf("bad: %#v", info)
+	}
+	if info.LockDelay <= 0 || info.LockDelay > time.Minute {
+		t.Fatalf("bad: %v", info.LockDelay)
+	}
+}
+
 func TestKVS_Issue_1626(t *testing.T) {
 	t.Parallel()
 	dir1, s1 := testServer(t)
@@ -202,3 +202,62 @@ REMOVE:
 	op.srv.logger.Printf("[WARN] consul.operator: Removed Raft peer with id %q", args.ID)
 	return nil
 }
+
+// RaftTransferLeader is used to hand leadership off to another voting server,
+// which is useful to do gracefully before taking the current leader down for
+// maintenance. If no ID is given then Raft will pick the most up-to-date
+// voter. The reply argument is not used, but is required to fulfill the RPC
+// interface.
+func (op *Operator) RaftTransferLeader(args *structs.RaftTransferLeaderRequest, reply *struct{}) error {
+	if done, err := op.srv.forward("Operator.RaftTransferLeader", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator write access.
+	rule, err := op.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+	if rule != nil && !rule.OperatorWrite() {
+		return acl.ErrPermissionDenied
+	}
+
+	var future raft.Future
+	if args.ID == "" {
+		future = op.srv.raft.LeadershipTransfer()
+	} else {
+		// Look up the target so we can give a helpful error if it's not a
+		// voter, or if it's this server, rather than leaving that to Raft.
+		configFuture := op.srv.raft.GetConfiguration()
+		if err := configFuture.Error(); err != nil {
+			return err
+		}
+
+		var target *raft.Server
+		for _, s := range configFuture.Configuration().Servers {
+			if s.ID == args.ID {
+				target = &s
+				break
+			}
+		}
+		if target == nil {
+			return fmt.Errorf("id %q was not found in the Raft configuration",
+				args.ID)
+		}
+		if target.Suffrage != raft.Voter {
+			return fmt.Errorf("server with id %q is not a voter", args.ID)
+		}
+		if target.Address == op.srv.raft.Leader() {
+			return fmt.Errorf("server with id %q is already the leader", args.ID)
+		}
+
+		future = op.srv.raft.LeadershipTransferToServer(target.ID, target.Address)
+	}
+	if err := future.Error(); err != nil {
+		op.srv.logger.Printf("[WARN] consul.operator: Failed to transfer Raft leadership: %v", err)
+		return err
+	}
+
+	op.srv.logger.Printf("[INFO] consul.operator: Transferred Raft leadership")
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/sdk/freeport"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/consul/testrpc"
 	"github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/hashicorp/raft"
@@ -370,3 +371,148 @@ func TestOperator_RaftRemovePeerByID_ACLDeny(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 }
+
+func TestOperator_RaftTransferLeader(t *testing.T) {
+	t.Parallel()
+	conf := func(c *Config) {
+		c.Bootstrap = false
+		c.BootstrapExpect = 3
+		c.RaftConfig.ProtocolVersion = 3
+	}
+	dir1, s1 := testServerWithConfig(t, conf)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	dir2, s2 := testServerWithConfig(t, conf)
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+
+	dir3, s3 := testServerWithConfig(t, conf)
+	defer os.RemoveAll(dir3)
+	defer s3.Shutdown()
+
+	servers := []*Server{s1, s2, s3}
+	joinLAN(t, s2, s1)
+	joinLAN(t, s3, s1)
+	for _, s := range servers {
+		testrpc.WaitForLeader(t, s.RPC, "dc1")
+		retry.Run(t, func(r *retry.R) { r.Check(wantPeers(s, 3)) })
+	}
+
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	// Try to transfer to a server that's not there.
+	arg := structs.RaftTransferLeaderRequest{
+		Datacenter: "dc1",
+		ID:         raft.ServerID("e35bde83-4e9c-434f-a6ef-453f44ee21ea"),
+	}
+	var reply struct{}
+	err := msgpackrpc.CallWithCodec(codec, "Operator.RaftTransferLeader", &arg, &reply)
+	if err == nil || !strings.Contains(err.Error(), "not found in the Raft configuration") {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Pick a follower and hand leadership to it.
+	var leader, target *Server
+	for _, s := range servers {
+		if s.IsLeader() {
+			leader = s
+		} else if target == nil {
+			target = s
+		}
+	}
+	if leader == nil || target == nil {
+		t.Fatalf("bad: leader=%v target=%v", leader, target)
+	}
+
+	arg.ID = raft.ServerID(target.config.NodeID)
+	if err := msgpackrpc.CallWithCodec(codec, "Operator.RaftTransferLeader", &arg, &reply); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	retry.Run(t, func(r *retry.R) {
+		if !target.IsLeader() {
+			r.Fatal("leadership was not transferred")
+		}
+	})
+
+	// Asking for the current leader again should be rejected.
+	err = msgpackrpc.CallWithCodec(codec, "Operator.RaftTransferLeader", &arg, &reply)
+	if err == nil || !strings.Contains(err.Error(), "is already the leader") {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Without an ID, Raft picks the new leader.
+	arg.ID = ""
+	if err := msgpackrpc.CallWithCodec(codec, "Operator.RaftTransferLeader", &arg, &reply); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	retry.Run(t, func(r *retry.R) {
+		if target.IsLeader() {
+			r.Fatal("leadership was not transferred")
+		}
+		for _, s := range servers {
+			if s.IsLeader() {
+				return
+			}
+		}
+		r.Fatal("no leader")
+	})
+}
+
+func TestOperator_RaftTransferLeader_ACLDeny(t *testing.T) {
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ACLDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLMasterToken = "root"
+		c.ACLDefaultPolicy = "deny"
+		c.RaftConfig.ProtocolVersion = 3
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	// Make a request with no token to make sure it gets denied.
+	arg := structs.RaftTransferLeaderRequest{
+		Datacenter: "dc1",
+	}
+	var reply struct{}
+	err := msgpackrpc.CallWithCodec(codec, "Operator.RaftTransferLeader", &arg, &reply)
+	if !acl.IsErrPermissionDenied(err) {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Create an ACL with operator write permissions.
+	var token string
+	{
+		var rules = `
+                    operator = "write"
+                `
+
+		req := structs.ACLRequest{
+			Datacenter: "dc1",
+			Op:         structs.ACLSet,
+			ACL: structs.ACL{
+				Name:  "User token",
+				Type:  structs.ACLTokenTypeClient,
+				Rules: rules,
+			},
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+		if err := msgpackrpc.CallWithCodec(codec, "ACL.Apply", &req, &token); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+
+	// Now it should kick back because there's nobody to transfer to, which
+	// means it tried to do the operation.
+	arg.Token = token
+	err = msgpackrpc.CallWithCodec(codec, "Operator.RaftTransferLeader", &arg, &reply)
+	if err == nil || acl.IsErrPermissionDenied(err) {
+		t.Fatalf("err: %v", err)
+	}
+}
@@ -181,6 +181,18 @@ func parseService(svc *structs.ServiceQuery) error {
 		return fmt.Errorf("Bad NearestN '%d', must be >= 0", svc.Failover.NearestN)
 	}
 
+	// MinHealthy can be 0 which means "fail over when there are none".
+	if svc.Failover.MinHealthy < 0 {
+		return fmt.Errorf("Bad MinHealthy '%d', must be >= 0", svc.Failover.MinHealthy)
+	}
+
+	// Failover targets must name a datacenter, the rest is optional.
+	for i, target := range svc.Failover.Targets {
+		if target.Datacenter == "" {
+			return fmt.Errorf("Failover target %d must provide a Datacenter", i)
+		}
+	}
+
 	// Make sure the metadata filters are valid
 	if err := structs.ValidateMetadata(svc.NodeMeta, true); err != nil {
 		return err
@@ -306,23 +318,35 @@ func (p *PreparedQuery) Explain(args *structs.PreparedQueryExecuteRequest,
 		return ErrQueryNotFound
 	}
 
+	filtered, err := p.filterExplainACL(args.Token, query)
+	if err != nil {
+		return err
+	}
+
+	reply.Query = *filtered
+	return nil
+}
+
+// filterExplainACL runs the standard ACL filter on a query that is about to
+// be explained, which redacts its token, and returns a permission denied error
+// if the query was filtered out.
+func (p *PreparedQuery) filterExplainACL(token string, query *structs.PreparedQuery) (*structs.PreparedQuery, error) {
 	// Place the query into a list so we can run the standard ACL filter on
 	// it.
 	queries := &structs.IndexedPreparedQueries{
 		Queries: structs.PreparedQueries{query},
 	}
-	if err := p.srv.filterACL(args.Token, queries); err != nil {
-		return err
+	if err := p.srv.filterACL(token, queries); err != nil {
+		return nil, err
 	}
 
 	// If the query was filtered out, return an error.
 	if len(queries.Queries) == 0 {
 		p.srv.logger.Printf("[WARN] consul.prepared_query: Explain on prepared query '%s' denied due to ACLs", query.ID)
-		return acl.ErrPermissionDenied
+		return nil, acl.ErrPermissionDenied
 	}
 
-	reply.Query = *(queries.Queries[0])
-	return nil
+	return queries.Queries[0], nil
 }
 
 // Execute runs a prepared query and returns the results. This will perform the
@@ -353,8 +377,28 @@ func (p *PreparedQuery) Execute(args *structs.PreparedQueryExecuteRequest,
 		return ErrQueryNotFound
 	}
 
+	// Explaining the execution shows the query, so it needs the same ACLs
+	// as explaining the query itself.
+	var explain *structs.QueryExecuteExplain
+	var local *structs.QueryDatacenterExplain
+	if args.Explain {
+		filtered, err := p.filterExplainACL(args.Token, query)
+		if err != nil {
+			return err
+		}
+
+		local = &structs.QueryDatacenterExplain{
+			Datacenter: p.srv.config.Datacenter,
+			Reason:     "local",
+		}
+		explain = &structs.QueryExecuteExplain{
+			Query:       *filtered,
+			Datacenters: []*structs.QueryDatacenterExplain{local},
+		}
+	}
+
 	// Execute the query for the local DC.
-	if err := p.execute(query, reply, args.Connect); err != nil {
+	if err := p.execute(query, reply, args.Connect, local); err != nil {
 		return err
 	}
 
@@ -364,18 +408,20 @@ func (p *PreparedQuery) Execute(args *structs.PreparedQueryExecuteRequest,
 	if query.Token != "" {
 		token = query.Token
 	}
+	before := len(reply.Nodes)
 	if err := p.srv.filterACL(token, &reply.Nodes); err != nil {
 		return err
 	}
+	explainFilter(local, "acl", before, len(reply.Nodes))
 
 	// TODO (slackpad) We could add a special case here that will avoid the
 	// fail over if we filtered everything due to ACLs. This seems like it
 	// might not be worth the code complexity and behavior differences,
 	// though, since this is essentially a misconfiguration.
 
-	// Shuffle the results in case coordinates are not available if they
-	// requested an RTT sort.
-	reply.Nodes.Shuffle()
+	// Shuffle the results by their weights in case coordinates are not
+	// available if they requested an RTT sort.
+	reply.Nodes.ShuffleByWeight()
 
 	// Build the query source. This can be provided by the client, or by
 	// the prepared query. Client-specified takes priority.
@@ -417,6 +463,10 @@ func (p *PreparedQuery) Execute(args *structs.PreparedQueryExecuteRequest,
 		}
 	}
 
+	if explain != nil {
+		explain.Near = qs.Node
+	}
+
 	// Perform the distance sort
 	err = p.srv.sortNodesByDistanceFrom(qs, reply.Nodes)
 	if err != nil {
@@ -442,13 +492,15 @@ func (p *PreparedQuery) Execute(args *structs.PreparedQueryExecuteRequest,
 
 	// Apply the limit if given.
 	if args.Limit > 0 && len(reply.Nodes) > args.Limit {
+		explainFilter(local, "limit", len(reply.Nodes), args.Limit)
 		reply.Nodes = reply.Nodes[:args.Limit]
 	}
+	reply.Explain = explain
 
-	// In the happy path where we found some healthy nodes we go with that
+	// In the happy path where we found enough healthy nodes we go with that
 	// and bail out. Otherwise, we fail over and try remote DCs, as allowed
 	// by the query setup.
-	if len(reply.Nodes) == 0 {
+	if len(reply.Nodes) < failoverThreshold(query, args.Limit) {
 		wrapper := &queryServerWrapper{p.srv}
 		if err := queryFailover(wrapper, query, args, reply); err != nil {
 			return err
@@ -478,8 +530,17 @@ func (p *PreparedQuery) ExecuteRemote(args *structs.PreparedQueryExecuteRemoteRe
 		}
 	}
 
+	// The explanation for this DC is sent back to the originating DC,
+	// which already checked the ACLs for it.
+	var explain *structs.QueryDatacenterExplain
+	if args.Explain {
+		explain = &structs.QueryDatacenterExplain{
+			Datacenter: p.srv.config.Datacenter,
+		}
+	}
+
 	// Run the query locally to see what we can find.
-	if err := p.execute(&args.Query, reply, args.Connect); err != nil {
+	if err := p.execute(&args.Query, reply, args.Connect, explain); err != nil {
 		return err
 	}
 
@@ -489,28 +550,39 @@ func (p *PreparedQuery) ExecuteRemote(args *structs.PreparedQueryExecuteRemoteRe
 	if args.Query.Token != "" {
 		token = args.Query.Token
 	}
+	before := len(reply.Nodes)
 	if err := p.srv.filterACL(token, &reply.Nodes); err != nil {
 		return err
 	}
+	explainFilter(explain, "acl", before, len(reply.Nodes))
 
 	// We don't bother trying to do an RTT sort here since we are by
-	// definition in another DC. We just shuffle to make sure that we
-	// balance the load across the results.
-	reply.Nodes.Shuffle()
+	// definition in another DC. We just shuffle by weight to make sure that
+	// we balance the load across the results.
+	reply.Nodes.ShuffleByWeight()
 
 	// Apply the limit if given.
 	if args.Limit > 0 && len(reply.Nodes) > args.Limit {
+		explainFilter(explain, "limit", len(reply.Nodes), args.Limit)
 		reply.Nodes = reply.Nodes[:args.Limit]
 	}
 
+	if explain != nil {
+		reply.Explain = &structs.QueryExecuteExplain{
+			Datacenters: []*structs.QueryDatacenterExplain{explain},
+		}
+	}
+
 	return nil
 }
 
 // execute runs a prepared query in the local DC without any failover. We don't
 // apply any sorting options or ACL checks at this level - it should be done up above.
+// If explain isn't nil, the number of nodes each filter removes is recorded
+// in it.
 func (p *PreparedQuery) execute(query *structs.PreparedQuery,
 	reply *structs.PreparedQueryExecuteResponse,
-	forceConnect bool) error {
+	forceConnect bool, explain *structs.QueryDatacenterExplain) error {
 	state := p.srv.fsm.State()
 
 	// If we're requesting Connect-capable services, then switch the
@@ -525,23 +597,50 @@ func (p *PreparedQuery) execute(query *structs.PreparedQuery,
 		return err
 	}
 
+	// Count how many nodes are only kept because their failing checks are
+	// ignored. The health filter modifies the slice, so use a copy.
+	notIgnored := -1
+	if explain != nil {
+		explain.Service = query.Service.Service
+		explain.Tags = query.Service.Tags
+		explain.Instances = len(nodes)
+		explain.Results = len(nodes)
+
+		if len(query.Service.IgnoreCheckIDs) > 0 {
+			clone := make(structs.CheckServiceNodes, len(nodes))
+			copy(clone, nodes)
+			notIgnored = len(clone.Filter(query.Service.OnlyPassing))
+		}
+	}
+
 	// Filter out any unhealthy nodes.
+	before := len(nodes)
 	nodes = nodes.FilterIgnore(query.Service.OnlyPassing,
 		query.Service.IgnoreCheckIDs)
+	explainFilter(explain, "health", before, len(nodes))
+	if notIgnored >= 0 {
+		explain.IgnoredChecks = len(nodes) - notIgnored
+	}
 
 	// Apply the node metadata filters, if any.
 	if len(query.Service.NodeMeta) > 0 {
+		before := len(nodes)
 		nodes = nodeMetaFilter(query.Service.NodeMeta, nodes)
+		explainFilter(explain, "node-meta", before, len(nodes))
 	}
 
 	// Apply the service metadata filters, if any.
 	if len(query.Service.ServiceMeta) > 0 {
+		before := len(nodes)
 		nodes = serviceMetaFilter(query.Service.ServiceMeta, nodes)
+		explainFilter(explain, "service-meta", before, len(nodes))
 	}
 
 	// Apply the tag filters, if any.
 	if len(query.Service.Tags) > 0 {
+		before := len(nodes)
 		nodes = tagFilter(query.Service.Tags, nodes)
+		explainFilter(explain, "tags", before, len(nodes))
 	}
 
 	// Capture the nodes and pass the DNS information through to the reply.
@@ -555,6 +654,19 @@ func (p *PreparedQuery) execute(query *structs.PreparedQuery,
 	return nil
 }
 
+// explainFilter records the number of nodes a filter removed in the
+// explanation of a query, if we are explaining it.
+func explainFilter(explain *structs.QueryDatacenterExplain, filter string, before, after int) {
+	if explain == nil {
+		return
+	}
+	explain.Filters = append(explain.Filters, structs.QueryFilterExplain{
+		Filter:  filter,
+		Removed: before - after,
+	})
+	explain.Results = after
+}
+
 // tagFilter returns a list of nodes who satisfy the given tags. Nodes must have
 // ALL the given tags, and NONE of the forbidden tags (prefixed with !). Note
 // for performance this modifies the original slice.
@@ -635,6 +747,7 @@ func serviceMetaFilter(filters map[string]string, nodes structs.CheckServiceNode
 type queryServer interface {
 	GetLogger() *log.Logger
 	GetOtherDatacentersByDistance() ([]string, error)
+	GetDatacenterRTTs() (map[string]time.Duration, error)
 	ForwardDC(method, dc string, args interface{}, reply interface{}) error
 }
 
@@ -667,13 +780,41 @@ func (q *queryServerWrapper) GetOtherDatacentersByDistance() ([]string, error) {
 	return result, nil
 }
 
+// GetDatacenterRTTs calls into the router to get the estimated RTTs to the
+// known DCs.
+func (q *queryServerWrapper) GetDatacenterRTTs() (map[string]time.Duration, error) {
+	return q.srv.router.GetDatacenterRTTs()
+}
+
 // ForwardDC calls into the server's RPC forwarder.
 func (q *queryServerWrapper) ForwardDC(method, dc string, args interface{}, reply interface{}) error {
 	return q.srv.forwardDC(method, dc, args, reply)
 }
 
+// failoverThreshold returns the number of healthy nodes below which a query
+// fails over. The limit caps it since we never return more nodes than that.
+func failoverThreshold(query *structs.PreparedQuery, limit int) int {
+	threshold := query.Service.Failover.MinHealthy
+	if threshold < 1 {
+		threshold = 1
+	}
+	if limit > 0 && limit < threshold {
+		threshold = limit
+	}
+	return threshold
+}
+
+// failoverTarget is a datacenter to try during failover, along with the query
+// to run there and the failover option that selected it.
+type failoverTarget struct {
+	dc     string
+	query  *structs.PreparedQuery
+	reason string
+}
+
 // queryFailover runs an algorithm to determine which DCs to try and then calls
-// them to try to locate alternative services.
+// them to try to locate alternative services. The reply holds the local
+// results when this is called, which are kept if no DC has more nodes.
 func queryFailover(q queryServer, query *structs.PreparedQuery,
 	args *structs.PreparedQueryExecuteRequest,
 	reply *structs.PreparedQueryExecuteResponse) error {
@@ -693,7 +834,7 @@ func queryFailover(q queryServer, query *structs.PreparedQuery,
 
 	// Build a candidate list of DCs to try, starting with the nearest N
 	// from RTTs.
-	var dcs []string
+	var targets []failoverTarget
 	index := make(map[string]struct{})
 	if query.Service.Failover.NearestN > 0 {
 		for i, dc := range nearest {
@@ -701,7 +842,7 @@ func queryFailover(q queryServer, query *structs.PreparedQuery,
 				break
 			}
 
-			dcs = append(dcs, dc)
+			targets = append(targets, failoverTarget{dc, query, "nearest"})
 			index[dc] = struct{}{}
 		}
 	}
@@ -718,49 +859,131 @@ func queryFailover(q queryServer, query *structs.PreparedQuery,
 		// This will make sure we don't re-try something that fails
 		// from the NearestN list.
 		if _, ok := index[dc]; !ok {
-			dcs = append(dcs, dc)
+			targets = append(targets, failoverTarget{dc, query, "datacenters"})
+			index[dc] = struct{}{}
 		}
 	}
 
+	// Finally add the failover targets, which can change what we query in
+	// their DC. The ones that don't are skipped like above if we already
+	// try their DC.
+	for _, target := range query.Service.Failover.Targets {
+		if _, ok := known[target.Datacenter]; !ok {
+			q.GetLogger().Printf("[DEBUG] consul.prepared_query: Skipping unknown datacenter '%s' in prepared query", target.Datacenter)
+			continue
+		}
+
+		if target.Service == "" && len(target.Tags) == 0 {
+			if _, ok := index[target.Datacenter]; !ok {
+				targets = append(targets, failoverTarget{target.Datacenter, query, "target"})
+				index[target.Datacenter] = struct{}{}
+			}
+			continue
+		}
+
+		remoteQuery := *query
+		if target.Service != "" {
+			remoteQuery.Service.Service = target.Service
+		}
+		if len(target.Tags) > 0 {
+			remoteQuery.Service.Tags = target.Tags
+		}
+		targets = append(targets, failoverTarget{target.Datacenter, &remoteQuery, "target"})
+	}
+
+	// The RTTs are only needed to explain the order of the DCs.
+	explain := reply.Explain
+	var rtts map[string]time.Duration
+	if explain != nil && len(targets) > 0 {
+		if rtts, err = q.GetDatacenterRTTs(); err != nil {
+			return err
+		}
+	}
+
+	// Keep the results with the most nodes in case no DC has enough of
+	// them, starting with the local ones.
+	threshold := failoverThreshold(query, args.Limit)
+	best := *reply
+
 	// Now try the selected DCs in priority order.
 	failovers := 0
-	for _, dc := range dcs {
+	for _, target := range targets {
 		// This keeps track of how many iterations we actually run.
 		failovers++
 
 		// Be super paranoid and set the nodes slice to nil since it's
-		// the same slice we used before. We know there's nothing in
-		// there, but the underlying msgpack library has a policy of
-		// updating the slice when it's non-nil, and that feels dirty.
-		// Let's just set it to nil so there's no way to communicate
-		// through this slice across successive RPC calls.
+		// the same slice we used before. The underlying msgpack library
+		// has a policy of updating the slice when it's non-nil, and that
+		// feels dirty. Let's just set it to nil so there's no way to
+		// communicate through this slice across successive RPC calls.
+		// This also keeps the slice of the best results intact.
 		reply.Nodes = nil
+		reply.Explain = nil
 
 		// Note that we pass along the limit since it can be applied
 		// remotely to save bandwidth. We also pass along the consistency
 		// mode information and token we were given, so that applies to
 		// the remote query as well.
 		remote := &structs.PreparedQueryExecuteRemoteRequest{
-			Datacenter:   dc,
-			Query:        *query,
+			Datacenter:   target.dc,
+			Query:        *target.query,
 			Limit:        args.Limit,
 			QueryOptions: args.QueryOptions,
 			Connect:      args.Connect,
+			Explain:      explain != nil,
 		}
-		if err := q.ForwardDC("PreparedQuery.ExecuteRemote", dc, remote, reply); err != nil {
-			q.GetLogger().Printf("[WARN] consul.prepared_query: Failed querying for service '%s' in datacenter '%s': %s", query.Service.Service, dc, err)
+		err := q.ForwardDC("PreparedQuery.ExecuteRemote", target.dc, remote, reply)
+		if explain != nil {
+			explain.Datacenters = append(explain.Datacenters,
+				explainFailover(target, rtts, reply, err))
+		}
+		if err != nil {
+			q.GetLogger().Printf("[WARN] consul.prepared_query: Failed querying for service '%s' in datacenter '%s': %s", target.query.Service.Service, target.dc, err)
 			continue
 		}
 
-		// We can stop if we found some nodes.
-		if len(reply.Nodes) > 0 {
+		// We can stop if we found enough nodes.
+		if len(reply.Nodes) >= threshold {
 			break
 		}
+		if len(reply.Nodes) > len(best.Nodes) {
+			best = *reply
+		}
+	}
+	if len(best.Nodes) > len(reply.Nodes) {
+		*reply = best
 	}
 
-	// Set this at the end because the response from the remote doesn't have
+	// Set these at the end because the response from the remote doesn't have
 	// this information.
 	reply.Failovers = failovers
+	reply.Explain = explain
 
 	return nil
 }
+
+// explainFailover returns the explanation of the query in a failover DC,
+// based on the explanation the remote DC returned, if any.
+func explainFailover(target failoverTarget, rtts map[string]time.Duration,
+	reply *structs.PreparedQueryExecuteResponse, err error) *structs.QueryDatacenterExplain {
+	explain := &structs.QueryDatacenterExplain{
+		Service: target.query.Service.Service,
+		Tags:    target.query.Service.Tags,
+	}
+	if err != nil {
+		explain.Error = err.Error()
+	} else if reply.Explain != nil && len(reply.Explain.Datacenters) == 1 {
+		explain = reply.Explain.Datacenters[0]
+	} else {
+		// The remote servers don't support explaining yet, so all we know
+		// is the results.
+		explain.Results = len(reply.Nodes)
+	}
+
+	explain.Datacenter = target.dc
+	explain.Reason = target.reason
+	if rtt, ok := rtts[target.dc]; ok {
+		explain.RTT = rtt.String()
+	}
+	return explain
+}
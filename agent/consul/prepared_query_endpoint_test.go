@@ -594,6 +594,28 @@ func TestPreparedQuery_parseQuery(t *testing.T) {
 			t.Fatalf("err: %v", err)
 		}
 
+		query.Service.Failover.MinHealthy = -1
+		err = parseQuery(query, version8)
+		if err == nil || !strings.Contains(err.Error(), "Bad MinHealthy") {
+			t.Fatalf("bad: %v", err)
+		}
+
+		query.Service.Failover.MinHealthy = 2
+		if err := parseQuery(query, version8); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		query.Service.Failover.Targets = []structs.QueryFailoverTarget{{Service: "bar"}}
+		err = parseQuery(query, version8)
+		if err == nil || !strings.Contains(err.Error(), "must provide a Datacenter") {
+			t.Fatalf("bad: %v", err)
+		}
+
+		query.Service.Failover.Targets[0].Datacenter = "dc2"
+		if err := parseQuery(query, version8); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
 		query.DNS.TTL = "two fortnights"
 		err = parseQuery(query, version8)
 		if err == nil || !strings.Contains(err.Error(), "Bad DNS TTL") {
@@ -2562,6 +2584,132 @@ func TestPreparedQuery_Execute(t *testing.T) {
 	}
 }
 
+func TestPreparedQuery_Execute_explain(t *testing.T) {
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec1 := rpcClient(t, s1)
+	defer codec1.Close()
+
+	dir2, s2 := testServerDC(t, "dc2")
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+	codec2 := rpcClient(t, s2)
+	defer codec2.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+	testrpc.WaitForLeader(t, s2.RPC, "dc2")
+
+	joinWAN(t, s2, s1)
+	retry.Run(t, func(r *retry.R) {
+		if got, want := len(s1.WANMembers()), 2; got != want {
+			r.Fatalf("got %d WAN members want %d", got, want)
+		}
+	})
+
+	// The instances in dc1 all have the wrong tag. The ones in dc2 have the
+	// right one, but one of them has a failing check the query ignores.
+	register := func(codec rpc.ClientCodec, dc, node, tag string, check *structs.HealthCheck) {
+		req := structs.RegisterRequest{
+			Datacenter: dc,
+			Node:       node,
+			Address:    "127.0.0.1",
+			Service: &structs.NodeService{
+				Service: "foo",
+				Port:    8000,
+				Tags:    []string{tag},
+			},
+			Check: check,
+		}
+		var reply struct{}
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.Register", &req, &reply))
+	}
+	for i := 0; i < 3; i++ {
+		register(codec1, "dc1", fmt.Sprintf("node%d", i), "secondary", nil)
+	}
+	register(codec2, "dc2", "node0", "primary", nil)
+	register(codec2, "dc2", "node1", "primary", &structs.HealthCheck{
+		CheckID:   "flaky",
+		Name:      "flaky",
+		Status:    api.HealthCritical,
+		ServiceID: "foo",
+	})
+
+	query := structs.PreparedQueryRequest{
+		Datacenter: "dc1",
+		Op:         structs.PreparedQueryCreate,
+		Query: &structs.PreparedQuery{
+			Name: "test",
+			Service: structs.ServiceQuery{
+				Service:        "foo",
+				Tags:           []string{"primary"},
+				IgnoreCheckIDs: []types.CheckID{"flaky"},
+				Failover: structs.QueryDatacenterOptions{
+					Datacenters: []string{"dc2"},
+				},
+			},
+		},
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec1, "PreparedQuery.Apply", &query, &query.Query.ID))
+
+	// Without explain there's no explanation.
+	{
+		req := structs.PreparedQueryExecuteRequest{
+			Datacenter:    "dc1",
+			QueryIDOrName: "test",
+		}
+		var reply structs.PreparedQueryExecuteResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec1, "PreparedQuery.Execute", &req, &reply))
+		require.Len(t, reply.Nodes, 2)
+		require.Nil(t, reply.Explain)
+	}
+
+	req := structs.PreparedQueryExecuteRequest{
+		Datacenter:    "dc1",
+		QueryIDOrName: "test",
+		Limit:         1,
+		Explain:       true,
+	}
+	var reply structs.PreparedQueryExecuteResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec1, "PreparedQuery.Execute", &req, &reply))
+	require.Len(t, reply.Nodes, 1)
+	require.Equal(t, "dc2", reply.Datacenter)
+
+	explain := reply.Explain
+	require.NotNil(t, explain)
+	require.Equal(t, query.Query.ID, explain.Query.ID)
+	require.Equal(t, "foo", explain.Query.Service.Service)
+	require.Len(t, explain.Datacenters, 2)
+
+	local := explain.Datacenters[0]
+	require.Equal(t, "dc1", local.Datacenter)
+	require.Equal(t, "local", local.Reason)
+	require.Empty(t, local.RTT)
+	require.Equal(t, 3, local.Instances)
+	require.Equal(t, []structs.QueryFilterExplain{
+		{Filter: "health", Removed: 0},
+		{Filter: "tags", Removed: 3},
+		{Filter: "acl", Removed: 0},
+	}, local.Filters)
+	require.Equal(t, 0, local.Results)
+
+	remote := explain.Datacenters[1]
+	require.Equal(t, "dc2", remote.Datacenter)
+	require.Equal(t, "datacenters", remote.Reason)
+	require.Equal(t, "foo", remote.Service)
+	require.Equal(t, []string{"primary"}, remote.Tags)
+	require.Equal(t, 2, remote.Instances)
+	require.Equal(t, 1, remote.IgnoredChecks)
+	require.Equal(t, []structs.QueryFilterExplain{
+		{Filter: "health", Removed: 0},
+		{Filter: "tags", Removed: 0},
+		{Filter: "acl", Removed: 0},
+		{Filter: "limit", Removed: 1},
+	}, remote.Filters)
+	require.Equal(t, 1, remote.Results)
+}
+
 func TestPreparedQuery_Execute_ForwardLeader(t *testing.T) {
 	t.Parallel()
 	dir1, s1 := testServer(t)
@@ -2985,6 +3133,7 @@ func TestPreparedQuery_Wrapper(t *testing.T) {
 type mockQueryServer struct {
 	Datacenters      []string
 	DatacentersError error
+	RTTs             map[string]time.Duration
 	QueryLog         []string
 	QueryFn          func(dc string, args interface{}, reply interface{}) error
 	Logger           *log.Logger
@@ -3007,6 +3156,10 @@ func (m *mockQueryServer) GetOtherDatacentersByDistance() ([]string, error) {
 	return m.Datacenters, m.DatacentersError
 }
 
+func (m *mockQueryServer) GetDatacenterRTTs() (map[string]time.Duration, error) {
+	return m.RTTs, nil
+}
+
 func (m *mockQueryServer) ForwardDC(method, dc string, args interface{}, reply interface{}) error {
 	m.QueryLog = append(m.QueryLog, fmt.Sprintf("%s:%s", dc, method))
 	if ret, ok := reply.(*structs.PreparedQueryExecuteResponse); ok {
@@ -3362,3 +3515,190 @@ func TestPreparedQuery_queryFailover(t *testing.T) {
 		}
 	}
 }
+
+func TestPreparedQuery_queryFailover_targets(t *testing.T) {
+	t.Parallel()
+	query := &structs.PreparedQuery{
+		Name: "test",
+		Service: structs.ServiceQuery{
+			Service: "db",
+			Tags:    []string{"primary"},
+			Failover: structs.QueryDatacenterOptions{
+				Datacenters: []string{"dc2"},
+				Targets: []structs.QueryFailoverTarget{
+					// Same as the Datacenters entry, so it's skipped.
+					{Datacenter: "dc2"},
+					{Datacenter: "dc2", Service: "db-replica", Tags: []string{"replica"}},
+					{Datacenter: "xxx", Service: "db"},
+					{Datacenter: "dc3"},
+				},
+			},
+		},
+	}
+
+	var queried []string
+	mock := &mockQueryServer{
+		Datacenters: []string{"dc2", "dc3"},
+		QueryFn: func(dc string, args interface{}, reply interface{}) error {
+			remote := args.(*structs.PreparedQueryExecuteRemoteRequest)
+			queried = append(queried, fmt.Sprintf("%s:%s:%s", dc,
+				remote.Query.Service.Service, strings.Join(remote.Query.Service.Tags, ",")))
+			ret := reply.(*structs.PreparedQueryExecuteResponse)
+			if remote.Query.Service.Service == "db-replica" {
+				ret.Nodes = structs.CheckServiceNodes{
+					{Node: &structs.Node{Node: "replica1"}},
+				}
+			}
+			return nil
+		},
+	}
+
+	var reply structs.PreparedQueryExecuteResponse
+	require.NoError(t, queryFailover(mock, query, &structs.PreparedQueryExecuteRequest{}, &reply))
+	require.Equal(t, []string{"dc2:db:primary", "dc2:db-replica:replica"}, queried)
+	require.Equal(t, "dc2", reply.Datacenter)
+	require.Equal(t, 2, reply.Failovers)
+	require.Len(t, reply.Nodes, 1)
+	require.Equal(t, "replica1", reply.Nodes[0].Node.Node)
+	require.Contains(t, mock.LogBuffer.String(), "Skipping unknown datacenter 'xxx'")
+}
+
+func TestPreparedQuery_queryFailover_minHealthy(t *testing.T) {
+	t.Parallel()
+	nodes := func(dc string, n int) structs.CheckServiceNodes {
+		var nodes structs.CheckServiceNodes
+		for i := 0; i < n; i++ {
+			nodes = append(nodes, structs.CheckServiceNode{
+				Node: &structs.Node{Node: fmt.Sprintf("%s-node%d", dc, i)},
+			})
+		}
+		return nodes
+	}
+
+	run := func(minHealthy, limit int, local int, remote map[string]int) *structs.PreparedQueryExecuteResponse {
+		query := &structs.PreparedQuery{
+			Name: "test",
+			Service: structs.ServiceQuery{
+				Service: "db",
+				Failover: structs.QueryDatacenterOptions{
+					Datacenters: []string{"dc2", "dc3"},
+					MinHealthy:  minHealthy,
+				},
+			},
+		}
+		mock := &mockQueryServer{
+			Datacenters: []string{"dc2", "dc3"},
+			QueryFn: func(dc string, args interface{}, reply interface{}) error {
+				ret := reply.(*structs.PreparedQueryExecuteResponse)
+				ret.Nodes = nodes(dc, remote[dc])
+				return nil
+			},
+		}
+		reply := &structs.PreparedQueryExecuteResponse{
+			Datacenter: "dc1",
+			Nodes:      nodes("dc1", local),
+		}
+		args := &structs.PreparedQueryExecuteRequest{Limit: limit}
+		require.NoError(t, queryFailover(mock, query, args, reply))
+		return reply
+	}
+
+	// The first DC with enough nodes wins.
+	reply := run(3, 0, 1, map[string]int{"dc2": 2, "dc3": 3})
+	require.Equal(t, "dc3", reply.Datacenter)
+	require.Len(t, reply.Nodes, 3)
+	require.Equal(t, 2, reply.Failovers)
+
+	// If no DC has enough, the one with the most nodes wins.
+	reply = run(5, 0, 1, map[string]int{"dc2": 3, "dc3": 2})
+	require.Equal(t, "dc2", reply.Datacenter)
+	require.Len(t, reply.Nodes, 3)
+	require.Equal(t, 2, reply.Failovers)
+
+	// Including the local one.
+	reply = run(5, 0, 2, map[string]int{"dc2": 1, "dc3": 0})
+	require.Equal(t, "dc1", reply.Datacenter)
+	require.Equal(t, "dc1-node0", reply.Nodes[0].Node.Node)
+	require.Len(t, reply.Nodes, 2)
+
+	// The limit caps the threshold.
+	reply = run(5, 2, 0, map[string]int{"dc2": 2, "dc3": 5})
+	require.Equal(t, "dc2", reply.Datacenter)
+	require.Equal(t, 1, reply.Failovers)
+
+	require.Equal(t, 1, failoverThreshold(&structs.PreparedQuery{}, 0))
+	require.Equal(t, 2, failoverThreshold(&structs.PreparedQuery{
+		Service: structs.ServiceQuery{
+			Failover: structs.QueryDatacenterOptions{MinHealthy: 5},
+		},
+	}, 2))
+}
+
+func TestPreparedQuery_queryFailover_explain(t *testing.T) {
+	t.Parallel()
+	query := &structs.PreparedQuery{
+		Name: "test",
+		Service: structs.ServiceQuery{
+			Service: "db",
+			Failover: structs.QueryDatacenterOptions{
+				NearestN: 1,
+				Targets: []structs.QueryFailoverTarget{
+					{Datacenter: "dc3", Service: "db-replica"},
+					{Datacenter: "dc4"},
+				},
+			},
+		},
+	}
+
+	mock := &mockQueryServer{
+		Datacenters: []string{"dc2", "dc3", "dc4"},
+		RTTs: map[string]time.Duration{
+			"dc2": 10 * time.Millisecond,
+			"dc3": 20 * time.Millisecond,
+		},
+		QueryFn: func(dc string, args interface{}, reply interface{}) error {
+			remote := args.(*structs.PreparedQueryExecuteRemoteRequest)
+			if !remote.Explain {
+				return fmt.Errorf("explain not requested")
+			}
+			ret := reply.(*structs.PreparedQueryExecuteResponse)
+			switch dc {
+			case "dc2":
+				return fmt.Errorf("XXX")
+			case "dc3":
+				// Remote servers that don't explain only give results.
+				ret.Nodes = structs.CheckServiceNodes{
+					{Node: &structs.Node{Node: "node1"}},
+				}
+			}
+			return nil
+		},
+	}
+
+	local := &structs.QueryDatacenterExplain{Datacenter: "dc1", Reason: "local"}
+	reply := &structs.PreparedQueryExecuteResponse{
+		Explain: &structs.QueryExecuteExplain{
+			Datacenters: []*structs.QueryDatacenterExplain{local},
+		},
+	}
+	require.NoError(t, queryFailover(mock, query, &structs.PreparedQueryExecuteRequest{}, reply))
+	require.Equal(t, "dc3", reply.Datacenter)
+	require.Equal(t, 2, reply.Failovers)
+	require.Equal(t, []*structs.QueryDatacenterExplain{
+		local,
+		{
+			Datacenter: "dc2",
+			Reason:     "nearest",
+			RTT:        "10ms",
+			Service:    "db",
+			Error:      "XXX",
+		},
+		{
+			Datacenter: "dc3",
+			Reason:     "target",
+			RTT:        "20ms",
+			Service:    "db-replica",
+			Results:    1,
+		},
+	}, reply.Explain.Datacenters)
+}
@@ -145,6 +145,11 @@ func (s *Session) Get(args *structs.SessionSpecificRequest,
 		return err
 	}
 
+	rule, err := s.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+
 	return s.srv.blockingQuery(
 		&args.QueryOptions,
 		&reply.QueryMeta,
@@ -163,7 +168,8 @@ func (s *Session) Get(args *structs.SessionSpecificRequest,
 			if err := s.srv.filterACL(args.Token, reply); err != nil {
 				return err
 			}
-			return nil
+			reply.HeldKeys, err = sessionsHeldKeys(state, rule, reply.Sessions)
+			return err
 		})
 }
 
@@ -174,6 +180,11 @@ func (s *Session) List(args *structs.DCSpecificRequest,
 		return err
 	}
 
+	rule, err := s.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+
 	return s.srv.blockingQuery(
 		&args.QueryOptions,
 		&reply.QueryMeta,
@@ -187,7 +198,8 @@ func (s *Session) List(args *structs.DCSpecificRequest,
 			if err := s.srv.filterACL(args.Token, reply); err != nil {
 				return err
 			}
-			return nil
+			reply.HeldKeys, err = sessionsHeldKeys(state, rule, reply.Sessions)
+			return err
 		})
 }
 
@@ -198,6 +210,11 @@ func (s *Session) NodeSessions(args *structs.NodeSpecificRequest,
 		return err
 	}
 
+	rule, err := s.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+
 	return s.srv.blockingQuery(
 		&args.QueryOptions,
 		&reply.QueryMeta,
@@ -211,6 +228,79 @@ func (s *Session) NodeSessions(args *structs.NodeSpecificRequest,
 			if err := s.srv.filterACL(args.Token, reply); err != nil {
 				return err
 			}
+			reply.HeldKeys, err = sessionsHeldKeys(state, rule, reply.Sessions)
+			return err
+		})
+}
+
+// sessionsHeldKeys returns the number of keys each of the given sessions
+// holds locks on, by session ID, counting only the keys the token can read.
+// The counts don't take part in blocking, since watching the keys would wake
+// up queries on sessions for every lock that changes.
+func sessionsHeldKeys(state *state.Store, rule acl.Authorizer, sessions structs.Sessions) (map[string]int, error) {
+	if len(sessions) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[string]int, len(sessions))
+	for _, session := range sessions {
+		_, keys, err := state.SessionKeys(nil, session.ID)
+		if err != nil {
+			return nil, err
+		}
+		if rule != nil {
+			keys = FilterKeys(rule, keys)
+		}
+		result[session.ID] = len(keys)
+	}
+	return result, nil
+}
+
+// Keys is used to get the keys that are locked by a session
+func (s *Session) Keys(args *structs.SessionSpecificRequest,
+	reply *structs.IndexedKeyList) error {
+	if done, err := s.srv.forward("Session.Keys", args, args, reply); done {
+		return err
+	}
+
+	rule, err := s.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+
+	return s.srv.blockingQuery(
+		&args.QueryOptions,
+		&reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			_, session, err := state.SessionGet(ws, args.Session)
+			if err != nil {
+				return err
+			}
+			index, keys, err := state.SessionKeys(ws, args.Session)
+			if err != nil {
+				return err
+			}
+
+			// Must provide non-zero index to prevent blocking
+			// Index 1 is impossible anyways (due to Raft internals)
+			if index == 0 {
+				reply.Index = 1
+			} else {
+				reply.Index = index
+			}
+
+			// Sessions that can't be read are treated like missing ones,
+			// as in Get, and only the keys that can be read are shown.
+			if session == nil {
+				keys = nil
+			} else if rule != nil {
+				if !rule.SessionRead(session.Node) {
+					keys = nil
+				} else {
+					keys = FilterKeys(rule, keys)
+				}
+			}
+			reply.Keys = keys
 			return nil
 		})
 }
@@ -246,8 +336,13 @@ func (s *Session) Renew(args *structs.SessionSpecificRequest,
 		}
 	}
 
-	// Reset the session TTL timer.
 	reply.Sessions = structs.Sessions{session}
+	reply.HeldKeys, err = sessionsHeldKeys(state, rule, reply.Sessions)
+	if err != nil {
+		return err
+	}
+
+	// Reset the session TTL timer.
 	if err := s.srv.resetSessionTimer(args.Session, session); err != nil {
 		s.srv.logger.Printf("[ERR] consul.session: Session renew failed: %v", err)
 		return err
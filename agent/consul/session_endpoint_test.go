@@ -2,11 +2,13 @@ package consul
 
 import (
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/lib"
 	"github.com/hashicorp/consul/testrpc"
 	"github.com/hashicorp/net-rpc-msgpackrpc"
@@ -841,6 +843,92 @@ func TestSession_NodeSessions(t *testing.T) {
 	}
 }
 
+func TestSession_Keys(t *testing.T) {
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	s1.fsm.State().EnsureNode(1, &structs.Node{Node: "foo", Address: "127.0.0.1"})
+	arg := structs.SessionRequest{
+		Datacenter: "dc1",
+		Op:         structs.SessionCreate,
+		Session: structs.Session{
+			Node: "foo",
+		},
+	}
+	var id string
+	if err := msgpackrpc.CallWithCodec(codec, "Session.Apply", &arg, &id); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for _, key := range []string{"lock/b", "lock/a"} {
+		kvArg := structs.KVSRequest{
+			Datacenter: "dc1",
+			Op:         api.KVLock,
+			DirEnt: structs.DirEntry{
+				Key:     key,
+				Session: id,
+			},
+		}
+		var ok bool
+		if err := msgpackrpc.CallWithCodec(codec, "KVS.Apply", &kvArg, &ok); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if !ok {
+			t.Fatalf("didn't get the lock")
+		}
+	}
+
+	getR := structs.SessionSpecificRequest{
+		Datacenter: "dc1",
+		Session:    id,
+	}
+	var keys structs.IndexedKeyList
+	if err := msgpackrpc.CallWithCodec(codec, "Session.Keys", &getR, &keys); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if keys.Index == 0 {
+		t.Fatalf("Bad: %v", keys)
+	}
+	if !reflect.DeepEqual(keys.Keys, []string{"lock/a", "lock/b"}) {
+		t.Fatalf("Bad: %v", keys.Keys)
+	}
+
+	// The number of held keys is filled in when sessions are read.
+	listR := structs.DCSpecificRequest{
+		Datacenter: "dc1",
+	}
+	var sessions structs.IndexedSessions
+	if err := msgpackrpc.CallWithCodec(codec, "Session.List", &listR, &sessions); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(sessions.Sessions) != 1 || sessions.HeldKeys[id] != 2 {
+		t.Fatalf("Bad: %v", sessions)
+	}
+	var session structs.IndexedSessions
+	if err := msgpackrpc.CallWithCodec(codec, "Session.Get", &getR, &session); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(session.Sessions) != 1 || session.HeldKeys[id] != 2 {
+		t.Fatalf("Bad: %v", session)
+	}
+
+	// An unknown session has no keys.
+	getR.Session = generateUUID()
+	var missing structs.IndexedKeyList
+	if err := msgpackrpc.CallWithCodec(codec, "Session.Keys", &getR, &missing); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(missing.Keys) != 0 {
+		t.Fatalf("Bad: %v", missing.Keys)
+	}
+}
+
 func TestSession_Apply_BadTTL(t *testing.T) {
 	t.Parallel()
 	dir1, s1 := testServer(t)
@@ -287,6 +287,19 @@ func (s *Store) intentionDeleteTxn(tx *memdb.Txn, idx uint64, queryID string) er
 	return nil
 }
 
+// intentionsCheckIndexTxn returns an error if the intentions have changed
+// since the given index, as returned by Intentions.
+func (s *Store) intentionsCheckIndexTxn(tx *memdb.Txn, cidx uint64) error {
+	idx := maxIndexTxn(tx, intentionsTableName)
+	if idx < 1 {
+		idx = 1
+	}
+	if idx != cidx {
+		return fmt.Errorf("Intentions changed since index %d, the current index is %d", cidx, idx)
+	}
+	return nil
+}
+
 // IntentionMatch returns the list of intentions that match the namespace and
 // name for either a source or destination. This applies the resolution rules
 // so wildcards will match any value.
@@ -655,3 +655,37 @@ func (s *Store) kvsCheckIndexTxn(tx *memdb.Txn, key string, cidx uint64) (*struc
 
 	return e, nil
 }
+
+// kvsCheckFenceTxn checks that the given session still holds a lock key with
+// the given lock index, which guards writes made on behalf of a single
+// acquisition of the lock. The lock index only changes when the lock is
+// acquired again, so other writes to the lock key don't affect the check. It
+// fails once the lock is released or acquired again, so holders that lost the
+// lock can't make writes guarded by this check. The Go API hands out the Raft
+// index of the acquisition as the fencing token for other systems, since the
+// lock index starts over if the lock key is deleted.
+func (s *Store) kvsCheckFenceTxn(tx *memdb.Txn, key, session string, lockIndex uint64) (*structs.DirEntry, error) {
+	entry, err := tx.First("kvs", "id", key)
+	if err != nil {
+		return nil, fmt.Errorf("failed kvs lookup: %s", err)
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("failed to check fence, key %q doesn't exist", key)
+	}
+
+	e := entry.(*structs.DirEntry)
+	if e.Session == "" {
+		return nil, fmt.Errorf("failed fence check for key %q, lock isn't held", key)
+	}
+	if e.Session != session {
+		return nil, fmt.Errorf("failed fence check for key %q, lock is held by another session", key)
+	}
+	if e.LockIndex != lockIndex {
+		if lockIndex < e.LockIndex {
+			return nil, fmt.Errorf("failed fence check for key %q, lock index %d is stale, current lock index is %d", key, lockIndex, e.LockIndex)
+		}
+		return nil, fmt.Errorf("failed fence check for key %q, lock index %d is invalid, current lock index is %d", key, lockIndex, e.LockIndex)
+	}
+
+	return e, nil
+}
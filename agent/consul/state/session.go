@@ -2,6 +2,7 @@ package state
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/hashicorp/consul/agent/structs"
@@ -273,6 +274,32 @@ func (s *Store) SessionList(ws memdb.WatchSet) (uint64, structs.Sessions, error)
 	return idx, result, nil
 }
 
+// SessionKeys returns the keys that are locked by the given session, sorted
+// by key. This uses the session index of the KV table, so it doesn't have to
+// go over all of the keys.
+func (s *Store) SessionKeys(ws memdb.WatchSet, sessionID string) (uint64, []string, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	// Get the table index. Keys are deleted when a session with the delete
+	// behavior is invalidated, so the tombstones count as well.
+	idx := maxIndexTxn(tx, "kvs", "tombstones")
+
+	// Get all of the keys which are locked by the session
+	entries, err := tx.Get("kvs", "session", sessionID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed kvs lookup: %s", err)
+	}
+	ws.Add(entries.WatchCh())
+
+	var keys []string
+	for entry := entries.Next(); entry != nil; entry = entries.Next() {
+		keys = append(keys, entry.(*structs.DirEntry).Key)
+	}
+	sort.Strings(keys)
+	return idx, keys, nil
+}
+
 // NodeSessions returns a set of active sessions associated
 // with the given node ID. The returned index is the highest
 // index seen from the result set.
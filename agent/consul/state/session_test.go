@@ -325,6 +325,81 @@ func TestStateStore_NodeSessions(t *testing.T) {
 	}
 }
 
+func TestStateStore_SessionKeys(t *testing.T) {
+	s := testStateStore(t)
+
+	// Listing keys for an unknown session returns nil
+	ws := memdb.NewWatchSet()
+	idx, keys, err := s.SessionKeys(ws, testUUID())
+	if idx != 0 || keys != nil || err != nil {
+		t.Fatalf("expected (0, nil, nil), got: (%d, %#v, %#v)", idx, keys, err)
+	}
+
+	testRegisterNode(t, s, 1, "node1")
+	session1, session2 := testUUID(), testUUID()
+	for i, id := range []string{session1, session2} {
+		if err := s.SessionCreate(uint64(2+i), &structs.Session{ID: id, Node: "node1"}); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	// Lock some keys with both sessions
+	testSetKey(t, s, 4, "unlocked", "foo")
+	for i, lock := range []struct {
+		key     string
+		session string
+	}{
+		{"foo/b", session1},
+		{"foo/a", session1},
+		{"bar", session2},
+	} {
+		ok, err := s.KVSLock(uint64(5+i), &structs.DirEntry{Key: lock.key, Session: lock.session})
+		if !ok || err != nil {
+			t.Fatalf("didn't get the lock: %v %s", ok, err)
+		}
+	}
+
+	ws1 := memdb.NewWatchSet()
+	idx, keys, err = s.SessionKeys(ws1, session1)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if idx != 7 {
+		t.Fatalf("bad index: %d", idx)
+	}
+	if !reflect.DeepEqual(keys, []string{"foo/a", "foo/b"}) {
+		t.Fatalf("bad: %#v", keys)
+	}
+
+	ws2 := memdb.NewWatchSet()
+	_, keys, err = s.SessionKeys(ws2, session2)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(keys, []string{"bar"}) {
+		t.Fatalf("bad: %#v", keys)
+	}
+
+	// Releasing a lock of session1 should not affect session2's watch.
+	ok, err := s.KVSUnlock(8, &structs.DirEntry{Key: "foo/a", Session: session1})
+	if !ok || err != nil {
+		t.Fatalf("didn't release the lock: %v %s", ok, err)
+	}
+	if !watchFired(ws1) {
+		t.Fatalf("bad")
+	}
+	if watchFired(ws2) {
+		t.Fatalf("bad")
+	}
+	idx, keys, err = s.SessionKeys(nil, session1)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if idx != 8 || !reflect.DeepEqual(keys, []string{"foo/b"}) {
+		t.Fatalf("bad: %d %#v", idx, keys)
+	}
+}
+
 func TestStateStore_SessionDestroy(t *testing.T) {
 	s := testStateStore(t)
 
@@ -79,6 +79,9 @@ func (s *Store) txnKVS(tx *memdb.Txn, idx uint64, op *structs.TxnKVOp) (structs.
 	case api.KVCheckIndex:
 		entry, err = s.kvsCheckIndexTxn(tx, op.DirEnt.Key, op.DirEnt.ModifyIndex)
 
+	case api.KVCheckFence:
+		entry, err = s.kvsCheckFenceTxn(tx, op.DirEnt.Key, op.DirEnt.Session, op.DirEnt.ModifyIndex)
+
 	case api.KVCheckNotExists:
 		_, entry, err = s.kvsGetTxn(tx, nil, op.DirEnt.Key)
 		if entry != nil && err == nil {
@@ -117,6 +120,8 @@ func (s *Store) txnIntention(tx *memdb.Txn, idx uint64, op *structs.TxnIntention
 		return s.intentionSetTxn(tx, idx, op.Intention)
 	case structs.IntentionOpDelete:
 		return s.intentionDeleteTxn(tx, idx, op.Intention.ID)
+	case structs.IntentionOpCheckIndex:
+		return s.intentionsCheckIndexTxn(tx, op.Intention.ModifyIndex)
 	default:
 		return fmt.Errorf("unknown Intention op %q", op.Op)
 	}
@@ -118,6 +118,53 @@ func TestStateStore_Txn_Intention(t *testing.T) {
 	verify.Values(t, "", actual, intentions)
 }
 
+func TestStateStore_Txn_Intention_CheckIndex(t *testing.T) {
+	require := require.New(t)
+	s := testStateStore(t)
+
+	ixn := &structs.Intention{
+		ID:              testUUID(),
+		SourceNS:        "default",
+		SourceName:      "web",
+		DestinationNS:   "default",
+		DestinationName: "db",
+		Meta:            map[string]string{},
+	}
+	checkIndex := func(index uint64) *structs.TxnOp {
+		return &structs.TxnOp{
+			Intention: &structs.TxnIntentionOp{
+				Op: structs.IntentionOpCheckIndex,
+				Intention: &structs.Intention{
+					RaftIndex: structs.RaftIndex{ModifyIndex: index},
+				},
+			},
+		}
+	}
+	create := &structs.TxnOp{
+		Intention: &structs.TxnIntentionOp{
+			Op:        structs.IntentionOpCreate,
+			Intention: ixn,
+		},
+	}
+
+	// A stale index rolls back the whole transaction.
+	_, errors := s.TxnRW(2, structs.TxnOps{checkIndex(5), create})
+	require.Len(errors, 1)
+	require.Contains(errors[0].What, "Intentions changed since index 5, the current index is 1")
+	idx, ixns, err := s.Intentions(nil)
+	require.NoError(err)
+	require.Equal(uint64(1), idx)
+	require.Empty(ixns)
+
+	// The current index lets it through.
+	_, errors = s.TxnRW(2, structs.TxnOps{checkIndex(1), create})
+	require.Empty(errors)
+	idx, ixns, err = s.Intentions(nil)
+	require.NoError(err)
+	require.Equal(uint64(2), idx)
+	require.Len(ixns, 1)
+}
+
 func TestStateStore_Txn_Node(t *testing.T) {
 	require := require.New(t)
 	s := testStateStore(t)
@@ -980,6 +1027,54 @@ func TestStateStore_Txn_KVS_Rollback(t *testing.T) {
 				},
 			},
 		},
+		&structs.TxnOp{
+			KV: &structs.TxnKVOp{
+				Verb: api.KVCheckFence,
+				DirEnt: structs.DirEntry{
+					Key:     "foo/lock",
+					Session: bogus,
+					RaftIndex: structs.RaftIndex{
+						ModifyIndex: 1,
+					},
+				},
+			},
+		},
+		&structs.TxnOp{
+			KV: &structs.TxnKVOp{
+				Verb: api.KVCheckFence,
+				DirEnt: structs.DirEntry{
+					Key:     "foo/lock",
+					Session: session,
+					RaftIndex: structs.RaftIndex{
+						ModifyIndex: 2,
+					},
+				},
+			},
+		},
+		&structs.TxnOp{
+			KV: &structs.TxnKVOp{
+				Verb: api.KVCheckFence,
+				DirEnt: structs.DirEntry{
+					Key:     "foo/update",
+					Session: session,
+					RaftIndex: structs.RaftIndex{
+						ModifyIndex: 1,
+					},
+				},
+			},
+		},
+		&structs.TxnOp{
+			KV: &structs.TxnKVOp{
+				Verb: api.KVCheckFence,
+				DirEnt: structs.DirEntry{
+					Key:     "nope",
+					Session: session,
+					RaftIndex: structs.RaftIndex{
+						ModifyIndex: 1,
+					},
+				},
+			},
+		},
 		&structs.TxnOp{
 			KV: &structs.TxnKVOp{
 				Verb: "nope",
@@ -1008,6 +1103,10 @@ func TestStateStore_Txn_KVS_Rollback(t *testing.T) {
 		`key "nope" doesn't exist`,
 		"current modify index",
 		`key "nope" doesn't exist`,
+		"lock is held by another session",
+		"lock index 2 is invalid, current lock index is 1",
+		"lock isn't held",
+		`key "nope" doesn't exist`,
 		"unknown KV verb",
 	}
 	if len(errors) != len(expected) {
@@ -1023,6 +1122,102 @@ func TestStateStore_Txn_KVS_Rollback(t *testing.T) {
 	}
 }
 
+func TestStateStore_Txn_KVS_CheckFence(t *testing.T) {
+	s := testStateStore(t)
+
+	testRegisterNode(t, s, 1, "node1")
+	session1, session2 := testUUID(), testUUID()
+	for i, id := range []string{session1, session2} {
+		if err := s.SessionCreate(uint64(2+i), &structs.Session{ID: id, Node: "node1"}); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	ok, err := s.KVSLock(4, &structs.DirEntry{Key: "lock", Session: session1})
+	if !ok || err != nil {
+		t.Fatalf("didn't get the lock: %v %s", ok, err)
+	}
+
+	// A write guarded by the current token goes through.
+	guardedSet := func(session string, token uint64, value string) structs.TxnOps {
+		return structs.TxnOps{
+			&structs.TxnOp{
+				KV: &structs.TxnKVOp{
+					Verb: api.KVCheckFence,
+					DirEnt: structs.DirEntry{
+						Key:       "lock",
+						Session:   session,
+						RaftIndex: structs.RaftIndex{ModifyIndex: token},
+					},
+				},
+			},
+			&structs.TxnOp{
+				KV: &structs.TxnKVOp{
+					Verb:   api.KVSet,
+					DirEnt: structs.DirEntry{Key: "data", Value: []byte(value)},
+				},
+			},
+		}
+	}
+	results, errors := s.TxnRW(5, guardedSet(session1, 1, "first"))
+	if len(errors) != 0 {
+		t.Fatalf("err: %v", errors)
+	}
+	if len(results) != 2 || results[0].KV.LockIndex != 1 || results[0].KV.Session != session1 {
+		t.Fatalf("bad: %v", results)
+	}
+
+	// Writing the lock key again while holding the lock keeps the token.
+	ok, err = s.KVSLock(6, &structs.DirEntry{Key: "lock", Value: []byte("new"), Session: session1})
+	if !ok || err != nil {
+		t.Fatalf("didn't get the lock: %v %s", ok, err)
+	}
+	_, errors = s.TxnRW(7, guardedSet(session1, 1, "again"))
+	if len(errors) != 0 {
+		t.Fatalf("err: %v", errors)
+	}
+
+	// Hand the lock over to another session, which gets a newer token.
+	ok, err = s.KVSUnlock(8, &structs.DirEntry{Key: "lock", Session: session1})
+	if !ok || err != nil {
+		t.Fatalf("didn't release the lock: %v %s", ok, err)
+	}
+	ok, err = s.KVSLock(9, &structs.DirEntry{Key: "lock", Session: session2})
+	if !ok || err != nil {
+		t.Fatalf("didn't get the lock: %v %s", ok, err)
+	}
+
+	// The old holder's write is rejected and the new holder's goes through.
+	_, errors = s.TxnRW(10, guardedSet(session1, 1, "zombie"))
+	if len(errors) != 1 || !strings.Contains(errors[0].Error(), "lock is held by another session") {
+		t.Fatalf("bad: %v", errors)
+	}
+	_, errors = s.TxnRW(11, guardedSet(session2, 2, "second"))
+	if len(errors) != 0 {
+		t.Fatalf("err: %v", errors)
+	}
+	_, entry, err := s.KVSGet(nil, "data")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if string(entry.Value) != "second" || entry.ModifyIndex != 11 {
+		t.Fatalf("bad: %v", entry)
+	}
+
+	// A session that acquires the lock again gets a newer token too.
+	ok, err = s.KVSUnlock(12, &structs.DirEntry{Key: "lock", Session: session2})
+	if !ok || err != nil {
+		t.Fatalf("didn't release the lock: %v %s", ok, err)
+	}
+	ok, err = s.KVSLock(13, &structs.DirEntry{Key: "lock", Session: session1})
+	if !ok || err != nil {
+		t.Fatalf("didn't get the lock: %v %s", ok, err)
+	}
+	_, errors = s.TxnRW(14, guardedSet(session1, 1, "zombie"))
+	if len(errors) != 1 || !strings.Contains(errors[0].Error(), "lock index 1 is stale, current lock index is 3") {
+		t.Fatalf("bad: %v", errors)
+	}
+}
+
 func TestStateStore_Txn_KVS_RO(t *testing.T) {
 	s := testStateStore(t)
 
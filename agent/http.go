@@ -477,6 +477,17 @@ func (s *HTTPServer) Index(resp http.ResponseWriter, req *http.Request) {
 
 // decodeBody is used to decode a JSON request body
 func decodeBody(req *http.Request, out interface{}, cb func(interface{}) error) error {
+	return decodeJSONBody(req, out, cb, false)
+}
+
+// decodeBodyExactNumbers is like decodeBody but doesn't decode numbers through
+// a float64, so that large uint64 values like KV flags keep their precision.
+// Callbacks see numbers as json.Number.
+func decodeBodyExactNumbers(req *http.Request, out interface{}, cb func(interface{}) error) error {
+	return decodeJSONBody(req, out, cb, true)
+}
+
+func decodeJSONBody(req *http.Request, out interface{}, cb func(interface{}) error, useNumber bool) error {
 	// This generally only happens in tests since real HTTP requests set
 	// a non-nil body with no content. We guard against it anyways to prevent
 	// a panic. The EOF response is the same behavior as an empty reader.
@@ -486,6 +497,9 @@ func decodeBody(req *http.Request, out interface{}, cb func(interface{}) error)
 
 	var raw interface{}
 	dec := json.NewDecoder(req.Body)
+	if useNumber {
+		dec.UseNumber()
+	}
 	if err := dec.Decode(&raw); err != nil {
 		return err
 	}
@@ -499,6 +513,7 @@ func decodeBody(req *http.Request, out interface{}, cb func(interface{}) error)
 
 	decodeConf := &mapstructure.DecoderConfig{
 		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			jsonNumberToNumberFunc(),
 			mapstructure.StringToTimeDurationHookFunc(),
 			stringToReadableDurationFunc(),
 		),
@@ -513,6 +528,37 @@ func decodeBody(req *http.Request, out interface{}, cb func(interface{}) error)
 	return decoder.Decode(raw)
 }
 
+// jsonNumberToNumberFunc is a mapstructure hook for decoding a json.Number
+// into a number of the target's kind. mapstructure can't decode a json.Number
+// into an unsigned integer by itself, and the duration hooks would otherwise
+// treat it as a string.
+func jsonNumberToNumberFunc() mapstructure.DecodeHookFunc {
+	return func(
+		f reflect.Type,
+		t reflect.Type,
+		data interface{}) (interface{}, error) {
+		n, ok := data.(json.Number)
+		if !ok {
+			return data, nil
+		}
+		switch t.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+				return u, nil
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if i, err := n.Int64(); err == nil {
+				return i, nil
+			}
+		case reflect.String:
+			return string(n), nil
+		}
+
+		// Fall back to what decoding without json.Number would have given.
+		return n.Float64()
+	}
+}
+
 // stringToReadableDurationFunc is a mapstructure hook for decoding a string
 // into an api.ReadableDuration for backwards compatibility.
 func stringToReadableDurationFunc() mapstructure.DecodeHookFunc {
@@ -3,6 +3,7 @@ package agent
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/consul/agent/consul"
@@ -62,6 +63,45 @@ func (s *HTTPServer) IntentionCreate(resp http.ResponseWriter, req *http.Request
 	return intentionCreateResponse{reply}, nil
 }
 
+// PUT /v1/connect/intentions/apply
+func (s *HTTPServer) IntentionApply(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.IntentionReconcileRequest{}
+	s.parseDC(req, &args.Datacenter)
+	s.parseToken(req, &args.Token)
+
+	params := req.URL.Query()
+	if _, ok := params["prune"]; ok {
+		args.Prune = true
+	}
+	if _, ok := params["dry-run"]; ok {
+		args.DryRun = true
+	}
+	if _, ok := params["cas"]; ok {
+		casVal, err := strconv.ParseUint(params.Get("cas"), 10, 64)
+		if err != nil {
+			return nil, BadRequestError{Reason: fmt.Sprintf("Invalid cas index: %v", err)}
+		}
+		args.CAS = casVal
+	}
+
+	if err := decodeBody(req, &args.Intentions, nil); err != nil {
+		return nil, BadRequestError{Reason: fmt.Sprintf("Request decode failed: %v", err)}
+	}
+
+	var reply structs.IntentionReconcileResponse
+	if err := s.agent.RPC("Intention.Reconcile", &args, &reply); err != nil {
+		return nil, err
+	}
+	if reply.Changes == nil {
+		reply.Changes = make([]*structs.IntentionChange, 0)
+	}
+	if reply.Unmanaged == nil {
+		reply.Unmanaged = make(structs.Intentions, 0)
+	}
+
+	return &reply, nil
+}
+
 // GET /v1/connect/intentions/match
 func (s *HTTPServer) IntentionMatch(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	// Prepare args
@@ -103,6 +143,7 @@ func (s *HTTPServer) IntentionMatch(resp http.ResponseWriter, req *http.Request)
 	}
 
 	var reply structs.IndexedIntentionMatches
+	defer setMeta(resp, &reply.QueryMeta)
 	if err := s.agent.RPC("Intention.Match", args, &reply); err != nil {
 		return nil, err
 	}
@@ -128,50 +169,78 @@ func (s *HTTPServer) IntentionCheck(resp http.ResponseWriter, req *http.Request)
 	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
 		return nil, nil
 	}
+	if err := parseIntentionQueryCheck(req, args.Check); err != nil {
+		return nil, err
+	}
+
+	var reply structs.IntentionQueryCheckResponse
+	if err := s.agent.RPC("Intention.Check", args, &reply); err != nil {
+		return nil, err
+	}
+
+	return &reply, nil
+}
+
+// GET /v1/connect/intentions/explain
+func (s *HTTPServer) IntentionExplain(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Prepare args
+	args := &structs.IntentionQueryRequest{Check: &structs.IntentionQueryCheck{}}
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+	if err := parseIntentionQueryCheck(req, args.Check); err != nil {
+		return nil, err
+	}
+
+	var reply structs.IntentionQueryExplainResponse
+	defer setMeta(resp, &reply.QueryMeta)
+	if err := s.agent.RPC("Intention.Explain", args, &reply); err != nil {
+		return nil, err
+	}
 
+	return &reply, nil
+}
+
+// parseIntentionQueryCheck parses the source, destination and source type
+// of a check or explain request.
+func parseIntentionQueryCheck(req *http.Request, check *structs.IntentionQueryCheck) error {
 	q := req.URL.Query()
 
 	// Set the source type if set
-	args.Check.SourceType = structs.IntentionSourceConsul
+	check.SourceType = structs.IntentionSourceConsul
 	if sourceType, ok := q["source-type"]; ok && len(sourceType) > 0 {
-		args.Check.SourceType = structs.IntentionSourceType(sourceType[0])
+		check.SourceType = structs.IntentionSourceType(sourceType[0])
 	}
 
 	// Extract the source/destination
 	source, ok := q["source"]
 	if !ok || len(source) != 1 {
-		return nil, fmt.Errorf("required query parameter 'source' not set")
+		return fmt.Errorf("required query parameter 'source' not set")
 	}
 	destination, ok := q["destination"]
 	if !ok || len(destination) != 1 {
-		return nil, fmt.Errorf("required query parameter 'destination' not set")
+		return fmt.Errorf("required query parameter 'destination' not set")
 	}
 
 	// We parse them the same way as matches to extract namespace/name
-	args.Check.SourceName = source[0]
-	if args.Check.SourceType == structs.IntentionSourceConsul {
+	check.SourceName = source[0]
+	if check.SourceType == structs.IntentionSourceConsul {
 		entry, err := parseIntentionMatchEntry(source[0])
 		if err != nil {
-			return nil, fmt.Errorf("source %q is invalid: %s", source[0], err)
+			return fmt.Errorf("source %q is invalid: %s", source[0], err)
 		}
-		args.Check.SourceNS = entry.Namespace
-		args.Check.SourceName = entry.Name
+		check.SourceNS = entry.Namespace
+		check.SourceName = entry.Name
 	}
 
 	// The destination is always in the Consul format
 	entry, err := parseIntentionMatchEntry(destination[0])
 	if err != nil {
-		return nil, fmt.Errorf("destination %q is invalid: %s", destination[0], err)
+		return fmt.Errorf("destination %q is invalid: %s", destination[0], err)
 	}
-	args.Check.DestinationNS = entry.Namespace
-	args.Check.DestinationName = entry.Name
-
-	var reply structs.IntentionQueryCheckResponse
-	if err := s.agent.RPC("Intention.Check", args, &reply); err != nil {
-		return nil, err
-	}
-
-	return &reply, nil
+	check.DestinationNS = entry.Namespace
+	check.DestinationName = entry.Name
+	return nil
 }
 
 // IntentionSpecific handles the endpoint for /v1/connection/intentions/:id
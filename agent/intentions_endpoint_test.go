@@ -274,6 +274,69 @@ func TestIntentionsCheck_noDestination(t *testing.T) {
 	require.Nil(obj)
 }
 
+func TestIntentionsExplain_basic(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+
+	// Create some intentions
+	var id string
+	{
+		ixn := structs.IntentionRequest{
+			Datacenter: "dc1",
+			Op:         structs.IntentionOpCreate,
+			Intention:  structs.TestIntention(t),
+		}
+		ixn.Intention.SourceNS = "foo"
+		ixn.Intention.SourceName = "*"
+		ixn.Intention.DestinationNS = "foo"
+		ixn.Intention.DestinationName = "bar"
+		ixn.Intention.Action = structs.IntentionActionDeny
+		require.Nil(a.RPC("Intention.Apply", &ixn, &id))
+	}
+
+	// Request matching intention
+	{
+		req, _ := http.NewRequest("GET",
+			"/v1/connect/intentions/explain?source=foo/baz&destination=foo/bar", nil)
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.IntentionExplain(resp, req)
+		require.Nil(err)
+		value := obj.(*structs.IntentionQueryExplainResponse)
+		require.False(value.Allowed)
+		require.Equal(id, value.Matched.ID)
+		require.Len(value.Intentions, 1)
+		require.True(value.Intentions[0].SourceMatch)
+		require.NotEmpty(resp.Header().Get("X-Consul-Index"))
+	}
+
+	// Request non-matching intention
+	{
+		req, _ := http.NewRequest("GET",
+			"/v1/connect/intentions/explain?source=bar/baz&destination=foo/bar", nil)
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.IntentionExplain(resp, req)
+		require.Nil(err)
+		value := obj.(*structs.IntentionQueryExplainResponse)
+		require.True(value.Allowed)
+		require.Nil(value.Matched)
+		require.Len(value.Intentions, 1)
+		require.False(value.Intentions[0].SourceMatch)
+	}
+
+	// Missing source
+	{
+		req, _ := http.NewRequest("GET",
+			"/v1/connect/intentions/explain?destination=foo/bar", nil)
+		resp := httptest.NewRecorder()
+		_, err := a.srv.IntentionExplain(resp, req)
+		require.NotNil(err)
+		require.Contains(err.Error(), "'source' not set")
+	}
+}
+
 func TestIntentionsCreate_good(t *testing.T) {
 	t.Parallel()
 
@@ -306,6 +369,62 @@ func TestIntentionsCreate_good(t *testing.T) {
 	}
 }
 
+func TestIntentionsApply(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+
+	// Create an intention that isn't part of the desired set.
+	{
+		ixn := structs.IntentionRequest{
+			Datacenter: "dc1",
+			Op:         structs.IntentionOpCreate,
+			Intention:  structs.TestIntention(t),
+		}
+		ixn.Intention.SourceName = "api"
+		var reply string
+		require.Nil(a.RPC("Intention.Apply", &ixn, &reply))
+	}
+
+	body := []*structs.Intention{
+		{SourceName: "web", DestinationName: "db", Action: structs.IntentionActionAllow},
+	}
+
+	// Plan
+	req, _ := http.NewRequest("PUT", "/v1/connect/intentions/apply?dry-run&prune", jsonReader(body))
+	resp := httptest.NewRecorder()
+	obj, err := a.srv.IntentionApply(resp, req)
+	require.Nil(err)
+	plan := obj.(*structs.IntentionReconcileResponse)
+	require.Len(plan.Changes, 2)
+	require.Equal(structs.IntentionOpDelete, plan.Changes[0].Op)
+	require.Equal(structs.IntentionOpCreate, plan.Changes[1].Op)
+	require.Empty(plan.Unmanaged)
+
+	// Apply without pruning
+	req, _ = http.NewRequest("PUT",
+		fmt.Sprintf("/v1/connect/intentions/apply?cas=%d", plan.Index), jsonReader(body))
+	resp = httptest.NewRecorder()
+	obj, err = a.srv.IntentionApply(resp, req)
+	require.Nil(err)
+	result := obj.(*structs.IntentionReconcileResponse)
+	require.Len(result.Changes, 1)
+	require.Len(result.Unmanaged, 1)
+
+	var list structs.IndexedIntentions
+	require.Nil(a.RPC("Intention.List", &structs.DCSpecificRequest{Datacenter: "dc1"}, &list))
+	require.Len(list.Intentions, 2)
+
+	// Bad cas
+	req, _ = http.NewRequest("PUT", "/v1/connect/intentions/apply?cas=nope", jsonReader(body))
+	resp = httptest.NewRecorder()
+	_, err = a.srv.IntentionApply(resp, req)
+	require.Error(err)
+	require.IsType(BadRequestError{}, err)
+}
+
 func TestIntentionsCreate_noBody(t *testing.T) {
 	t.Parallel()
 
@@ -398,6 +398,53 @@ func TestKVSEndpoint_AcquireRelease(t *testing.T) {
 	}
 }
 
+func TestKVSEndpoint_LockInfo(t *testing.T) {
+	t.Parallel()
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	// Acquire the lock
+	id := makeTestSession(t, a.srv)
+	req, _ := http.NewRequest("PUT", "/v1/kv/test?acquire="+id, bytes.NewReader(nil))
+	resp := httptest.NewRecorder()
+	obj, err := a.srv.KVSEndpoint(resp, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if res := obj.(bool); !res {
+		t.Fatalf("should work")
+	}
+
+	req, _ = http.NewRequest("GET", "/v1/kv/test?lock-info", nil)
+	resp = httptest.NewRecorder()
+	obj, err = a.srv.KVSEndpoint(resp, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	info := obj.(*structs.KeyLockInfo)
+	expected := &structs.KeyLockInfo{
+		Key:       "test",
+		Session:   id,
+		Node:      a.Config.NodeName,
+		LockIndex: 1,
+	}
+	if !reflect.DeepEqual(info, expected) {
+		t.Fatalf("bad: %#v", info)
+	}
+
+	// A key is required
+	req, _ = http.NewRequest("GET", "/v1/kv/?lock-info", nil)
+	resp = httptest.NewRecorder()
+	if _, err := a.srv.KVSEndpoint(resp, req); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if resp.Code != 400 {
+		t.Fatalf("bad: %d", resp.Code)
+	}
+}
+
 func TestKVSEndpoint_GET_Raw(t *testing.T) {
 	t.Parallel()
 	a := NewTestAgent(t, t.Name(), "")
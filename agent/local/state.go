@@ -52,6 +52,11 @@ type ServiceState struct {
 	// but has not been removed on the server yet.
 	Deleted bool
 
+	// SyncError is the error of the last failed attempt to sync the
+	// service record, including attempts blocked by ACLs. It is cleared
+	// when the service record is synced.
+	SyncError string
+
 	// WatchCh is closed when the service state changes suitable for use in a
 	// memdb.WatchSet when watching agent local changes with hash-based blocking.
 	WatchCh chan struct{}
@@ -67,6 +72,27 @@ func (s *ServiceState) Clone() *ServiceState {
 	return s2
 }
 
+const (
+	// CheckHistoryLen is the number of status changes kept for each check.
+	CheckHistoryLen = 32
+
+	// CheckFlapWindow is how far back status changes are counted towards a
+	// check's flap count.
+	CheckFlapWindow = time.Hour
+)
+
+// CheckHistoryEntry records a status change of a health check.
+type CheckHistoryEntry struct {
+	// Time is when the check changed to Status.
+	Time time.Time
+
+	// Status is the status the check changed to.
+	Status string
+
+	// Output is the output the check reported along with the change.
+	Output string
+}
+
 // CheckState describes the state of a health check record.
 type CheckState struct {
 	// Check is the local copy of the health check record.
@@ -93,6 +119,16 @@ type CheckState struct {
 	// Deleted is true when the health check record has been marked as
 	// deleted but has not been removed on the server yet.
 	Deleted bool
+
+	// SyncError is the error of the last failed attempt to sync the health
+	// check record, including attempts blocked by ACLs. It is cleared when
+	// the health check record is synced.
+	SyncError string
+
+	// history is a ring of the most recent status changes. Once it is
+	// full, historyNext is the index of the oldest entry.
+	history     []CheckHistoryEntry
+	historyNext int
 }
 
 // Clone returns a shallow copy of the object. The check record and the
@@ -101,9 +137,50 @@ type CheckState struct {
 func (c *CheckState) Clone() *CheckState {
 	c2 := new(CheckState)
 	*c2 = *c
+
+	// The history ring is overwritten in place so the clone needs its own.
+	c2.history = c.History()
+	c2.historyNext = 0
 	return c2
 }
 
+// recordHistory adds a status change to the history ring.
+func (c *CheckState) recordHistory(status, output string, now time.Time) {
+	entry := CheckHistoryEntry{Time: now, Status: status, Output: output}
+	if len(c.history) < CheckHistoryLen {
+		c.history = append(c.history, entry)
+		return
+	}
+	c.history[c.historyNext] = entry
+	c.historyNext = (c.historyNext + 1) % CheckHistoryLen
+}
+
+// History returns a copy of the most recent status changes of the check,
+// oldest first.
+func (c *CheckState) History() []CheckHistoryEntry {
+	if len(c.history) == 0 {
+		return nil
+	}
+	entries := make([]CheckHistoryEntry, 0, len(c.history))
+	entries = append(entries, c.history[c.historyNext:]...)
+	entries = append(entries, c.history[:c.historyNext]...)
+	return entries
+}
+
+// FlapCount returns the number of times the check changed status within
+// CheckFlapWindow of now. The initial status a check was registered with
+// isn't counted.
+func (c *CheckState) FlapCount(now time.Time) int {
+	entries := c.History()
+	count := 0
+	for i := 1; i < len(entries); i++ {
+		if now.Sub(entries[i].Time) <= CheckFlapWindow {
+			count++
+		}
+	}
+	return count
+}
+
 // Critical returns true when the health check is in critical state.
 func (c *CheckState) Critical() bool {
 	return !c.CriticalTime.IsZero()
@@ -173,6 +250,10 @@ type State struct {
 	// node information in sync
 	nodeInfoInSync bool
 
+	// nodeInfoSyncError is the error of the last failed attempt to sync
+	// the node information.
+	nodeInfoSyncError string
+
 	// Services tracks the local services
 	services map[string]*ServiceState
 
@@ -491,10 +572,22 @@ func (l *State) addCheckLocked(check *structs.HealthCheck, token string) error {
 	// hard-set the node name
 	check.Node = l.config.NodeName
 
-	l.setCheckStateLocked(&CheckState{
+	// Keep the history of a check that is being re-registered so that
+	// reloads and updated definitions don't hide earlier flapping. Reloads
+	// remove checks before adding them again so deleted checks that haven't
+	// been synced yet count too.
+	c := &CheckState{
 		Check: check,
 		Token: token,
-	})
+	}
+	if existing := l.checks[check.CheckID]; existing != nil {
+		c.history = existing.History()
+	}
+	if n := len(c.history); n == 0 || c.history[n-1].Status != check.Status {
+		c.recordHistory(check.Status, check.Output, time.Now())
+	}
+
+	l.setCheckStateLocked(c)
 	return nil
 }
 
@@ -572,6 +665,11 @@ func (l *State) UpdateCheck(id types.CheckID, status, output string) {
 		output = ""
 	}
 
+	// Record status changes in the check's history.
+	if c.Check.Status != status {
+		c.recordHistory(status, output, time.Now())
+	}
+
 	// Update the critical time tracking (this doesn't cause a server updates
 	// so we can always keep this up to date).
 	if status == api.HealthCritical {
@@ -705,6 +803,25 @@ func (l *State) CheckStates() map[types.CheckID]*CheckState {
 	return m
 }
 
+// SyncStates returns the sync state of all local services and checks,
+// including the ones that are marked as deleted but have not been removed
+// on the servers yet. The maps contain shallow copies of the current
+// states.
+func (l *State) SyncStates() (map[string]*ServiceState, map[types.CheckID]*CheckState) {
+	l.RLock()
+	defer l.RUnlock()
+
+	services := make(map[string]*ServiceState)
+	for id, s := range l.services {
+		services[id] = s.Clone()
+	}
+	checks := make(map[types.CheckID]*CheckState)
+	for id, c := range l.checks {
+		checks[id] = c.Clone()
+	}
+	return services, checks
+}
+
 // CriticalCheckStates returns the locally registered checks that the
 // agent is aware of and are being kept in sync with the server.
 // The map contains a shallow copy of the current check states but
@@ -1278,11 +1395,13 @@ func (l *State) deleteService(id string) error {
 		// todo(fs): mark the service to be in sync to prevent excessive retrying before next full sync
 		// todo(fs): some backoff strategy might be a better solution
 		l.services[id].InSync = true
+		l.services[id].SyncError = err.Error()
 		l.logger.Printf("[WARN] agent: Service %q deregistration blocked by ACLs", id)
 		metrics.IncrCounter([]string{"acl", "blocked", "service", "deregistration"}, 1)
 		return nil
 
 	default:
+		l.services[id].SyncError = err.Error()
 		l.logger.Printf("[WARN] agent: Deregistering service %q failed. %s", id, err)
 		return err
 	}
@@ -1316,11 +1435,13 @@ func (l *State) deleteCheck(id types.CheckID) error {
 		// todo(fs): mark the check to be in sync to prevent excessive retrying before next full sync
 		// todo(fs): some backoff strategy might be a better solution
 		l.checks[id].InSync = true
+		l.checks[id].SyncError = err.Error()
 		l.logger.Printf("[WARN] agent: Check %q deregistration blocked by ACLs", id)
 		metrics.IncrCounter([]string{"acl", "blocked", "check", "deregistration"}, 1)
 		return nil
 
 	default:
+		l.checks[id].SyncError = err.Error()
 		l.logger.Printf("[WARN] agent: Deregistering check %q failed. %s", id, err)
 		return err
 	}
@@ -1371,11 +1492,14 @@ func (l *State) syncService(id string) error {
 	switch {
 	case err == nil:
 		l.services[id].InSync = true
+		l.services[id].SyncError = ""
 		// Given how the register API works, this info is also updated
 		// every time we sync a service.
 		l.nodeInfoInSync = true
+		l.nodeInfoSyncError = ""
 		for _, check := range checks {
 			l.checks[check.CheckID].InSync = true
+			l.checks[check.CheckID].SyncError = ""
 		}
 		l.logger.Printf("[INFO] agent: Synced service %q", id)
 		return nil
@@ -1384,14 +1508,20 @@ func (l *State) syncService(id string) error {
 		// todo(fs): mark the service and the checks to be in sync to prevent excessive retrying before next full sync
 		// todo(fs): some backoff strategy might be a better solution
 		l.services[id].InSync = true
+		l.services[id].SyncError = err.Error()
 		for _, check := range checks {
 			l.checks[check.CheckID].InSync = true
+			l.checks[check.CheckID].SyncError = err.Error()
 		}
 		l.logger.Printf("[WARN] agent: Service %q registration blocked by ACLs", id)
 		metrics.IncrCounter([]string{"acl", "blocked", "service", "registration"}, 1)
 		return nil
 
 	default:
+		l.services[id].SyncError = err.Error()
+		for _, check := range checks {
+			l.checks[check.CheckID].SyncError = err.Error()
+		}
 		l.logger.Printf("[WARN] agent: Syncing service %q failed. %s", id, err)
 		return err
 	}
@@ -1423,9 +1553,11 @@ func (l *State) syncCheck(id types.CheckID) error {
 	switch {
 	case err == nil:
 		l.checks[id].InSync = true
+		l.checks[id].SyncError = ""
 		// Given how the register API works, this info is also updated
 		// every time we sync a check.
 		l.nodeInfoInSync = true
+		l.nodeInfoSyncError = ""
 		l.logger.Printf("[INFO] agent: Synced check %q", id)
 		return nil
 
@@ -1433,11 +1565,13 @@ func (l *State) syncCheck(id types.CheckID) error {
 		// todo(fs): mark the check to be in sync to prevent excessive retrying before next full sync
 		// todo(fs): some backoff strategy might be a better solution
 		l.checks[id].InSync = true
+		l.checks[id].SyncError = err.Error()
 		l.logger.Printf("[WARN] agent: Check %q registration blocked by ACLs", id)
 		metrics.IncrCounter([]string{"acl", "blocked", "check", "registration"}, 1)
 		return nil
 
 	default:
+		l.checks[id].SyncError = err.Error()
 		l.logger.Printf("[WARN] agent: Syncing check %q failed. %s", id, err)
 		return err
 	}
@@ -1458,6 +1592,7 @@ func (l *State) syncNodeInfo() error {
 	switch {
 	case err == nil:
 		l.nodeInfoInSync = true
+		l.nodeInfoSyncError = ""
 		l.logger.Printf("[INFO] agent: Synced node info")
 		return nil
 
@@ -1465,12 +1600,22 @@ func (l *State) syncNodeInfo() error {
 		// todo(fs): mark the node info to be in sync to prevent excessive retrying before next full sync
 		// todo(fs): some backoff strategy might be a better solution
 		l.nodeInfoInSync = true
+		l.nodeInfoSyncError = err.Error()
 		l.logger.Printf("[WARN] agent: Node info update blocked by ACLs")
 		metrics.IncrCounter([]string{"acl", "blocked", "node", "registration"}, 1)
 		return nil
 
 	default:
+		l.nodeInfoSyncError = err.Error()
 		l.logger.Printf("[WARN] agent: Syncing node info failed. %s", err)
 		return err
 	}
 }
+
+// NodeInfoSyncState returns whether the node information is in sync with the
+// servers and the error of the last failed attempt to sync it, if any.
+func (l *State) NodeInfoSyncState() (bool, string) {
+	l.RLock()
+	defer l.RUnlock()
+	return l.nodeInfoInSync, l.nodeInfoSyncError
+}
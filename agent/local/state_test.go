@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -863,6 +864,15 @@ func TestAgentAntiEntropy_Services_ACLDeny(t *testing.T) {
 		if err := servicesInSync(a.State, 2); err != nil {
 			t.Fatal(err)
 		}
+
+		// The blocked registration should be recorded
+		states, _ := a.State.SyncStates()
+		if !strings.Contains(states["mysql"].SyncError, "Permission denied") {
+			t.Fatalf("bad: %q", states["mysql"].SyncError)
+		}
+		if states["api"].SyncError != "" {
+			t.Fatalf("bad: %q", states["api"].SyncError)
+		}
 	}
 
 	// Now remove the service and re-sync
@@ -1763,6 +1773,61 @@ func TestAgent_CheckCriticalTime(t *testing.T) {
 	}
 }
 
+func TestAgent_CheckHistory(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	cfg := config.DefaultRuntimeConfig(`bind_addr = "127.0.0.1" data_dir = "dummy"`)
+	l := local.NewState(agent.LocalConfig(cfg), nil, new(token.Store))
+	l.TriggerSyncChanges = func() {}
+
+	checkID := types.CheckID("web")
+	chk := &structs.HealthCheck{
+		Node:    "node",
+		CheckID: checkID,
+		Name:    "web",
+		Status:  api.HealthPassing,
+	}
+	require.NoError(l.AddCheck(chk, ""))
+
+	// Only changes of status are recorded.
+	l.UpdateCheck(checkID, api.HealthPassing, "still ok")
+	l.UpdateCheck(checkID, api.HealthCritical, "down")
+	l.UpdateCheck(checkID, api.HealthCritical, "still down")
+	l.UpdateCheck(checkID, api.HealthPassing, "up")
+
+	history := l.CheckState(checkID).History()
+	require.Len(history, 3)
+	require.Equal(api.HealthPassing, history[0].Status)
+	require.Equal(api.HealthCritical, history[1].Status)
+	require.Equal("down", history[1].Output)
+	require.Equal(api.HealthPassing, history[2].Status)
+
+	// The initial status isn't a flap and old changes drop out of the
+	// window.
+	require.Equal(2, l.CheckState(checkID).FlapCount(time.Now()))
+	require.Equal(0, l.CheckState(checkID).FlapCount(time.Now().Add(2*local.CheckFlapWindow)))
+
+	// Re-registering the check, like a reload does, keeps its history.
+	require.NoError(l.RemoveCheck(checkID))
+	chk2 := *chk
+	require.NoError(l.AddCheck(&chk2, ""))
+	require.Len(l.CheckState(checkID).History(), 3)
+
+	// The history is capped and keeps the most recent changes.
+	for i := 0; i < local.CheckHistoryLen; i++ {
+		status := api.HealthWarning
+		if i%2 == 1 {
+			status = api.HealthPassing
+		}
+		l.UpdateCheck(checkID, status, fmt.Sprintf("update %d", i))
+	}
+	history = l.CheckState(checkID).History()
+	require.Len(history, local.CheckHistoryLen)
+	require.Equal("update 0", history[0].Output)
+	require.Equal(fmt.Sprintf("update %d", local.CheckHistoryLen-1), history[len(history)-1].Output)
+	require.Equal(local.CheckHistoryLen, l.CheckState(checkID).FlapCount(time.Now())+1)
+}
+
 func TestAgent_AddCheckFailure(t *testing.T) {
 	t.Parallel()
 	cfg := config.DefaultRuntimeConfig(`bind_addr = "127.0.0.1" data_dir = "dummy"`)
@@ -69,6 +69,23 @@ func (s *HTTPServer) OperatorRaftPeer(resp http.ResponseWriter, req *http.Reques
 	return nil, nil
 }
 
+// OperatorRaftTransferLeader is used to hand Raft leadership off to another
+// server. An optional ?id can be given to pick the new leader.
+func (s *HTTPServer) OperatorRaftTransferLeader(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.RaftTransferLeaderRequest
+	s.parseDC(req, &args.Datacenter)
+	s.parseToken(req, &args.Token)
+
+	args.ID = raft.ServerID(req.URL.Query().Get("id"))
+
+	var reply struct{}
+	if err := s.agent.RPC("Operator.RaftTransferLeader", &args, &reply); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
 type keyringArgs struct {
 	Key         string
 	Token       string
@@ -293,6 +310,17 @@ func (s *HTTPServer) OperatorServerHealth(resp http.ResponseWriter, req *http.Re
 			Healthy:     server.Healthy,
 			Voter:       server.Voter,
 			StableSince: server.StableSince.Round(time.Second).UTC(),
+
+			RedundancyZone: server.RedundancyZone,
+		})
+	}
+	for _, zone := range reply.RedundancyZones {
+		out.RedundancyZones = append(out.RedundancyZones, api.ZoneHealth{
+			Name:             zone.Name,
+			Servers:          zone.Servers,
+			Voters:           zone.Voters,
+			Healthy:          zone.Healthy,
+			FailureTolerance: zone.FailureTolerance,
 		})
 	}
 
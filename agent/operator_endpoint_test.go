@@ -77,6 +77,22 @@ func TestOperator_RaftPeer(t *testing.T) {
 	})
 }
 
+func TestOperator_RaftTransferLeader(t *testing.T) {
+	t.Parallel()
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+
+	body := bytes.NewBuffer(nil)
+	req, _ := http.NewRequest("POST", "/v1/operator/raft/transfer-leader?id=nope", body)
+	// If we get this error, it proves we sent the ID all the way through.
+	resp := httptest.NewRecorder()
+	_, err := a.srv.OperatorRaftTransferLeader(resp, req)
+	if err == nil || !strings.Contains(err.Error(),
+		"id \"nope\" was not found in the Raft configuration") {
+		t.Fatalf("err: %v", err)
+	}
+}
+
 func TestOperator_KeyringInstall(t *testing.T) {
 	t.Parallel()
 	oldKey := "H3/9gBxcKKRf45CaI2DlRg=="
@@ -117,11 +117,21 @@ func (s *HTTPServer) preparedQueryExecute(id string, resp http.ResponseWriter, r
 
 		args.Connect = val
 	}
+	if raw := params.Get("explain"); raw != "" {
+		val, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing 'explain' value: %s", err)
+		}
+
+		args.Explain = val
+	}
 
 	var reply structs.PreparedQueryExecuteResponse
 	defer setMeta(resp, &reply.QueryMeta)
 
-	if args.QueryOptions.UseCache {
+	// Explanations are for debugging, so they always come fresh from the
+	// servers.
+	if args.QueryOptions.UseCache && !args.Explain {
 		raw, m, err := s.agent.cache.Get(cachetype.PreparedQueryName, &args)
 		if err != nil {
 			// Don't return error if StaleIfError is set and we are within it and had
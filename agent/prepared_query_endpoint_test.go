@@ -763,6 +763,38 @@ func TestPreparedQuery_Explain(t *testing.T) {
 		require.NoError(err)
 		require.Equal(200, resp.Code)
 	})
+
+	// Ensure that Explain is passed through, even when using the cache
+	t.Run("", func(t *testing.T) {
+		a := NewTestAgent(t, t.Name(), "")
+		defer a.Shutdown()
+		require := require.New(t)
+
+		m := MockPreparedQuery{
+			executeFn: func(args *structs.PreparedQueryExecuteRequest, reply *structs.PreparedQueryExecuteResponse) error {
+				require.True(args.Explain)
+				reply.Explain = &structs.QueryExecuteExplain{
+					Near: "node1",
+				}
+				return nil
+			},
+		}
+		require.NoError(a.registerEndpoint("PreparedQuery", &m))
+
+		body := bytes.NewBuffer(nil)
+		req, _ := http.NewRequest("GET", "/v1/query/my-id/execute?explain=1&cached", body)
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.PreparedQuerySpecific(resp, req)
+		require.NoError(err)
+		require.Equal(200, resp.Code)
+		require.Equal("node1", obj.(structs.PreparedQueryExecuteResponse).Explain.Near)
+		require.Empty(resp.Header().Get("X-Cache"))
+
+		req, _ = http.NewRequest("GET", "/v1/query/my-id/execute?explain=nope", body)
+		resp = httptest.NewRecorder()
+		_, err = a.srv.PreparedQuerySpecific(resp, req)
+		require.Error(err)
+	})
 }
 
 func TestPreparedQuery_Get(t *testing.T) {
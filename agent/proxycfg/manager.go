@@ -66,6 +66,9 @@ type ManagerConfig struct {
 	Source *structs.QuerySource
 	// logger is the agent's logger to be used for logging logs.
 	Logger *log.Logger
+	// IntentionDefaultAllow is whether connections that match no intention
+	// are allowed by default.
+	IntentionDefaultAllow bool
 }
 
 // NewManager constructs a manager from the provided agent cache.
@@ -127,7 +130,11 @@ func (m *Manager) Run() error {
 func (m *Manager) syncState() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.syncStateLocked()
+}
 
+// syncStateLocked is like syncState but expects the lock to be held.
+func (m *Manager) syncStateLocked() {
 	// Traverse the local state and ensure all proxy services are registered
 	services := m.State.Services()
 	for svcID, svc := range services {
@@ -158,6 +165,31 @@ func (m *Manager) syncState() {
 	}
 }
 
+// SetIntentionDefaultAllow changes whether connections that match no intention
+// are allowed by default, for example when the ACL default policy is
+// reloaded. The states of the current proxies are replaced so that their
+// snapshots are delivered again with the new value.
+func (m *Manager) SetIntentionDefaultAllow(allow bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.IntentionDefaultAllow == allow {
+		return
+	}
+	m.IntentionDefaultAllow = allow
+
+	// Before Run there are no states, and once closed there's nothing to
+	// restart.
+	if !m.started || m.stateCh == nil {
+		return
+	}
+	for proxyID, state := range m.proxies {
+		state.Close()
+		delete(m.proxies, proxyID)
+	}
+	m.syncStateLocked()
+}
+
 // ensureProxyServiceLocked adds or changes the proxy to our state.
 func (m *Manager) ensureProxyServiceLocked(ns *structs.NodeService, token string) error {
 	state, ok := m.proxies[ns.ID]
@@ -182,6 +214,7 @@ func (m *Manager) ensureProxyServiceLocked(ns *structs.NodeService, token string
 	state.logger = m.Logger
 	state.cache = m.Cache
 	state.source = m.Source
+	state.intentionDefaultAllow = m.IntentionDefaultAllow
 
 	ch, err := state.Watch()
 	if err != nil {
@@ -67,7 +67,7 @@ func TestManager_BasicLifecycle(t *testing.T) {
 	state.TriggerSyncChanges = func() {}
 
 	// Create manager
-	m, err := NewManager(ManagerConfig{c, state, source, logger})
+	m, err := NewManager(ManagerConfig{c, state, source, logger, true})
 	require.NoError(err)
 
 	// And run it
@@ -115,6 +115,9 @@ func TestManager_BasicLifecycle(t *testing.T) {
 		UpstreamEndpoints: map[string]structs.CheckServiceNodes{
 			"service:db": TestUpstreamNodes(t),
 		},
+		Intentions:            TestIntentions(t).Matches[0],
+		IntentionsSet:         true,
+		IntentionDefaultAllow: true,
 	}
 	start := time.Now()
 	assertWatchChanRecvs(t, wCh, expectSnap)
@@ -163,6 +166,14 @@ func TestManager_BasicLifecycle(t *testing.T) {
 	assertWatchChanRecvs(t, wCh, expectSnap)
 	assertWatchChanRecvs(t, wCh2, expectSnap)
 
+	// Change the default for connections that match no intention
+	m.SetIntentionDefaultAllow(false)
+
+	// Expect the new default in snapshot
+	expectSnap.IntentionDefaultAllow = false
+	assertWatchChanRecvs(t, wCh, expectSnap)
+	assertWatchChanRecvs(t, wCh2, expectSnap)
+
 	// Remove the proxy
 	state.RemoveService(webProxy.ID)
 
@@ -17,12 +17,19 @@ type ConfigSnapshot struct {
 	Leaf              *structs.IssuedCert
 	UpstreamEndpoints map[string]structs.CheckServiceNodes
 
-	// Skip intentions for now as we don't push those down yet, just pre-warm them.
+	// Intentions are the intentions that match the destination service,
+	// ordered by precedence. IntentionsSet is true once they are loaded.
+	Intentions    structs.Intentions
+	IntentionsSet bool
+
+	// IntentionDefaultAllow is whether connections that match no intention
+	// are allowed.
+	IntentionDefaultAllow bool
 }
 
 // Valid returns whether or not the snapshot has all required fields filled yet.
 func (s *ConfigSnapshot) Valid() bool {
-	return s.Roots != nil && s.Leaf != nil
+	return s.Roots != nil && s.Leaf != nil && s.IntentionsSet
 }
 
 // Clone makes a deep copy of the snapshot we can send to other goroutines
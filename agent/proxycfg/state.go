@@ -34,6 +34,9 @@ type state struct {
 	source *structs.QuerySource
 	cache  *cache.Cache
 
+	// intentionDefaultAllow is copied into the snapshots.
+	intentionDefaultAllow bool
+
 	// ctx and cancel store the context created during initWatches call
 	ctx    context.Context
 	cancel func()
@@ -205,6 +208,8 @@ func (s *state) run() {
 		Port:              s.port,
 		Proxy:             s.proxyCfg,
 		UpstreamEndpoints: make(map[string]structs.CheckServiceNodes),
+
+		IntentionDefaultAllow: s.intentionDefaultAllow,
 	}
 	// This turns out to be really fiddly/painful by just using time.Timer.C
 	// directly in the code below since you can't detect when a timer is stopped
@@ -293,7 +298,16 @@ func (s *state) handleUpdate(u cache.UpdateEvent, snap *ConfigSnapshot) error {
 		}
 		snap.Leaf = leaf
 	case intentionsWatchID:
-		// Not in snapshot currently, no op
+		resp, ok := u.Result.(*structs.IndexedIntentionMatches)
+		if !ok {
+			return fmt.Errorf("invalid type for intentions response: %T", u.Result)
+		}
+		// There is exactly one match set since we only match the destination
+		// service.
+		if len(resp.Matches) > 0 {
+			snap.Intentions = resp.Matches[0]
+		}
+		snap.IntentionsSet = true
 	default:
 		// Service discovery result, figure out which type
 		switch {
@@ -167,6 +167,8 @@ func TestConfigSnapshot(t testing.T) *ConfigSnapshot {
 		UpstreamEndpoints: map[string]structs.CheckServiceNodes{
 			"service:db": TestUpstreamNodes(t),
 		},
+		Intentions:    TestIntentions(t).Matches[0],
+		IntentionsSet: true,
 	}
 }
 
@@ -3,8 +3,10 @@ package router
 import (
 	"fmt"
 	"log"
+	"math"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/consul/agent/metadata"
 	"github.com/hashicorp/consul/agent/structs"
@@ -388,6 +390,52 @@ func (r *Router) GetDatacentersByDistance() ([]string, error) {
 	r.RLock()
 	defer r.RUnlock()
 
+	dcs, err := r.getDatacenterRTTs()
+	if err != nil {
+		return nil, err
+	}
+
+	// First sort by DC name, since we do a stable sort later.
+	names := make([]string, 0, len(dcs))
+	for dc := range dcs {
+		names = append(names, dc)
+	}
+	sort.Strings(names)
+
+	// Then stable sort by median RTT.
+	rtts := make([]float64, 0, len(dcs))
+	for _, dc := range names {
+		rtts = append(rtts, dcs[dc])
+	}
+	sort.Stable(&datacenterSorter{names, rtts})
+	return names, nil
+}
+
+// GetDatacenterRTTs returns the median RTT from this server to the servers in
+// each datacenter known to the router, which is what GetDatacentersByDistance
+// sorts by. Datacenters without an RTT estimate are left out.
+func (r *Router) GetDatacenterRTTs() (map[string]time.Duration, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dcs, err := r.getDatacenterRTTs()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]time.Duration)
+	for dc, rtt := range dcs {
+		if !math.IsInf(rtt, 0) {
+			result[dc] = time.Duration(rtt * float64(time.Second))
+		}
+	}
+	return result, nil
+}
+
+// getDatacenterRTTs returns the median RTT in seconds from this server to the
+// servers in each datacenter. The RTT is positive infinity if it can't be
+// estimated. The read lock must be held when calling this.
+func (r *Router) getDatacenterRTTs() (map[string]float64, error) {
 	// Go through each area and aggregate the median RTT from the current
 	// server to the other servers in each datacenter.
 	dcs := make(map[string]float64)
@@ -433,21 +481,7 @@ func (r *Router) GetDatacentersByDistance() ([]string, error) {
 			}
 		}
 	}
-
-	// First sort by DC name, since we do a stable sort later.
-	names := make([]string, 0, len(dcs))
-	for dc := range dcs {
-		names = append(names, dc)
-	}
-	sort.Strings(names)
-
-	// Then stable sort by median RTT.
-	rtts := make([]float64, 0, len(dcs))
-	for _, dc := range names {
-		rtts = append(rtts, dcs[dc])
-	}
-	sort.Stable(&datacenterSorter{names, rtts})
-	return names, nil
+	return dcs, nil
 }
 
 // GetDatacenterMaps returns a structure with the raw network coordinates of
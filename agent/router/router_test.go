@@ -418,6 +418,32 @@ func TestRouter_GetDatacentersByDistance(t *testing.T) {
 	}
 }
 
+func TestRouter_GetDatacenterRTTs(t *testing.T) {
+	r := testRouter("dc0")
+
+	self := "node0.dc0"
+	wan := testCluster(self)
+	if err := r.AddArea(types.AreaWAN, wan, &fauxConnPool{}, false); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	actual, err := r.GetDatacenterRTTs()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// dcX has no coordinates so it's left out.
+	if len(actual) != 3 {
+		t.Fatalf("bad: %#v", actual)
+	}
+	if actual["dc0"] != 0 {
+		t.Fatalf("bad: %#v", actual)
+	}
+	if !(0 < actual["dc2"] && actual["dc2"] < actual["dc1"]) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
 func TestRouter_GetDatacenterMaps(t *testing.T) {
 	r := testRouter("dc0")
 
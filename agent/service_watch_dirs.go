@@ -0,0 +1,439 @@
+package agent
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/hashicorp/consul/agent/config"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/types"
+	"github.com/mitchellh/hashstructure"
+)
+
+// serviceWatchDirSyncWait is how long the watcher waits for more changes to
+// the watched directories before it syncs the services. This avoids syncing
+// on every step of a file being written.
+var serviceWatchDirSyncWait = 250 * time.Millisecond
+
+// serviceDirFile is a service definition file in a watched directory.
+type serviceDirFile struct {
+	// hash identifies the contents of the file so that unchanged files
+	// aren't parsed again.
+	hash uint64
+
+	// services are the service definitions of the file, or nil if it
+	// failed to parse.
+	services []*structs.ServiceDefinition
+
+	// parseErr is the error of reading or parsing the file. It is kept
+	// until the file changes.
+	parseErr string
+
+	// err is the error of registering the services of the file during the
+	// last sync.
+	err string
+}
+
+// serviceDirService is a service registered from a watched directory.
+type serviceDirService struct {
+	file      string
+	hash      uint64
+	checkIDs  []types.CheckID
+	sidecarID string
+}
+
+// serviceDirWatcher keeps the services registered from a set of directories
+// in sync with the service definition files in them. Services are added,
+// updated and removed along with their files, and services whose
+// definitions haven't changed are left alone so their checks keep their
+// state.
+//
+// Only the services registered by the watcher are managed by it. Services
+// registered from the configuration or the HTTP API are never touched.
+type serviceDirWatcher struct {
+	agent  *Agent
+	logger *log.Logger
+	dirs   []string
+
+	watcher *fsnotify.Watcher
+	stopCh  chan struct{}
+
+	// lock protects the fields below. It must be acquired after the
+	// stateLock of the agent.
+	lock     sync.Mutex
+	stopped  bool
+	files    map[string]*serviceDirFile
+	services map[string]*serviceDirService
+}
+
+func newServiceDirWatcher(a *Agent, dirs []string) (*serviceDirWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return nil, fmt.Errorf("failed to watch service directory %q: %v", dir, err)
+		}
+	}
+
+	return &serviceDirWatcher{
+		agent:    a,
+		logger:   a.logger,
+		dirs:     dirs,
+		watcher:  watcher,
+		stopCh:   make(chan struct{}),
+		files:    make(map[string]*serviceDirFile),
+		services: make(map[string]*serviceDirService),
+	}, nil
+}
+
+// run syncs the services whenever the watched directories change until the
+// watcher is stopped.
+func (w *serviceDirWatcher) run() {
+	var syncCh <-chan time.Time
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			w.logger.Printf("[DEBUG] agent: Service directory change: %s", event)
+			if syncCh == nil {
+				syncCh = time.After(serviceWatchDirSyncWait)
+			}
+
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.logger.Printf("[ERR] agent: Failed watching service directories: %v", err)
+
+		case <-syncCh:
+			syncCh = nil
+			w.sync()
+
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// stop stops watching the directories. The services registered by the
+// watcher are left in place.
+func (w *serviceDirWatcher) stop() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.stopped {
+		return
+	}
+	w.stopped = true
+	close(w.stopCh)
+	w.watcher.Close()
+}
+
+// sync reads the watched directories and reconciles the local services with
+// the definitions in them.
+func (w *serviceDirWatcher) sync() {
+	w.agent.stateLock.Lock()
+	defer w.agent.stateLock.Unlock()
+	w.syncLocked()
+}
+
+// syncLocked is like sync but expects the stateLock of the agent to be held.
+func (w *serviceDirWatcher) syncLocked() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.stopped {
+		return
+	}
+
+	w.readFiles()
+	for _, f := range w.files {
+		f.err = ""
+	}
+
+	// Collect the wanted services from the files in a stable order so that
+	// conflicts between files are always resolved the same way.
+	var paths []string
+	for path := range w.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	type wantedService struct {
+		file string
+		def  *structs.ServiceDefinition
+	}
+	wanted := make(map[string]wantedService)
+	for _, path := range paths {
+		f := w.files[path]
+		if f.services == nil {
+			// Keep the services of a file that failed to parse as they are
+			// rather than removing them because of a typo.
+			for id, svc := range w.services {
+				if svc.file == path {
+					wanted[id] = wantedService{file: path}
+				}
+			}
+			continue
+		}
+
+		for _, def := range f.services {
+			id := def.ID
+			if id == "" {
+				id = def.Name
+			}
+			if other, ok := wanted[id]; ok {
+				w.setFileError(path, fmt.Errorf("service %q is already defined in %s", id, other.file))
+				continue
+			}
+			wanted[id] = wantedService{file: path, def: def}
+		}
+	}
+
+	// Pause the service syncs during modification
+	w.agent.PauseSync()
+	defer w.agent.ResumeSync()
+
+	// Remove the services whose definitions are gone.
+	for id, svc := range w.services {
+		if _, ok := wanted[id]; ok {
+			continue
+		}
+		w.removeService(id, svc)
+	}
+
+	// Add new services and update changed ones.
+	for id, want := range wanted {
+		if want.def == nil {
+			continue
+		}
+		if err := w.registerService(id, want.file, want.def); err != nil {
+			w.setFileError(want.file, err)
+		}
+	}
+}
+
+// readFiles reads the service definition files in the watched directories.
+// Files that haven't changed since they were last read are not parsed again.
+func (w *serviceDirWatcher) readFiles() {
+	seen := make(map[string]struct{})
+	for _, dir := range w.dirs {
+		fis, err := ioutil.ReadDir(dir)
+		if err != nil {
+			w.logger.Printf("[ERR] agent: Failed reading service directory %q: %v", dir, err)
+			// Keep the files of the directory as they were.
+			for path := range w.files {
+				if filepath.Dir(path) == filepath.Clean(dir) {
+					seen[path] = struct{}{}
+				}
+			}
+			continue
+		}
+
+		for _, fi := range fis {
+			path := filepath.Join(dir, fi.Name())
+			format := config.FormatFrom(path)
+			if format == "" {
+				continue
+			}
+
+			// Follow symlinks but do not recurse into sub dirs.
+			if fi.Mode()&os.ModeSymlink != 0 {
+				if fi, err = os.Stat(path); err != nil {
+					continue
+				}
+			}
+			if fi.IsDir() {
+				continue
+			}
+
+			seen[path] = struct{}{}
+			w.readFile(path, format)
+		}
+	}
+
+	for path := range w.files {
+		if _, ok := seen[path]; !ok {
+			delete(w.files, path)
+		}
+	}
+}
+
+// readFile parses a service definition file unless it's unchanged.
+func (w *serviceDirWatcher) readFile(path, format string) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		w.setParseError(path, 0, err)
+		return
+	}
+	hash, err := hashstructure.Hash(string(data), nil)
+	if err != nil {
+		w.setParseError(path, 0, err)
+		return
+	}
+	if f, ok := w.files[path]; ok && f.hash == hash {
+		return
+	}
+
+	defs, err := config.ParseServices(string(data), format)
+	if err != nil {
+		w.setParseError(path, hash, err)
+		return
+	}
+	if defs == nil {
+		defs = make([]*structs.ServiceDefinition, 0)
+	}
+	w.files[path] = &serviceDirFile{hash: hash, services: defs}
+}
+
+// setParseError records and logs the error of reading or parsing a file. The
+// previous services of the file are kept.
+func (w *serviceDirWatcher) setParseError(path string, hash uint64, err error) {
+	w.files[path] = &serviceDirFile{hash: hash, parseErr: err.Error()}
+	w.logger.Printf("[ERR] agent: Failed parsing service file %q: %v", path, err)
+}
+
+// setFileError records and logs the error of registering the services of a
+// file.
+func (w *serviceDirWatcher) setFileError(path string, err error) {
+	w.files[path].err = err.Error()
+	w.logger.Printf("[ERR] agent: Failed loading service file %q: %v", path, err)
+}
+
+// registerService adds or updates a service from a watched directory.
+func (w *serviceDirWatcher) registerService(id, file string, def *structs.ServiceDefinition) error {
+	hash, err := hashstructure.Hash(def, nil)
+	if err != nil {
+		return err
+	}
+
+	prev, ok := w.services[id]
+	registered := w.agent.State.Service(id) != nil
+	if ok && prev.hash == hash && registered {
+		// Leave unchanged services alone so that their checks keep their
+		// state.
+		prev.file = file
+		return nil
+	}
+	if !ok && registered {
+		return fmt.Errorf("service %q is already registered by another source", id)
+	}
+
+	ns := def.NodeService()
+	chkTypes, err := def.CheckTypes()
+	if err != nil {
+		return fmt.Errorf("failed to validate checks for service %q: %v", def.Name, err)
+	}
+
+	sidecar, sidecarChecks, sidecarToken, err := w.agent.sidecarServiceFromNodeService(ns, def.Token)
+	if err != nil {
+		return fmt.Errorf("failed to validate sidecar for service %q: %v", def.Name, err)
+	}
+	// Remove sidecar from NodeService now it's done it's job it's just a config
+	// syntax sugar and shouldn't be persisted in local or server state.
+	ns.Connect.SidecarService = nil
+
+	svc := &serviceDirService{file: file, hash: hash}
+	if ns.ID == "" {
+		ns.ID = ns.Service
+	}
+	for _, check := range w.agent.serviceHealthChecks(ns, chkTypes) {
+		svc.checkIDs = append(svc.checkIDs, check.CheckID)
+	}
+	if sidecar != nil {
+		svc.sidecarID = sidecar.ID
+	}
+
+	if err := w.agent.addServiceLocked(ns, chkTypes, false, def.Token, ConfigSourceLocal); err != nil {
+		return fmt.Errorf("failed to register service %q: %v", def.Name, err)
+	}
+	if sidecar != nil {
+		if err := w.agent.addServiceLocked(sidecar, sidecarChecks, false, sidecarToken, ConfigSourceLocal); err != nil {
+			return fmt.Errorf("failed to register sidecar for service %q: %v", def.Name, err)
+		}
+	}
+	w.services[id] = svc
+
+	// Now that the new definition is registered, remove the checks and the
+	// sidecar that are no longer part of it. Doing this first would leave
+	// the service half updated if registering it failed.
+	if prev != nil {
+		for _, checkID := range prev.checkIDs {
+			if !containsCheckID(svc.checkIDs, checkID) {
+				if err := w.agent.removeCheckLocked(checkID, false); err != nil {
+					return fmt.Errorf("failed to remove check %q: %v", checkID, err)
+				}
+			}
+		}
+		if prev.sidecarID != "" && prev.sidecarID != svc.sidecarID {
+			if err := w.agent.removeServiceLocked(prev.sidecarID, false); err != nil {
+				return fmt.Errorf("failed to remove sidecar %q: %v", prev.sidecarID, err)
+			}
+		}
+	}
+
+	if prev == nil {
+		w.logger.Printf("[INFO] agent: Registered service %q from %s", id, file)
+	} else {
+		w.logger.Printf("[INFO] agent: Updated service %q from %s", id, file)
+	}
+	return nil
+}
+
+// removeService removes a service whose definition is gone from the watched
+// directories. Its sidecar, if any, is removed along with it.
+func (w *serviceDirWatcher) removeService(id string, svc *serviceDirService) {
+	if err := w.agent.removeServiceLocked(id, false); err != nil {
+		w.logger.Printf("[ERR] agent: Failed removing service %q: %v", id, err)
+		return
+	}
+
+	// removeServiceLocked only removes a sidecar that it can tell was
+	// registered for the service, so remove the one registered here
+	// explicitly rather than relying on that.
+	if svc.sidecarID != "" && w.agent.State.Service(svc.sidecarID) != nil {
+		if err := w.agent.removeServiceLocked(svc.sidecarID, false); err != nil {
+			w.logger.Printf("[ERR] agent: Failed removing sidecar %q of service %q: %v",
+				svc.sidecarID, id, err)
+			return
+		}
+	}
+	delete(w.services, id)
+	w.logger.Printf("[INFO] agent: Removed service %q of %s", id, svc.file)
+}
+
+// fileErrors returns the errors of the service definition files by path.
+func (w *serviceDirWatcher) fileErrors() map[string]string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	errs := make(map[string]string)
+	for path, f := range w.files {
+		switch {
+		case f.parseErr != "":
+			errs[path] = f.parseErr
+		case f.err != "":
+			errs[path] = f.err
+		}
+	}
+	return errs
+}
+
+func containsCheckID(ids []types.CheckID, id types.CheckID) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
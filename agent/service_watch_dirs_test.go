@@ -0,0 +1,157 @@
+package agent
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/consul/agent/config"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgent_ServiceWatchDirs(t *testing.T) {
+	t.Parallel()
+
+	dir := testutil.TempDir(t, "services")
+	defer os.RemoveAll(dir)
+
+	writeFile := func(name, contents string) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0600))
+	}
+	writeFile("web.json", `{"service": {"name": "web", "port": 8080, "check": {"ttl": "30s"}}}`)
+	writeFile("broken.hcl", `service { name = `)
+	writeFile("README", `not a service definition`)
+
+	a := NewTestAgent(t, t.Name(), fmt.Sprintf(`service_watch_dirs = [%q]`, dir))
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	// The valid file is loaded and the broken one is reported.
+	require.NotNil(t, a.State.Service("web"))
+	errs := a.serviceDirs.fileErrors()
+	require.Len(t, errs, 1)
+	require.Contains(t, errs, filepath.Join(dir, "broken.hcl"))
+
+	// The errors are reported with the sync status.
+	req, _ := http.NewRequest("GET", "/v1/agent/sync-status", nil)
+	obj, err := a.srv.AgentSyncStatus(nil, req)
+	require.NoError(t, err)
+	require.Equal(t, errs, obj.(*api.AgentSyncStatus).ServiceFileErrors)
+
+	// Services from other sources are left alone.
+	require.NoError(t, a.AddService(&structs.NodeService{ID: "api", Service: "api"}, nil, false, "", ConfigSourceRemote))
+	a.State.UpdateCheck("service:web", api.HealthPassing, "ok")
+
+	// New files are added and unchanged services keep their check state.
+	writeFile("db.hcl", `services = [{ name = "db", port = 5432 }, { name = "cache" }]`)
+	retry.Run(t, func(r *retry.R) {
+		if a.State.Service("db") == nil || a.State.Service("cache") == nil {
+			r.Fatal("services not registered")
+		}
+	})
+	require.Equal(t, api.HealthPassing, a.State.Check("service:web").Status)
+	require.NotNil(t, a.State.Service("api"))
+
+	// Changed definitions are updated and checks that are still defined
+	// keep their state.
+	writeFile("web.json", `{"service": {"name": "web", "port": 9090, "check": {"ttl": "30s"}}}`)
+	retry.Run(t, func(r *retry.R) {
+		if port := a.State.Service("web").Port; port != 9090 {
+			r.Fatalf("bad port: %d", port)
+		}
+	})
+	require.Equal(t, api.HealthPassing, a.State.Check("service:web").Status)
+
+	// Checks that are no longer defined are removed.
+	writeFile("web.json", `{"service": {"name": "web", "port": 9090}}`)
+	retry.Run(t, func(r *retry.R) {
+		if a.State.Check("service:web") != nil {
+			r.Fatal("check not removed")
+		}
+	})
+
+	// A broken file keeps its previous services.
+	writeFile("db.hcl", `services = [{ name = `)
+	retry.Run(t, func(r *retry.R) {
+		if _, ok := a.serviceDirs.fileErrors()[filepath.Join(dir, "db.hcl")]; !ok {
+			r.Fatal("missing error")
+		}
+	})
+	require.NotNil(t, a.State.Service("db"))
+
+	// Removed files remove their services.
+	require.NoError(t, os.Remove(filepath.Join(dir, "db.hcl")))
+	retry.Run(t, func(r *retry.R) {
+		if a.State.Service("db") != nil || a.State.Service("cache") != nil {
+			r.Fatal("services not removed")
+		}
+	})
+	require.NotNil(t, a.State.Service("web"))
+	require.NotNil(t, a.State.Service("api"))
+
+	// Sidecars are registered and removed along with their service.
+	writeFile("proxied.json", `{"service": {"name": "proxied", "port": 3000, "connect": {
+		"sidecar_service": {"port": 21000}}}}`)
+	retry.Run(t, func(r *retry.R) {
+		if a.State.Service("proxied") == nil || a.State.Service("proxied-sidecar-proxy") == nil {
+			r.Fatal("services not registered")
+		}
+	})
+	require.NoError(t, os.Remove(filepath.Join(dir, "proxied.json")))
+	retry.Run(t, func(r *retry.R) {
+		if a.State.Service("proxied") != nil || a.State.Service("proxied-sidecar-proxy") != nil {
+			r.Fatal("services not removed")
+		}
+	})
+	a.serviceDirs.lock.Lock()
+	require.NotContains(t, a.serviceDirs.services, "proxied")
+	a.serviceDirs.lock.Unlock()
+
+	// Services registered by another source are not taken over.
+	writeFile("api.json", `{"service": {"name": "api", "port": 1234}}`)
+	retry.Run(t, func(r *retry.R) {
+		if _, ok := a.serviceDirs.fileErrors()[filepath.Join(dir, "api.json")]; !ok {
+			r.Fatal("missing error")
+		}
+	})
+	require.Equal(t, 0, a.State.Service("api").Port)
+}
+
+func TestAgent_ServiceWatchDirs_Reload(t *testing.T) {
+	t.Parallel()
+
+	dir := testutil.TempDir(t, "services")
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "web.json"),
+		[]byte(`{"service": {"name": "web", "check": {"ttl": "30s"}}}`), 0600))
+
+	hcl := fmt.Sprintf(`service_watch_dirs = [%q]`, dir)
+	a := NewTestAgent(t, t.Name(), hcl)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	require.NotNil(t, a.State.Service("web"))
+	a.State.UpdateCheck("service:web", api.HealthPassing, "ok")
+
+	// The services are registered again after a reload and keep their check
+	// state.
+	dataDir := fmt.Sprintf(`data_dir = %q`, a.Config.DataDir)
+	c := TestConfig(config.Source{Name: t.Name(), Format: "hcl", Data: dataDir + "\n" + hcl})
+	require.NoError(t, a.ReloadConfig(c))
+	require.NotNil(t, a.State.Service("web"))
+	require.Equal(t, api.HealthPassing, a.State.Check("service:web").Status)
+
+	// Without the directory the services are gone.
+	c = TestConfig(config.Source{Name: t.Name(), Format: "hcl", Data: dataDir})
+	require.NoError(t, a.ReloadConfig(c))
+	require.Nil(t, a.State.Service("web"))
+	require.Nil(t, a.serviceDirs)
+}
@@ -177,7 +177,7 @@ func (s *HTTPServer) SessionRenew(resp http.ResponseWriter, req *http.Request) (
 		return nil, nil
 	}
 
-	return out.Sessions, nil
+	return sessionsWithHeldKeys(&out), nil
 }
 
 // SessionGet is used to get info for a particular session
@@ -201,11 +201,64 @@ func (s *HTTPServer) SessionGet(resp http.ResponseWriter, req *http.Request) (in
 		return nil, err
 	}
 
+	return sessionsWithHeldKeys(&out), nil
+}
+
+// sessionWithHeldKeys is a session as returned by the HTTP API, along with
+// the number of keys it holds locks on, which isn't part of the stored
+// session.
+type sessionWithHeldKeys struct {
+	*structs.Session
+	HeldKeys int
+}
+
+// sessionsWithHeldKeys pairs the sessions of a reply with their held key
+// counts. It always returns a non-nil list.
+func sessionsWithHeldKeys(out *structs.IndexedSessions) []*sessionWithHeldKeys {
+	sessions := make([]*sessionWithHeldKeys, 0, len(out.Sessions))
+	for _, session := range out.Sessions {
+		sessions = append(sessions, &sessionWithHeldKeys{
+			Session:  session,
+			HeldKeys: out.HeldKeys[session.ID],
+		})
+	}
+	return sessions
+}
+
+// SessionKeys is used to get the keys that are locked by a session. It serves
+// /v1/session/:id/keys.
+func (s *HTTPServer) SessionKeys(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/session/")
+	if !strings.HasSuffix(path, "/keys") {
+		resp.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(resp, "Invalid path %q", req.URL.Path)
+		return nil, nil
+	}
+
+	args := structs.SessionSpecificRequest{}
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	// Pull out the session id
+	args.Session = strings.TrimSuffix(path, "/keys")
+	if args.Session == "" {
+		resp.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(resp, "Missing session")
+		return nil, nil
+	}
+
+	var out structs.IndexedKeyList
+	defer setMeta(resp, &out.QueryMeta)
+	if err := s.agent.RPC("Session.Keys", &args, &out); err != nil {
+		return nil, err
+	}
+
 	// Use empty list instead of nil
-	if out.Sessions == nil {
-		out.Sessions = make(structs.Sessions, 0)
+	if out.Keys == nil {
+		out.Keys = make([]string, 0)
 	}
-	return out.Sessions, nil
+	return out.Keys, nil
 }
 
 // SessionList is used to list all the sessions
@@ -221,11 +274,7 @@ func (s *HTTPServer) SessionList(resp http.ResponseWriter, req *http.Request) (i
 		return nil, err
 	}
 
-	// Use empty list instead of nil
-	if out.Sessions == nil {
-		out.Sessions = make(structs.Sessions, 0)
-	}
-	return out.Sessions, nil
+	return sessionsWithHeldKeys(&out), nil
 }
 
 // SessionsForNode returns all the nodes belonging to a node
@@ -249,9 +298,5 @@ func (s *HTTPServer) SessionsForNode(resp http.ResponseWriter, req *http.Request
 		return nil, err
 	}
 
-	// Use empty list instead of nil
-	if out.Sessions == nil {
-		out.Sessions = make(structs.Sessions, 0)
-	}
-	return out.Sessions, nil
+	return sessionsWithHeldKeys(&out), nil
 }
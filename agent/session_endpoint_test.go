@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 
@@ -342,7 +343,7 @@ func TestSessionCustomTTL(t *testing.T) {
 		if err != nil {
 			r.Fatalf("err: %v", err)
 		}
-		respObj, ok := obj.(structs.Sessions)
+		respObj, ok := obj.([]*sessionWithHeldKeys)
 		if !ok {
 			r.Fatalf("should work")
 		}
@@ -361,7 +362,7 @@ func TestSessionCustomTTL(t *testing.T) {
 		if err != nil {
 			r.Fatalf("err: %v", err)
 		}
-		respObj, ok = obj.(structs.Sessions)
+		respObj, ok = obj.([]*sessionWithHeldKeys)
 		if len(respObj) != 0 {
 			r.Fatalf("session '%s' should have been destroyed", id)
 		}
@@ -385,7 +386,7 @@ func TestSessionTTLRenew(t *testing.T) {
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
-	respObj, ok := obj.(structs.Sessions)
+	respObj, ok := obj.([]*sessionWithHeldKeys)
 	if !ok {
 		t.Fatalf("should work")
 	}
@@ -405,7 +406,7 @@ func TestSessionTTLRenew(t *testing.T) {
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
-	respObj, ok = obj.(structs.Sessions)
+	respObj, ok = obj.([]*sessionWithHeldKeys)
 	if !ok {
 		t.Fatalf("should work")
 	}
@@ -422,7 +423,7 @@ func TestSessionTTLRenew(t *testing.T) {
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
-	respObj, ok = obj.(structs.Sessions)
+	respObj, ok = obj.([]*sessionWithHeldKeys)
 	if !ok {
 		t.Fatalf("session '%s' should have renewed", id)
 	}
@@ -439,7 +440,7 @@ func TestSessionTTLRenew(t *testing.T) {
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
-	respObj, ok = obj.(structs.Sessions)
+	respObj, ok = obj.([]*sessionWithHeldKeys)
 	if !ok {
 		t.Fatalf("session '%s' should have destroyed", id)
 	}
@@ -462,7 +463,7 @@ func TestSessionGet(t *testing.T) {
 			if err != nil {
 				r.Fatalf("err: %v", err)
 			}
-			respObj, ok := obj.(structs.Sessions)
+			respObj, ok := obj.([]*sessionWithHeldKeys)
 			if !ok {
 				r.Fatalf("should work")
 			}
@@ -485,7 +486,7 @@ func TestSessionGet(t *testing.T) {
 		if err != nil {
 			t.Fatalf("err: %v", err)
 		}
-		respObj, ok := obj.(structs.Sessions)
+		respObj, ok := obj.([]*sessionWithHeldKeys)
 		if !ok {
 			t.Fatalf("should work")
 		}
@@ -507,7 +508,7 @@ func TestSessionList(t *testing.T) {
 		if err != nil {
 			t.Fatalf("err: %v", err)
 		}
-		respObj, ok := obj.(structs.Sessions)
+		respObj, ok := obj.([]*sessionWithHeldKeys)
 		if !ok {
 			t.Fatalf("should work")
 		}
@@ -532,7 +533,7 @@ func TestSessionList(t *testing.T) {
 		if err != nil {
 			t.Fatalf("err: %v", err)
 		}
-		respObj, ok := obj.(structs.Sessions)
+		respObj, ok := obj.([]*sessionWithHeldKeys)
 		if !ok {
 			t.Fatalf("should work")
 		}
@@ -555,7 +556,7 @@ func TestSessionsForNode(t *testing.T) {
 		if err != nil {
 			t.Fatalf("err: %v", err)
 		}
-		respObj, ok := obj.(structs.Sessions)
+		respObj, ok := obj.([]*sessionWithHeldKeys)
 		if !ok {
 			t.Fatalf("should work")
 		}
@@ -580,7 +581,7 @@ func TestSessionsForNode(t *testing.T) {
 		if err != nil {
 			t.Fatalf("err: %v", err)
 		}
-		respObj, ok := obj.(structs.Sessions)
+		respObj, ok := obj.([]*sessionWithHeldKeys)
 		if !ok {
 			t.Fatalf("should work")
 		}
@@ -590,6 +591,73 @@ func TestSessionsForNode(t *testing.T) {
 	})
 }
 
+func TestSessionKeys(t *testing.T) {
+	t.Parallel()
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	id := makeTestSession(t, a.srv)
+
+	// No keys are locked yet
+	req, _ := http.NewRequest("GET", "/v1/session/"+id+"/keys", nil)
+	resp := httptest.NewRecorder()
+	obj, err := a.srv.SessionKeys(resp, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	keys, ok := obj.([]string)
+	if !ok {
+		t.Fatalf("should work")
+	}
+	if keys == nil || len(keys) != 0 {
+		t.Fatalf("bad: %v", keys)
+	}
+
+	// Lock a key
+	req, _ = http.NewRequest("PUT", "/v1/kv/test?acquire="+id, bytes.NewReader(nil))
+	resp = httptest.NewRecorder()
+	obj, err = a.srv.KVSEndpoint(resp, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if res := obj.(bool); !res {
+		t.Fatalf("should work")
+	}
+
+	req, _ = http.NewRequest("GET", "/v1/session/"+id+"/keys", nil)
+	resp = httptest.NewRecorder()
+	obj, err = a.srv.SessionKeys(resp, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if keys := obj.([]string); !reflect.DeepEqual(keys, []string{"test"}) {
+		t.Fatalf("bad: %v", keys)
+	}
+
+	// The session counts the key it holds
+	req, _ = http.NewRequest("GET", "/v1/session/info/"+id, nil)
+	resp = httptest.NewRecorder()
+	obj, err = a.srv.SessionGet(resp, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	sessions := obj.([]*sessionWithHeldKeys)
+	if len(sessions) != 1 || sessions[0].ID != id || sessions[0].HeldKeys != 1 {
+		t.Fatalf("bad: %v", sessions)
+	}
+
+	// Other paths below /v1/session/ aren't found
+	req, _ = http.NewRequest("GET", "/v1/session/"+id+"/nope", nil)
+	resp = httptest.NewRecorder()
+	if _, err := a.srv.SessionKeys(resp, req); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("bad: %d", resp.Code)
+	}
+}
+
 func TestSessionDeleteDestroy(t *testing.T) {
 	t.Parallel()
 	a := NewTestAgent(t, t.Name(), "")
@@ -25,7 +25,20 @@ type CheckDefinition struct {
 	HTTP                           string
 	Header                         map[string][]string
 	Method                         string
+	BodyMatch                      string
+	BodyMatchRegex                 bool
+	JSONAssertions                 []CheckJSONAssertion
 	TCP                            string
+	UDP                            string
+	UDPSend                        string
+	UDPExpect                      string
+	DNS                            string
+	DNSServer                      string
+	DNSRecordType                  string
+	DNSExpect                      string
+	TLS                            string
+	TLSServerName                  string
+	TLSExpiryWarningDays           int
 	Interval                       time.Duration
 	DockerContainerID              string
 	Shell                          string
@@ -36,6 +49,8 @@ type CheckDefinition struct {
 	AliasService                   string
 	Timeout                        time.Duration
 	TTL                            time.Duration
+	SuccessBeforePassing           int
+	FailuresBeforeCritical         int
 	DeregisterCriticalServiceAfter time.Duration
 }
 
@@ -72,13 +87,28 @@ func (c *CheckDefinition) CheckType() *CheckType {
 		GRPCUseTLS:                     c.GRPCUseTLS,
 		Header:                         c.Header,
 		Method:                         c.Method,
+		BodyMatch:                      c.BodyMatch,
+		BodyMatchRegex:                 c.BodyMatchRegex,
+		JSONAssertions:                 c.JSONAssertions,
 		TCP:                            c.TCP,
+		UDP:                            c.UDP,
+		UDPSend:                        c.UDPSend,
+		UDPExpect:                      c.UDPExpect,
+		DNS:                            c.DNS,
+		DNSServer:                      c.DNSServer,
+		DNSRecordType:                  c.DNSRecordType,
+		DNSExpect:                      c.DNSExpect,
+		TLS:                            c.TLS,
+		TLSServerName:                  c.TLSServerName,
+		TLSExpiryWarningDays:           c.TLSExpiryWarningDays,
 		Interval:                       c.Interval,
 		DockerContainerID:              c.DockerContainerID,
 		Shell:                          c.Shell,
 		TLSSkipVerify:                  c.TLSSkipVerify,
 		Timeout:                        c.Timeout,
 		TTL:                            c.TTL,
+		SuccessBeforePassing:           c.SuccessBeforePassing,
+		FailuresBeforeCritical:         c.FailuresBeforeCritical,
 		DeregisterCriticalServiceAfter: c.DeregisterCriticalServiceAfter,
 	}
 }
@@ -92,6 +92,8 @@ func TestCheckDefinitionToCheckType(t *testing.T) {
 		TLSSkipVerify:                  true,
 		Timeout:                        2 * time.Second,
 		TTL:                            3 * time.Second,
+		SuccessBeforePassing:           5,
+		FailuresBeforeCritical:         6,
 		DeregisterCriticalServiceAfter: 4 * time.Second,
 	}
 	want := &CheckType{
@@ -109,6 +111,8 @@ func TestCheckDefinitionToCheckType(t *testing.T) {
 		TLSSkipVerify:                  true,
 		Timeout:                        2 * time.Second,
 		TTL:                            3 * time.Second,
+		SuccessBeforePassing:           5,
+		FailuresBeforeCritical:         6,
 		DeregisterCriticalServiceAfter: 4 * time.Second,
 	}
 	verify.Values(t, "", got.CheckType(), want)
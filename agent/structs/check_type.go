@@ -3,16 +3,22 @@ package structs
 import (
 	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/types"
+	"github.com/miekg/dns"
 )
 
 // CheckType is used to create either the CheckMonitor or the CheckTTL.
-// The following types are supported: Script, HTTP, TCP, Docker, TTL, GRPC, Alias. Script,
-// HTTP, Docker, TCP and GRPC all require Interval. Only one of the types may
-// to be provided: TTL or Script/Interval or HTTP/Interval or TCP/Interval or
-// Docker/Interval or GRPC/Interval or AliasService.
+// The following types are supported: Script, HTTP, TCP, UDP, DNS, TLS, Docker,
+// TTL, GRPC, Alias. Script, HTTP, Docker, TCP, UDP, DNS, TLS and GRPC all
+// require Interval. Only one of the types may to be provided: TTL or
+// Script/Interval or HTTP/Interval or TCP/Interval or UDP/Interval or
+// DNS/Interval or TLS/Interval or Docker/Interval or GRPC/Interval or
+// AliasService.
 type CheckType struct {
 	// fields already embedded in CheckDefinition
 	// Note: CheckType.CheckID == CheckDefinition.ID
@@ -29,7 +35,19 @@ type CheckType struct {
 	HTTP              string
 	Header            map[string][]string
 	Method            string
+	BodyMatch         string
+	BodyMatchRegex    bool
+	JSONAssertions    []CheckJSONAssertion
 	TCP               string
+	UDP               string
+	UDPSend           string
+	UDPExpect         string
+	DNS               string
+	DNSServer         string
+	DNSRecordType     string
+	DNSExpect         string
+	TLS               string
+	TLSServerName     string
 	Interval          time.Duration
 	AliasNode         string
 	AliasService      string
@@ -41,6 +59,18 @@ type CheckType struct {
 	Timeout           time.Duration
 	TTL               time.Duration
 
+	// TLSExpiryWarningDays is the number of days before the certificate
+	// presented to a TLS check expires that the check starts warning. 0
+	// disables the warning.
+	TLSExpiryWarningDays int
+
+	// SuccessBeforePassing and FailuresBeforeCritical are the number of
+	// consecutive successful or failed results a check must return before
+	// its status is changed to passing or critical. Values of 0 and 1 both
+	// mean the status changes on the first result.
+	SuccessBeforePassing   int
+	FailuresBeforeCritical int
+
 	// DeregisterCriticalServiceAfter, if >0, will cause the associated
 	// service, if any, to be deregistered if this check is critical for
 	// longer than this duration.
@@ -48,15 +78,33 @@ type CheckType struct {
 }
 type CheckTypes []*CheckType
 
+// CheckJSONAssertion asserts that the value at Path in the JSON body returned
+// to an HTTP check equals Value. If it doesn't, the check reports Status,
+// which is critical if not set.
+type CheckJSONAssertion struct {
+	// Path is a dot separated path into the body, such as "checks.db.status"
+	// or "items[0].state". It may start with "$.".
+	Path string
+
+	// Value is compared to the value at Path. Strings are compared as is,
+	// other values are compared to their JSON encoding.
+	Value string
+
+	// Status is the status the check reports when the assertion fails,
+	// either warning or critical.
+	Status string
+}
+
 // Validate returns an error message if the check is invalid
 func (c *CheckType) Validate() error {
-	intervalCheck := c.IsScript() || c.HTTP != "" || c.TCP != "" || c.GRPC != ""
+	intervalCheck := c.IsScript() || c.HTTP != "" || c.TCP != "" || c.GRPC != "" ||
+		c.UDP != "" || c.DNS != "" || c.TLS != ""
 
 	if c.Interval > 0 && c.TTL > 0 {
 		return fmt.Errorf("Interval and TTL cannot both be specified")
 	}
 	if intervalCheck && c.Interval <= 0 {
-		return fmt.Errorf("Interval must be > 0 for Script, HTTP, or TCP checks")
+		return fmt.Errorf("Interval must be > 0 for Script, HTTP, TCP, gRPC, UDP, DNS, or TLS checks")
 	}
 	if intervalCheck && c.IsAlias() {
 		return fmt.Errorf("Interval cannot be set for Alias checks")
@@ -67,6 +115,38 @@ func (c *CheckType) Validate() error {
 	if !intervalCheck && !c.IsAlias() && c.TTL <= 0 {
 		return fmt.Errorf("TTL must be > 0 for TTL checks")
 	}
+	if c.SuccessBeforePassing < 0 || c.FailuresBeforeCritical < 0 {
+		return fmt.Errorf("SuccessBeforePassing and FailuresBeforeCritical must not be negative")
+	}
+	if c.DNS != "" && c.DNSServer == "" {
+		return fmt.Errorf("DNSServer must be set for DNS checks")
+	}
+	if c.DNSRecordType != "" {
+		if _, ok := dns.StringToType[strings.ToUpper(c.DNSRecordType)]; !ok {
+			return fmt.Errorf("DNSRecordType %q is not a valid DNS record type", c.DNSRecordType)
+		}
+	}
+	if c.TLSExpiryWarningDays < 0 {
+		return fmt.Errorf("TLSExpiryWarningDays must not be negative")
+	}
+	if c.HTTP == "" && (c.BodyMatch != "" || len(c.JSONAssertions) > 0) {
+		return fmt.Errorf("BodyMatch and JSONAssertions are only supported for HTTP checks")
+	}
+	if c.BodyMatchRegex {
+		if _, err := regexp.Compile(c.BodyMatch); err != nil {
+			return fmt.Errorf("BodyMatch is not a valid regular expression: %v", err)
+		}
+	}
+	for _, a := range c.JSONAssertions {
+		if a.Path == "" {
+			return fmt.Errorf("JSONAssertions must have a Path")
+		}
+		switch a.Status {
+		case "", api.HealthWarning, api.HealthCritical:
+		default:
+			return fmt.Errorf("JSONAssertions Status must be %q or %q", api.HealthWarning, api.HealthCritical)
+		}
+	}
 	return nil
 }
 
@@ -105,6 +185,21 @@ func (c *CheckType) IsTCP() bool {
 	return c.TCP != "" && c.Interval > 0
 }
 
+// IsUDP checks if this is a UDP type
+func (c *CheckType) IsUDP() bool {
+	return c.UDP != "" && c.Interval > 0
+}
+
+// IsDNS checks if this is a DNS type
+func (c *CheckType) IsDNS() bool {
+	return c.DNS != "" && c.Interval > 0
+}
+
+// IsTLS checks if this is a TLS type
+func (c *CheckType) IsTLS() bool {
+	return c.TLS != "" && c.Interval > 0
+}
+
 // IsDocker returns true when checking a docker container.
 func (c *CheckType) IsDocker() bool {
 	return c.IsScript() && c.DockerContainerID != "" && c.Interval > 0
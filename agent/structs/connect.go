@@ -20,6 +20,10 @@ type ConnectAuthorizeRequest struct {
 	// lists.
 	ClientCertURI    string
 	ClientCertSerial string
+
+	// ClientAddr is the IP address the client connects from, if known. It
+	// is matched against the intentions with a CIDR source.
+	ClientAddr string
 }
 
 // ProxyExecMode encodes the mode for running a managed connect proxy.
@@ -2,6 +2,8 @@ package structs
 
 import (
 	"fmt"
+	"net"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -49,9 +51,19 @@ type Intention struct {
 	// SourceType is the type of the value for the source.
 	SourceType IntentionSourceType
 
-	// Action is whether this is a whitelist or blacklist intention.
+	// Action is whether this is a whitelist or blacklist intention. It must
+	// be empty when Permissions are set.
 	Action IntentionAction
 
+	// Permissions is the ordered list of L7 permissions that apply to
+	// requests from the source to the destination. The first permission that
+	// matches a request decides whether it is allowed, and requests that
+	// match none of them fall back to the default intention behavior.
+	// Permissions can only be enforced by proxies that speak HTTP to the
+	// destination, so connections from the source are denied by proxies that
+	// only authorize connections.
+	Permissions []*IntentionPermission `json:",omitempty"`
+
 	// DefaultAddr, DefaultPort of the local listening proxy (if any) to
 	// make this connection.
 	DefaultAddr string
@@ -148,25 +160,55 @@ func (x *Intention) Validate() error {
 		}
 	}
 
-	switch x.Action {
-	case IntentionActionAllow, IntentionActionDeny:
-	default:
-		result = multierror.Append(result, fmt.Errorf(
-			"Action must be set to 'allow' or 'deny'"))
+	if len(x.Permissions) > 0 {
+		if x.Action != "" {
+			result = multierror.Append(result, fmt.Errorf(
+				"Action must not be set when Permissions are set"))
+		}
+		for i, perm := range x.Permissions {
+			if err := perm.Validate(); err != nil {
+				result = multierror.Append(result, fmt.Errorf(
+					"Permissions[%d]: %v", i, err))
+			}
+		}
+	} else {
+		switch x.Action {
+		case IntentionActionAllow, IntentionActionDeny:
+		default:
+			result = multierror.Append(result, fmt.Errorf(
+				"Action must be set to 'allow' or 'deny'"))
+		}
 	}
 
 	switch x.SourceType {
 	case IntentionSourceConsul:
+	case IntentionSourceCIDR:
+		// The CIDR block must be in its canonical form so that the same
+		// block can't be used for more than one intention.
+		_, ipNet, err := net.ParseCIDR(x.SourceName)
+		if err != nil {
+			result = multierror.Append(result, fmt.Errorf(
+				"SourceName must be a CIDR block for the 'cidr' SourceType"))
+		} else if ipNet.String() != x.SourceName {
+			result = multierror.Append(result, fmt.Errorf(
+				"SourceName must be the CIDR block %q", ipNet.String()))
+		}
+		if x.SourceNS != IntentionDefaultNamespace {
+			result = multierror.Append(result, fmt.Errorf(
+				"SourceNS must be %q for the 'cidr' SourceType", IntentionDefaultNamespace))
+		}
 	default:
 		result = multierror.Append(result, fmt.Errorf(
-			"SourceType must be set to 'consul'"))
+			"SourceType must be set to 'consul' or 'cidr'"))
 	}
 
 	return result
 }
 
 // UpdatePrecedence sets the Precedence value based on the fields of this
-// structure.
+// structure. A CIDR source counts as an exact source, and the
+// IntentionPrecedenceSorter orders it after the service sources of the same
+// precedence.
 func (x *Intention) UpdatePrecedence() {
 	// Max maintains the maximum value that the precedence can be depending
 	// on the number of exact values in the destination.
@@ -208,6 +250,66 @@ func (x *Intention) countExact(ns, n string) int {
 	return 2
 }
 
+// SourceCIDRContains returns true if the intention has a CIDR source that
+// contains the given address.
+func (x *Intention) SourceCIDRContains(ip net.IP) bool {
+	if x.SourceType != IntentionSourceCIDR || ip == nil {
+		return false
+	}
+	_, ipNet, err := net.ParseCIDR(x.SourceName)
+	if err != nil {
+		return false
+	}
+	return ipNet.Contains(ip)
+}
+
+// AuthorizeSourceAddr is like connect.SpiffeIDService.Authorize for the
+// intentions with a CIDR source, which match on the address of the client.
+// It returns whether the intention allows the address, and whether the
+// intention applies to it at all.
+func (x *Intention) AuthorizeSourceAddr(ip net.IP) (bool, bool) {
+	if !x.SourceCIDRContains(ip) {
+		return false, false
+	}
+	if x.HasPermissions() {
+		return false, true
+	}
+	return x.Action == IntentionActionAllow, true
+}
+
+// SourceCIDRCovers returns true if both intentions have a CIDR source and
+// every address in the source of other is also in the source of x.
+func (x *Intention) SourceCIDRCovers(other *Intention) bool {
+	if x.SourceType != IntentionSourceCIDR || other.SourceType != IntentionSourceCIDR {
+		return false
+	}
+	_, xNet, err := net.ParseCIDR(x.SourceName)
+	if err != nil {
+		return false
+	}
+	_, otherNet, err := net.ParseCIDR(other.SourceName)
+	if err != nil {
+		return false
+	}
+	xOnes, xBits := xNet.Mask.Size()
+	otherOnes, otherBits := otherNet.Mask.Size()
+	return xBits == otherBits && xOnes <= otherOnes && xNet.Contains(otherNet.IP)
+}
+
+// sourceCIDRPrefixLen returns the prefix length of a CIDR source, or -1 if
+// the source isn't a CIDR block.
+func (x *Intention) sourceCIDRPrefixLen() int {
+	if x.SourceType != IntentionSourceCIDR {
+		return -1
+	}
+	_, ipNet, err := net.ParseCIDR(x.SourceName)
+	if err != nil {
+		return -1
+	}
+	ones, _ := ipNet.Mask.Size()
+	return ones
+}
+
 // GetACLPrefix returns the prefix to look up the ACL policy for this
 // intention, and a boolean noting whether the prefix is valid to check
 // or not. You must check the ok value before using the prefix.
@@ -215,10 +317,20 @@ func (x *Intention) GetACLPrefix() (string, bool) {
 	return x.DestinationName, x.DestinationName != ""
 }
 
+// HasPermissions returns true if the intention has L7 permissions rather
+// than a single action.
+func (x *Intention) HasPermissions() bool {
+	return len(x.Permissions) > 0
+}
+
 // String returns a human-friendly string for this intention.
 func (x *Intention) String() string {
+	action := strings.ToUpper(string(x.Action))
+	if x.HasPermissions() {
+		action = "L7"
+	}
 	return fmt.Sprintf("%s %s/%s => %s/%s (ID: %s, Precedence: %d)",
-		strings.ToUpper(string(x.Action)),
+		action,
 		x.SourceNS, x.SourceName,
 		x.DestinationNS, x.DestinationName,
 		x.ID, x.Precedence)
@@ -234,9 +346,189 @@ func (x *Intention) EstimateSize() int {
 		size += len(k) + len(v)
 	}
 
+	for _, perm := range x.Permissions {
+		size += perm.estimateSize()
+	}
+
+	return size
+}
+
+// IntentionPermission is an L7 permission of an intention. It allows or
+// denies the HTTP requests that match it.
+type IntentionPermission struct {
+	// Action is whether matching requests are allowed or denied.
+	Action IntentionAction
+
+	// HTTP matches the HTTP requests the permission applies to.
+	HTTP *IntentionHTTPPermission
+}
+
+// Validate returns an error if the permission is invalid.
+func (p *IntentionPermission) Validate() error {
+	if p == nil {
+		return fmt.Errorf("permission must not be empty")
+	}
+	switch p.Action {
+	case IntentionActionAllow, IntentionActionDeny:
+	default:
+		return fmt.Errorf("Action must be set to 'allow' or 'deny'")
+	}
+	if p.HTTP == nil {
+		return fmt.Errorf("HTTP must be set")
+	}
+	return p.HTTP.Validate()
+}
+
+func (p *IntentionPermission) estimateSize() int {
+	if p == nil {
+		return 0
+	}
+	size := len(p.Action)
+	if h := p.HTTP; h != nil {
+		size += len(h.PathExact) + len(h.PathPrefix) + len(h.PathRegex)
+		for _, m := range h.Methods {
+			size += len(m)
+		}
+		for _, hdr := range h.Header {
+			size += len(hdr.Name) + len(hdr.Exact) + len(hdr.Prefix) +
+				len(hdr.Suffix) + len(hdr.Regex) + 2
+		}
+	}
 	return size
 }
 
+// IntentionHTTPPermission matches HTTP requests by their path, method and
+// headers. All of the set fields have to match.
+type IntentionHTTPPermission struct {
+	// PathExact, PathPrefix and PathRegex match the path of the request
+	// without its query string. At most one of them may be set.
+	PathExact  string `json:",omitempty"`
+	PathPrefix string `json:",omitempty"`
+	PathRegex  string `json:",omitempty"`
+
+	// Header matches the headers of the request.
+	Header []IntentionHTTPHeaderPermission `json:",omitempty"`
+
+	// Methods are the HTTP methods that match. Any method matches if it
+	// is empty.
+	Methods []string `json:",omitempty"`
+}
+
+// intentionHTTPMethods are the methods that can be used in permissions.
+var intentionHTTPMethods = map[string]struct{}{
+	"GET":     {},
+	"HEAD":    {},
+	"POST":    {},
+	"PUT":     {},
+	"PATCH":   {},
+	"DELETE":  {},
+	"CONNECT": {},
+	"OPTIONS": {},
+	"TRACE":   {},
+}
+
+// Validate returns an error if the HTTP permission is invalid.
+func (p *IntentionHTTPPermission) Validate() error {
+	var result error
+
+	paths := 0
+	for _, path := range []string{p.PathExact, p.PathPrefix, p.PathRegex} {
+		if path != "" {
+			paths++
+		}
+	}
+	if paths > 1 {
+		result = multierror.Append(result, fmt.Errorf(
+			"at most one of PathExact, PathPrefix or PathRegex may be set"))
+	}
+	if p.PathExact != "" && !strings.HasPrefix(p.PathExact, "/") {
+		result = multierror.Append(result, fmt.Errorf(
+			"PathExact must start with '/'"))
+	}
+	if p.PathPrefix != "" && !strings.HasPrefix(p.PathPrefix, "/") {
+		result = multierror.Append(result, fmt.Errorf(
+			"PathPrefix must start with '/'"))
+	}
+	if p.PathRegex != "" {
+		if _, err := regexp.Compile(p.PathRegex); err != nil {
+			result = multierror.Append(result, fmt.Errorf(
+				"PathRegex is invalid: %v", err))
+		}
+	}
+
+	for i, hdr := range p.Header {
+		if err := hdr.Validate(); err != nil {
+			result = multierror.Append(result, fmt.Errorf(
+				"Header[%d]: %v", i, err))
+		}
+	}
+
+	seen := make(map[string]struct{}, len(p.Methods))
+	for _, method := range p.Methods {
+		if _, ok := intentionHTTPMethods[method]; !ok {
+			result = multierror.Append(result, fmt.Errorf(
+				"Methods contains invalid method %q", method))
+		}
+		if _, ok := seen[method]; ok {
+			result = multierror.Append(result, fmt.Errorf(
+				"Methods contains %q more than once", method))
+		}
+		seen[method] = struct{}{}
+	}
+
+	if paths == 0 && len(p.Header) == 0 && len(p.Methods) == 0 {
+		result = multierror.Append(result, fmt.Errorf(
+			"at least one of a path, Header or Methods must be set"))
+	}
+
+	return result
+}
+
+// IntentionHTTPHeaderPermission matches a header of an HTTP request.
+type IntentionHTTPHeaderPermission struct {
+	// Name is the name of the header.
+	Name string
+
+	// Present, Exact, Prefix, Suffix and Regex are the ways to match the
+	// value of the header. Exactly one of them must be set.
+	Present bool   `json:",omitempty"`
+	Exact   string `json:",omitempty"`
+	Prefix  string `json:",omitempty"`
+	Suffix  string `json:",omitempty"`
+	Regex   string `json:",omitempty"`
+
+	// Invert inverts the match.
+	Invert bool `json:",omitempty"`
+}
+
+// Validate returns an error if the header permission is invalid.
+func (p *IntentionHTTPHeaderPermission) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("Name must be set")
+	}
+
+	matches := 0
+	if p.Present {
+		matches++
+	}
+	for _, v := range []string{p.Exact, p.Prefix, p.Suffix, p.Regex} {
+		if v != "" {
+			matches++
+		}
+	}
+	if matches != 1 {
+		return fmt.Errorf(
+			"exactly one of Present, Exact, Prefix, Suffix or Regex must be set")
+	}
+
+	if p.Regex != "" {
+		if _, err := regexp.Compile(p.Regex); err != nil {
+			return fmt.Errorf("Regex is invalid: %v", err)
+		}
+	}
+	return nil
+}
+
 // IntentionAction is the action that the intention represents. This
 // can be "allow" or "deny" to whitelist or blacklist intentions.
 type IntentionAction string
@@ -252,6 +544,11 @@ type IntentionSourceType string
 const (
 	// IntentionSourceConsul is a service within the Consul catalog.
 	IntentionSourceConsul IntentionSourceType = "consul"
+
+	// IntentionSourceCIDR is a block of network addresses in CIDR notation
+	// that connections come from, which allows intentions for clients
+	// outside of the service mesh.
+	IntentionSourceCIDR IntentionSourceType = "cidr"
 )
 
 // Intentions is a list of intentions.
@@ -276,6 +573,10 @@ const (
 	IntentionOpCreate IntentionOp = "create"
 	IntentionOpUpdate IntentionOp = "update"
 	IntentionOpDelete IntentionOp = "delete"
+
+	// IntentionOpCheckIndex fails a transaction if the intentions index isn't
+	// the ModifyIndex of the intention. It's only valid within a transaction.
+	IntentionOpCheckIndex IntentionOp = "check-index"
 )
 
 // IntentionRequest is used to create, update, and delete intentions.
@@ -299,6 +600,63 @@ func (q *IntentionRequest) RequestDatacenter() string {
 	return q.Datacenter
 }
 
+// IntentionReconcileRequest is used to make the intentions match a desired
+// set in a single transaction. Intentions are identified by their source and
+// destination, so the desired intentions must not have an ID.
+type IntentionReconcileRequest struct {
+	// Datacenter is the target for this request.
+	Datacenter string
+
+	// Intentions is the desired set of intentions.
+	Intentions Intentions
+
+	// Prune deletes the existing intentions that are not part of the
+	// desired set. Otherwise they are left alone and reported as unmanaged.
+	Prune bool
+
+	// DryRun only computes the changes without applying them.
+	DryRun bool
+
+	// CAS is the intentions index the changes were planned at. If set, the
+	// changes are only applied if the intentions haven't changed since.
+	CAS uint64
+
+	// WriteRequest is a common struct containing ACL tokens and other
+	// write-related common elements for requests.
+	WriteRequest
+}
+
+// RequestDatacenter returns the datacenter for a given request.
+func (q *IntentionReconcileRequest) RequestDatacenter() string {
+	return q.Datacenter
+}
+
+// IntentionReconcileResponse is the response for a reconcile request.
+type IntentionReconcileResponse struct {
+	// Changes are the operations needed to reconcile the intentions, in the
+	// order they are applied.
+	Changes []*IntentionChange
+
+	// Unmanaged are the existing intentions that are not part of the
+	// desired set and are left alone because Prune isn't set.
+	Unmanaged Intentions
+
+	// Index is the intentions index the changes were planned at.
+	Index uint64
+}
+
+// IntentionChange is a single operation of a reconcile request.
+type IntentionChange struct {
+	Op IntentionOp
+
+	// Intention is the intention to create, the updated intention, or the
+	// intention to delete.
+	Intention *Intention
+
+	// Previous is the existing intention of an update.
+	Previous *Intention `json:",omitempty"`
+}
+
 // IntentionMatchType is the target for a match request. For example,
 // matching by source will look for all intentions that match the given
 // source value.
@@ -402,6 +760,44 @@ type IntentionQueryCheckResponse struct {
 	Allowed bool
 }
 
+// IntentionQueryExplainResponse is the response for an explain request. It
+// explains the decision a check request with the same parameters returns.
+type IntentionQueryExplainResponse struct {
+	// Allowed is whether a connection from the source to the destination is
+	// allowed.
+	Allowed bool
+
+	// Reason is a human-friendly explanation of the decision.
+	Reason string
+
+	// Matched is the intention that decided, or nil if the default behavior
+	// configured by ACLs applies.
+	Matched *Intention
+
+	// DefaultAllow is the default behavior for connections that match no
+	// intention.
+	DefaultAllow bool
+
+	// Intentions are all intentions for the destination in the order they
+	// are evaluated, which is by precedence.
+	Intentions []*IntentionExplainEntry
+
+	QueryMeta
+}
+
+// IntentionExplainEntry is an intention of an explain response.
+type IntentionExplainEntry struct {
+	Intention *Intention
+
+	// SourceMatch is true if the intention matches the explained source.
+	SourceMatch bool
+
+	// ShadowedBy is the ID of an intention with a higher precedence that
+	// matches every source this intention matches, so this intention never
+	// applies to the destination.
+	ShadowedBy string `json:",omitempty"`
+}
+
 // IntentionPrecedenceSorter takes a list of intentions and sorts them
 // based on the match precedence rules for intentions. The intentions
 // closer to the head of the list have higher precedence. i.e. index 0 has
@@ -419,6 +815,16 @@ func (s IntentionPrecedenceSorter) Less(i, j int) bool {
 		return a.Precedence > b.Precedence
 	}
 
+	// At the same precedence, service sources come before CIDR sources and
+	// smaller CIDR blocks come before larger ones, so the most specific
+	// source matches first.
+	if aLen, bLen := a.sourceCIDRPrefixLen(), b.sourceCIDRPrefixLen(); aLen != bLen {
+		if aLen < 0 || bLen < 0 {
+			return aLen < 0
+		}
+		return aLen > bLen
+	}
+
 	// Tie break on lexicographic order of the 4-tuple in canonical form (SrcNS,
 	// Src, DstNS, Dst). This is arbitrary but it keeps sorting deterministic
 	// which is a nice property for consistency. It is arguably open to abuse if
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIntentionGetACLPrefix(t *testing.T) {
@@ -142,6 +143,179 @@ func TestIntentionValidate(t *testing.T) {
 			func(x *Intention) { x.SourceType = IntentionSourceType("other") },
 			"SourceType must",
 		},
+
+		{
+			"SourceType is cidr",
+			func(x *Intention) {
+				x.SourceType = IntentionSourceCIDR
+				x.SourceNS = IntentionDefaultNamespace
+				x.SourceName = "10.0.0.0/8"
+			},
+			"",
+		},
+
+		{
+			"SourceType is cidr with invalid block",
+			func(x *Intention) {
+				x.SourceType = IntentionSourceCIDR
+				x.SourceNS = IntentionDefaultNamespace
+				x.SourceName = "web"
+			},
+			"must be a CIDR block",
+		},
+
+		{
+			"SourceType is cidr with non-canonical block",
+			func(x *Intention) {
+				x.SourceType = IntentionSourceCIDR
+				x.SourceNS = IntentionDefaultNamespace
+				x.SourceName = "10.1.2.3/8"
+			},
+			"10.0.0.0/8",
+		},
+
+		{
+			"SourceType is cidr with namespace",
+			func(x *Intention) {
+				x.SourceType = IntentionSourceCIDR
+				x.SourceNS = "foo"
+				x.SourceName = "10.0.0.0/8"
+			},
+			"SourceNS must",
+		},
+
+		{
+			"permissions",
+			func(x *Intention) {
+				x.Action = ""
+				x.Permissions = []*IntentionPermission{
+					{
+						Action: IntentionActionAllow,
+						HTTP: &IntentionHTTPPermission{
+							PathExact: "/charge",
+							Methods:   []string{"POST"},
+							Header: []IntentionHTTPHeaderPermission{
+								{Name: "x-debug", Present: true, Invert: true},
+							},
+						},
+					},
+					{
+						Action: IntentionActionDeny,
+						HTTP:   &IntentionHTTPPermission{PathRegex: "/admin/.*"},
+					},
+				}
+			},
+			"",
+		},
+
+		{
+			"permissions with action",
+			func(x *Intention) {
+				x.Permissions = []*IntentionPermission{
+					{
+						Action: IntentionActionAllow,
+						HTTP:   &IntentionHTTPPermission{PathPrefix: "/"},
+					},
+				}
+			},
+			"Action must not be set",
+		},
+
+		{
+			"permission without action",
+			func(x *Intention) {
+				x.Action = ""
+				x.Permissions = []*IntentionPermission{
+					{HTTP: &IntentionHTTPPermission{PathPrefix: "/"}},
+				}
+			},
+			"Permissions[0]: Action must be set",
+		},
+
+		{
+			"permission without HTTP",
+			func(x *Intention) {
+				x.Action = ""
+				x.Permissions = []*IntentionPermission{
+					{Action: IntentionActionAllow},
+				}
+			},
+			"HTTP must be set",
+		},
+
+		{
+			"permission with empty HTTP",
+			func(x *Intention) {
+				x.Action = ""
+				x.Permissions = []*IntentionPermission{
+					{Action: IntentionActionAllow, HTTP: &IntentionHTTPPermission{}},
+				}
+			},
+			"at least one of",
+		},
+
+		{
+			"permission with several paths",
+			func(x *Intention) {
+				x.Action = ""
+				x.Permissions = []*IntentionPermission{
+					{
+						Action: IntentionActionAllow,
+						HTTP: &IntentionHTTPPermission{
+							PathExact:  "/foo",
+							PathPrefix: "/bar",
+						},
+					},
+				}
+			},
+			"at most one of",
+		},
+
+		{
+			"permission with relative path",
+			func(x *Intention) {
+				x.Action = ""
+				x.Permissions = []*IntentionPermission{
+					{
+						Action: IntentionActionAllow,
+						HTTP:   &IntentionHTTPPermission{PathPrefix: "foo"},
+					},
+				}
+			},
+			"PathPrefix must start with '/'",
+		},
+
+		{
+			"permission with invalid method",
+			func(x *Intention) {
+				x.Action = ""
+				x.Permissions = []*IntentionPermission{
+					{
+						Action: IntentionActionAllow,
+						HTTP:   &IntentionHTTPPermission{Methods: []string{"post"}},
+					},
+				}
+			},
+			"invalid method",
+		},
+
+		{
+			"permission with invalid header",
+			func(x *Intention) {
+				x.Action = ""
+				x.Permissions = []*IntentionPermission{
+					{
+						Action: IntentionActionAllow,
+						HTTP: &IntentionHTTPPermission{
+							Header: []IntentionHTTPHeaderPermission{
+								{Name: "x-foo", Exact: "a", Prefix: "b"},
+							},
+						},
+					},
+				}
+			},
+			"Header[0]: exactly one of",
+		},
 	}
 
 	for _, tc := range cases {
@@ -254,3 +428,38 @@ func TestIntentionPrecedenceSorter(t *testing.T) {
 		})
 	}
 }
+
+func TestIntentionPrecedenceSorter_cidr(t *testing.T) {
+	ixn := func(sourceType IntentionSourceType, src, dst string) *Intention {
+		x := &Intention{
+			SourceNS:        IntentionDefaultNamespace,
+			SourceName:      src,
+			DestinationNS:   IntentionDefaultNamespace,
+			DestinationName: dst,
+			SourceType:      sourceType,
+		}
+		x.UpdatePrecedence()
+		return x
+	}
+
+	input := Intentions{
+		ixn(IntentionSourceConsul, "*", "db"),
+		ixn(IntentionSourceCIDR, "10.0.0.0/8", "db"),
+		ixn(IntentionSourceCIDR, "10.1.0.0/16", "*"),
+		ixn(IntentionSourceConsul, "web", "db"),
+		ixn(IntentionSourceCIDR, "10.1.0.0/16", "db"),
+	}
+	sort.Sort(IntentionPrecedenceSorter(input))
+
+	var actual []string
+	for _, v := range input {
+		actual = append(actual, v.SourceName+" => "+v.DestinationName)
+	}
+	require.Equal(t, []string{
+		"web => db",
+		"10.1.0.0/16 => db",
+		"10.0.0.0/8 => db",
+		"* => db",
+		"10.1.0.0/16 => *",
+	}, actual)
+}
@@ -65,6 +65,25 @@ func (op *RaftRemovePeerRequest) RequestDatacenter() string {
 	return op.Datacenter
 }
 
+// RaftTransferLeaderRequest is used by the Operator endpoint to hand Raft
+// leadership off to another voting server.
+type RaftTransferLeaderRequest struct {
+	// Datacenter is the target this request is intended for.
+	Datacenter string
+
+	// ID is the optional ID of the server that should become the new leader.
+	// If this is blank then Raft will pick the most up-to-date voter.
+	ID raft.ServerID
+
+	// WriteRequest holds the ACL token to go along with this request.
+	WriteRequest
+}
+
+// RequestDatacenter returns the datacenter for a given request.
+func (op *RaftTransferLeaderRequest) RequestDatacenter() string {
+	return op.Datacenter
+}
+
 // AutopilotSetConfigRequest is used by the Operator endpoint to update the
 // current Autopilot configuration of the cluster.
 type AutopilotSetConfigRequest struct {
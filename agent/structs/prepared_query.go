@@ -19,6 +19,30 @@ type QueryDatacenterOptions struct {
 	// never try a datacenter multiple times, so those are subtracted from
 	// this list before proceeding.
 	Datacenters []string
+
+	// Targets is a list of failover targets to try in order after NearestN
+	// and Datacenters. Unlike Datacenters, a target can query a different
+	// service or set of tags in its datacenter.
+	Targets []QueryFailoverTarget
+
+	// MinHealthy is the number of healthy instances below which we fail
+	// over. We also keep trying datacenters until one of them has at least
+	// this many. If this is 0 we only fail over when there are no healthy
+	// instances at all.
+	MinHealthy int
+}
+
+// QueryFailoverTarget is a datacenter to fail over to, along with what to
+// query there.
+type QueryFailoverTarget struct {
+	// Datacenter is the datacenter to query.
+	Datacenter string
+
+	// Service, if set, replaces the service of the query.
+	Service string
+
+	// Tags, if set, replaces the tags of the query.
+	Tags []string
 }
 
 // QueryDNSOptions controls settings when query results are served over DNS.
@@ -228,6 +252,10 @@ type PreparedQueryExecuteRequest struct {
 	// the execute request. Used to distance-sort relative to the local node.
 	Agent QuerySource
 
+	// Explain, if true, adds an explanation of how the query was executed
+	// to the response.
+	Explain bool
+
 	// QueryOptions (unfortunately named here) controls the consistency
 	// settings for the query lookup itself, as well as the service lookups.
 	QueryOptions
@@ -287,6 +315,9 @@ type PreparedQueryExecuteRemoteRequest struct {
 	// Connect is the same as ExecuteRequest.
 	Connect bool
 
+	// Explain is the same as ExecuteRequest.
+	Explain bool
+
 	// QueryOptions (unfortunately named here) controls the consistency
 	// settings for the the service lookups.
 	QueryOptions
@@ -315,10 +346,78 @@ type PreparedQueryExecuteResponse struct {
 	// datacenter.
 	Failovers int
 
+	// Explain has an explanation of how the query was executed, if it was
+	// requested.
+	Explain *QueryExecuteExplain `json:",omitempty"`
+
 	// QueryMeta has freshness information about the query.
 	QueryMeta
 }
 
+// QueryExecuteExplain describes how a prepared query was executed, which is
+// useful to debug queries that return no or unexpected results.
+type QueryExecuteExplain struct {
+	// Query is the resolved query, with any template rendered and its token
+	// redacted.
+	Query PreparedQuery
+
+	// Near is the node the results were sorted near, if any.
+	Near string
+
+	// Datacenters has the datacenters the query ran in, in order, starting
+	// with the local one.
+	Datacenters []*QueryDatacenterExplain
+}
+
+// QueryDatacenterExplain describes the execution of a query in one
+// datacenter.
+type QueryDatacenterExplain struct {
+	// Datacenter is the name of the datacenter.
+	Datacenter string
+
+	// Reason is why the datacenter was queried. This is one of "local",
+	// "nearest", "datacenters" or "target", the latter three naming the
+	// failover option that selected it.
+	Reason string
+
+	// RTT is the estimated median round trip time to the servers in a
+	// failover datacenter, if it's known.
+	RTT string `json:",omitempty"`
+
+	// Service and Tags are what was queried in the datacenter, which
+	// failover targets can change.
+	Service string
+	Tags    []string
+
+	// Error is the error if the query failed in the datacenter.
+	Error string `json:",omitempty"`
+
+	// Instances is the number of instances of the service before any
+	// filters were applied.
+	Instances int
+
+	// Filters has the number of instances each filter removed, in the order
+	// they were applied.
+	Filters []QueryFilterExplain
+
+	// IgnoredChecks is the number of instances the health filter kept only
+	// because of the IgnoreCheckIDs of the query.
+	IgnoredChecks int
+
+	// Results is the number of instances that were left.
+	Results int
+}
+
+// QueryFilterExplain is the number of instances removed by a filter.
+type QueryFilterExplain struct {
+	// Filter is the name of the filter. This is one of "health",
+	// "node-meta", "service-meta", "tags", "acl" or "limit".
+	Filter string
+
+	// Removed is the number of instances the filter removed.
+	Removed int
+}
+
 // PreparedQueryExplainResponse has the results when explaining a query/
 type PreparedQueryExplainResponse struct {
 	// Query has the fully-rendered query.
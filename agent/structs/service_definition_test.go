@@ -44,9 +44,17 @@ func TestAgentStructs_CheckTypes(t *testing.T) {
 		err  error
 		desc string
 	}{
-		{&CheckType{HTTP: "http://foo/baz"}, fmt.Errorf("Interval must be > 0 for Script, HTTP, or TCP checks"), "Missing interval"},
+		{&CheckType{HTTP: "http://foo/baz"}, fmt.Errorf("Interval must be > 0 for Script, HTTP, TCP, gRPC, UDP, DNS, or TLS checks"), "Missing interval"},
 		{&CheckType{TTL: -1}, fmt.Errorf("TTL must be > 0 for TTL checks"), "Negative TTL"},
 		{&CheckType{TTL: 20 * time.Second, Interval: 10 * time.Second}, fmt.Errorf("Interval and TTL cannot both be specified"), "Interval and TTL both set"},
+		{&CheckType{UDP: "127.0.0.1:53"}, fmt.Errorf("Interval must be > 0 for Script, HTTP, TCP, gRPC, UDP, DNS, or TLS checks"), "UDP missing interval"},
+		{&CheckType{DNS: "example.com", Interval: 10 * time.Second}, fmt.Errorf("DNSServer must be set for DNS checks"), "DNS missing server"},
+		{&CheckType{DNS: "example.com", DNSServer: "127.0.0.1", DNSRecordType: "BOGUS", Interval: 10 * time.Second}, fmt.Errorf(`DNSRecordType "BOGUS" is not a valid DNS record type`), "DNS bad record type"},
+		{&CheckType{TCP: "127.0.0.1:80", BodyMatch: "ok", Interval: 10 * time.Second}, fmt.Errorf("BodyMatch and JSONAssertions are only supported for HTTP checks"), "Body match on TCP check"},
+		{&CheckType{HTTP: "http://foo/baz", BodyMatch: "(", BodyMatchRegex: true, Interval: 10 * time.Second}, fmt.Errorf("BodyMatch is not a valid regular expression: error parsing regexp: missing closing ): `(`"), "Bad body regex"},
+		{&CheckType{HTTP: "http://foo/baz", JSONAssertions: []CheckJSONAssertion{{Value: "ok"}}, Interval: 10 * time.Second}, fmt.Errorf("JSONAssertions must have a Path"), "JSON assertion missing path"},
+		{&CheckType{HTTP: "http://foo/baz", JSONAssertions: []CheckJSONAssertion{{Path: "status", Status: "passing"}}, Interval: 10 * time.Second}, fmt.Errorf(`JSONAssertions Status must be "warning" or "critical"`), "JSON assertion bad status"},
+		{&CheckType{TLS: "127.0.0.1:443", TLSExpiryWarningDays: -1, Interval: 10 * time.Second}, fmt.Errorf("TLSExpiryWarningDays must not be negative"), "TLS negative expiry warning"},
 	}
 	for _, tc := range cases {
 		svc.Check = *tc.in
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/rand"
 	"reflect"
 	"regexp"
@@ -1048,6 +1049,56 @@ func (nodes CheckServiceNodes) Shuffle() {
 	}
 }
 
+// ShuffleByWeight does an in-place weighted random shuffle, so that nodes
+// with a larger weight are more likely to come first. Nodes with a weight of
+// 0 always come last. With the default weights this is the same as Shuffle.
+func (nodes CheckServiceNodes) ShuffleByWeight() {
+	// This is the weighted random sampling of Efraimidis and Spirakis,
+	// which orders the nodes by a random key of u^(1/w).
+	keys := make([]float64, len(nodes))
+	for i, node := range nodes {
+		keys[i] = -1
+		if w := node.Weight(); w > 0 {
+			keys[i] = math.Pow(rand.Float64(), 1/float64(w))
+		}
+	}
+	sort.Sort(&checkServiceNodesByKey{nodes, keys})
+}
+
+// checkServiceNodesByKey sorts nodes by descending keys.
+type checkServiceNodesByKey struct {
+	nodes CheckServiceNodes
+	keys  []float64
+}
+
+func (s *checkServiceNodesByKey) Len() int {
+	return len(s.nodes)
+}
+
+func (s *checkServiceNodesByKey) Less(i, j int) bool {
+	return s.keys[i] > s.keys[j]
+}
+
+func (s *checkServiceNodesByKey) Swap(i, j int) {
+	s.nodes[i], s.nodes[j] = s.nodes[j], s.nodes[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+// Weight returns the weight of the service instance for its current health,
+// which is its Warning weight if any of its checks is warning and its Passing
+// weight otherwise. Instances without weights have a weight of 1.
+func (node CheckServiceNode) Weight() int {
+	if node.Service == nil || node.Service.Weights == nil {
+		return 1
+	}
+	for _, check := range node.Checks {
+		if check.Status == api.HealthWarning {
+			return node.Service.Weights.Warning
+		}
+	}
+	return node.Service.Weights.Passing
+}
+
 // Filter removes nodes that are failing health checks (and any non-passing
 // check if that option is selected). Note that this returns the filtered
 // results AND modifies the receiver for performance.
@@ -1215,6 +1266,32 @@ type IndexedKeyList struct {
 	QueryMeta
 }
 
+// KeyLockInfo describes the lock on a key, to help find out who holds a
+// lock or why it can't be acquired.
+type KeyLockInfo struct {
+	Key string
+
+	// Session is the session that holds the lock, or empty if the lock
+	// isn't held.
+	Session string
+
+	// Node is the node of the session that holds the lock. It's empty if the
+	// lock isn't held or the session can't be read.
+	Node string
+
+	// LockIndex is the number of times the lock has been acquired.
+	LockIndex uint64
+
+	// LockDelay is the time that's left until the lock can be acquired
+	// again after the session that held it was invalidated.
+	LockDelay time.Duration
+}
+
+type IndexedKeyLockInfo struct {
+	Info *KeyLockInfo
+	QueryMeta
+}
+
 type SessionBehavior string
 
 const (
@@ -1274,6 +1351,11 @@ func (r *SessionSpecificRequest) RequestDatacenter() string {
 
 type IndexedSessions struct {
 	Sessions Sessions
+
+	// HeldKeys maps the ID of each session to the number of keys it holds
+	// locks on. It's computed when sessions are read and isn't stored.
+	HeldKeys map[string]int
+
 	QueryMeta
 }
 
@@ -704,6 +704,36 @@ func TestStructs_CheckServiceNodes_Shuffle(t *testing.T) {
 	}
 }
 
+func TestStructs_CheckServiceNodes_ShuffleByWeight(t *testing.T) {
+	node := func(name string, weights *Weights, status string) CheckServiceNode {
+		return CheckServiceNode{
+			Node:    &Node{Node: name},
+			Service: &NodeService{Service: "web", Weights: weights},
+			Checks:  HealthChecks{&HealthCheck{Status: status}},
+		}
+	}
+	nodes := CheckServiceNodes{
+		node("heavy", &Weights{Passing: 100, Warning: 1}, api.HealthPassing),
+		node("light", &Weights{Passing: 1, Warning: 1}, api.HealthPassing),
+		node("warning", &Weights{Passing: 100, Warning: 0}, api.HealthWarning),
+	}
+
+	require.Equal(t, 100, nodes[0].Weight())
+	require.Equal(t, 0, nodes[2].Weight())
+	require.Equal(t, 1, CheckServiceNode{Node: &Node{Node: "none"}}.Weight())
+
+	// The heavy node should almost always come first, and a node with a
+	// weight of 0 always comes last.
+	first := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		nodes.ShuffleByWeight()
+		first[nodes[0].Node.Node]++
+		require.Equal(t, "warning", nodes[2].Node.Node)
+	}
+	require.True(t, first["heavy"] > 900, "%v", first)
+	require.True(t, first["light"] > 0, "%v", first)
+}
+
 func TestStructs_CheckServiceNodes_Filter(t *testing.T) {
 	nodes := CheckServiceNodes{
 		CheckServiceNode{
@@ -130,6 +130,43 @@ func TestTxnEndpoint_Bad_Size_Ops(t *testing.T) {
 	}
 }
 
+func TestTxnEndpoint_KV_LargeFlags(t *testing.T) {
+	t.Parallel()
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	// Flags that can't be represented exactly as a float64 must survive.
+	buf := bytes.NewBuffer([]byte(fmt.Sprintf(`
+ [
+     {
+         "KV": {
+             "Verb": "set",
+             "Key": "key",
+             "Flags": %d
+         }
+     }
+ ]
+ `, api.LockFlagValue)))
+	req, _ := http.NewRequest("PUT", "/v1/txn", buf)
+	resp := httptest.NewRecorder()
+	obj, err := a.srv.Txn(resp, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if resp.Code != 200 {
+		t.Fatalf("expected 200, got %d", resp.Code)
+	}
+
+	txnResp, ok := obj.(structs.TxnResponse)
+	if !ok {
+		t.Fatalf("bad type: %T", obj)
+	}
+	if len(txnResp.Results) != 1 || txnResp.Results[0].KV.Flags != api.LockFlagValue {
+		t.Fatalf("bad: %v", txnResp)
+	}
+}
+
 func TestTxnEndpoint_KV_Actions(t *testing.T) {
 	t.Parallel()
 	t.Run("", func(t *testing.T) {
@@ -11,9 +11,11 @@ import (
 	"os"
 	osexec "os/exec"
 	"strconv"
+	"text/template"
 
 	"github.com/armon/circbuf"
 	"github.com/hashicorp/consul/agent/exec"
+	"github.com/hashicorp/consul/lib/file"
 	"github.com/hashicorp/consul/watch"
 	"github.com/hashicorp/go-cleanhttp"
 	"golang.org/x/net/context"
@@ -27,7 +29,7 @@ const (
 )
 
 // makeWatchHandler returns a handler for the given watch
-func makeWatchHandler(logOutput io.Writer, handler interface{}) watch.HandlerFunc {
+func makeWatchHandler(logOutput io.Writer, handler interface{}) watch.RetryableHandlerFunc {
 	var args []string
 	var script string
 
@@ -42,7 +44,7 @@ func makeWatchHandler(logOutput io.Writer, handler interface{}) watch.HandlerFun
 	}
 
 	logger := log.New(logOutput, "", log.LstdFlags)
-	fn := func(idx uint64, data interface{}) {
+	fn := func(idx uint64, data interface{}) error {
 		// Create the command
 		var cmd *osexec.Cmd
 		var err error
@@ -53,8 +55,7 @@ func makeWatchHandler(logOutput io.Writer, handler interface{}) watch.HandlerFun
 			cmd, err = exec.Script(script)
 		}
 		if err != nil {
-			logger.Printf("[ERR] agent: Failed to setup watch: %v", err)
-			return
+			return fmt.Errorf("Failed to setup watch: %v", err)
 		}
 
 		cmd.Env = append(os.Environ(),
@@ -70,15 +71,12 @@ func makeWatchHandler(logOutput io.Writer, handler interface{}) watch.HandlerFun
 		var inp bytes.Buffer
 		enc := json.NewEncoder(&inp)
 		if err := enc.Encode(data); err != nil {
-			logger.Printf("[ERR] agent: Failed to encode data for watch '%v': %v", handler, err)
-			return
+			return fmt.Errorf("Failed to encode data for watch '%v': %v", handler, err)
 		}
 		cmd.Stdin = &inp
 
 		// Run the handler
-		if err := cmd.Run(); err != nil {
-			logger.Printf("[ERR] agent: Failed to run watch handler '%v': %v", handler, err)
-		}
+		runErr := cmd.Run()
 
 		// Get the output, add a message about truncation
 		outputStr := string(output.Bytes())
@@ -89,14 +87,19 @@ func makeWatchHandler(logOutput io.Writer, handler interface{}) watch.HandlerFun
 
 		// Log the output
 		logger.Printf("[DEBUG] agent: watch handler '%v' output: %s", handler, outputStr)
+
+		if runErr != nil {
+			return fmt.Errorf("Failed to run watch handler '%v': %v", handler, runErr)
+		}
+		return nil
 	}
 	return fn
 }
 
-func makeHTTPWatchHandler(logOutput io.Writer, config *watch.HttpHandlerConfig) watch.HandlerFunc {
+func makeHTTPWatchHandler(logOutput io.Writer, config *watch.HttpHandlerConfig) watch.RetryableHandlerFunc {
 	logger := log.New(logOutput, "", log.LstdFlags)
 
-	fn := func(idx uint64, data interface{}) {
+	fn := func(idx uint64, data interface{}) error {
 		trans := cleanhttp.DefaultTransport()
 
 		// Skip SSL certificate verification if TLSSkipVerify is true
@@ -121,14 +124,12 @@ func makeHTTPWatchHandler(logOutput io.Writer, config *watch.HttpHandlerConfig)
 		var inp bytes.Buffer
 		enc := json.NewEncoder(&inp)
 		if err := enc.Encode(data); err != nil {
-			logger.Printf("[ERR] agent: Failed to encode data for http watch '%s': %v", config.Path, err)
-			return
+			return fmt.Errorf("Failed to encode data for http watch '%s': %v", config.Path, err)
 		}
 
 		req, err := http.NewRequest(config.Method, config.Path, &inp)
 		if err != nil {
-			logger.Printf("[ERR] agent: Failed to setup http watch: %v", err)
-			return
+			return fmt.Errorf("Failed to setup http watch: %v", err)
 		}
 		req = req.WithContext(ctx)
 		req.Header.Add("Content-Type", "application/json")
@@ -140,8 +141,7 @@ func makeHTTPWatchHandler(logOutput io.Writer, config *watch.HttpHandlerConfig)
 		}
 		resp, err := httpClient.Do(req)
 		if err != nil {
-			logger.Printf("[ERR] agent: Failed to invoke http watch handler '%s': %v", config.Path, err)
-			return
+			return fmt.Errorf("Failed to invoke http watch handler '%s': %v", config.Path, err)
 		}
 		defer resp.Body.Close()
 
@@ -156,13 +156,50 @@ func makeHTTPWatchHandler(logOutput io.Writer, config *watch.HttpHandlerConfig)
 				output.Size(), output.TotalWritten(), outputStr)
 		}
 
-		if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
-			// Log the output
-			logger.Printf("[TRACE] agent: http watch handler '%s' output: %s", config.Path, outputStr)
-		} else {
-			logger.Printf("[ERR] agent: http watch handler '%s' got '%s' with output: %s",
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("http watch handler '%s' got '%s' with output: %s",
 				config.Path, resp.Status, outputStr)
 		}
+
+		// Log the output
+		logger.Printf("[TRACE] agent: http watch handler '%s' output: %s", config.Path, outputStr)
+		return nil
 	}
 	return fn
 }
+
+// makeFileWatchHandler returns a handler that atomically replaces a file with
+// the result of the watch, rendered through the template if one is set.
+func makeFileWatchHandler(logOutput io.Writer, config *watch.FileHandlerConfig) (watch.RetryableHandlerFunc, error) {
+	logger := log.New(logOutput, "", log.LstdFlags)
+
+	var tmpl *template.Template
+	if config.Template != "" {
+		var err error
+		tmpl, err = template.New(config.Path).Funcs(watch.FileHandlerTemplateFuncs).Parse(config.Template)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse template for file watch '%s': %v", config.Path, err)
+		}
+	}
+
+	fn := func(idx uint64, data interface{}) error {
+		var out bytes.Buffer
+		if tmpl != nil {
+			if err := tmpl.Execute(&out, data); err != nil {
+				return fmt.Errorf("Failed to render template for file watch '%s': %v", config.Path, err)
+			}
+		} else {
+			if err := json.NewEncoder(&out).Encode(data); err != nil {
+				return fmt.Errorf("Failed to encode data for file watch '%s': %v", config.Path, err)
+			}
+		}
+
+		if err := file.WriteAtomicWithFilePerms(config.Path, out.Bytes(), 0755, config.Perms); err != nil {
+			return fmt.Errorf("Failed to write file watch '%s': %v", config.Path, err)
+		}
+
+		logger.Printf("[TRACE] agent: file watch handler wrote index %d to '%s'", idx, config.Path)
+		return nil
+	}
+	return fn, nil
+}
@@ -5,10 +5,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/consul/sdk/testutil"
 	"github.com/hashicorp/consul/watch"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMakeWatchHandler(t *testing.T) {
@@ -17,7 +21,9 @@ func TestMakeWatchHandler(t *testing.T) {
 	defer os.Remove("handler_index_out")
 	script := "bash -c 'echo $CONSUL_INDEX >> handler_index_out && cat >> handler_out'"
 	handler := makeWatchHandler(os.Stderr, script)
-	handler(100, []string{"foo", "bar", "baz"})
+	if err := handler(100, []string{"foo", "bar", "baz"}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
 	raw, err := ioutil.ReadFile("handler_out")
 	if err != nil {
 		t.Fatalf("err: %v", err)
@@ -62,5 +68,76 @@ func TestMakeHTTPWatchHandler(t *testing.T) {
 		Timeout: time.Minute,
 	}
 	handler := makeHTTPWatchHandler(os.Stderr, &config)
-	handler(100, []string{"foo", "bar", "baz"})
+	if err := handler(100, []string{"foo", "bar", "baz"}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+}
+
+func TestMakeHTTPWatchHandler_Failure(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	config := watch.HttpHandlerConfig{
+		Path:    server.URL,
+		Method:  "POST",
+		Timeout: time.Minute,
+	}
+	handler := makeHTTPWatchHandler(os.Stderr, &config)
+	err := handler(100, []string{"foo"})
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("err: %v", err)
+	}
+}
+
+func TestMakeFileWatchHandler(t *testing.T) {
+	t.Parallel()
+	dir := testutil.TempDir(t, "watch")
+	defer os.RemoveAll(dir)
+
+	t.Run("json", func(t *testing.T) {
+		config := watch.FileHandlerConfig{
+			Path:  filepath.Join(dir, "nested", "out.json"),
+			Perms: 0600,
+		}
+		handler, err := makeFileWatchHandler(os.Stderr, &config)
+		require.NoError(t, err)
+		require.NoError(t, handler(100, []string{"foo", "bar", "baz"}))
+
+		raw, err := ioutil.ReadFile(config.Path)
+		require.NoError(t, err)
+		require.Equal(t, "[\"foo\",\"bar\",\"baz\"]\n", string(raw))
+		fi, err := os.Stat(config.Path)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+	})
+
+	t.Run("template", func(t *testing.T) {
+		config := watch.FileHandlerConfig{
+			Path:     filepath.Join(dir, "out.txt"),
+			Template: `{{ range . }}{{ . }};{{ end }}{{ toJSON . }}`,
+			Perms:    0644,
+		}
+		handler, err := makeFileWatchHandler(os.Stderr, &config)
+		require.NoError(t, err)
+		require.NoError(t, handler(100, []string{"foo", "bar"}))
+
+		raw, err := ioutil.ReadFile(config.Path)
+		require.NoError(t, err)
+		require.Equal(t, `foo;bar;["foo","bar"]`, string(raw))
+	})
+
+	t.Run("template error", func(t *testing.T) {
+		config := watch.FileHandlerConfig{
+			Path:     filepath.Join(dir, "bad.txt"),
+			Template: `{{ .Missing }}`,
+			Perms:    0644,
+		}
+		handler, err := makeFileWatchHandler(os.Stderr, &config)
+		require.NoError(t, err)
+		require.Error(t, handler(100, []string{"foo"}))
+		_, err = os.Stat(config.Path)
+		require.True(t, os.IsNotExist(err))
+	})
 }
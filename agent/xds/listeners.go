@@ -4,12 +4,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	envoy "github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	envoyauth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
 	envoycore "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	envoylistener "github.com/envoyproxy/go-control-plane/envoy/api/v2/listener"
+	envoyroute "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
 	extauthz "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/ext_authz/v2"
+	envoyhttp "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
 	envoytcp "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/tcp_proxy/v2"
 	"github.com/envoyproxy/go-control-plane/pkg/util"
 	"github.com/gogo/protobuf/jsonpb"
@@ -127,14 +130,19 @@ func injectConnectFilters(cfgSnap *proxycfg.ConfigSnapshot, token string, listen
 		// Insert our authz filter before any others
 		listener.FilterChains[idx].Filters =
 			append([]envoylistener.Filter{authFilter}, listener.FilterChains[idx].Filters...)
+	}
+	injectConnectTLS(cfgSnap, listener)
+	return nil
+}
 
-		// Force our TLS for all filter chains on a public listener
+// injectConnectTLS forces our TLS for all filter chains on a public listener.
+func injectConnectTLS(cfgSnap *proxycfg.ConfigSnapshot, listener *envoy.Listener) {
+	for idx := range listener.FilterChains {
 		listener.FilterChains[idx].TlsContext = &envoyauth.DownstreamTlsContext{
 			CommonTlsContext:         makeCommonTLSContext(cfgSnap),
 			RequireClientCertificate: &types.BoolValue{Value: true},
 		}
 	}
-	return nil
 }
 
 func makePublicListener(cfgSnap *proxycfg.ConfigSnapshot, token string) (proto.Message, error) {
@@ -157,6 +165,26 @@ func makePublicListener(cfgSnap *proxycfg.ConfigSnapshot, token string) (proto.M
 			addr = "0.0.0.0"
 		}
 		l = makeListener(PublicListenerName, addr, cfgSnap.Port)
+
+		if isHTTPProtocol(cfgSnap.Proxy.Config) {
+			// HTTP services get their intentions enforced on each request by
+			// the RBAC filter so that L7 permissions apply. Only TLS needs
+			// injecting.
+			httpFilter, err := makePublicHTTPConnectionManagerFilter(cfgSnap)
+			if err != nil {
+				return l, err
+			}
+			l.FilterChains = []envoylistener.FilterChain{
+				{
+					Filters: []envoylistener.Filter{
+						httpFilter,
+					},
+				},
+			}
+			injectConnectTLS(cfgSnap, l)
+			return l, nil
+		}
+
 		tcpProxy, err := makeTCPProxyFilter("public_listener", LocalAppClusterName)
 		if err != nil {
 			return l, err
@@ -176,6 +204,60 @@ func makePublicListener(cfgSnap *proxycfg.ConfigSnapshot, token string) (proto.M
 	return l, err
 }
 
+// isHTTPProtocol returns true if the opaque proxy config declares that the
+// local application speaks HTTP.
+func isHTTPProtocol(config map[string]interface{}) bool {
+	protocol, _ := config["protocol"].(string)
+	return strings.ToLower(protocol) == "http"
+}
+
+// makePublicHTTPConnectionManagerFilter returns an HTTP connection manager
+// that enforces the intentions of the destination service on each request
+// and routes all requests to the local application.
+func makePublicHTTPConnectionManagerFilter(cfgSnap *proxycfg.ConfigSnapshot) (envoylistener.Filter, error) {
+	rbacFilter, err := makeRBACHTTPFilter(cfgSnap.Intentions, cfgSnap.IntentionDefaultAllow)
+	if err != nil {
+		return envoylistener.Filter{}, err
+	}
+
+	cfg := &envoyhttp.HttpConnectionManager{
+		StatPrefix: "public_listener",
+		CodecType:  envoyhttp.AUTO,
+		RouteSpecifier: &envoyhttp.HttpConnectionManager_RouteConfig{
+			RouteConfig: &envoy.RouteConfiguration{
+				Name: "public_listener",
+				VirtualHosts: []envoyroute.VirtualHost{
+					{
+						Name:    "public_listener",
+						Domains: []string{"*"},
+						Routes: []envoyroute.Route{
+							{
+								Match: envoyroute.RouteMatch{
+									PathSpecifier: &envoyroute.RouteMatch_Prefix{
+										Prefix: "/",
+									},
+								},
+								Action: &envoyroute.Route_Route{
+									Route: &envoyroute.RouteAction{
+										ClusterSpecifier: &envoyroute.RouteAction_Cluster{
+											Cluster: LocalAppClusterName,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		HttpFilters: []*envoyhttp.HttpFilter{
+			rbacFilter,
+			{Name: "envoy.router"},
+		},
+	}
+	return makeFilter("envoy.http_connection_manager", cfg)
+}
+
 func makeUpstreamListener(u *structs.Upstream) (proto.Message, error) {
 	if listenerJSONRaw, ok := u.Config["envoy_listener_json"]; ok {
 		if listenerJSON, ok := listenerJSONRaw.(string); ok {
@@ -0,0 +1,55 @@
+package xds
+
+import (
+	"testing"
+
+	envoy "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/proxycfg"
+)
+
+func Test_makePublicListener(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol string
+		filters  []string
+	}{
+		{
+			name:    "tcp",
+			filters: []string{"envoy.ext_authz", "envoy.tcp_proxy"},
+		},
+		{
+			name:     "http",
+			protocol: "http",
+			filters:  []string{"envoy.http_connection_manager"},
+		},
+		{
+			name:     "http uppercase",
+			protocol: "HTTP",
+			filters:  []string{"envoy.http_connection_manager"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			snap := proxycfg.TestConfigSnapshot(t)
+			if tt.protocol != "" {
+				snap.Proxy.Config["protocol"] = tt.protocol
+			}
+
+			msg, err := makePublicListener(snap, "my-token")
+			require.NoError(err)
+			l := msg.(*envoy.Listener)
+
+			require.Len(l.FilterChains, 1)
+			var names []string
+			for _, f := range l.FilterChains[0].Filters {
+				names = append(names, f.Name)
+			}
+			require.Equal(tt.filters, names)
+			require.NotNil(l.FilterChains[0].TlsContext)
+		})
+	}
+}
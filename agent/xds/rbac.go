@@ -0,0 +1,272 @@
+package xds
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+
+	envoycore "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoyroute "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	envoyhttprbac "github.com/envoyproxy/go-control-plane/envoy/config/filter/http/rbac/v2"
+	envoyhttp "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
+	envoyrbac "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v2alpha"
+	envoymatcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher"
+	"github.com/envoyproxy/go-control-plane/pkg/util"
+	"github.com/gogo/protobuf/types"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// makeRBACHTTPFilter returns an HTTP filter that enforces the intentions of
+// the destination service, including their L7 permissions, on each request.
+func makeRBACHTTPFilter(intentions structs.Intentions, intentionDefaultAllow bool) (*envoyhttp.HttpFilter, error) {
+	cfg := &envoyhttprbac.RBAC{
+		Rules: makeRBACRules(intentions, intentionDefaultAllow),
+	}
+	cfgStruct, err := util.MessageToStruct(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &envoyhttp.HttpFilter{
+		Name:   "envoy.filters.http.rbac",
+		Config: cfgStruct,
+	}, nil
+}
+
+// makeRBACRules translates the intentions into RBAC rules.
+//
+// Intentions are evaluated in precedence order and the first one whose source
+// matches decides, while RBAC policies are unordered and all either allow or
+// deny. So the rules only contain the policies that disagree with the default
+// behavior, and the policy of each intention excludes the sources of the
+// intentions with a higher precedence. The permissions of an L7 intention
+// are handled the same way: each permission excludes the earlier ones with
+// the opposite action.
+func makeRBACRules(intentions structs.Intentions, intentionDefaultAllow bool) *envoyrbac.RBAC {
+	rbacAction := envoyrbac.RBAC_ALLOW
+	ixnAction := structs.IntentionActionAllow
+	if intentionDefaultAllow {
+		rbacAction = envoyrbac.RBAC_DENY
+		ixnAction = structs.IntentionActionDeny
+	}
+
+	// Make sure the intentions are in precedence order.
+	sorted := make(structs.Intentions, len(intentions))
+	copy(sorted, intentions)
+	sort.Sort(structs.IntentionPrecedenceSorter(sorted))
+
+	rbac := &envoyrbac.RBAC{
+		Action:   rbacAction,
+		Policies: make(map[string]*envoyrbac.Policy),
+	}
+
+	var higher []*envoyrbac.Principal
+	for i, ixn := range sorted {
+		source := makeRBACSourcePrincipal(ixn)
+		principal := source
+		if len(higher) > 0 {
+			ids := []*envoyrbac.Principal{source}
+			for _, h := range higher {
+				ids = append(ids, notRBACPrincipal(h))
+			}
+			principal = &envoyrbac.Principal{
+				Identifier: &envoyrbac.Principal_AndIds{
+					AndIds: &envoyrbac.Principal_Set{Ids: ids},
+				},
+			}
+		}
+		higher = append(higher, source)
+
+		var permissions []*envoyrbac.Permission
+		if ixn.HasPermissions() {
+			permissions = makeRBACPermissions(ixn.Permissions, ixnAction)
+		} else if ixn.Action == ixnAction {
+			permissions = []*envoyrbac.Permission{
+				{Rule: &envoyrbac.Permission_Any{Any: true}},
+			}
+		}
+		if len(permissions) == 0 {
+			continue
+		}
+
+		rbac.Policies[fmt.Sprintf("consul-intentions-%d", i)] = &envoyrbac.Policy{
+			Permissions: permissions,
+			Principals:  []*envoyrbac.Principal{principal},
+		}
+	}
+	return rbac
+}
+
+// makeRBACSourcePrincipal returns a principal that matches the certificates
+// of the source services of an intention in any trust domain and datacenter,
+// or the address of the downstream connection for a CIDR source.
+func makeRBACSourcePrincipal(ixn *structs.Intention) *envoyrbac.Principal {
+	if ixn.SourceType == structs.IntentionSourceCIDR {
+		return makeRBACSourceIPPrincipal(ixn.SourceName)
+	}
+
+	pattern := func(v string) string {
+		if v == structs.IntentionWildcard {
+			return "[^/]+"
+		}
+		return regexp.QuoteMeta(v)
+	}
+	uri := fmt.Sprintf("^spiffe://[^/]+/ns/%s/dc/[^/]+/svc/%s$",
+		pattern(ixn.SourceNS), pattern(ixn.SourceName))
+
+	return &envoyrbac.Principal{
+		Identifier: &envoyrbac.Principal_Authenticated_{
+			Authenticated: &envoyrbac.Principal_Authenticated{
+				PrincipalName: &envoymatcher.StringMatcher{
+					MatchPattern: &envoymatcher.StringMatcher_Regex{Regex: uri},
+				},
+			},
+		},
+	}
+}
+
+func makeRBACSourceIPPrincipal(cidr string) *envoyrbac.Principal {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		// Intentions are validated before they are stored, so this can't
+		// happen. Match nothing rather than everything to be safe.
+		return notRBACPrincipal(&envoyrbac.Principal{
+			Identifier: &envoyrbac.Principal_Any{Any: true},
+		})
+	}
+	ones, _ := ipNet.Mask.Size()
+
+	return &envoyrbac.Principal{
+		Identifier: &envoyrbac.Principal_SourceIp{
+			SourceIp: &envoycore.CidrRange{
+				AddressPrefix: ipNet.IP.String(),
+				PrefixLen:     &types.UInt32Value{Value: uint32(ones)},
+			},
+		},
+	}
+}
+
+func notRBACPrincipal(p *envoyrbac.Principal) *envoyrbac.Principal {
+	return &envoyrbac.Principal{
+		Identifier: &envoyrbac.Principal_NotId{NotId: p},
+	}
+}
+
+// makeRBACPermissions returns the RBAC permissions that match the requests
+// the L7 permissions decide with the given action.
+func makeRBACPermissions(perms []*structs.IntentionPermission, action structs.IntentionAction) []*envoyrbac.Permission {
+	var result []*envoyrbac.Permission
+	var opposite []*envoyrbac.Permission
+	for _, perm := range perms {
+		match := makeRBACHTTPPermission(perm.HTTP)
+		if perm.Action != action {
+			opposite = append(opposite, match)
+			continue
+		}
+
+		if len(opposite) == 0 {
+			result = append(result, match)
+			continue
+		}
+		rules := []*envoyrbac.Permission{match}
+		for _, o := range opposite {
+			rules = append(rules, &envoyrbac.Permission{
+				Rule: &envoyrbac.Permission_NotRule{NotRule: o},
+			})
+		}
+		result = append(result, andRBACPermissions(rules))
+	}
+	return result
+}
+
+// makeRBACHTTPPermission returns an RBAC permission that matches the
+// requests of an HTTP permission.
+func makeRBACHTTPPermission(p *structs.IntentionHTTPPermission) *envoyrbac.Permission {
+	var rules []*envoyrbac.Permission
+
+	// The path header includes the query string, so the exact and regex
+	// matches need to allow for it.
+	const query = `(?:\?.*)?`
+	switch {
+	case p.PathExact != "":
+		rules = append(rules, makeRBACHeaderPermission(&envoyroute.HeaderMatcher{
+			Name: ":path",
+			HeaderMatchSpecifier: &envoyroute.HeaderMatcher_RegexMatch{
+				RegexMatch: regexp.QuoteMeta(p.PathExact) + query,
+			},
+		}))
+	case p.PathPrefix != "":
+		rules = append(rules, makeRBACHeaderPermission(&envoyroute.HeaderMatcher{
+			Name: ":path",
+			HeaderMatchSpecifier: &envoyroute.HeaderMatcher_PrefixMatch{
+				PrefixMatch: p.PathPrefix,
+			},
+		}))
+	case p.PathRegex != "":
+		rules = append(rules, makeRBACHeaderPermission(&envoyroute.HeaderMatcher{
+			Name: ":path",
+			HeaderMatchSpecifier: &envoyroute.HeaderMatcher_RegexMatch{
+				RegexMatch: "(?:" + p.PathRegex + ")" + query,
+			},
+		}))
+	}
+
+	for _, hdr := range p.Header {
+		m := &envoyroute.HeaderMatcher{
+			Name:        hdr.Name,
+			InvertMatch: hdr.Invert,
+		}
+		switch {
+		case hdr.Present:
+			m.HeaderMatchSpecifier = &envoyroute.HeaderMatcher_PresentMatch{PresentMatch: true}
+		case hdr.Exact != "":
+			m.HeaderMatchSpecifier = &envoyroute.HeaderMatcher_ExactMatch{ExactMatch: hdr.Exact}
+		case hdr.Prefix != "":
+			m.HeaderMatchSpecifier = &envoyroute.HeaderMatcher_PrefixMatch{PrefixMatch: hdr.Prefix}
+		case hdr.Suffix != "":
+			m.HeaderMatchSpecifier = &envoyroute.HeaderMatcher_SuffixMatch{SuffixMatch: hdr.Suffix}
+		case hdr.Regex != "":
+			m.HeaderMatchSpecifier = &envoyroute.HeaderMatcher_RegexMatch{RegexMatch: hdr.Regex}
+		}
+		rules = append(rules, makeRBACHeaderPermission(m))
+	}
+
+	if len(p.Methods) > 0 {
+		var methods []*envoyrbac.Permission
+		for _, method := range p.Methods {
+			methods = append(methods, makeRBACHeaderPermission(&envoyroute.HeaderMatcher{
+				Name:                 ":method",
+				HeaderMatchSpecifier: &envoyroute.HeaderMatcher_ExactMatch{ExactMatch: method},
+			}))
+		}
+		if len(methods) == 1 {
+			rules = append(rules, methods[0])
+		} else {
+			rules = append(rules, &envoyrbac.Permission{
+				Rule: &envoyrbac.Permission_OrRules{
+					OrRules: &envoyrbac.Permission_Set{Rules: methods},
+				},
+			})
+		}
+	}
+
+	return andRBACPermissions(rules)
+}
+
+func makeRBACHeaderPermission(m *envoyroute.HeaderMatcher) *envoyrbac.Permission {
+	return &envoyrbac.Permission{
+		Rule: &envoyrbac.Permission_Header{Header: m},
+	}
+}
+
+func andRBACPermissions(rules []*envoyrbac.Permission) *envoyrbac.Permission {
+	if len(rules) == 1 {
+		return rules[0]
+	}
+	return &envoyrbac.Permission{
+		Rule: &envoyrbac.Permission_AndRules{
+			AndRules: &envoyrbac.Permission_Set{Rules: rules},
+		},
+	}
+}
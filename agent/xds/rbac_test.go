@@ -0,0 +1,306 @@
+package xds
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"testing"
+
+	envoyroute "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	envoyrbac "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v2alpha"
+	envoymatcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func TestMakeRBACRules(t *testing.T) {
+	ixn := func(src, dst string, action structs.IntentionAction, perms ...*structs.IntentionPermission) *structs.Intention {
+		x := &structs.Intention{
+			SourceNS:        structs.IntentionDefaultNamespace,
+			SourceName:      src,
+			DestinationNS:   structs.IntentionDefaultNamespace,
+			DestinationName: dst,
+			Action:          action,
+			Permissions:     perms,
+		}
+		x.UpdatePrecedence()
+		return x
+	}
+	perm := func(action structs.IntentionAction, http *structs.IntentionHTTPPermission) *structs.IntentionPermission {
+		return &structs.IntentionPermission{Action: action, HTTP: http}
+	}
+	allow, deny := structs.IntentionActionAllow, structs.IntentionActionDeny
+
+	cidr := func(block, dst string, action structs.IntentionAction) *structs.Intention {
+		x := ixn(block, dst, action)
+		x.SourceType = structs.IntentionSourceCIDR
+		x.UpdatePrecedence()
+		return x
+	}
+
+	type request struct {
+		source  string
+		addr    string
+		method  string
+		path    string
+		headers map[string]string
+		allowed bool
+	}
+
+	cases := []struct {
+		name         string
+		intentions   structs.Intentions
+		defaultAllow bool
+		requests     []request
+	}{
+		{
+			name:         "no intentions default deny",
+			defaultAllow: false,
+			requests: []request{
+				{source: "billing", method: "GET", path: "/", allowed: false},
+			},
+		},
+		{
+			name:         "no intentions default allow",
+			defaultAllow: true,
+			requests: []request{
+				{source: "billing", method: "GET", path: "/", allowed: true},
+			},
+		},
+		{
+			name: "exact allow over wildcard deny",
+			intentions: structs.Intentions{
+				ixn("*", "payments", deny),
+				ixn("billing", "payments", allow),
+			},
+			defaultAllow: true,
+			requests: []request{
+				{source: "billing", method: "GET", path: "/", allowed: true},
+				{source: "web", method: "GET", path: "/", allowed: false},
+			},
+		},
+		{
+			name: "exact deny over wildcard allow",
+			intentions: structs.Intentions{
+				ixn("*", "*", allow),
+				ixn("billing", "payments", deny),
+			},
+			defaultAllow: false,
+			requests: []request{
+				{source: "billing", method: "GET", path: "/", allowed: false},
+				{source: "web", method: "GET", path: "/", allowed: true},
+			},
+		},
+		{
+			name: "only POST /charge",
+			intentions: structs.Intentions{
+				ixn("billing", "payments", "",
+					perm(allow, &structs.IntentionHTTPPermission{
+						PathExact: "/charge",
+						Methods:   []string{"POST"},
+					}),
+				),
+			},
+			defaultAllow: false,
+			requests: []request{
+				{source: "billing", method: "POST", path: "/charge", allowed: true},
+				{source: "billing", method: "POST", path: "/charge?amount=5", allowed: true},
+				{source: "billing", method: "GET", path: "/charge", allowed: false},
+				{source: "billing", method: "POST", path: "/charge/all", allowed: false},
+				{source: "billing", method: "POST", path: "/refund", allowed: false},
+				{source: "web", method: "POST", path: "/charge", allowed: false},
+			},
+		},
+		{
+			name: "only POST /charge with default allow",
+			intentions: structs.Intentions{
+				ixn("billing", "payments", "",
+					perm(allow, &structs.IntentionHTTPPermission{
+						PathExact: "/charge",
+						Methods:   []string{"POST"},
+					}),
+					perm(deny, &structs.IntentionHTTPPermission{
+						PathPrefix: "/",
+					}),
+				),
+			},
+			defaultAllow: true,
+			requests: []request{
+				{source: "billing", method: "POST", path: "/charge", allowed: true},
+				{source: "billing", method: "GET", path: "/charge", allowed: false},
+				{source: "billing", method: "POST", path: "/refund", allowed: false},
+				{source: "web", method: "POST", path: "/refund", allowed: true},
+			},
+		},
+		{
+			name: "first matching permission wins",
+			intentions: structs.Intentions{
+				ixn("billing", "payments", "",
+					perm(deny, &structs.IntentionHTTPPermission{
+						PathPrefix: "/admin",
+						Header: []structs.IntentionHTTPHeaderPermission{
+							{Name: "x-role", Exact: "admin", Invert: true},
+						},
+					}),
+					perm(allow, &structs.IntentionHTTPPermission{
+						PathRegex: "/(admin|api)/.*",
+					}),
+				),
+				ixn("*", "payments", allow),
+			},
+			defaultAllow: false,
+			requests: []request{
+				{source: "billing", method: "GET", path: "/api/users", allowed: true},
+				{source: "billing", method: "GET", path: "/admin/users", allowed: false},
+				{source: "billing", method: "GET", path: "/admin/users",
+					headers: map[string]string{"x-role": "admin"}, allowed: true},
+				// Requests that match no permission fall back to the default
+				// rather than the wildcard intention.
+				{source: "billing", method: "GET", path: "/", allowed: false},
+				{source: "web", method: "GET", path: "/admin/users", allowed: true},
+			},
+		},
+		{
+			name: "cidr sources",
+			intentions: structs.Intentions{
+				cidr("10.0.0.0/8", "payments", allow),
+				cidr("10.1.0.0/16", "payments", deny),
+				ixn("billing", "payments", deny),
+			},
+			defaultAllow: false,
+			requests: []request{
+				{source: "web", addr: "10.0.0.1", method: "GET", path: "/", allowed: true},
+				{source: "web", addr: "10.1.0.1", method: "GET", path: "/", allowed: false},
+				{source: "web", addr: "192.168.0.1", method: "GET", path: "/", allowed: false},
+				// The service source takes precedence over the address.
+				{source: "billing", addr: "10.0.0.1", method: "GET", path: "/", allowed: false},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rbac := makeRBACRules(tc.intentions, tc.defaultAllow)
+			for _, req := range tc.requests {
+				headers := map[string]string{
+					":method": req.method,
+					":path":   req.path,
+				}
+				for k, v := range req.headers {
+					headers[k] = v
+				}
+				uri := "spiffe://11111111-2222-3333-4444-555555555555.consul/ns/default/dc/dc1/svc/" + req.source
+				addr := "10.0.0.1"
+				if req.addr != "" {
+					addr = req.addr
+				}
+
+				require.Equal(t, req.allowed, evalRBAC(t, rbac, uri, net.ParseIP(addr), headers),
+					"%s %s from %s at %s", req.method, req.path, req.source, addr)
+			}
+		})
+	}
+}
+
+// evalRBAC evaluates the RBAC rules like Envoy does for a request from the
+// given source and address with the given headers.
+func evalRBAC(t *testing.T, rbac *envoyrbac.RBAC, uri string, addr net.IP, headers map[string]string) bool {
+	matched := false
+	for _, policy := range rbac.Policies {
+		principal := false
+		for _, p := range policy.Principals {
+			if evalRBACPrincipal(t, p, uri, addr) {
+				principal = true
+			}
+		}
+		permission := false
+		for _, p := range policy.Permissions {
+			if evalRBACPermission(t, p, headers) {
+				permission = true
+			}
+		}
+		if principal && permission {
+			matched = true
+		}
+	}
+	if rbac.Action == envoyrbac.RBAC_ALLOW {
+		return matched
+	}
+	return !matched
+}
+
+func evalRBACPrincipal(t *testing.T, p *envoyrbac.Principal, uri string, addr net.IP) bool {
+	switch id := p.Identifier.(type) {
+	case *envoyrbac.Principal_AndIds:
+		for _, p := range id.AndIds.Ids {
+			if !evalRBACPrincipal(t, p, uri, addr) {
+				return false
+			}
+		}
+		return true
+	case *envoyrbac.Principal_NotId:
+		return !evalRBACPrincipal(t, id.NotId, uri, addr)
+	case *envoyrbac.Principal_Authenticated_:
+		m := id.Authenticated.PrincipalName.MatchPattern.(*envoymatcher.StringMatcher_Regex)
+		return fullMatch(m.Regex, uri)
+	case *envoyrbac.Principal_SourceIp:
+		_, ipNet, err := net.ParseCIDR(fmt.Sprintf("%s/%d",
+			id.SourceIp.AddressPrefix, id.SourceIp.PrefixLen.GetValue()))
+		require.NoError(t, err)
+		return ipNet.Contains(addr)
+	}
+	t.Fatalf("unexpected principal %T", p.Identifier)
+	return false
+}
+
+func evalRBACPermission(t *testing.T, p *envoyrbac.Permission, headers map[string]string) bool {
+	switch rule := p.Rule.(type) {
+	case *envoyrbac.Permission_Any:
+		return rule.Any
+	case *envoyrbac.Permission_AndRules:
+		for _, p := range rule.AndRules.Rules {
+			if !evalRBACPermission(t, p, headers) {
+				return false
+			}
+		}
+		return true
+	case *envoyrbac.Permission_OrRules:
+		for _, p := range rule.OrRules.Rules {
+			if evalRBACPermission(t, p, headers) {
+				return true
+			}
+		}
+		return false
+	case *envoyrbac.Permission_NotRule:
+		return !evalRBACPermission(t, rule.NotRule, headers)
+	case *envoyrbac.Permission_Header:
+		return evalHeaderMatcher(t, rule.Header, headers) != rule.Header.InvertMatch
+	}
+	t.Fatalf("unexpected permission %T", p.Rule)
+	return false
+}
+
+func evalHeaderMatcher(t *testing.T, m *envoyroute.HeaderMatcher, headers map[string]string) bool {
+	v, ok := headers[m.Name]
+	switch spec := m.HeaderMatchSpecifier.(type) {
+	case *envoyroute.HeaderMatcher_PresentMatch:
+		return ok
+	case *envoyroute.HeaderMatcher_ExactMatch:
+		return ok && v == spec.ExactMatch
+	case *envoyroute.HeaderMatcher_PrefixMatch:
+		return ok && strings.HasPrefix(v, spec.PrefixMatch)
+	case *envoyroute.HeaderMatcher_SuffixMatch:
+		return ok && strings.HasSuffix(v, spec.SuffixMatch)
+	case *envoyroute.HeaderMatcher_RegexMatch:
+		return ok && fullMatch(spec.RegexMatch, v)
+	}
+	t.Fatalf("unexpected header matcher %T", m.HeaderMatchSpecifier)
+	return false
+}
+
+// fullMatch matches the whole value like Envoy's regex matchers do.
+func fullMatch(pattern, v string) bool {
+	return regexp.MustCompile("^(?:" + pattern + ")$").MatchString(v)
+}
@@ -447,6 +447,7 @@ func (s *Server) Check(ctx context.Context, r *envoyauthz.CheckRequest) (*envoya
 		ClientCertURI: r.Attributes.Source.Principal,
 		// TODO(banks): need Envoy to support sending cert serial/hash to enforce
 		// revocation later.
+		ClientAddr: r.Attributes.Source.GetAddress().GetSocketAddress().GetAddress(),
 	}
 	token := tokenFromContext(ctx)
 	authed, reason, _, err := s.Authz.ConnectAuthorize(token, req)
@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
 // ServiceKind is the kind of service being registered.
@@ -65,6 +67,49 @@ type AgentCheck struct {
 	ServiceID   string
 	ServiceName string
 	Definition  HealthCheckDefinition
+
+	// FlapCount is the number of times the check changed status in the
+	// last hour.
+	FlapCount int
+}
+
+// AgentCheckHistoryEntry is a status change of a check known to the agent.
+type AgentCheckHistoryEntry struct {
+	Time   time.Time
+	Status string
+	Output string
+}
+
+// AgentSyncStatus describes how the agent's local state is being synced to
+// the catalog.
+type AgentSyncStatus struct {
+	// NodeInSync is true when the node information is in sync and
+	// NodeSyncError is the error of the last failed attempt to sync it.
+	NodeInSync    bool
+	NodeSyncError string
+
+	LastFullSync         time.Time
+	LastFullSyncError    string
+	LastPartialSync      time.Time
+	LastPartialSyncError string
+	NextFullSync         time.Time
+	Paused               bool
+
+	// Services and Checks are the sync states of the local services and
+	// checks, keyed by their ID.
+	Services map[string]*AgentSyncEntry
+	Checks   map[string]*AgentSyncEntry
+
+	// ServiceFileErrors are the errors of the service definition files in
+	// the service_watch_dirs, keyed by their path.
+	ServiceFileErrors map[string]string `json:",omitempty"`
+}
+
+// AgentSyncEntry is the sync state of a local service or check.
+type AgentSyncEntry struct {
+	InSync  bool
+	Deleted bool
+	Error   string `json:",omitempty"`
 }
 
 // AgentWeights represent optional weights for a service
@@ -183,6 +228,22 @@ type AgentCheckRegistration struct {
 	AgentServiceCheck
 }
 
+// CheckJSONAssertion asserts that the value at Path in the JSON body returned
+// to an HTTP check equals Value. If it doesn't, the check reports Status,
+// which is critical if not set.
+type CheckJSONAssertion struct {
+	Path   string
+	Value  string
+	Status string `json:",omitempty"`
+}
+
+// AgentServiceRegisterResult is the outcome of registering one of the
+// services of a batch.
+type AgentServiceRegisterResult struct {
+	ID    string
+	Error string `json:",omitempty"`
+}
+
 // AgentServiceCheck is used to define a node or service level check
 type AgentServiceCheck struct {
 	CheckID           string              `json:",omitempty"`
@@ -197,6 +258,15 @@ type AgentServiceCheck struct {
 	Header            map[string][]string `json:",omitempty"`
 	Method            string              `json:",omitempty"`
 	TCP               string              `json:",omitempty"`
+	UDP               string              `json:",omitempty"`
+	UDPSend           string              `json:",omitempty"`
+	UDPExpect         string              `json:",omitempty"`
+	DNS               string              `json:",omitempty"`
+	DNSServer         string              `json:",omitempty"`
+	DNSRecordType     string              `json:",omitempty"`
+	DNSExpect         string              `json:",omitempty"`
+	TLS               string              `json:",omitempty"`
+	TLSServerName     string              `json:",omitempty"`
 	Status            string              `json:",omitempty"`
 	Notes             string              `json:",omitempty"`
 	TLSSkipVerify     bool                `json:",omitempty"`
@@ -205,6 +275,23 @@ type AgentServiceCheck struct {
 	AliasNode         string              `json:",omitempty"`
 	AliasService      string              `json:",omitempty"`
 
+	// BodyMatch and JSONAssertions are checked against the body returned to
+	// an HTTP check. BodyMatch is a regular expression if BodyMatchRegex is
+	// set.
+	BodyMatch      string               `json:",omitempty"`
+	BodyMatchRegex bool                 `json:",omitempty"`
+	JSONAssertions []CheckJSONAssertion `json:",omitempty"`
+
+	// TLSExpiryWarningDays is the number of days before the certificate
+	// presented to a TLS check expires that the check starts warning.
+	TLSExpiryWarningDays int `json:",omitempty"`
+
+	// SuccessBeforePassing and FailuresBeforeCritical are the number of
+	// consecutive successful or failed results needed before the check's
+	// status changes to passing or critical.
+	SuccessBeforePassing   int `json:",omitempty"`
+	FailuresBeforeCritical int `json:",omitempty"`
+
 	// In Consul 0.7 and later, checks that are associated with a service
 	// may also contain this optional DeregisterCriticalServiceAfter field,
 	// which is a timeout in the same Go time format as Interval and TTL. If
@@ -261,6 +348,7 @@ type AgentAuthorizeParams struct {
 	Target           string
 	ClientCertURI    string
 	ClientCertSerial string
+	ClientAddr       string `json:",omitempty"`
 }
 
 // AgentAuthorize is the response structure for Connect authorization.
@@ -401,6 +489,40 @@ func (a *Agent) Checks() (map[string]*AgentCheck, error) {
 	return out, nil
 }
 
+// CheckHistory returns the most recent status changes of a locally
+// registered check, oldest first.
+func (a *Agent) CheckHistory(checkID string) ([]*AgentCheckHistoryEntry, error) {
+	r := a.c.newRequest("GET", "/v1/agent/check/"+checkID+"/history")
+	_, resp, err := requireOK(a.c.doRequest(r))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out []*AgentCheckHistoryEntry
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SyncStatus returns how the agent's local services and checks are being
+// synced to the catalog.
+func (a *Agent) SyncStatus() (*AgentSyncStatus, error) {
+	r := a.c.newRequest("GET", "/v1/agent/sync-status")
+	_, resp, err := requireOK(a.c.doRequest(r))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out AgentSyncStatus
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 // Services returns the locally registered services
 func (a *Agent) Services() (map[string]*AgentService, error) {
 	r := a.c.newRequest("GET", "/v1/agent/services")
@@ -567,6 +689,38 @@ func (a *Agent) ServiceRegister(service *AgentServiceRegistration) error {
 	return nil
 }
 
+// ServiceRegisterBatch is used to register several services with the local
+// agent at once. If any of the services is invalid, none of them is
+// registered and the per-service errors are returned in the results along
+// with an error. If a valid batch fails to be added, it is rolled back and
+// services that were already registered keep their previous definition.
+func (a *Agent) ServiceRegisterBatch(services []*AgentServiceRegistration) ([]*AgentServiceRegisterResult, error) {
+	r := a.c.newRequest("PUT", "/v1/agent/services/register")
+	r.obj = services
+	_, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	invalid := resp.StatusCode == http.StatusBadRequest &&
+		strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json")
+	if resp.StatusCode != http.StatusOK && !invalid {
+		var buf bytes.Buffer
+		io.Copy(&buf, resp.Body)
+		return nil, fmt.Errorf("Unexpected response code: %d (%s)", resp.StatusCode, buf.Bytes())
+	}
+
+	var out []*AgentServiceRegisterResult
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, err
+	}
+	if invalid {
+		return out, fmt.Errorf("Invalid service definitions")
+	}
+	return out, nil
+}
+
 // ServiceDeregister is used to deregister a service with
 // the local agent
 func (a *Agent) ServiceDeregister(serviceID string) error {
@@ -860,6 +860,124 @@ func TestAPI_AgentChecks(t *testing.T) {
 	}
 }
 
+func TestAPI_AgentCheckHistory(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	agent := c.Agent()
+
+	reg := &AgentCheckRegistration{
+		Name: "foo",
+	}
+	reg.TTL = "15s"
+	if err := agent.CheckRegister(reg); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := agent.PassTTL("foo", "ok"); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	history, err := agent.CheckHistory("foo")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("bad: %v", history)
+	}
+	if history[1].Status != HealthPassing || history[1].Output != "ok" {
+		t.Fatalf("bad: %v", history[1])
+	}
+
+	checks, err := agent.Checks()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if checks["foo"].FlapCount != 1 {
+		t.Fatalf("bad: %v", checks["foo"])
+	}
+}
+
+func TestAPI_AgentServiceRegisterBatch(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	agent := c.Agent()
+
+	regs := []*AgentServiceRegistration{
+		{Name: "foo", Port: 8000},
+		{Name: "bar", Address: "0.0.0.0"},
+	}
+	results, err := agent.ServiceRegisterBatch(regs)
+	if err == nil {
+		t.Fatalf("should fail")
+	}
+	if len(results) != 2 || results[0].Error != "" || results[1].Error == "" {
+		t.Fatalf("bad: %v", results)
+	}
+
+	services, err := agent.Services()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if _, ok := services["foo"]; ok {
+		t.Fatalf("should not be registered: %v", services)
+	}
+
+	regs[1].Address = ""
+	results, err = agent.ServiceRegisterBatch(regs)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(results) != 2 || results[0].ID != "foo" || results[1].ID != "bar" {
+		t.Fatalf("bad: %v", results)
+	}
+
+	services, err = agent.Services()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if _, ok := services["foo"]; !ok {
+		t.Fatalf("missing service: %v", services)
+	}
+	if _, ok := services["bar"]; !ok {
+		t.Fatalf("missing service: %v", services)
+	}
+}
+
+func TestAPI_AgentSyncStatus(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	agent := c.Agent()
+
+	reg := &AgentServiceRegistration{
+		Name: "foo",
+	}
+	if err := agent.ServiceRegister(reg); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	retry.Run(t, func(r *retry.R) {
+		status, err := agent.SyncStatus()
+		if err != nil {
+			r.Fatalf("err: %v", err)
+		}
+		entry, ok := status.Services["foo"]
+		if !ok {
+			r.Fatalf("missing service: %v", status.Services)
+		}
+		if !entry.InSync || entry.Error != "" {
+			r.Fatalf("bad: %v", entry)
+		}
+		if status.LastFullSync.IsZero() {
+			r.Fatalf("bad: %v", status)
+		}
+	})
+}
+
 func TestAPI_AgentScriptCheck(t *testing.T) {
 	t.Parallel()
 	c, s := makeClientWithConfig(t, nil, func(c *testutil.TestServerConfig) {
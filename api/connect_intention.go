@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"strconv"
 	"time"
 )
 
@@ -33,9 +34,15 @@ type Intention struct {
 	// SourceType is the type of the value for the source.
 	SourceType IntentionSourceType
 
-	// Action is whether this is a whitelist or blacklist intention.
+	// Action is whether this is a whitelist or blacklist intention. It is
+	// empty when Permissions are set.
 	Action IntentionAction
 
+	// Permissions is the ordered list of L7 permissions of the intention.
+	// The first permission that matches an HTTP request decides whether it
+	// is allowed.
+	Permissions []*IntentionPermission `json:",omitempty"`
+
 	// DefaultAddr, DefaultPort of the local listening proxy (if any) to
 	// make this connection.
 	DefaultAddr string
@@ -58,12 +65,46 @@ type Intention struct {
 	ModifyIndex uint64
 }
 
+// IntentionPermission is an L7 permission of an intention.
+type IntentionPermission struct {
+	Action IntentionAction
+	HTTP   *IntentionHTTPPermission
+}
+
+// IntentionHTTPPermission matches HTTP requests by their path, method and
+// headers. At most one of the path fields may be set.
+type IntentionHTTPPermission struct {
+	PathExact  string `json:",omitempty"`
+	PathPrefix string `json:",omitempty"`
+	PathRegex  string `json:",omitempty"`
+
+	Header []IntentionHTTPHeaderPermission `json:",omitempty"`
+
+	Methods []string `json:",omitempty"`
+}
+
+// IntentionHTTPHeaderPermission matches a header of an HTTP request. Exactly
+// one of Present, Exact, Prefix, Suffix and Regex must be set.
+type IntentionHTTPHeaderPermission struct {
+	Name    string
+	Present bool   `json:",omitempty"`
+	Exact   string `json:",omitempty"`
+	Prefix  string `json:",omitempty"`
+	Suffix  string `json:",omitempty"`
+	Regex   string `json:",omitempty"`
+	Invert  bool   `json:",omitempty"`
+}
+
 // String returns human-friendly output describing ths intention.
 func (i *Intention) String() string {
+	action := string(i.Action)
+	if len(i.Permissions) > 0 {
+		action = "L7"
+	}
 	return fmt.Sprintf("%s => %s (%s)",
 		i.SourceString(),
 		i.DestinationString(),
-		i.Action)
+		action)
 }
 
 // SourceString returns the namespace/name format for the source, or
@@ -106,6 +147,10 @@ type IntentionSourceType string
 const (
 	// IntentionSourceConsul is a service within the Consul catalog.
 	IntentionSourceConsul IntentionSourceType = "consul"
+
+	// IntentionSourceCIDR is a block of network addresses in CIDR notation
+	// that connections come from.
+	IntentionSourceCIDR IntentionSourceType = "cidr"
 )
 
 // IntentionMatch are the arguments for the intention match API.
@@ -136,6 +181,91 @@ type IntentionCheck struct {
 	SourceType IntentionSourceType
 }
 
+// IntentionExplanation explains the result of checking a source/destination
+// against the intentions.
+type IntentionExplanation struct {
+	// Allowed is whether the source is allowed to connect to the destination.
+	Allowed bool
+
+	// Reason is a human-friendly explanation of the decision.
+	Reason string
+
+	// Matched is the intention that decided, or nil if the default behavior
+	// configured by ACLs applies.
+	Matched *Intention
+
+	// DefaultAllow is the default behavior for connections that match no
+	// intention.
+	DefaultAllow bool
+
+	// Intentions are all intentions for the destination in the order they
+	// are evaluated.
+	Intentions []*IntentionExplanationEntry
+}
+
+// IntentionExplanationEntry is an intention of an explanation.
+type IntentionExplanationEntry struct {
+	Intention *Intention
+
+	// SourceMatch is true if the intention matches the checked source.
+	SourceMatch bool
+
+	// ShadowedBy is the ID of an intention with a higher precedence that
+	// matches every source this intention matches, so this intention never
+	// applies to the destination.
+	ShadowedBy string
+}
+
+// IntentionApplyOptions are the options for applying a desired set of
+// intentions.
+type IntentionApplyOptions struct {
+	// Prune deletes the existing intentions that are not part of the
+	// desired set. Otherwise they are left alone.
+	Prune bool
+
+	// DryRun only plans the changes without applying them.
+	DryRun bool
+
+	// CAS is the index of a previous plan. If set, the changes are only
+	// applied if the intentions haven't changed since the plan.
+	CAS uint64
+}
+
+// IntentionChangeOp is the operation of an intention change.
+type IntentionChangeOp string
+
+const (
+	IntentionChangeCreate IntentionChangeOp = "create"
+	IntentionChangeUpdate IntentionChangeOp = "update"
+	IntentionChangeDelete IntentionChangeOp = "delete"
+)
+
+// IntentionPlan lists the changes needed to make the intentions match a
+// desired set.
+type IntentionPlan struct {
+	// Changes are the changes in the order they are applied.
+	Changes []*IntentionChange
+
+	// Unmanaged are the existing intentions that are not part of the
+	// desired set and are left alone because Prune isn't set.
+	Unmanaged []*Intention
+
+	// Index is the intentions index the plan was made at.
+	Index uint64
+}
+
+// IntentionChange is a single change of an IntentionPlan.
+type IntentionChange struct {
+	Op IntentionChangeOp
+
+	// Intention is the intention to create, the updated intention, or the
+	// intention to delete.
+	Intention *Intention
+
+	// Previous is the existing intention of an update.
+	Previous *Intention
+}
+
 // Intentions returns the list of intentions.
 func (h *Connect) Intentions(q *QueryOptions) ([]*Intention, *QueryMeta, error) {
 	r := h.c.newRequest("GET", "/v1/connect/intentions")
@@ -261,6 +391,33 @@ func (h *Connect) IntentionCheck(args *IntentionCheck, q *QueryOptions) (bool, *
 	return out.Allowed, qm, nil
 }
 
+// IntentionExplain is like IntentionCheck but explains the result with the
+// intention that decided and all intentions for the destination.
+func (h *Connect) IntentionExplain(args *IntentionCheck, q *QueryOptions) (*IntentionExplanation, *QueryMeta, error) {
+	r := h.c.newRequest("GET", "/v1/connect/intentions/explain")
+	r.setQueryOptions(q)
+	r.params.Set("source", args.Source)
+	r.params.Set("destination", args.Destination)
+	if args.SourceType != "" {
+		r.params.Set("source-type", string(args.SourceType))
+	}
+	rtt, resp, err := requireOK(h.c.doRequest(r))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out IntentionExplanation
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return &out, qm, nil
+}
+
 // IntentionCreate will create a new intention. The ID in the given
 // structure must be empty and a generate ID will be returned on
 // success.
@@ -284,6 +441,44 @@ func (c *Connect) IntentionCreate(ixn *Intention, q *WriteOptions) (string, *Wri
 	return out.ID, wm, nil
 }
 
+// IntentionApply makes the intentions match the given set in a single
+// transaction. Intentions are matched by source and destination, so the
+// given intentions must not have an ID. The returned plan lists the changes
+// that were made, or that would be made if DryRun is set.
+func (c *Connect) IntentionApply(ixns []*Intention, opts *IntentionApplyOptions, q *WriteOptions) (*IntentionPlan, *WriteMeta, error) {
+	r := c.c.newRequest("PUT", "/v1/connect/intentions/apply")
+	r.setWriteOptions(q)
+	if opts != nil {
+		if opts.Prune {
+			r.params.Set("prune", "")
+		}
+		if opts.DryRun {
+			r.params.Set("dry-run", "")
+		}
+		if opts.CAS != 0 {
+			r.params.Set("cas", strconv.FormatUint(opts.CAS, 10))
+		}
+	}
+	if ixns == nil {
+		ixns = make([]*Intention, 0)
+	}
+	r.obj = ixns
+	rtt, resp, err := requireOK(c.c.doRequest(r))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	wm := &WriteMeta{}
+	wm.RequestTime = rtt
+
+	var out IntentionPlan
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return &out, wm, nil
+}
+
 // IntentionUpdate will update an existing intention. The ID in the given
 // structure must be non-empty.
 func (c *Connect) IntentionUpdate(ixn *Intention, q *WriteOptions) (*WriteMeta, error) {
@@ -173,6 +173,95 @@ func TestAPI_ConnectIntentionCheck(t *testing.T) {
 	}
 }
 
+func TestAPI_ConnectIntentionExplain(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	connect := c.Connect()
+
+	// Create
+	ixn := testIntention()
+	ixn.SourceNS = "foo"
+	ixn.SourceName = "*"
+	ixn.DestinationNS = "foo"
+	ixn.DestinationName = "bar"
+	ixn.Action = IntentionActionDeny
+	id, _, err := connect.IntentionCreate(ixn, nil)
+	require.Nil(err)
+
+	// Explain it
+	{
+		result, qm, err := connect.IntentionExplain(&IntentionCheck{
+			Source:      "foo/qux",
+			Destination: "foo/bar",
+		}, nil)
+		require.Nil(err)
+		require.NotZero(qm.LastIndex)
+		require.False(result.Allowed)
+		require.Equal(id, result.Matched.ID)
+		require.Len(result.Intentions, 1)
+		require.True(result.Intentions[0].SourceMatch)
+	}
+
+	// Explain it (non-matching)
+	{
+		result, _, err := connect.IntentionExplain(&IntentionCheck{
+			Source:      "bar/qux",
+			Destination: "foo/bar",
+		}, nil)
+		require.Nil(err)
+		require.True(result.Allowed)
+		require.True(result.DefaultAllow)
+		require.Nil(result.Matched)
+		require.NotEmpty(result.Reason)
+	}
+}
+
+func TestAPI_ConnectIntentionApply(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	connect := c.Connect()
+
+	// Create an intention that isn't part of the desired set.
+	ixn := testIntention()
+	oldID, _, err := connect.IntentionCreate(ixn, nil)
+	require.Nil(err)
+
+	desired := []*Intention{
+		{SourceName: "web", DestinationName: "db", Action: IntentionActionAllow},
+	}
+
+	// Plan it
+	plan, _, err := connect.IntentionApply(desired, &IntentionApplyOptions{DryRun: true, Prune: true}, nil)
+	require.Nil(err)
+	require.Len(plan.Changes, 2)
+	require.Equal(IntentionChangeDelete, plan.Changes[0].Op)
+	require.Equal(oldID, plan.Changes[0].Intention.ID)
+	require.Equal(IntentionChangeCreate, plan.Changes[1].Op)
+	require.Equal("web", plan.Changes[1].Intention.SourceName)
+
+	// Apply it
+	result, _, err := connect.IntentionApply(desired, &IntentionApplyOptions{Prune: true, CAS: plan.Index}, nil)
+	require.Nil(err)
+	require.Len(result.Changes, 2)
+
+	actual, _, err := connect.Intentions(nil)
+	require.Nil(err)
+	require.Len(actual, 1)
+	require.Equal("web", actual[0].SourceName)
+
+	// The stale plan is rejected
+	_, _, err = connect.IntentionApply(desired, &IntentionApplyOptions{CAS: plan.Index}, nil)
+	require.Error(err)
+}
+
 func testIntention() *Intention {
 	return &Intention{
 		SourceNS:        "eng",
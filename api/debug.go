@@ -104,3 +104,25 @@ func (d *Debug) Goroutine() ([]byte, error) {
 
 	return body, nil
 }
+
+// GoroutineStacks returns the goroutine profile in its text format, which
+// gives a count for each unique stack
+func (d *Debug) GoroutineStacks() ([]byte, error) {
+	r := d.c.newRequest("GET", "/debug/pprof/goroutine")
+	r.params.Set("debug", "1")
+
+	_, resp, err := d.c.doRequest(r)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	// We return a raw response because we're just passing through a response
+	// from the pprof handlers
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding body: %s", err)
+	}
+
+	return body, nil
+}
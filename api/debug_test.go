@@ -1,6 +1,7 @@
 package api
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/consul/sdk/testutil"
@@ -81,3 +82,22 @@ func TestAPI_DebugTrace(t *testing.T) {
 		t.Fatalf("no response: %#v", raw)
 	}
 }
+
+func TestAPI_DebugGoroutineStacks(t *testing.T) {
+	t.Parallel()
+	c, s := makeClientWithConfig(t, nil, func(conf *testutil.TestServerConfig) {
+		conf.EnableDebug = true
+	})
+
+	defer s.Stop()
+
+	debug := c.Debug()
+	raw, err := debug.GoroutineStacks()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if !strings.HasPrefix(string(raw), "goroutine profile:") {
+		t.Fatalf("bad: %s", raw)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // KVPair is used to represent a single K/V entry
@@ -45,6 +46,28 @@ type KVPair struct {
 // KVPairs is a list of KVPair objects
 type KVPairs []*KVPair
 
+// KVLockInfo describes the lock on a key, to help find out who holds a lock
+// or why it can't be acquired.
+type KVLockInfo struct {
+	// Key is the name of the key.
+	Key string
+
+	// Session is the ID of the session that holds the lock, or empty if the
+	// lock isn't held.
+	Session string
+
+	// Node is the node of the session that holds the lock. It's empty if the
+	// lock isn't held or the session can't be read.
+	Node string
+
+	// LockIndex is the number of times the lock has been acquired.
+	LockIndex uint64
+
+	// LockDelay is the time that's left until the lock can be acquired again
+	// after the session that held it was invalidated.
+	LockDelay time.Duration
+}
+
 // KV is used to manipulate the K/V API
 type KV struct {
 	c *Client
@@ -118,6 +141,26 @@ func (k *KV) Keys(prefix, separator string, q *QueryOptions) ([]string, *QueryMe
 	return entries, qm, nil
 }
 
+// LockInfo is used to look up who holds the lock on a key. The key doesn't
+// have to exist, since a lock-delay can be in effect for a key that was
+// deleted when the session that held it was invalidated.
+func (k *KV) LockInfo(key string, q *QueryOptions) (*KVLockInfo, *QueryMeta, error) {
+	resp, qm, err := k.getInternal(key, map[string]string{"lock-info": ""}, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp == nil {
+		return nil, qm, nil
+	}
+	defer resp.Body.Close()
+
+	var info KVLockInfo
+	if err := decodeBody(resp, &info); err != nil {
+		return nil, nil, err
+	}
+	return &info, qm, nil
+}
+
 func (k *KV) getInternal(key string, params map[string]string, q *QueryOptions) (*http.Response, *QueryMeta, error) {
 	r := k.c.newRequest("GET", "/v1/kv/"+strings.TrimPrefix(key, "/"))
 	r.setQueryOptions(q)
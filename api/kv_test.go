@@ -3,6 +3,7 @@ package api
 import (
 	"bytes"
 	"path"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -458,6 +459,67 @@ func TestAPI_ClientAcquireRelease(t *testing.T) {
 	}
 }
 
+func TestAPI_ClientLockInfo(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	session := c.Session()
+	kv := c.KV()
+
+	// Make a session
+	id, _, err := session.CreateNoChecks(&SessionEntry{LockDelay: time.Minute}, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Acquire the key
+	key := testKey()
+	p := &KVPair{Key: key, Session: id}
+	if work, _, err := kv.Acquire(p, nil); err != nil {
+		t.Fatalf("err: %v", err)
+	} else if !work {
+		t.Fatalf("Lock failure")
+	}
+	info, meta, err := session.Info(id, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if info.HeldKeys != 1 {
+		t.Fatalf("bad: %v", info)
+	}
+
+	// The holder should be shown
+	lock, meta, err := kv.LockInfo(key, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	expected := &KVLockInfo{
+		Key:       key,
+		Session:   id,
+		Node:      info.Node,
+		LockIndex: 1,
+	}
+	if !reflect.DeepEqual(lock, expected) {
+		t.Fatalf("bad: %#v", lock)
+	}
+	if meta.LastIndex == 0 {
+		t.Fatalf("unexpected value: %#v", meta)
+	}
+
+	// Once the session is destroyed the lock-delay should be shown
+	if _, err := session.Destroy(id, nil); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	lock, _, err = kv.LockInfo(key, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if lock.Session != "" || lock.LockDelay <= 0 || lock.LockDelay > time.Minute {
+		t.Fatalf("bad: %#v", lock)
+	}
+}
+
 func TestAPI_KVClientTxn(t *testing.T) {
 	t.Parallel()
 	c, s := makeClient(t)
@@ -2,6 +2,7 @@ package api
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 )
@@ -64,6 +65,8 @@ type Lock struct {
 	isHeld       bool
 	sessionRenew chan struct{}
 	lockSession  string
+	fencingToken uint64
+	lockIndex    uint64
 	l            sync.Mutex
 }
 
@@ -208,11 +211,11 @@ WAIT:
 	}
 
 	// Try to acquire the lock
-	pair = l.lockEntry(l.lockSession)
-	locked, _, err = kv.Acquire(pair, nil)
+	pair, err = l.acquire(l.lockSession)
 	if err != nil {
 		return nil, fmt.Errorf("failed to acquire lock: %v", err)
 	}
+	locked = pair != nil
 
 	// Handle the case of not getting the lock
 	if !locked {
@@ -243,11 +246,44 @@ HELD:
 
 	// Set that we own the lock
 	l.isHeld = true
+	l.fencingToken = pair.ModifyIndex
+	l.lockIndex = pair.LockIndex
 
 	// Locked! All done
 	return leaderCh, nil
 }
 
+// FencingToken returns the fencing token of the lock, or zero if the lock
+// isn't held. The token is the Raft index at which the lock was acquired, so
+// it grows with every acquisition, even if the lock key is deleted in between.
+// Passing the token along with writes lets other systems reject writes from a
+// holder that has lost the lock without noticing, for example because it was
+// partitioned. Writes to Consul can be guarded with the operation returned by
+// CheckFenceOp.
+func (l *Lock) FencingToken() uint64 {
+	l.l.Lock()
+	defer l.l.Unlock()
+	return l.fencingToken
+}
+
+// CheckFenceOp returns a KVCheckFence operation that fails a transaction
+// unless the lock is still held by this acquisition, or nil if the lock isn't
+// held. The operation checks the session and the lock index of the lock key
+// rather than the fencing token itself.
+func (l *Lock) CheckFenceOp() *KVTxnOp {
+	l.l.Lock()
+	defer l.l.Unlock()
+	if !l.isHeld {
+		return nil
+	}
+	return &KVTxnOp{
+		Verb:    KVCheckFence,
+		Key:     l.opts.Key,
+		Session: l.lockSession,
+		Index:   l.lockIndex,
+	}
+}
+
 // Unlock released the lock. It is an error to call this
 // if the lock is not currently held.
 func (l *Lock) Unlock() error {
@@ -262,6 +298,8 @@ func (l *Lock) Unlock() error {
 
 	// Set that we no longer own the lock
 	l.isHeld = false
+	l.fencingToken = 0
+	l.lockIndex = 0
 
 	// Stop the session renew
 	if l.sessionRenew != nil {
@@ -356,6 +394,45 @@ func (l *Lock) lockEntry(session string) *KVPair {
 	}
 }
 
+// acquire tries to acquire the lock with the given session. It uses a
+// transaction so the lock entry is returned along with the outcome, which
+// gives the fencing token and lock index of this acquisition. A nil entry is returned if the
+// lock is held by another session or a lock-delay is in effect.
+func (l *Lock) acquire(session string) (*KVPair, error) {
+	pair := l.lockEntry(session)
+	ok, resp, _, err := l.c.Txn().Txn(TxnOps{
+		&TxnOp{
+			KV: &KVTxnOp{
+				Verb:    KVLock,
+				Key:     pair.Key,
+				Value:   pair.Value,
+				Flags:   pair.Flags,
+				Session: pair.Session,
+			},
+		},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		for _, e := range resp.Errors {
+			if strings.Contains(e.What, "lock is already held") ||
+				strings.Contains(e.What, "lock delay") {
+				return nil, nil
+			}
+		}
+		var errs []string
+		for _, e := range resp.Errors {
+			errs = append(errs, e.What)
+		}
+		return nil, fmt.Errorf("%s", strings.Join(errs, ", "))
+	}
+	if len(resp.Results) != 1 || resp.Results[0].KV == nil {
+		return nil, fmt.Errorf("unexpected transaction response")
+	}
+	return resp.Results[0].KV, nil
+}
+
 // monitorLock is a long running routine to monitor a lock ownership
 // It closes the stopCh if we lose our leadership.
 func (l *Lock) monitorLock(session string, stopCh chan struct{}) {
@@ -97,6 +97,113 @@ func TestAPI_LockLockUnlock(t *testing.T) {
 	}
 }
 
+func TestAPI_LockFencingToken(t *testing.T) {
+	t.Parallel()
+	c, s := makeClientWithoutConnect(t)
+	defer s.Stop()
+
+	lock, session := createTestLock(t, c, "test/lock")
+	defer session.Destroy(lock.opts.Session, nil)
+
+	if token := lock.FencingToken(); token != 0 {
+		t.Fatalf("bad: %d", token)
+	}
+	if _, err := lock.Lock(nil); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// The token is the index at which the lock was acquired
+	token := lock.FencingToken()
+	pair, _, err := c.KV().Get("test/lock", nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if token == 0 || token != pair.ModifyIndex {
+		t.Fatalf("bad: %d != %d", token, pair.ModifyIndex)
+	}
+
+	// A write guarded by the token goes through
+	guardedSet := func(fence *KVTxnOp) (bool, *TxnResponse) {
+		ok, resp, _, err := c.Txn().Txn(TxnOps{
+			&TxnOp{KV: fence},
+			&TxnOp{KV: &KVTxnOp{Verb: KVSet, Key: "test/data", Value: []byte("hello")}},
+		}, nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		return ok, resp
+	}
+	fence := lock.CheckFenceOp()
+	if ok, resp := guardedSet(fence); !ok {
+		t.Fatalf("transaction failure: %v", resp.Errors)
+	}
+
+	// Other writes to the lock key don't change the token
+	pair.Value = []byte("updated")
+	if _, _, err := c.KV().Acquire(pair, nil); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok, resp := guardedSet(fence); !ok {
+		t.Fatalf("transaction failure: %v", resp.Errors)
+	}
+
+	if err := lock.Unlock(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if token := lock.FencingToken(); token != 0 {
+		t.Fatalf("bad: %d", token)
+	}
+	if op := lock.CheckFenceOp(); op != nil {
+		t.Fatalf("bad: %v", op)
+	}
+
+	// Another holder gets a newer token, and the old one is rejected
+	lock2, session2 := createTestLock(t, c, "test/lock")
+	defer session2.Destroy(lock2.opts.Session, nil)
+	if _, err := lock2.Lock(nil); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if token2 := lock2.FencingToken(); token2 <= token {
+		t.Fatalf("bad: %d <= %d", token2, token)
+	}
+	ok, resp := guardedSet(fence)
+	if ok || len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].What, "held by another session") {
+		t.Fatalf("bad: %v %v", ok, resp.Errors)
+	}
+	if ok, resp := guardedSet(lock2.CheckFenceOp()); !ok {
+		t.Fatalf("transaction failure: %v", resp.Errors)
+	}
+
+	// Deleting the lock key starts its lock index over, but the token of a
+	// new acquisition is still higher than any before it.
+	token2 := lock2.FencingToken()
+	if err := lock2.Unlock(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := lock2.Destroy(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	lock3, session3 := createTestLock(t, c, "test/lock")
+	defer session3.Destroy(lock3.opts.Session, nil)
+	if _, err := lock3.Lock(nil); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer lock3.Unlock()
+	pair, _, err = c.KV().Get("test/lock", nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if pair.LockIndex != 1 {
+		t.Fatalf("bad: %d", pair.LockIndex)
+	}
+	if token3 := lock3.FencingToken(); token3 <= token2 {
+		t.Fatalf("bad: %d <= %d", token3, token2)
+	}
+	if ok, resp := guardedSet(lock3.CheckFenceOp()); !ok {
+		t.Fatalf("transaction failure: %v", resp.Errors)
+	}
+}
+
 func TestAPI_LockForceInvalidate(t *testing.T) {
 	t.Parallel()
 	c, s := makeClientWithoutConnect(t)
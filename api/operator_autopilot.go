@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -30,8 +31,10 @@ type AutopilotConfiguration struct {
 	// applicable with Raft protocol version 3 or higher.
 	ServerStabilizationTime *ReadableDuration
 
-	// (Enterprise-only) RedundancyZoneTag is the node tag to use for separating
-	// servers into zones for redundancy. If left blank, this feature will be disabled.
+	// RedundancyZoneTag is the node meta key to use for separating servers
+	// into zones for redundancy. Only one server in each zone is a voter, and
+	// the rest are kept as non-voters that are promoted if that voter fails.
+	// If left blank, this feature will be disabled.
 	RedundancyZoneTag string
 
 	// (Enterprise-only) DisableUpgradeMigration will disable Autopilot's upgrade migration
@@ -92,6 +95,10 @@ type ServerHealth struct {
 
 	// StableSince is the last time this server's Healthy value changed.
 	StableSince time.Time
+
+	// RedundancyZone is the redundancy zone this server belongs to, if
+	// redundancy zones are enabled.
+	RedundancyZone string `json:",omitempty"`
 }
 
 // OperatorHealthReply is a representation of the overall health of the cluster
@@ -105,6 +112,29 @@ type OperatorHealthReply struct {
 
 	// Servers holds the health of each server.
 	Servers []ServerHealth
+
+	// RedundancyZones holds the health of each redundancy zone, if
+	// redundancy zones are enabled.
+	RedundancyZones []ZoneHealth `json:",omitempty"`
+}
+
+// ZoneHealth is the health of a redundancy zone.
+type ZoneHealth struct {
+	// Name is the name of the zone, taken from the configured node meta key.
+	Name string
+
+	// Servers holds the IDs of the servers in the zone.
+	Servers []string
+
+	// Voters holds the IDs of the voting servers in the zone.
+	Voters []string
+
+	// Healthy is true if the zone has a healthy voter.
+	Healthy bool
+
+	// FailureTolerance is the number of healthy non-voters in the zone that
+	// are available to replace its voter.
+	FailureTolerance int
 }
 
 // ReadableDuration is a duration type that is serialized to JSON in human readable format.
@@ -205,10 +235,18 @@ func (op *Operator) AutopilotCASConfiguration(conf *AutopilotConfiguration, q *W
 func (op *Operator) AutopilotServerHealth(q *QueryOptions) (*OperatorHealthReply, error) {
 	r := op.c.newRequest("GET", "/v1/operator/autopilot/health")
 	r.setQueryOptions(q)
-	_, resp, err := requireOK(op.c.doRequest(r))
+
+	// The endpoint uses a 429 status to indicate the cluster is unhealthy,
+	// but still returns the health details which are most useful then.
+	_, resp, err := op.c.doRequest(r)
 	if err != nil {
 		return nil, err
 	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		if _, resp, err = requireOK(0, resp, nil); err != nil {
+			return nil, err
+		}
+	}
 	defer resp.Body.Close()
 
 	var out OperatorHealthReply
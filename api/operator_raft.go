@@ -87,3 +87,28 @@ func (op *Operator) RaftRemovePeerByID(id string, q *WriteOptions) error {
 	resp.Body.Close()
 	return nil
 }
+
+// RaftTransferLeader is used to hand Raft leadership off to another voting
+// server. Raft will pick the most up-to-date voter as the new leader.
+func (op *Operator) RaftTransferLeader(q *WriteOptions) error {
+	return op.RaftTransferLeaderToID("", q)
+}
+
+// RaftTransferLeaderToID is used to hand Raft leadership off to the voting
+// server with the given ID.
+func (op *Operator) RaftTransferLeaderToID(id string, q *WriteOptions) error {
+	r := op.c.newRequest("POST", "/v1/operator/raft/transfer-leader")
+	r.setWriteOptions(q)
+
+	if id != "" {
+		r.params.Set("id", id)
+	}
+
+	_, resp, err := requireOK(op.c.doRequest(r))
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+	return nil
+}
@@ -11,6 +11,29 @@ type QueryDatacenterOptions struct {
 	// never try a datacenter multiple times, so those are subtracted from
 	// this list before proceeding.
 	Datacenters []string
+
+	// Targets is a list of failover targets to try in order after NearestN
+	// and Datacenters. Unlike Datacenters, a target can query a different
+	// service or set of tags in its datacenter.
+	Targets []QueryFailoverTarget `json:",omitempty"`
+
+	// MinHealthy is the number of healthy instances below which we fail
+	// over. If this is 0 we only fail over when there are no healthy
+	// instances at all.
+	MinHealthy int `json:",omitempty"`
+}
+
+// QueryFailoverTarget is a datacenter to fail over to, along with what to
+// query there.
+type QueryFailoverTarget struct {
+	// Datacenter is the datacenter to query.
+	Datacenter string
+
+	// Service, if set, replaces the service of the query.
+	Service string `json:",omitempty"`
+
+	// Tags, if set, replaces the tags of the query.
+	Tags []string `json:",omitempty"`
 }
 
 // QueryDNSOptions controls settings when query results are served over DNS.
@@ -131,6 +154,71 @@ type PreparedQueryExecuteResponse struct {
 	// Failovers is a count of how many times we had to query a remote
 	// datacenter.
 	Failovers int
+
+	// Explain has an explanation of how the query was executed, if it was
+	// requested with ExecuteExplain.
+	Explain *QueryExecuteExplain `json:",omitempty"`
+}
+
+// QueryExecuteExplain describes how a prepared query was executed.
+type QueryExecuteExplain struct {
+	// Query is the resolved query, with any template rendered and its token
+	// redacted.
+	Query PreparedQueryDefinition
+
+	// Near is the node the results were sorted near, if any.
+	Near string
+
+	// Datacenters has the datacenters the query ran in, in order, starting
+	// with the local one.
+	Datacenters []*QueryDatacenterExplain
+}
+
+// QueryDatacenterExplain describes the execution of a query in one
+// datacenter.
+type QueryDatacenterExplain struct {
+	// Datacenter is the name of the datacenter.
+	Datacenter string
+
+	// Reason is why the datacenter was queried. This is one of "local",
+	// "nearest", "datacenters" or "target".
+	Reason string
+
+	// RTT is the estimated median round trip time to the servers in a
+	// failover datacenter, if it's known.
+	RTT string
+
+	// Service and Tags are what was queried in the datacenter.
+	Service string
+	Tags    []string
+
+	// Error is the error if the query failed in the datacenter.
+	Error string
+
+	// Instances is the number of instances of the service before any
+	// filters were applied.
+	Instances int
+
+	// Filters has the number of instances each filter removed, in the order
+	// they were applied.
+	Filters []QueryFilterExplain
+
+	// IgnoredChecks is the number of instances the health filter kept only
+	// because of the IgnoreCheckIDs of the query.
+	IgnoredChecks int
+
+	// Results is the number of instances that were left.
+	Results int
+}
+
+// QueryFilterExplain is the number of instances removed by a filter.
+type QueryFilterExplain struct {
+	// Filter is the name of the filter. This is one of "health",
+	// "node-meta", "service-meta", "tags", "acl" or "limit".
+	Filter string
+
+	// Removed is the number of instances the filter removed.
+	Removed int
 }
 
 // PreparedQuery can be used to query the prepared query endpoints.
@@ -215,3 +303,27 @@ func (c *PreparedQuery) Execute(queryIDOrName string, q *QueryOptions) (*Prepare
 	}
 	return out, qm, nil
 }
+
+// ExecuteExplain is like Execute but also returns an explanation of how the
+// query was executed, such as which datacenters were tried and how many
+// instances each filter removed.
+func (c *PreparedQuery) ExecuteExplain(queryIDOrName string, q *QueryOptions) (*PreparedQueryExecuteResponse, *QueryMeta, error) {
+	r := c.c.newRequest("GET", "/v1/query/"+queryIDOrName+"/execute")
+	r.setQueryOptions(q)
+	r.params.Set("explain", "1")
+	rtt, resp, err := requireOK(c.c.doRequest(r))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out *PreparedQueryExecuteResponse
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return out, qm, nil
+}
@@ -118,6 +118,22 @@ func TestAPI_PreparedQuery(t *testing.T) {
 		t.Fatalf("bad datacenter: %v", results)
 	}
 
+	// Execute with an explanation.
+	results, _, err = query.ExecuteExplain("my-query", nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(results.Nodes) != 1 || results.Explain == nil {
+		t.Fatalf("bad: %v", results)
+	}
+	if results.Explain.Query.ID != def.ID || len(results.Explain.Datacenters) != 1 {
+		t.Fatalf("bad: %v", results.Explain)
+	}
+	if local := results.Explain.Datacenters[0]; local.Datacenter != "dc1" ||
+		local.Reason != "local" || local.Instances != 1 || local.Results != 1 {
+		t.Fatalf("bad: %v", local)
+	}
+
 	// Add new node with failing health check.
 	reg2 := reg
 	reg2.Node = "failingnode"
@@ -29,6 +29,10 @@ type SessionEntry struct {
 	LockDelay   time.Duration
 	Behavior    string
 	TTL         string
+
+	// HeldKeys is the number of keys the session holds locks on. This is a
+	// read-only field.
+	HeldKeys int
 }
 
 // Session can be used to query the Session endpoints
@@ -203,6 +207,16 @@ func (s *Session) Info(id string, q *QueryOptions) (*SessionEntry, *QueryMeta, e
 	return nil, qm, nil
 }
 
+// Keys gets the keys that are locked by a session
+func (s *Session) Keys(id string, q *QueryOptions) ([]string, *QueryMeta, error) {
+	var keys []string
+	qm, err := s.c.query("/v1/session/"+id+"/keys", &keys, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return keys, qm, nil
+}
+
 // List gets sessions for a node
 func (s *Session) Node(node string, q *QueryOptions) ([]*SessionEntry, *QueryMeta, error) {
 	var entries []*SessionEntry
@@ -402,6 +402,46 @@ func TestAPI_SessionNode(t *testing.T) {
 	}
 }
 
+func TestAPI_SessionKeys(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	s.WaitForSerfCheck(t)
+
+	session := c.Session()
+
+	id, _, err := session.Create(nil, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer session.Destroy(id, nil)
+
+	ok, _, err := c.KV().Acquire(&KVPair{Key: "test/lock", Session: id}, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !ok {
+		t.Fatalf("didn't get the lock")
+	}
+
+	keys, qm, err := session.Keys(id, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if len(keys) != 1 || keys[0] != "test/lock" {
+		t.Fatalf("bad: %v", keys)
+	}
+
+	if qm.LastIndex == 0 {
+		t.Fatalf("bad: %v", qm)
+	}
+	if !qm.KnownLeader {
+		t.Fatalf("bad: %v", qm)
+	}
+}
+
 func TestAPI_SessionList(t *testing.T) {
 	t.Parallel()
 	c, s := makeClient(t)
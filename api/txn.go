@@ -71,6 +71,7 @@ const (
 	KVCheckSession   KVOp = "check-session"
 	KVCheckIndex     KVOp = "check-index"
 	KVCheckNotExists KVOp = "check-not-exists"
+	KVCheckFence     KVOp = "check-fence"
 )
 
 // KVTxnOp defines a single operation inside a transaction.
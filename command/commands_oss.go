@@ -35,9 +35,11 @@ import (
 	"github.com/hashicorp/consul/command/forceleave"
 	"github.com/hashicorp/consul/command/info"
 	"github.com/hashicorp/consul/command/intention"
+	ixnapply "github.com/hashicorp/consul/command/intention/apply"
 	ixncheck "github.com/hashicorp/consul/command/intention/check"
 	ixncreate "github.com/hashicorp/consul/command/intention/create"
 	ixndelete "github.com/hashicorp/consul/command/intention/delete"
+	ixnexplain "github.com/hashicorp/consul/command/intention/explain"
 	ixnget "github.com/hashicorp/consul/command/intention/get"
 	ixnmatch "github.com/hashicorp/consul/command/intention/match"
 	"github.com/hashicorp/consul/command/join"
@@ -61,11 +63,27 @@ import (
 	operraft "github.com/hashicorp/consul/command/operator/raft"
 	operraftlist "github.com/hashicorp/consul/command/operator/raft/listpeers"
 	operraftremove "github.com/hashicorp/consul/command/operator/raft/removepeer"
+	operrafttransfer "github.com/hashicorp/consul/command/operator/raft/transferleader"
+	"github.com/hashicorp/consul/command/query"
+	querycreate "github.com/hashicorp/consul/command/query/create"
+	querydelete "github.com/hashicorp/consul/command/query/delete"
+	queryexecute "github.com/hashicorp/consul/command/query/execute"
+	queryexplain "github.com/hashicorp/consul/command/query/explain"
+	querylist "github.com/hashicorp/consul/command/query/list"
+	queryread "github.com/hashicorp/consul/command/query/read"
+	queryupdate "github.com/hashicorp/consul/command/query/update"
 	"github.com/hashicorp/consul/command/reload"
 	"github.com/hashicorp/consul/command/rtt"
 	"github.com/hashicorp/consul/command/services"
 	svcsderegister "github.com/hashicorp/consul/command/services/deregister"
 	svcsregister "github.com/hashicorp/consul/command/services/register"
+	svcsstatus "github.com/hashicorp/consul/command/services/status"
+	"github.com/hashicorp/consul/command/session"
+	sessioncreate "github.com/hashicorp/consul/command/session/create"
+	sessiondestroy "github.com/hashicorp/consul/command/session/destroy"
+	sessionlist "github.com/hashicorp/consul/command/session/list"
+	sessionread "github.com/hashicorp/consul/command/session/read"
+	sessionrenew "github.com/hashicorp/consul/command/session/renew"
 	"github.com/hashicorp/consul/command/snapshot"
 	snapinspect "github.com/hashicorp/consul/command/snapshot/inspect"
 	snaprestore "github.com/hashicorp/consul/command/snapshot/restore"
@@ -125,9 +143,11 @@ func init() {
 	Register("force-leave", func(ui cli.Ui) (cli.Command, error) { return forceleave.New(ui), nil })
 	Register("info", func(ui cli.Ui) (cli.Command, error) { return info.New(ui), nil })
 	Register("intention", func(ui cli.Ui) (cli.Command, error) { return intention.New(), nil })
+	Register("intention apply", func(ui cli.Ui) (cli.Command, error) { return ixnapply.New(ui), nil })
 	Register("intention check", func(ui cli.Ui) (cli.Command, error) { return ixncheck.New(ui), nil })
 	Register("intention create", func(ui cli.Ui) (cli.Command, error) { return ixncreate.New(ui), nil })
 	Register("intention delete", func(ui cli.Ui) (cli.Command, error) { return ixndelete.New(ui), nil })
+	Register("intention explain", func(ui cli.Ui) (cli.Command, error) { return ixnexplain.New(ui), nil })
 	Register("intention get", func(ui cli.Ui) (cli.Command, error) { return ixnget.New(ui), nil })
 	Register("intention match", func(ui cli.Ui) (cli.Command, error) { return ixnmatch.New(ui), nil })
 	Register("join", func(ui cli.Ui) (cli.Command, error) { return join.New(ui), nil })
@@ -151,11 +171,27 @@ func init() {
 	Register("operator raft", func(cli.Ui) (cli.Command, error) { return operraft.New(), nil })
 	Register("operator raft list-peers", func(ui cli.Ui) (cli.Command, error) { return operraftlist.New(ui), nil })
 	Register("operator raft remove-peer", func(ui cli.Ui) (cli.Command, error) { return operraftremove.New(ui), nil })
+	Register("operator raft transfer-leader", func(ui cli.Ui) (cli.Command, error) { return operrafttransfer.New(ui), nil })
+	Register("query", func(cli.Ui) (cli.Command, error) { return query.New(), nil })
+	Register("query create", func(ui cli.Ui) (cli.Command, error) { return querycreate.New(ui), nil })
+	Register("query delete", func(ui cli.Ui) (cli.Command, error) { return querydelete.New(ui), nil })
+	Register("query execute", func(ui cli.Ui) (cli.Command, error) { return queryexecute.New(ui), nil })
+	Register("query explain", func(ui cli.Ui) (cli.Command, error) { return queryexplain.New(ui), nil })
+	Register("query list", func(ui cli.Ui) (cli.Command, error) { return querylist.New(ui), nil })
+	Register("query read", func(ui cli.Ui) (cli.Command, error) { return queryread.New(ui), nil })
+	Register("query update", func(ui cli.Ui) (cli.Command, error) { return queryupdate.New(ui), nil })
 	Register("reload", func(ui cli.Ui) (cli.Command, error) { return reload.New(ui), nil })
 	Register("rtt", func(ui cli.Ui) (cli.Command, error) { return rtt.New(ui), nil })
 	Register("services", func(cli.Ui) (cli.Command, error) { return services.New(), nil })
 	Register("services register", func(ui cli.Ui) (cli.Command, error) { return svcsregister.New(ui), nil })
 	Register("services deregister", func(ui cli.Ui) (cli.Command, error) { return svcsderegister.New(ui), nil })
+	Register("services status", func(ui cli.Ui) (cli.Command, error) { return svcsstatus.New(ui), nil })
+	Register("session", func(cli.Ui) (cli.Command, error) { return session.New(), nil })
+	Register("session create", func(ui cli.Ui) (cli.Command, error) { return sessioncreate.New(ui), nil })
+	Register("session destroy", func(ui cli.Ui) (cli.Command, error) { return sessiondestroy.New(ui), nil })
+	Register("session list", func(ui cli.Ui) (cli.Command, error) { return sessionlist.New(ui), nil })
+	Register("session read", func(ui cli.Ui) (cli.Command, error) { return sessionread.New(ui), nil })
+	Register("session renew", func(ui cli.Ui) (cli.Command, error) { return sessionrenew.New(ui), nil })
 	Register("snapshot", func(cli.Ui) (cli.Command, error) { return snapshot.New(), nil })
 	Register("snapshot inspect", func(ui cli.Ui) (cli.Command, error) { return snapinspect.New(ui), nil })
 	Register("snapshot restore", func(ui cli.Ui) (cli.Command, error) { return snaprestore.New(ui), nil })
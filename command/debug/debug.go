@@ -9,8 +9,11 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -18,6 +21,7 @@ import (
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/flags"
 	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/serf/serf"
 	"github.com/mitchellh/cli"
 )
 
@@ -49,6 +53,14 @@ const (
 	// generated. If this format changes interface, this version
 	// can be incremented so clients can selectively support packages
 	debugProtocolVersion = 1
+
+	// goroutineStacksFile is the name of the text goroutine profile
+	// captured at each interval
+	goroutineStacksFile = "goroutine.txt"
+
+	// goroutineDiffFile is the name of the summary of goroutine stacks
+	// that grew over the capture
+	goroutineDiffFile = "goroutine_diff.txt"
 )
 
 func New(ui cli.Ui, shutdownCh <-chan struct{}) *cmd {
@@ -78,14 +90,39 @@ type cmd struct {
 	output   string
 	archive  bool
 	capture  []string
+	cluster  bool
 	client   *api.Client
 	// validateTiming can be used to skip validation of interval, duration. This
 	// is primarily useful for testing
 	validateTiming bool
 
+	// agents are the agents to capture information from. This is just the
+	// target agent, unless -cluster is given in which case it's every
+	// alive server in the target agent's datacenter.
+	agents []*debugAgent
+
 	index *debugIndex
 }
 
+// debugAgent is an agent that information is captured from, along with the
+// directory that information is written to.
+type debugAgent struct {
+	// name is the node name of the agent. This is only set when capturing
+	// from the whole cluster, and is used to tell the agents apart in
+	// output.
+	name   string
+	client *api.Client
+	dir    string
+}
+
+// wrapErr prefixes err with the agent's name, if it has one.
+func (a *debugAgent) wrapErr(err error) error {
+	if a.name == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %v", a.name, err)
+}
+
 // debugIndex is used to manage the summary of all data recorded
 // during the debug, to be written to json at the end of the run
 // and stored at the root. Each attribute corresponds to a file or files.
@@ -99,6 +136,10 @@ type debugIndex struct {
 	Duration string
 
 	Targets []string
+
+	// Agents holds the node names of the servers that were captured when
+	// running with -cluster. Each has its own directory of information.
+	Agents []string
 }
 
 func (c *cmd) init() {
@@ -123,6 +164,11 @@ func (c *cmd) init() {
 	c.flags.StringVar(&c.output, "output", defaultFilename, "The path "+
 		"to the compressed archive that will be created with the "+
 		"information after collection.")
+	c.flags.BoolVar(&c.cluster, "cluster", false, "Capture information from "+
+		"every alive server in the datacenter, rather than just the target "+
+		"agent. The servers are reached on the same HTTP port and scheme as "+
+		"the target agent, and each server's information is written to its "+
+		"own directory.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -179,18 +225,26 @@ func (c *cmd) Run(args []string) int {
 		Duration:     c.duration.String(),
 		Targets:      c.capture,
 	}
+	if c.cluster {
+		for _, a := range c.agents {
+			index.Agents = append(index.Agents, a.name)
+		}
+		c.UI.Info(fmt.Sprintf("       Servers: '%s'", strings.Join(index.Agents, ", ")))
+	}
 
 	// Add the extra grace period to ensure
 	// all intervals will be captured within the time allotted
 	c.duration = c.duration + debugDurationGrace
 
-	// Capture static information from the target agent
-	err = c.captureStatic()
-	if err != nil {
-		c.UI.Warn(fmt.Sprintf("Static capture failed: %v", err))
+	// Capture static information from the target agents
+	for _, a := range c.agents {
+		err = c.captureStatic(a)
+		if err != nil {
+			c.UI.Warn(fmt.Sprintf("Static capture failed: %v", a.wrapErr(err)))
+		}
 	}
 
-	// Capture dynamic information from the target agent, blocking for duration
+	// Capture dynamic information from the target agents, blocking for duration
 	if c.configuredTarget("metrics") || c.configuredTarget("logs") || c.configuredTarget("pprof") {
 		err = c.captureDynamic()
 		if err != nil {
@@ -198,6 +252,15 @@ func (c *cmd) Run(args []string) int {
 		}
 	}
 
+	// Summarize how the goroutines changed over the profiles we took
+	if c.configuredTarget("pprof") {
+		for _, a := range c.agents {
+			if err := c.captureGoroutineDiff(a); err != nil {
+				c.UI.Warn(fmt.Sprintf("Goroutine diff failed: %v", a.wrapErr(err)))
+			}
+		}
+	}
+
 	// Write the index document
 	idxMarshalled, err := json.MarshalIndent(index, "", "\t")
 	if err != nil {
@@ -282,6 +345,15 @@ func (c *cmd) prepare() (version string, err error) {
 		}
 	}
 
+	if c.cluster {
+		c.agents, err = c.clusterAgents()
+		if err != nil {
+			return version, err
+		}
+	} else {
+		c.agents = []*debugAgent{{client: c.client, dir: c.output}}
+	}
+
 	if _, err := os.Stat(c.output); os.IsNotExist(err) {
 		err := os.MkdirAll(c.output, 0755)
 		if err != nil {
@@ -291,12 +363,80 @@ func (c *cmd) prepare() (version string, err error) {
 		return version, fmt.Errorf("output directory already exists: %s", c.output)
 	}
 
+	for _, a := range c.agents {
+		if err := os.MkdirAll(a.dir, 0755); err != nil {
+			return version, fmt.Errorf("could not create output directory: %s", err)
+		}
+	}
+
 	return version, nil
 }
 
-// captureStatic captures static target information and writes it
-// to the output path
-func (c *cmd) captureStatic() error {
+// clusterAgents returns an agent to capture from for each alive server in the
+// target agent's datacenter. The servers' HTTP APIs are assumed to be on the
+// same port and scheme as the target agent's.
+func (c *cmd) clusterAgents() ([]*debugAgent, error) {
+	members, err := c.client.Agent().Members(false)
+	if err != nil {
+		return nil, fmt.Errorf("error listing cluster members: %s", err)
+	}
+
+	var agents []*debugAgent
+	for _, m := range members {
+		if m.Tags["role"] != "consul" || serf.MemberStatus(m.Status) != serf.StatusAlive {
+			continue
+		}
+
+		conf := api.DefaultConfig()
+		c.http.MergeOntoConfig(conf)
+		conf.Address, err = serverHTTPAddr(conf.Address, m.Addr)
+		if err != nil {
+			return nil, err
+		}
+		client, err := api.NewClient(conf)
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to server %s: %s", m.Name, err)
+		}
+
+		agents = append(agents, &debugAgent{
+			name:   m.Name,
+			client: client,
+			dir:    filepath.Join(c.output, m.Name),
+		})
+	}
+	if len(agents) == 0 {
+		return nil, fmt.Errorf("no alive servers found")
+	}
+
+	return agents, nil
+}
+
+// serverHTTPAddr swaps the host in the target agent's HTTP address for the
+// given server IP, keeping the scheme and port.
+func serverHTTPAddr(addr, ip string) (string, error) {
+	var scheme string
+	if parts := strings.SplitN(addr, "://", 2); len(parts) == 2 {
+		scheme, addr = parts[0], parts[1]
+	}
+	if scheme == "unix" {
+		return "", fmt.Errorf("-cluster can't be used when connecting over a unix socket")
+	}
+
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", fmt.Errorf("error parsing HTTP address %q: %s", addr, err)
+	}
+
+	addr = net.JoinHostPort(ip, port)
+	if scheme != "" {
+		addr = scheme + "://" + addr
+	}
+	return addr, nil
+}
+
+// captureStatic captures static target information from the given agent
+// and writes it to the agent's output path
+func (c *cmd) captureStatic(a *debugAgent) error {
 	// Collect errors via multierror as we want to gracefully
 	// fail if an API is inacessible
 	var errors error
@@ -306,7 +446,7 @@ func (c *cmd) captureStatic() error {
 
 	// Capture host information
 	if c.configuredTarget("host") {
-		host, err := c.client.Agent().Host()
+		host, err := a.client.Agent().Host()
 		if err != nil {
 			errors = multierror.Append(errors, err)
 		}
@@ -315,20 +455,71 @@ func (c *cmd) captureStatic() error {
 
 	// Capture agent information
 	if c.configuredTarget("agent") {
-		agent, err := c.client.Agent().Self()
+		agent, err := a.client.Agent().Self()
 		if err != nil {
 			errors = multierror.Append(errors, err)
 		}
 		outputs["agent"] = agent
 	}
 
-	// Capture cluster members information, including WAN
+	// Capture cluster members information, both LAN and WAN
 	if c.configuredTarget("cluster") {
-		members, err := c.client.Agent().Members(true)
+		members, err := a.client.Agent().Members(true)
 		if err != nil {
 			errors = multierror.Append(errors, err)
 		}
 		outputs["cluster"] = members
+
+		members, err = a.client.Agent().Members(false)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		}
+		outputs["members"] = members
+	}
+
+	// Capture the Raft configuration, along with the agent's Raft stats
+	// if it's a server
+	if c.configuredTarget("raft") {
+		raft := make(map[string]interface{})
+		conf, err := a.client.Operator().RaftGetConfiguration(nil)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		}
+		raft["Configuration"] = conf
+
+		self, err := a.client.Agent().Self()
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		}
+		raft["Stats"] = self["Stats"]["raft"]
+		outputs["raft"] = raft
+	}
+
+	// Capture the autopilot configuration and its view of server health
+	if c.configuredTarget("autopilot") {
+		autopilot := make(map[string]interface{})
+		conf, err := a.client.Operator().AutopilotGetConfiguration(nil)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		}
+		autopilot["Configuration"] = conf
+
+		health, err := a.client.Operator().AutopilotServerHealth(nil)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		}
+		autopilot["Health"] = health
+		outputs["autopilot"] = autopilot
+	}
+
+	// Capture the agent's local services and checks, and whether they
+	// match the catalog
+	if c.configuredTarget("services") {
+		services, err := captureServiceSync(a.client)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		}
+		outputs["services"] = services
 	}
 
 	// Write all outputs to disk as JSON
@@ -338,7 +529,7 @@ func (c *cmd) captureStatic() error {
 			errors = multierror.Append(errors, err)
 		}
 
-		err = ioutil.WriteFile(fmt.Sprintf("%s/%s.json", c.output, output), marshaled, 0644)
+		err = ioutil.WriteFile(fmt.Sprintf("%s/%s.json", a.dir, output), marshaled, 0644)
 		if err != nil {
 			errors = multierror.Append(errors, err)
 		}
@@ -347,9 +538,105 @@ func (c *cmd) captureStatic() error {
 	return errors
 }
 
+// serviceSync holds an agent's local services and checks alongside what the
+// catalog has registered for the agent's node, to help spot anti-entropy
+// problems such as a token that can't register a service.
+type serviceSync struct {
+	Node string
+
+	Services        map[string]*api.AgentService
+	Checks          map[string]*api.AgentCheck
+	CatalogServices map[string]*api.AgentService
+	CatalogChecks   map[string]*api.HealthCheck
+
+	// OutOfSyncServices and OutOfSyncChecks hold the IDs of the services
+	// and checks that are missing from the catalog, differ from the local
+	// definition, or are only in the catalog.
+	OutOfSyncServices []string
+	OutOfSyncChecks   []string
+}
+
+// captureServiceSync fetches an agent's local services and checks along with
+// the catalog's view of them.
+func captureServiceSync(client *api.Client) (*serviceSync, error) {
+	self, err := client.Agent().Self()
+	if err != nil {
+		return nil, err
+	}
+	node, ok := self["Config"]["NodeName"].(string)
+	if !ok {
+		return nil, fmt.Errorf("agent response did not contain node name")
+	}
+
+	out := &serviceSync{
+		Node:            node,
+		CatalogServices: make(map[string]*api.AgentService),
+		CatalogChecks:   make(map[string]*api.HealthCheck),
+	}
+	if out.Services, err = client.Agent().Services(); err != nil {
+		return nil, err
+	}
+	if out.Checks, err = client.Agent().Checks(); err != nil {
+		return nil, err
+	}
+
+	catalogNode, _, err := client.Catalog().Node(node, nil)
+	if err != nil {
+		return nil, err
+	}
+	if catalogNode != nil {
+		out.CatalogServices = catalogNode.Services
+	}
+	checks, _, err := client.Health().Node(node, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, check := range checks {
+		out.CatalogChecks[check.CheckID] = check
+	}
+
+	out.OutOfSyncServices, out.OutOfSyncChecks = out.outOfSync()
+	return out, nil
+}
+
+// outOfSync compares the local services and checks with the catalog and
+// returns the sorted IDs of those that don't match.
+func (s *serviceSync) outOfSync() (services []string, checks []string) {
+	for id, local := range s.Services {
+		remote, ok := s.CatalogServices[id]
+		if !ok || local.Service != remote.Service || local.Port != remote.Port ||
+			local.Address != remote.Address || !reflect.DeepEqual(local.Tags, remote.Tags) {
+			services = append(services, id)
+		}
+	}
+	for id := range s.CatalogServices {
+		// The consul service is registered by the servers themselves.
+		if _, ok := s.Services[id]; !ok && id != "consul" {
+			services = append(services, id)
+		}
+	}
+
+	for id, local := range s.Checks {
+		remote, ok := s.CatalogChecks[id]
+		if !ok || local.Status != remote.Status {
+			checks = append(checks, id)
+		}
+	}
+	for id := range s.CatalogChecks {
+		// The serf health check is managed by the servers.
+		if _, ok := s.Checks[id]; !ok && id != "serfHealth" {
+			checks = append(checks, id)
+		}
+	}
+
+	sort.Strings(services)
+	sort.Strings(checks)
+	return services, checks
+}
+
 // captureDynamic blocks for the duration of the command
 // specified by the duration flag, capturing the dynamic
-// targets at the interval specified
+// targets from each agent at the interval specified
 func (c *cmd) captureDynamic() error {
 	successChan := make(chan int64)
 	errCh := make(chan error)
@@ -358,189 +645,244 @@ func (c *cmd) captureDynamic() error {
 
 	c.UI.Output(fmt.Sprintf("Beginning capture interval %s (%d)", time.Now().Local().String(), intervalCount))
 
-	// We'll wait for all of the targets configured to be
-	// captured before continuing
-	var wg sync.WaitGroup
-
 	capture := func() {
 		timestamp := time.Now().Local().Unix()
 
-		// Make the directory that will store all captured data
-		// for this interval
-		timestampDir := fmt.Sprintf("%s/%d", c.output, timestamp)
-		err := os.MkdirAll(timestampDir, 0755)
-		if err != nil {
-			errCh <- err
+		// We'll wait for all of the targets configured to be
+		// captured from every agent before continuing
+		var wg sync.WaitGroup
+		for _, a := range c.agents {
+			c.captureInterval(a, timestamp, &wg, errCh)
 		}
+		wg.Wait()
 
-		// Capture metrics
-		if c.configuredTarget("metrics") {
-			wg.Add(1)
-
-			go func() {
-				metrics, err := c.client.Agent().Metrics()
-				if err != nil {
-					errCh <- err
-				}
-
-				marshaled, err := json.MarshalIndent(metrics, "", "\t")
-				if err != nil {
-					errCh <- err
-				}
-
-				err = ioutil.WriteFile(fmt.Sprintf("%s/%s.json", timestampDir, "metrics"), marshaled, 0644)
-				if err != nil {
-					errCh <- err
-				}
+		// Send down the timestamp for UI output
+		successChan <- timestamp
+	}
 
-				// We need to sleep for the configured interval in the case
-				// of metrics being the only target captured. When it is,
-				// the waitgroup would return on Wait() and repeat without
-				// waiting for the interval.
-				time.Sleep(c.interval)
+	go capture()
 
-				wg.Done()
-			}()
+	for {
+		select {
+		case t := <-successChan:
+			intervalCount++
+			c.UI.Output(fmt.Sprintf("Capture successful %s (%d)", time.Unix(t, 0).Local().String(), intervalCount))
+			go capture()
+		case e := <-errCh:
+			c.UI.Error(fmt.Sprintf("Capture failure %s", e))
+		case <-durationChn:
+			return nil
+		case <-c.shutdownCh:
+			return errors.New("stopping collection due to shutdown signal")
 		}
+	}
+}
 
-		// Capture pprof
-		if c.configuredTarget("pprof") {
-			wg.Add(1)
+// captureInterval starts capturing the dynamic targets from the given agent
+// for a single interval, adding to wg for each target being captured.
+func (c *cmd) captureInterval(a *debugAgent, timestamp int64, wg *sync.WaitGroup, errCh chan<- error) {
+	fail := func(err error) {
+		errCh <- a.wrapErr(err)
+	}
 
-			go func() {
-				// We need to capture profiles and traces at the same time
-				// and block for both of them
-				var wgProf sync.WaitGroup
+	// Make the directory that will store all captured data
+	// for this interval
+	timestampDir := fmt.Sprintf("%s/%d", a.dir, timestamp)
+	err := os.MkdirAll(timestampDir, 0755)
+	if err != nil {
+		fail(err)
+	}
 
-				heap, err := c.client.Debug().Heap()
-				if err != nil {
-					errCh <- err
-				}
+	// Capture metrics
+	if c.configuredTarget("metrics") {
+		wg.Add(1)
 
-				err = ioutil.WriteFile(fmt.Sprintf("%s/heap.prof", timestampDir), heap, 0644)
-				if err != nil {
-					errCh <- err
-				}
+		go func() {
+			metrics, err := a.client.Agent().Metrics()
+			if err != nil {
+				fail(err)
+			}
 
-				// Capture a profile/trace with a minimum of 1s
-				s := c.interval.Seconds()
-				if s < 1 {
-					s = 1
-				}
+			marshaled, err := json.MarshalIndent(metrics, "", "\t")
+			if err != nil {
+				fail(err)
+			}
 
-				go func() {
-					wgProf.Add(1)
+			err = ioutil.WriteFile(fmt.Sprintf("%s/%s.json", timestampDir, "metrics"), marshaled, 0644)
+			if err != nil {
+				fail(err)
+			}
 
-					prof, err := c.client.Debug().Profile(int(s))
-					if err != nil {
-						errCh <- err
-					}
+			// We need to sleep for the configured interval in the case
+			// of metrics being the only target captured. When it is,
+			// the waitgroup would return on Wait() and repeat without
+			// waiting for the interval.
+			time.Sleep(c.interval)
 
-					err = ioutil.WriteFile(fmt.Sprintf("%s/profile.prof", timestampDir), prof, 0644)
-					if err != nil {
-						errCh <- err
-					}
+			wg.Done()
+		}()
+	}
 
-					wgProf.Done()
-				}()
+	// Capture pprof
+	if c.configuredTarget("pprof") {
+		wg.Add(1)
 
-				go func() {
-					wgProf.Add(1)
+		go func() {
+			// We need to capture profiles and traces at the same time
+			// and block for both of them
+			var wgProf sync.WaitGroup
 
-					trace, err := c.client.Debug().Trace(int(s))
-					if err != nil {
-						errCh <- err
-					}
+			heap, err := a.client.Debug().Heap()
+			if err != nil {
+				fail(err)
+			}
 
-					err = ioutil.WriteFile(fmt.Sprintf("%s/trace.out", timestampDir), trace, 0644)
-					if err != nil {
-						errCh <- err
-					}
+			err = ioutil.WriteFile(fmt.Sprintf("%s/heap.prof", timestampDir), heap, 0644)
+			if err != nil {
+				fail(err)
+			}
 
-					wgProf.Done()
-				}()
+			// Capture a profile/trace with a minimum of 1s
+			s := c.interval.Seconds()
+			if s < 1 {
+				s = 1
+			}
 
-				gr, err := c.client.Debug().Goroutine()
+			wgProf.Add(2)
+			go func() {
+				prof, err := a.client.Debug().Profile(int(s))
 				if err != nil {
-					errCh <- err
+					fail(err)
 				}
 
-				err = ioutil.WriteFile(fmt.Sprintf("%s/goroutine.prof", timestampDir), gr, 0644)
+				err = ioutil.WriteFile(fmt.Sprintf("%s/profile.prof", timestampDir), prof, 0644)
 				if err != nil {
-					errCh <- err
+					fail(err)
 				}
 
-				wgProf.Wait()
-
-				wg.Done()
+				wgProf.Done()
 			}()
-		}
-
-		// Capture logs
-		if c.configuredTarget("logs") {
-			wg.Add(1)
 
 			go func() {
-				endLogChn := make(chan struct{})
-				logCh, err := c.client.Agent().Monitor("DEBUG", endLogChn, nil)
+				trace, err := a.client.Debug().Trace(int(s))
 				if err != nil {
-					errCh <- err
+					fail(err)
 				}
-				// Close the log stream
-				defer close(endLogChn)
 
-				// Create the log file for writing
-				f, err := os.Create(fmt.Sprintf("%s/%s", timestampDir, "consul.log"))
+				err = ioutil.WriteFile(fmt.Sprintf("%s/trace.out", timestampDir), trace, 0644)
 				if err != nil {
-					errCh <- err
-				}
-				defer f.Close()
-
-				intervalChn := time.After(c.interval)
-
-			OUTER:
-
-				for {
-					select {
-					case log := <-logCh:
-						// Append the line to the file
-						if _, err = f.WriteString(log + "\n"); err != nil {
-							errCh <- err
-							break OUTER
-						}
-					// Stop collecting the logs after the interval specified
-					case <-intervalChn:
-						break OUTER
-					}
+					fail(err)
 				}
 
-				wg.Done()
+				wgProf.Done()
 			}()
-		}
 
-		// Wait for all captures to complete
-		wg.Wait()
+			gr, err := a.client.Debug().Goroutine()
+			if err != nil {
+				fail(err)
+			}
 
-		// Send down the timestamp for UI output
-		successChan <- timestamp
+			err = ioutil.WriteFile(fmt.Sprintf("%s/goroutine.prof", timestampDir), gr, 0644)
+			if err != nil {
+				fail(err)
+			}
+
+			// Also grab the text form, which is used to summarize the
+			// stacks that grew over the whole capture
+			stacks, err := a.client.Debug().GoroutineStacks()
+			if err != nil {
+				fail(err)
+			}
+
+			err = ioutil.WriteFile(fmt.Sprintf("%s/%s", timestampDir, goroutineStacksFile), stacks, 0644)
+			if err != nil {
+				fail(err)
+			}
+
+			wgProf.Wait()
+
+			wg.Done()
+		}()
 	}
 
-	go capture()
+	// Capture logs
+	if c.configuredTarget("logs") {
+		wg.Add(1)
 
-	for {
-		select {
-		case t := <-successChan:
-			intervalCount++
-			c.UI.Output(fmt.Sprintf("Capture successful %s (%d)", time.Unix(t, 0).Local().String(), intervalCount))
-			go capture()
-		case e := <-errCh:
-			c.UI.Error(fmt.Sprintf("Capture failure %s", e))
-		case <-durationChn:
-			return nil
-		case <-c.shutdownCh:
-			return errors.New("stopping collection due to shutdown signal")
+		go func() {
+			endLogChn := make(chan struct{})
+			logCh, err := a.client.Agent().Monitor("DEBUG", endLogChn, nil)
+			if err != nil {
+				fail(err)
+			}
+			// Close the log stream
+			defer close(endLogChn)
+
+			// Create the log file for writing
+			f, err := os.Create(fmt.Sprintf("%s/%s", timestampDir, "consul.log"))
+			if err != nil {
+				fail(err)
+			}
+			defer f.Close()
+
+			intervalChn := time.After(c.interval)
+
+		OUTER:
+
+			for {
+				select {
+				case log := <-logCh:
+					// Append the line to the file
+					if _, err = f.WriteString(log + "\n"); err != nil {
+						fail(err)
+						break OUTER
+					}
+				// Stop collecting the logs after the interval specified
+				case <-intervalChn:
+					break OUTER
+				}
+			}
+
+			wg.Done()
+		}()
+	}
+}
+
+// captureGoroutineDiff reads the goroutine stacks captured from the given
+// agent at each interval, and writes a summary of the stacks that grew
+// between the first and last intervals. Nothing is written unless there were
+// at least two intervals.
+func (c *cmd) captureGoroutineDiff(a *debugAgent) error {
+	// The interval directories are named by timestamp, so the glob is
+	// already in the order they were captured
+	files, err := filepath.Glob(filepath.Join(a.dir, "*", goroutineStacksFile))
+	if err != nil {
+		return err
+	}
+	if len(files) < 2 {
+		return nil
+	}
+
+	var profiles []goroutineProfile
+	for _, file := range files {
+		raw, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
 		}
+		profile, err := parseGoroutineStacks(raw)
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %s", file, err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	f, err := os.Create(filepath.Join(a.dir, goroutineDiffFile))
+	if err != nil {
+		return err
 	}
+	defer f.Close()
+
+	return writeGoroutineDiff(f, profiles, diffGoroutines(profiles))
 }
 
 // allowedTarget returns a boolean if the target is able to be captured
@@ -698,7 +1040,7 @@ func (c *cmd) dynamicTargets() []string {
 // staticTargets returns all the supported targets
 // that are retrieved at the start of the command execution
 func (c *cmd) staticTargets() []string {
-	return []string{"host", "agent", "cluster"}
+	return []string{"host", "agent", "cluster", "raft", "autopilot", "services"}
 }
 
 func (c *cmd) Synopsis() string {
@@ -741,6 +1083,16 @@ Usage: consul debug [options]
 
       $ consul debug -capture metrics -capture agent
 
+  To capture the same information from every server in the datacenter,
+  use the cluster flag. Each server's information is stored in its own
+  directory within the archive.
+
+      $ consul debug -cluster
+
+  When profiles are captured over several intervals, a summary of the
+  goroutine stacks that grew over the capture is also recorded, which is
+  useful for spotting goroutine leaks.
+
   By default, the archive containing the debugging information is
   saved to the current directory as a .tar.gz file. The
   output path can be specified, as well as an option to disable
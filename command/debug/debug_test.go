@@ -3,14 +3,17 @@ package debug
 import (
 	"archive/tar"
 	"compress/gzip"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/logger"
 	"github.com/hashicorp/consul/testrpc"
 	"github.com/hashicorp/consul/sdk/testutil"
@@ -236,8 +239,16 @@ func TestDebugCommand_CaptureTargets(t *testing.T) {
 			[]string{"host.json", "cluster.json"},
 		},
 		"static": {
-			[]string{"agent", "host", "cluster"},
-			[]string{"agent.json", "host.json", "cluster.json"},
+			[]string{"agent", "host", "cluster", "raft", "autopilot", "services"},
+			[]string{
+				"agent.json",
+				"host.json",
+				"cluster.json",
+				"members.json",
+				"raft.json",
+				"autopilot.json",
+				"services.json",
+			},
 			[]string{"*/metrics.json"},
 		},
 		"metrics-only": {
@@ -485,3 +496,165 @@ func TestDebugCommand_DebugDisabled(t *testing.T) {
 		t.Errorf("expected warn output, got %s", errOutput)
 	}
 }
+
+func TestDebugCommand_GoroutineDiff(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutil.TempDir(t, "debug")
+	defer os.RemoveAll(testDir)
+
+	a := agent.NewTestAgent(t, t.Name(), `
+	enable_debug = true
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	ui := cli.NewMockUi()
+	cmd := New(ui, nil)
+	cmd.validateTiming = false
+
+	outputPath := fmt.Sprintf("%s/debug", testDir)
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-output=" + outputPath,
+		"-archive=false",
+		// CPU profile has a minimum of 1s, so this gives two intervals
+		"-duration=2s",
+		"-interval=1s",
+		"-capture=pprof",
+	}
+
+	if code := cmd.Run(args); code != 0 {
+		t.Fatalf("should exit 0, got code: %d", code)
+	}
+
+	fs, _ := filepath.Glob(fmt.Sprintf("%s/*/%s", outputPath, goroutineStacksFile))
+	if len(fs) < 2 {
+		t.Fatalf("expected at least two goroutine profiles, got %v", fs)
+	}
+
+	diff, err := ioutil.ReadFile(filepath.Join(outputPath, goroutineDiffFile))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !strings.HasPrefix(string(diff), "Goroutine totals over") {
+		t.Fatalf("bad: %s", diff)
+	}
+}
+
+func TestDebugCommand_Cluster(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutil.TempDir(t, "debug")
+	defer os.RemoveAll(testDir)
+
+	a := agent.NewTestAgent(t, t.Name(), `
+	enable_debug = true
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	ui := cli.NewMockUi()
+	cmd := New(ui, nil)
+	cmd.validateTiming = false
+
+	outputPath := fmt.Sprintf("%s/debug", testDir)
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-output=" + outputPath,
+		"-archive=false",
+		"-cluster",
+		"-capture=agent",
+		"-capture=raft",
+	}
+
+	if code := cmd.Run(args); code != 0 {
+		t.Fatalf("should exit 0, got code: %d, %s", code, ui.ErrorWriter.String())
+	}
+
+	// The test agent is the only server, so it gets its own directory.
+	for _, f := range []string{"agent.json", "raft.json"} {
+		path := filepath.Join(outputPath, a.Config.NodeName, f)
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("output data should exist for %s: %s", path, err)
+		}
+	}
+
+	raw, err := ioutil.ReadFile(filepath.Join(outputPath, "index.json"))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	var index debugIndex
+	if err := json.Unmarshal(raw, &index); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(index.Agents) != 1 || index.Agents[0] != a.Config.NodeName {
+		t.Fatalf("bad: %v", index.Agents)
+	}
+}
+
+func TestServerHTTPAddr(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		addr     string
+		expected string
+		err      string
+	}{
+		"plain":  {"127.0.0.1:8500", "10.0.0.1:8500", ""},
+		"scheme": {"https://localhost:8501", "https://10.0.0.1:8501", ""},
+		"unix":   {"unix:///tmp/consul.sock", "", "unix socket"},
+		"bad":    {"localhost", "", "error parsing"},
+	}
+
+	for name, tc := range cases {
+		addr, err := serverHTTPAddr(tc.addr, "10.0.0.1")
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("%s: bad err: %v", name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: err: %v", name, err)
+		}
+		if addr != tc.expected {
+			t.Fatalf("%s: expected %q, got %q", name, tc.expected, addr)
+		}
+	}
+}
+
+func TestServiceSync_OutOfSync(t *testing.T) {
+	t.Parallel()
+
+	s := &serviceSync{
+		Services: map[string]*api.AgentService{
+			"web":   {ID: "web", Service: "web", Port: 80},
+			"db":    {ID: "db", Service: "db", Port: 5432},
+			"cache": {ID: "cache", Service: "cache", Port: 6379},
+		},
+		Checks: map[string]*api.AgentCheck{
+			"web-check": {CheckID: "web-check", Status: api.HealthPassing},
+			"db-check":  {CheckID: "db-check", Status: api.HealthPassing},
+		},
+		CatalogServices: map[string]*api.AgentService{
+			"consul": {ID: "consul", Service: "consul", Port: 8300},
+			"web":    {ID: "web", Service: "web", Port: 80},
+			"db":     {ID: "db", Service: "db", Port: 5433},
+			"old":    {ID: "old", Service: "old", Port: 8080},
+		},
+		CatalogChecks: map[string]*api.HealthCheck{
+			"serfHealth": {CheckID: "serfHealth", Status: api.HealthPassing},
+			"web-check":  {CheckID: "web-check", Status: api.HealthPassing},
+			"db-check":   {CheckID: "db-check", Status: api.HealthCritical},
+		},
+	}
+
+	services, checks := s.outOfSync()
+	if got, want := strings.Join(services, ","), "cache,db,old"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got, want := strings.Join(checks, ","), "db-check"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,164 @@
+package debug
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// goroutineProfile maps each unique goroutine stack to the number of
+// goroutines that had that stack when the profile was taken.
+type goroutineProfile map[string]int
+
+// parseGoroutineStacks parses a goroutine profile in the text format served
+// by /debug/pprof/goroutine?debug=1. Each stack is keyed by its functions and
+// source lines, without addresses or offsets, so the same stack can be matched
+// across profiles.
+func parseGoroutineStacks(raw []byte) (goroutineProfile, error) {
+	profile := make(goroutineProfile)
+
+	var count int
+	var frames []string
+	flush := func() {
+		if count > 0 && len(frames) > 0 {
+			profile[strings.Join(frames, "\n")] += count
+		}
+		count = 0
+		frames = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			flush()
+
+		case strings.HasPrefix(line, "goroutine profile:"):
+			continue
+
+		case strings.HasPrefix(line, "#"):
+			// Frames look like "#\t0x43d5c5\truntime.gopark+0x105\t/path/proc.go:302"
+			// and there may also be "# labels: {...}" lines we don't need.
+			fields := strings.Split(line, "\t")
+			if len(fields) != 4 {
+				continue
+			}
+			fn := fields[2]
+			if i := strings.LastIndex(fn, "+0x"); i != -1 {
+				fn = fn[:i]
+			}
+			frames = append(frames, fn+"\n\t"+strings.TrimSpace(fields[3]))
+
+		default:
+			// A new stack starts with "<count> @ <addresses>".
+			flush()
+			parts := strings.SplitN(line, " @ ", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("unexpected line in goroutine profile: %q", line)
+			}
+			n, err := strconv.Atoi(parts[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid count in goroutine profile: %q", line)
+			}
+			count = n
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	return profile, nil
+}
+
+// goroutineGrowth is a stack whose goroutine count grew over a series of
+// profiles.
+type goroutineGrowth struct {
+	// Stack is the stack, as keyed in a goroutineProfile.
+	Stack string
+
+	// Counts is the number of goroutines with this stack in each profile.
+	Counts []int
+}
+
+// Delta is the change in count from the first profile to the last.
+func (g *goroutineGrowth) Delta() int {
+	return g.Counts[len(g.Counts)-1] - g.Counts[0]
+}
+
+// diffGoroutines returns the stacks that have more goroutines in the last
+// profile than in the first, with the biggest growth first.
+func diffGoroutines(profiles []goroutineProfile) []goroutineGrowth {
+	if len(profiles) < 2 {
+		return nil
+	}
+
+	stacks := make(map[string]struct{})
+	for _, profile := range profiles {
+		for stack := range profile {
+			stacks[stack] = struct{}{}
+		}
+	}
+
+	var growth []goroutineGrowth
+	for stack := range stacks {
+		g := goroutineGrowth{Stack: stack}
+		for _, profile := range profiles {
+			g.Counts = append(g.Counts, profile[stack])
+		}
+		if g.Delta() > 0 {
+			growth = append(growth, g)
+		}
+	}
+
+	sort.Slice(growth, func(i, j int) bool {
+		if growth[i].Delta() != growth[j].Delta() {
+			return growth[i].Delta() > growth[j].Delta()
+		}
+		return growth[i].Stack < growth[j].Stack
+	})
+	return growth
+}
+
+// writeGoroutineDiff writes a human readable summary of the growing stacks.
+func writeGoroutineDiff(w io.Writer, profiles []goroutineProfile, growth []goroutineGrowth) error {
+	var totals []string
+	for _, profile := range profiles {
+		total := 0
+		for _, n := range profile {
+			total += n
+		}
+		totals = append(totals, strconv.Itoa(total))
+	}
+
+	if _, err := fmt.Fprintf(w, "Goroutine totals over %d profiles: %s\n",
+		len(profiles), strings.Join(totals, ", ")); err != nil {
+		return err
+	}
+	if len(growth) == 0 {
+		_, err := fmt.Fprintln(w, "\nNo goroutine stacks grew.")
+		return err
+	}
+
+	for _, g := range growth {
+		var counts []string
+		for _, n := range g.Counts {
+			counts = append(counts, strconv.Itoa(n))
+		}
+		if _, err := fmt.Fprintf(w, "\n+%d goroutines (%s)\n", g.Delta(), strings.Join(counts, ", ")); err != nil {
+			return err
+		}
+		for _, line := range strings.Split(g.Stack, "\n") {
+			if _, err := fmt.Fprintf(w, "    %s\n", line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package debug
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testGoroutineStacks = `goroutine profile: total 5
+3 @ 0x42f1e5 0x42a2d9 0x429856
+#	0x429855	internal/poll.runtime_pollWait+0x65	/usr/local/go/src/runtime/netpoll.go:173
+#	0x4a3c1b	net/http.(*persistConn).readLoop+0x185	/usr/local/go/src/net/http/transport.go:1717
+
+2 @ 0x42f1e5 0x43f0b0
+# labels: {"foo":"bar"}
+#	0x43f0af	time.Sleep+0x12f	/usr/local/go/src/runtime/time.go:105
+`
+
+func TestParseGoroutineStacks(t *testing.T) {
+	t.Parallel()
+
+	profile, err := parseGoroutineStacks([]byte(testGoroutineStacks))
+	require.NoError(t, err)
+
+	expected := goroutineProfile{
+		"internal/poll.runtime_pollWait\n\t/usr/local/go/src/runtime/netpoll.go:173\n" +
+			"net/http.(*persistConn).readLoop\n\t/usr/local/go/src/net/http/transport.go:1717": 3,
+		"time.Sleep\n\t/usr/local/go/src/runtime/time.go:105": 2,
+	}
+	require.Equal(t, expected, profile)
+
+	_, err = parseGoroutineStacks([]byte("nope\n"))
+	require.Error(t, err)
+}
+
+func TestDiffGoroutines(t *testing.T) {
+	t.Parallel()
+
+	profiles := []goroutineProfile{
+		{"a": 1, "b": 5, "c": 2},
+		{"a": 3, "b": 4, "c": 2},
+		{"a": 10, "b": 1, "c": 3, "d": 2},
+	}
+	growth := diffGoroutines(profiles)
+	require.Equal(t, []goroutineGrowth{
+		{Stack: "a", Counts: []int{1, 3, 10}},
+		{Stack: "d", Counts: []int{0, 0, 2}},
+		{Stack: "c", Counts: []int{2, 2, 3}},
+	}, growth)
+
+	// A single profile has nothing to compare against.
+	require.Nil(t, diffGoroutines(profiles[:1]))
+
+	var buf bytes.Buffer
+	require.NoError(t, writeGoroutineDiff(&buf, profiles, growth))
+	out := buf.String()
+	require.True(t, strings.HasPrefix(out, "Goroutine totals over 3 profiles: 8, 9, 16\n"), out)
+	require.Contains(t, out, "+9 goroutines (1, 3, 10)\n    a\n")
+}
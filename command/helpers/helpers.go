@@ -6,6 +6,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"reflect"
 )
 
 func LoadDataSource(data string, testStdin io.Reader) (string, error) {
@@ -40,3 +41,24 @@ func LoadDataSource(data string, testStdin io.Reader) (string, error) {
 		return data, nil
 	}
 }
+
+// SliceOfMapsHook is a mapstructure decode hook that merges the lists of
+// objects that HCL produces for nested blocks into a single object when the
+// target is a struct or a map.
+func SliceOfMapsHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if to.Kind() != reflect.Struct && to.Kind() != reflect.Map {
+		return data, nil
+	}
+	maps, ok := data.([]map[string]interface{})
+	if !ok {
+		return data, nil
+	}
+
+	merged := make(map[string]interface{})
+	for _, m := range maps {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+	return merged, nil
+}
@@ -0,0 +1,274 @@
+package apply
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/helpers"
+	"github.com/hashicorp/hcl"
+	"github.com/mitchellh/cli"
+	"github.com/mitchellh/mapstructure"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	flagFile   string
+	flagPrune  bool
+	flagDryRun bool
+
+	// testStdin is the input for testing.
+	testStdin io.Reader
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.flagFile, "f", "",
+		"Path to the file with the desired intentions in HCL or JSON format. "+
+			"Use \"-\" to read from stdin.")
+	c.flags.BoolVar(&c.flagPrune, "prune", false,
+		"Delete the intentions that are not in the file.")
+	c.flags.BoolVar(&c.flagDryRun, "dry-run", false,
+		"Only show the changes without applying them.")
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(c.flags.Args()) != 0 {
+		c.UI.Error("Error: command takes no arguments")
+		return 1
+	}
+	if c.flagFile == "" {
+		c.UI.Error("Error: the -f flag is required")
+		return 1
+	}
+
+	ixns, err := c.ixnsFromFile(c.flagFile)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading %s: %s", c.flagFile, err))
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	// Plan the changes first so they can be shown before they are applied.
+	plan, _, err := client.Connect().IntentionApply(ixns, &api.IntentionApplyOptions{
+		Prune:  c.flagPrune,
+		DryRun: true,
+	}, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error planning intention changes: %s", err))
+		return 1
+	}
+	c.outputPlan(plan)
+
+	if len(plan.Changes) == 0 || c.flagDryRun {
+		return 0
+	}
+
+	// Apply the changes only if the intentions haven't changed since the
+	// plan so the changes are exactly those shown.
+	result, _, err := client.Connect().IntentionApply(ixns, &api.IntentionApplyOptions{
+		Prune: c.flagPrune,
+		CAS:   plan.Index,
+	}, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error applying intention changes: %s", err))
+		return 1
+	}
+
+	c.UI.Output("")
+	c.UI.Output(fmt.Sprintf("Applied %d changes.", len(result.Changes)))
+	return 0
+}
+
+// outputPlan shows the changes of a plan and the unmanaged intentions.
+func (c *cmd) outputPlan(plan *api.IntentionPlan) {
+	if len(plan.Changes) == 0 {
+		c.UI.Output("No changes. The intentions match the file.")
+	} else {
+		c.UI.Output("Changes:")
+		for _, change := range plan.Changes {
+			ixn := change.Intention
+			switch change.Op {
+			case api.IntentionChangeCreate:
+				c.UI.Output(fmt.Sprintf("  + create %s", ixn))
+			case api.IntentionChangeUpdate:
+				c.UI.Output(fmt.Sprintf("  ~ update %s", ixn))
+				for _, diff := range diffIntentions(change.Previous, ixn) {
+					c.UI.Output("      " + diff)
+				}
+			case api.IntentionChangeDelete:
+				c.UI.Output(fmt.Sprintf("  - delete %s", ixn))
+			}
+		}
+	}
+
+	if len(plan.Unmanaged) > 0 {
+		c.UI.Output("")
+		c.UI.Output("Unmanaged intentions, use -prune to delete them:")
+		for _, ixn := range plan.Unmanaged {
+			c.UI.Output(fmt.Sprintf("    %s", ixn))
+		}
+	}
+}
+
+// diffIntentions describes the changes of the user-defined fields between
+// two versions of an intention.
+func diffIntentions(old, new *api.Intention) []string {
+	var result []string
+	if old == nil {
+		return result
+	}
+	if old.SourceType != new.SourceType {
+		result = append(result, fmt.Sprintf("SourceType: %q => %q", old.SourceType, new.SourceType))
+	}
+	if old.Action != new.Action {
+		result = append(result, fmt.Sprintf("Action: %q => %q", old.Action, new.Action))
+	}
+	if old.Description != new.Description {
+		result = append(result, fmt.Sprintf("Description: %q => %q", old.Description, new.Description))
+	}
+	if (len(old.Meta) > 0 || len(new.Meta) > 0) && !reflect.DeepEqual(old.Meta, new.Meta) {
+		result = append(result, fmt.Sprintf("Meta: %v => %v", old.Meta, new.Meta))
+	}
+	if (len(old.Permissions) > 0 || len(new.Permissions) > 0) && !reflect.DeepEqual(old.Permissions, new.Permissions) {
+		result = append(result, fmt.Sprintf("Permissions: %d => %d entries", len(old.Permissions), len(new.Permissions)))
+	}
+	if old.DefaultAddr != new.DefaultAddr || old.DefaultPort != new.DefaultPort {
+		result = append(result, fmt.Sprintf("Default: %s:%d => %s:%d",
+			old.DefaultAddr, old.DefaultPort, new.DefaultAddr, new.DefaultPort))
+	}
+	return result
+}
+
+// ixnsFromFile reads the desired intentions from a file or stdin.
+func (c *cmd) ixnsFromFile(path string) ([]*api.Intention, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		var stdin io.Reader = os.Stdin
+		if c.testStdin != nil {
+			stdin = c.testStdin
+		}
+		var b bytes.Buffer
+		_, err = io.Copy(&b, stdin)
+		data = b.Bytes()
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return parseIntentions(string(data))
+}
+
+// intentionsFile is the format of a file with the desired intentions.
+type intentionsFile struct {
+	Intentions []*api.Intention
+}
+
+// parseIntentions parses the desired intentions in HCL or JSON format. The
+// intentions use the same fields as the HTTP API.
+func parseIntentions(data string) ([]*api.Intention, error) {
+	var raw map[string]interface{}
+	if err := hcl.Decode(&raw, data); err != nil {
+		return nil, err
+	}
+
+	var result intentionsFile
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:  helpers.SliceOfMapsHook,
+		ErrorUnused: true,
+		Result:      &result,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(raw); err != nil {
+		return nil, err
+	}
+
+	for i, ixn := range result.Intentions {
+		if ixn == nil {
+			return nil, fmt.Errorf("intention %d is empty", i)
+		}
+		if ixn.ID != "" {
+			return nil, fmt.Errorf("intention %s must not have an ID", ixn)
+		}
+		if ixn.SourceType == "" {
+			ixn.SourceType = api.IntentionSourceConsul
+		}
+	}
+	return result.Intentions, nil
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Apply a declarative set of intentions."
+const help = `
+Usage: consul intention apply [options] -f FILE
+
+  Make the intentions match the set of intentions in FILE. Intentions are
+  matched by source and destination, and all the needed creates, updates
+  and deletes are applied in a single transaction after the changes are
+  shown. Intentions that are not in FILE are left alone unless -prune is
+  set.
+
+  FILE is in HCL or JSON format and lists the intentions with the same
+  fields as the HTTP API:
+
+      Intentions = [
+        {
+          SourceName      = "web"
+          DestinationName = "db"
+          Action          = "allow"
+        },
+      ]
+
+  Show the changes without applying them:
+
+      $ consul intention apply -dry-run -f intentions.hcl
+
+  Apply the changes and delete all intentions that are not in the file:
+
+      $ consul intention apply -prune -f intentions.hcl
+
+`
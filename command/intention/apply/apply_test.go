@@ -0,0 +1,209 @@
+package apply
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		args   []string
+		output string
+	}{
+		"no file": {
+			[]string{},
+			"-f flag is required",
+		},
+		"args": {
+			[]string{"-f", "foo.hcl", "a"},
+			"takes no arguments",
+		},
+		"missing file": {
+			[]string{"-f", "does-not-exist.hcl"},
+			"Error reading does-not-exist.hcl",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			c := New(ui)
+			require.Equal(t, 1, c.Run(tc.args))
+			require.Contains(t, ui.ErrorWriter.String(), tc.output)
+		})
+	}
+}
+
+func TestParseIntentions(t *testing.T) {
+	t.Parallel()
+
+	expected := []*api.Intention{
+		{
+			SourceName:      "web",
+			DestinationName: "db",
+			SourceType:      api.IntentionSourceConsul,
+			Action:          api.IntentionActionAllow,
+			Meta:            map[string]string{"team": "payments"},
+		},
+		{
+			SourceName:      "billing",
+			DestinationName: "payments",
+			SourceType:      api.IntentionSourceConsul,
+			Permissions: []*api.IntentionPermission{
+				{
+					Action: api.IntentionActionAllow,
+					HTTP: &api.IntentionHTTPPermission{
+						PathExact: "/charge",
+						Methods:   []string{"POST"},
+					},
+				},
+			},
+		},
+	}
+
+	cases := map[string]string{
+		"hcl": `
+Intentions = [
+  {
+    SourceName      = "web"
+    DestinationName = "db"
+    Action          = "allow"
+    Meta {
+      team = "payments"
+    }
+  },
+  {
+    SourceName      = "billing"
+    DestinationName = "payments"
+    Permissions = [
+      {
+        Action = "allow"
+        HTTP {
+          PathExact = "/charge"
+          Methods   = ["POST"]
+        }
+      },
+    ]
+  },
+]`,
+		"json": `{
+  "Intentions": [
+    {
+      "SourceName": "web",
+      "DestinationName": "db",
+      "Action": "allow",
+      "Meta": {"team": "payments"}
+    },
+    {
+      "SourceName": "billing",
+      "DestinationName": "payments",
+      "Permissions": [
+        {
+          "Action": "allow",
+          "HTTP": {"PathExact": "/charge", "Methods": ["POST"]}
+        }
+      ]
+    }
+  ]
+}`,
+	}
+
+	for name, data := range cases {
+		t.Run(name, func(t *testing.T) {
+			ixns, err := parseIntentions(data)
+			require.NoError(t, err)
+			require.Equal(t, expected, ixns)
+		})
+	}
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := parseIntentions(`Intentions = [{ Source = "web" }]`)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Source")
+	})
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	// Create the intentions that are changed and left alone.
+	for _, ixn := range []*api.Intention{
+		{SourceName: "web", DestinationName: "db", Action: api.IntentionActionAllow},
+		{SourceName: "*", DestinationName: "db", Action: api.IntentionActionDeny},
+	} {
+		_, _, err := client.Connect().IntentionCreate(ixn, nil)
+		require.NoError(err)
+	}
+
+	dir := testutil.TempDir(t, "intentions")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "intentions.hcl")
+	require.NoError(ioutil.WriteFile(path, []byte(`
+Intentions = [
+  { SourceName = "web", DestinationName = "db", Action = "deny" },
+  { SourceName = "api", DestinationName = "db", Action = "allow" },
+]`), 0600))
+
+	run := func(args ...string) string {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		args = append([]string{"-http-addr=" + a.HTTPAddr()}, args...)
+		require.Equal(0, c.Run(args), ui.ErrorWriter.String())
+		return ui.OutputWriter.String()
+	}
+
+	// A dry run shows the changes only.
+	output := run("-dry-run", "-f", path)
+	require.Contains(output, "~ update web => db (deny)")
+	require.Contains(output, `Action: "allow" => "deny"`)
+	require.Contains(output, "+ create api => db (allow)")
+	require.Contains(output, "Unmanaged intentions")
+	require.NotContains(output, "Applied")
+
+	ixns, _, err := client.Connect().Intentions(nil)
+	require.NoError(err)
+	require.Len(ixns, 2)
+
+	// Apply with pruning.
+	output = run("-prune", "-f", path)
+	require.Contains(output, "- delete * => db (deny)")
+	require.Contains(output, "Applied 3 changes.")
+
+	ixns, _, err = client.Connect().Intentions(nil)
+	require.NoError(err)
+	actual := make(map[string]api.IntentionAction)
+	for _, ixn := range ixns {
+		actual[ixn.SourceName] = ixn.Action
+	}
+	require.Equal(map[string]api.IntentionAction{
+		"web": api.IntentionActionDeny,
+		"api": api.IntentionActionAllow,
+	}, actual)
+
+	// Nothing left to do.
+	output = run("-prune", "-f", path)
+	require.Contains(output, "No changes")
+}
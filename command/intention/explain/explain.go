@@ -0,0 +1,130 @@
+package explain
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 2 {
+		c.UI.Error(fmt.Sprintf("Error: command requires exactly two arguments: src and dst"))
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	// Explain the connection
+	result, _, err := client.Connect().IntentionExplain(&api.IntentionCheck{
+		Source:      args[0],
+		Destination: args[1],
+		SourceType:  api.IntentionSourceConsul,
+	}, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error explaining the connection: %s", err))
+		return 1
+	}
+
+	decision := "Denied"
+	if result.Allowed {
+		decision = "Allowed"
+	}
+	defaultPolicy := "deny"
+	if result.DefaultAllow {
+		defaultPolicy = "allow"
+	}
+	c.UI.Output(columnize.SimpleFormat([]string{
+		fmt.Sprintf("Decision:|%s", decision),
+		fmt.Sprintf("Reason:|%s", result.Reason),
+		fmt.Sprintf("Default:|%s", defaultPolicy),
+	}))
+
+	if len(result.Intentions) == 0 {
+		return 0
+	}
+
+	// List the intentions in the order they are evaluated.
+	lines := []string{"Precedence\x1fSource\x1fAction\x1fID\x1fStatus"}
+	for _, entry := range result.Intentions {
+		ixn := entry.Intention
+		action := string(ixn.Action)
+		if len(ixn.Permissions) > 0 {
+			action = "L7"
+		}
+
+		var status string
+		switch {
+		case result.Matched != nil && ixn.ID == result.Matched.ID:
+			status = "matched"
+		case entry.ShadowedBy != "":
+			status = "shadowed by " + entry.ShadowedBy
+		case entry.SourceMatch:
+			status = "matches, lower precedence"
+		}
+
+		lines = append(lines, fmt.Sprintf("%d\x1f%s\x1f%s\x1f%s\x1f%s",
+			ixn.Precedence, ixn.SourceString(), action, ixn.ID, status))
+	}
+	c.UI.Output("")
+	c.UI.Output(columnize.Format(lines, &columnize.Config{Delim: string([]byte{0x1f})}))
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Explain whether a connection between two services is allowed."
+const help = `
+Usage: consul intention explain [options] SRC DST
+
+  Explain whether a connection between SRC and DST would be allowed by
+  Connect given the current Consul configuration. The output shows the
+  intention that decides, or the default behavior configured by ACLs if no
+  intention matches, followed by all intentions for DST in the order they
+  are evaluated. Intentions that can never apply to DST because an
+  intention with a higher precedence covers all of their sources are
+  marked as shadowed.
+
+      $ consul intention explain web db
+
+`
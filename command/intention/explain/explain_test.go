@@ -0,0 +1,66 @@
+package explain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	require.Equal(t, 1, c.Run([]string{"a"}))
+	require.Contains(t, ui.ErrorWriter.String(), "requires exactly two")
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	// Create the intentions. web => * is shadowed by * => db for db.
+	var ids []string
+	for _, ixn := range []*api.Intention{
+		{SourceName: "*", DestinationName: "db", Action: api.IntentionActionDeny},
+		{SourceName: "web", DestinationName: "*", Action: api.IntentionActionAllow},
+	} {
+		id, _, err := client.Connect().IntentionCreate(ixn, nil)
+		require.NoError(err)
+		ids = append(ids, id)
+	}
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"web", "db",
+	}
+	require.Equal(0, c.Run(args), ui.ErrorWriter.String())
+
+	output := ui.OutputWriter.String()
+	require.Contains(output, "Denied")
+	require.Contains(output, "Matched intention")
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	require.Len(lines, 7)
+	require.Contains(lines[5], ids[0])
+	require.Contains(lines[5], "matched")
+	require.Contains(lines[6], ids[1])
+	require.Contains(lines[6], "shadowed by "+ids[0])
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/flags"
 	"github.com/hashicorp/consul/command/intention/finder"
 	"github.com/mitchellh/cli"
@@ -68,9 +70,11 @@ func (c *cmd) Run(args []string) int {
 	data := []string{
 		fmt.Sprintf("Source:|%s", ixn.SourceString()),
 		fmt.Sprintf("Destination:|%s", ixn.DestinationString()),
-		fmt.Sprintf("Action:|%s", ixn.Action),
-		fmt.Sprintf("ID:|%s", ixn.ID),
 	}
+	if len(ixn.Permissions) == 0 {
+		data = append(data, fmt.Sprintf("Action:|%s", ixn.Action))
+	}
+	data = append(data, fmt.Sprintf("ID:|%s", ixn.ID))
 	if v := ixn.Description; v != "" {
 		data = append(data, fmt.Sprintf("Description:|%s", v))
 	}
@@ -89,9 +93,55 @@ func (c *cmd) Run(args []string) int {
 	)
 
 	c.UI.Output(columnize.SimpleFormat(data))
+
+	// Permissions are printed outside of the table since their patterns may
+	// contain the column delimiter.
+	if len(ixn.Permissions) > 0 {
+		c.UI.Output("Permissions:")
+		for i, perm := range ixn.Permissions {
+			c.UI.Output(fmt.Sprintf("  %d. %s", i+1, formatPermission(perm)))
+		}
+	}
 	return 0
 }
 
+// formatPermission returns a one line description of an L7 permission.
+func formatPermission(perm *api.IntentionPermission) string {
+	parts := []string{string(perm.Action)}
+	if h := perm.HTTP; h != nil {
+		if len(h.Methods) > 0 {
+			parts = append(parts, strings.Join(h.Methods, ","))
+		}
+		switch {
+		case h.PathExact != "":
+			parts = append(parts, "path="+h.PathExact)
+		case h.PathPrefix != "":
+			parts = append(parts, "path-prefix="+h.PathPrefix)
+		case h.PathRegex != "":
+			parts = append(parts, "path-regex="+h.PathRegex)
+		}
+		for _, hdr := range h.Header {
+			op, v := "=", hdr.Exact
+			switch {
+			case hdr.Present:
+				op, v = " present", ""
+			case hdr.Prefix != "":
+				op, v = " prefix ", hdr.Prefix
+			case hdr.Suffix != "":
+				op, v = " suffix ", hdr.Suffix
+			case hdr.Regex != "":
+				op, v = " regex ", hdr.Regex
+			}
+			not := ""
+			if hdr.Invert {
+				not = "not "
+			}
+			parts = append(parts, fmt.Sprintf("%sheader %s%s%s", not, hdr.Name, op, v))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
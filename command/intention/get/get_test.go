@@ -122,3 +122,51 @@ func TestCommand_srcDst(t *testing.T) {
 	require.Equal(0, c.Run(args), ui.ErrorWriter.String())
 	require.Contains(ui.OutputWriter.String(), id)
 }
+
+func TestCommand_permissions(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	// Create the intention
+	_, _, err := client.Connect().IntentionCreate(&api.Intention{
+		SourceName:      "billing",
+		DestinationName: "payments",
+		Permissions: []*api.IntentionPermission{
+			{
+				Action: api.IntentionActionAllow,
+				HTTP: &api.IntentionHTTPPermission{
+					PathExact: "/charge",
+					Methods:   []string{"POST"},
+				},
+			},
+			{
+				Action: api.IntentionActionDeny,
+				HTTP: &api.IntentionHTTPPermission{
+					PathPrefix: "/",
+					Header: []api.IntentionHTTPHeaderPermission{
+						{Name: "x-debug", Present: true},
+					},
+				},
+			},
+		},
+	}, nil)
+	require.NoError(err)
+
+	// Get it
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"billing", "payments",
+	}
+	require.Equal(0, c.Run(args), ui.ErrorWriter.String())
+	output := ui.OutputWriter.String()
+	require.NotContains(output, "Action:")
+	require.Contains(output, "1. allow POST path=/charge")
+	require.Contains(output, "2. deny path-prefix=/ header x-debug present")
+}
@@ -40,6 +40,14 @@ Usage: consul intention <subcommand> [options] [args]
 
       $ consul intention check web db
 
+  Explain why "web" is or isn't allowed to connect to "db":
+
+      $ consul intention explain web db
+
+  Make the intentions match the intentions in a file:
+
+      $ consul intention apply -f intentions.hcl
+
   Find all intentions for communicating to the "db" service:
 
       $ consul intention match db
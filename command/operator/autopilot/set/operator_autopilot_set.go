@@ -50,7 +50,7 @@ func (c *cmd) init() {
 			"servers are running Raft protocol version 3 or higher. Must be a duration "+
 			"value such as `10s`.")
 	c.flags.Var(&c.redundancyZoneTag, "redundancy-zone-tag",
-		"(Enterprise-only) Controls the node_meta tag name used for separating servers into "+
+		"Controls the node_meta tag name used for separating servers into "+
 			"different redundancy zones.")
 	c.flags.Var(&c.disableUpgradeMigration, "disable-upgrade-migration",
 		"(Enterprise-only) Controls whether Consul will avoid promoting new servers until "+
@@ -0,0 +1,87 @@
+package transferleader
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	id string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.id, "id", "",
+		"The ID of the server that should become the new leader. If this is "+
+			"not given then Raft will pick the most up-to-date voter.")
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		c.UI.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	// Set up a client.
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Hand off leadership.
+	if err := client.Operator().RaftTransferLeaderToID(c.id, nil); err != nil {
+		c.UI.Error(fmt.Sprintf("Error transferring leadership: %v", err))
+		return 1
+	}
+	if c.id != "" {
+		c.UI.Output(fmt.Sprintf("Transferred leadership to server with id %q", c.id))
+	} else {
+		c.UI.Output("Transferred leadership")
+	}
+
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Transfer Raft leadership to another Consul server"
+const help = `
+Usage: consul operator raft transfer-leader [options]
+
+  Transfer Raft leadership from the current leader to another voting server.
+
+  This can be used to gracefully move leadership off a server before taking it
+  down for maintenance, rather than waiting for an election after it stops.
+  If -id is given then leadership is handed to that server, otherwise Raft
+  picks the most up-to-date voter. This requires Raft protocol version 3.
+`
@@ -0,0 +1,105 @@
+package create
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/query/definition"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// testStdin is the input for testing.
+	testStdin io.Reader
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("Error: command requires exactly one argument: the definition file")
+		return 1
+	}
+
+	query, err := definition.Load(args[0], c.testStdin)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading %s: %s", args[0], err))
+		return 1
+	}
+	if query.ID != "" {
+		c.UI.Error("Error: the definition of a new query must not have an ID")
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	id, _, err := client.PreparedQuery().Create(query, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error creating the query: %s", err))
+		return 1
+	}
+
+	c.UI.Output(fmt.Sprintf("Created query with ID %s", id))
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Create a prepared query"
+const help = `
+Usage: consul query create [options] FILE
+
+  Create a prepared query from a definition in HCL or JSON format. The
+  definition uses the same fields as the HTTP API, and unknown fields are an
+  error. Use "-" as the file to read the definition from stdin. The servers
+  check the definition, including any template, before it's stored.
+
+      $ consul query create db.hcl
+
+  An example definition:
+
+      Name = "db"
+      Service {
+        Service     = "db"
+        OnlyPassing = true
+        Failover {
+          NearestN = 2
+        }
+      }
+
+`
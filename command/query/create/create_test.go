@@ -0,0 +1,75 @@
+package create
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	require.Equal(t, 1, c.Run(nil))
+	require.Contains(t, ui.ErrorWriter.String(), "requires exactly one")
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	client := a.Client()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	c.testStdin = strings.NewReader(`
+Name = "db"
+Service {
+  Service = "db"
+  Tags    = ["primary"]
+}
+`)
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-",
+	}
+	require.Equal(0, c.Run(args), ui.ErrorWriter.String())
+
+	queries, _, err := client.PreparedQuery().List(nil)
+	require.NoError(err)
+	require.Len(queries, 1)
+	require.Equal("db", queries[0].Name)
+	require.Equal([]string{"primary"}, queries[0].Service.Tags)
+	require.Contains(ui.OutputWriter.String(), queries[0].ID)
+}
+
+func TestCommand_id(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	c.testStdin = strings.NewReader(`
+ID = "a7a4f1f4-7c2e-4c7c-9e4b-5b0f7e5d2a35"
+Service {
+  Service = "db"
+}
+`)
+	require.Equal(t, 1, c.Run([]string{"-"}))
+	require.Contains(t, ui.ErrorWriter.String(), "must not have an ID")
+}
@@ -0,0 +1,94 @@
+package definition
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/helpers"
+	"github.com/hashicorp/hcl"
+	"github.com/mitchellh/mapstructure"
+)
+
+// Load reads a prepared query definition from a file, or from stdin if the
+// path is "-". The testStdin reader is used instead of stdin if it's set.
+func Load(path string, testStdin io.Reader) (*api.PreparedQueryDefinition, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		var stdin io.Reader = os.Stdin
+		if testStdin != nil {
+			stdin = testStdin
+		}
+		var b bytes.Buffer
+		_, err = io.Copy(&b, stdin)
+		data = b.Bytes()
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(string(data))
+}
+
+// Parse parses a prepared query definition in HCL or JSON format. The
+// definition uses the same fields as the HTTP API, and unknown fields are
+// an error so that typos don't go unnoticed.
+func Parse(data string) (*api.PreparedQueryDefinition, error) {
+	var result *api.PreparedQueryDefinition
+	var err error
+	if strings.HasPrefix(strings.TrimSpace(data), "{") {
+		result, err = parseJSON(data)
+	} else {
+		result, err = parseHCL(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Service.Service == "" {
+		return nil, fmt.Errorf("the query must have a Service.Service")
+	}
+	return result, nil
+}
+
+// parseJSON decodes JSON with the standard library rather than HCL, since
+// HCL turns null values, like the ones in the output of "consul query
+// read", into strings.
+func parseJSON(data string) (*api.PreparedQueryDefinition, error) {
+	var result api.PreparedQueryDefinition
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func parseHCL(data string) (*api.PreparedQueryDefinition, error) {
+	var raw map[string]interface{}
+	if err := hcl.Decode(&raw, data); err != nil {
+		return nil, err
+	}
+
+	var result api.PreparedQueryDefinition
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:  helpers.SliceOfMapsHook,
+		ErrorUnused: true,
+		Result:      &result,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(raw); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
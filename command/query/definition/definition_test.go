@@ -0,0 +1,158 @@
+package definition
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		Name  string
+		Input string
+		Want  *api.PreparedQueryDefinition
+		Err   string
+	}{
+		{
+			"hcl",
+			`
+Name = "db"
+Service {
+  Service     = "db"
+  Tags        = ["primary"]
+  OnlyPassing = true
+  Failover {
+    NearestN    = 2
+    Datacenters = ["dc2"]
+  }
+}
+DNS {
+  TTL = "10s"
+}
+`,
+			&api.PreparedQueryDefinition{
+				Name: "db",
+				Service: api.ServiceQuery{
+					Service:     "db",
+					Tags:        []string{"primary"},
+					OnlyPassing: true,
+					Failover: api.QueryDatacenterOptions{
+						NearestN:    2,
+						Datacenters: []string{"dc2"},
+					},
+				},
+				DNS: api.QueryDNSOptions{TTL: "10s"},
+			},
+			"",
+		},
+		{
+			"json",
+			`{"Name": "db", "Service": {"Service": "db", "NodeMeta": {"rack": "a"}}}`,
+			&api.PreparedQueryDefinition{
+				Name: "db",
+				Service: api.ServiceQuery{
+					Service:  "db",
+					NodeMeta: map[string]string{"rack": "a"},
+				},
+			},
+			"",
+		},
+		{
+			"template",
+			`
+Name = "geo-db"
+Template {
+  Type   = "name_prefix_match"
+  Regexp = "^geo-db-(.*?)$"
+}
+Service {
+  Service = "mysql-${match(1)}"
+}
+`,
+			&api.PreparedQueryDefinition{
+				Name: "geo-db",
+				Template: api.QueryTemplate{
+					Type:   "name_prefix_match",
+					Regexp: "^geo-db-(.*?)$",
+				},
+				Service: api.ServiceQuery{
+					Service: "mysql-${match(1)}",
+				},
+			},
+			"",
+		},
+		{
+			"unknown field",
+			`Name = "db"
+Service { Servce = "db" }`,
+			nil,
+			"Servce",
+		},
+		{
+			"unknown json field",
+			`{"Service": {"Service": "db", "OnlyPasing": true}}`,
+			nil,
+			"OnlyPasing",
+		},
+		{
+			"no service",
+			`Name = "db"`,
+			nil,
+			"must have a Service.Service",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require := require.New(t)
+			actual, err := Parse(tc.Input)
+			if tc.Err != "" {
+				require.Error(err)
+				require.Contains(err.Error(), tc.Err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(tc.Want, actual)
+		})
+	}
+}
+
+// The JSON output of "consul query read" must be accepted as a definition.
+func TestParse_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	query := &api.PreparedQueryDefinition{
+		ID:   "a7a4f1f4-7c2e-4c7c-9e4b-5b0f7e5d2a35",
+		Name: "db",
+		Service: api.ServiceQuery{
+			Service: "db",
+			Tags:    []string{"primary"},
+			Failover: api.QueryDatacenterOptions{
+				Targets: []api.QueryFailoverTarget{
+					{Datacenter: "dc2", Service: "db-backup"},
+				},
+			},
+		},
+	}
+	b, err := json.MarshalIndent(query, "", "  ")
+	require.NoError(err)
+
+	actual, err := Parse(string(b))
+	require.NoError(err)
+	require.Equal(query, actual)
+}
+
+func TestLoad_stdin(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	actual, err := Load("-", strings.NewReader(`Service { Service = "db" }`))
+	require.NoError(err)
+	require.Equal("db", actual.Service.Service)
+}
@@ -0,0 +1,112 @@
+package execute
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	near string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.near, "near", "",
+		"Node name to sort the results near. The special value \"_agent\" "+
+			"sorts near the agent servicing the request.")
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("Error: command requires exactly one argument: query ID or name")
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	result, _, err := client.PreparedQuery().Execute(args[0], &api.QueryOptions{
+		Near: c.near,
+	})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error executing the query: %s", err))
+		return 1
+	}
+
+	c.UI.Output(columnize.SimpleFormat([]string{
+		fmt.Sprintf("Service:|%s", result.Service),
+		fmt.Sprintf("Datacenter:|%s", result.Datacenter),
+		fmt.Sprintf("Failovers:|%d", result.Failovers),
+	}))
+	if len(result.Nodes) == 0 {
+		return 0
+	}
+
+	c.UI.Output("")
+	out := []string{"Node\x1fAddress\x1fService ID\x1fPort\x1fTags"}
+	for _, entry := range result.Nodes {
+		address := entry.Service.Address
+		if address == "" {
+			address = entry.Node.Address
+		}
+		out = append(out, fmt.Sprintf("%s\x1f%s\x1f%s\x1f%d\x1f%s",
+			entry.Node.Node, address, entry.Service.ID, entry.Service.Port,
+			strings.Join(entry.Service.Tags, ",")))
+	}
+	c.UI.Output(columnize.Format(out, &columnize.Config{Delim: string([]byte{0x1f})}))
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Execute a prepared query"
+const help = `
+Usage: consul query execute [options] QUERY
+
+  Execute the prepared query with the given ID or name and print the
+  healthy instances it returns, in the order they would be served.
+
+      $ consul query execute db
+
+  Sort the results by round trip time from a node:
+
+      $ consul query execute -near=web1 db
+
+`
@@ -0,0 +1,67 @@
+package execute
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	require.Equal(t, 1, c.Run(nil))
+	require.Contains(t, ui.ErrorWriter.String(), "requires exactly one")
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	client := a.Client()
+
+	require.NoError(client.Agent().ServiceRegister(&api.AgentServiceRegistration{
+		ID:   "db1",
+		Name: "db",
+		Tags: []string{"primary"},
+		Port: 5432,
+	}))
+	_, _, err := client.PreparedQuery().Create(&api.PreparedQueryDefinition{
+		Name:    "db",
+		Service: api.ServiceQuery{Service: "db"},
+	}, nil)
+	require.NoError(err)
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-near=_agent",
+		"db",
+	}
+	require.Equal(0, c.Run(args), ui.ErrorWriter.String())
+
+	output := ui.OutputWriter.String()
+	require.Contains(output, "dc1")
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	require.Len(lines, 6)
+	require.Regexp(`^Node\s+Address\s+Service ID\s+Port\s+Tags$`, lines[4])
+	require.Regexp(`^`+a.Config.NodeName+`\s+127.0.0.1\s+db1\s+5432\s+primary$`, lines[5])
+}
@@ -0,0 +1,153 @@
+package explain
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	near string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.near, "near", "",
+		"Node name to sort the results near. The special value \"_agent\" "+
+			"sorts near the agent servicing the request.")
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error(fmt.Sprintf("Error: command requires exactly one argument: query ID or name"))
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	// Execute the query with an explanation
+	result, _, err := client.PreparedQuery().ExecuteExplain(args[0], &api.QueryOptions{
+		Near: c.near,
+	})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error executing the query: %s", err))
+		return 1
+	}
+	if result.Explain == nil {
+		c.UI.Error("Error: the servers didn't explain the query execution, they may need to be upgraded")
+		return 1
+	}
+
+	explain := result.Explain
+	query := explain.Query.ID
+	if explain.Query.Name != "" {
+		query = fmt.Sprintf("%s (%s)", explain.Query.Name, explain.Query.ID)
+	}
+	near := explain.Near
+	if near == "" {
+		near = "(none)"
+	}
+	c.UI.Output(columnize.SimpleFormat([]string{
+		fmt.Sprintf("Query:|%s", query),
+		fmt.Sprintf("Service:|%s", explain.Query.Service.Service),
+		fmt.Sprintf("Near:|%s", near),
+		fmt.Sprintf("Results:|%d from %s", len(result.Nodes), result.Datacenter),
+		fmt.Sprintf("Failovers:|%d", result.Failovers),
+	}))
+
+	// List the datacenters in the order they were tried.
+	lines := []string{"Datacenter\x1fReason\x1fRTT\x1fService\x1fInstances\x1fFiltered\x1fResults\x1fError"}
+	for _, dc := range explain.Datacenters {
+		service := dc.Service
+		if len(dc.Tags) > 0 {
+			service = fmt.Sprintf("%s [%s]", dc.Service, strings.Join(dc.Tags, ","))
+		}
+		lines = append(lines, fmt.Sprintf("%s\x1f%s\x1f%s\x1f%s\x1f%d\x1f%s\x1f%d\x1f%s",
+			dc.Datacenter, dc.Reason, orDash(dc.RTT), service, dc.Instances,
+			orDash(filterSummary(dc)), dc.Results, dc.Error))
+	}
+	c.UI.Output("")
+	c.UI.Output(columnize.Format(lines, &columnize.Config{Delim: string([]byte{0x1f})}))
+	return 0
+}
+
+// filterSummary lists the filters that removed instances in a datacenter.
+func filterSummary(dc *api.QueryDatacenterExplain) string {
+	var parts []string
+	for _, f := range dc.Filters {
+		if f.Removed > 0 {
+			parts = append(parts, fmt.Sprintf("%s -%d", f.Filter, f.Removed))
+		}
+	}
+	if dc.IgnoredChecks > 0 {
+		parts = append(parts, fmt.Sprintf("ignored checks +%d", dc.IgnoredChecks))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Explain how a prepared query is executed"
+const help = `
+Usage: consul query explain [options] QUERY
+
+  Execute a prepared query by ID or name and explain how the results were
+  found. The output shows the resolved query and the node the results were
+  sorted near, followed by the datacenters that were queried in the order
+  they were tried. For each datacenter it shows why it was tried, the
+  estimated round trip time to it, how many instances of the service it
+  has, and how many of those each filter removed.
+
+      $ consul query explain db
+
+  Filters are the health checks ("health"), the node and service metadata
+  ("node-meta" and "service-meta"), the tags ("tags"), the ACLs of the
+  query token ("acl") and the limit of the request ("limit"). Instances the health
+  filter only kept because of the IgnoreCheckIDs of the query are counted
+  as "ignored checks".
+
+`
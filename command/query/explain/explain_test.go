@@ -0,0 +1,72 @@
+package explain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	require.Equal(t, 1, c.Run(nil))
+	require.Contains(t, ui.ErrorWriter.String(), "requires exactly one")
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	client := a.Client()
+
+	// Register instances of which only one has the tag of the query.
+	for _, svc := range []*api.AgentServiceRegistration{
+		{ID: "db1", Name: "db", Tags: []string{"primary"}},
+		{ID: "db2", Name: "db", Tags: []string{"replica"}},
+	} {
+		require.NoError(client.Agent().ServiceRegister(svc))
+	}
+	id, _, err := client.PreparedQuery().Create(&api.PreparedQueryDefinition{
+		Name: "db",
+		Service: api.ServiceQuery{
+			Service: "db",
+			Tags:    []string{"primary"},
+		},
+	}, nil)
+	require.NoError(err)
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-near=_agent",
+		"db",
+	}
+	require.Equal(0, c.Run(args), ui.ErrorWriter.String())
+
+	output := ui.OutputWriter.String()
+	require.Contains(output, "db ("+id+")")
+	require.Contains(output, "1 from dc1")
+	require.Contains(output, a.Config.NodeName)
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	require.Len(lines, 8)
+	require.Regexp(`^dc1\s+local\s+-\s+db \[primary\]\s+2\s+tags -1\s+1\s*$`, lines[7])
+}
@@ -0,0 +1,34 @@
+package finder
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/go-uuid"
+)
+
+// Find returns the prepared query with the given ID or name. Queries can only
+// be read by ID, so names are looked up in the list of queries the token can
+// read. An error is returned if the query is not found.
+func Find(client *api.Client, idOrName string) (*api.PreparedQueryDefinition, error) {
+	// A missing query isn't a proper error for Get, so any errors fall
+	// through to the list, which returns them again if they are real.
+	if _, err := uuid.ParseUUID(idOrName); err == nil {
+		queries, _, err := client.PreparedQuery().Get(idOrName, nil)
+		if err == nil && len(queries) == 1 {
+			return queries[0], nil
+		}
+	}
+
+	queries, _, err := client.PreparedQuery().List(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, query := range queries {
+		if query.Name == idOrName {
+			return query, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Prepared query %q not found", idOrName)
+}
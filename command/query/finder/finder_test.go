@@ -0,0 +1,40 @@
+package finder
+
+import (
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	client := a.Client()
+
+	id, _, err := client.PreparedQuery().Create(&api.PreparedQueryDefinition{
+		Name:    "db",
+		Service: api.ServiceQuery{Service: "db"},
+	}, nil)
+	require.NoError(err)
+
+	// Find by ID and by name.
+	for _, idOrName := range []string{id, "db"} {
+		query, err := Find(client, idOrName)
+		require.NoError(err)
+		require.Equal(id, query.ID)
+	}
+
+	// Missing queries are an error, whether they look like an ID or not.
+	for _, idOrName := range []string{"a7a4f1f4-7c2e-4c7c-9e4b-5b0f7e5d2a35", "web"} {
+		_, err := Find(client, idOrName)
+		require.Error(err)
+		require.Contains(err.Error(), "not found")
+	}
+}
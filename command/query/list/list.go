@@ -0,0 +1,105 @@
+package list
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(c.flags.Args()) != 0 {
+		c.UI.Error("Error: command takes no arguments")
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	queries, _, err := client.PreparedQuery().List(nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error listing queries: %s", err))
+		return 1
+	}
+	if len(queries) == 0 {
+		return 0
+	}
+
+	sort.Slice(queries, func(i, j int) bool {
+		if queries[i].Name != queries[j].Name {
+			return queries[i].Name < queries[j].Name
+		}
+		return queries[i].ID < queries[j].ID
+	})
+
+	result := []string{"ID\x1fName\x1fService\x1fTemplate"}
+	for _, query := range queries {
+		service := query.Service.Service
+		if len(query.Service.Tags) > 0 {
+			service = fmt.Sprintf("%s [%s]", service, strings.Join(query.Service.Tags, ", "))
+		}
+		result = append(result, fmt.Sprintf("%s\x1f%s\x1f%s\x1f%s",
+			query.ID, orDash(query.Name), service, orDash(query.Template.Type)))
+	}
+
+	c.UI.Output(columnize.Format(result, &columnize.Config{Delim: string([]byte{0x1f})}))
+	return 0
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "List prepared queries"
+const help = `
+Usage: consul query list [options]
+
+  List the prepared queries that the token can read, sorted by name.
+
+      $ consul query list
+
+`
@@ -0,0 +1,62 @@
+package list
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	require.Equal(t, 1, c.Run([]string{"db"}))
+	require.Contains(t, ui.ErrorWriter.String(), "takes no arguments")
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	client := a.Client()
+
+	var ids []string
+	for _, query := range []*api.PreparedQueryDefinition{
+		{Name: "web", Service: api.ServiceQuery{Service: "web", Tags: []string{"v1"}}},
+		{Name: "db", Service: api.ServiceQuery{Service: "db"}},
+	} {
+		id, _, err := client.PreparedQuery().Create(query, nil)
+		require.NoError(err)
+		ids = append(ids, id)
+	}
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+	}
+	require.Equal(0, c.Run(args), ui.ErrorWriter.String())
+
+	lines := strings.Split(strings.TrimSpace(ui.OutputWriter.String()), "\n")
+	require.Len(lines, 3)
+	require.Regexp(`^ID\s+Name\s+Service\s+Template$`, lines[0])
+	require.Regexp(`^`+ids[1]+`\s+db\s+db\s+-$`, lines[1])
+	require.Regexp(`^`+ids[0]+`\s+web\s+web \[v1\]\s+-$`, lines[2])
+}
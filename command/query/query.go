@@ -0,0 +1,51 @@
+package query
+
+import (
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New() *cmd {
+	return &cmd{}
+}
+
+type cmd struct{}
+
+func (c *cmd) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(help, nil)
+}
+
+const synopsis = "Interact with prepared queries"
+const help = `
+Usage: consul query <subcommand> [options] [args]
+
+  This command has subcommands for interacting with prepared queries. Here
+  are some simple examples, and more detailed examples are available in the
+  subcommands or the documentation.
+
+  Create a query from a definition file:
+
+      $ consul query create db.hcl
+
+  List all queries:
+
+      $ consul query list
+
+  Execute the "db" query:
+
+      $ consul query execute db
+
+  Explain how the "db" query is executed:
+
+      $ consul query explain db
+
+  For more examples, ask for subcommand help or view the documentation.
+`
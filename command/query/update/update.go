@@ -0,0 +1,103 @@
+package update
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/query/definition"
+	"github.com/hashicorp/consul/command/query/finder"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// testStdin is the input for testing.
+	testStdin io.Reader
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 2 {
+		c.UI.Error("Error: command requires exactly two arguments: the query ID or name and the definition file")
+		return 1
+	}
+
+	query, err := definition.Load(args[1], c.testStdin)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading %s: %s", args[1], err))
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	existing, err := finder.Find(client, args[0])
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error: %s", err))
+		return 1
+	}
+	if query.ID != "" && query.ID != existing.ID {
+		c.UI.Error(fmt.Sprintf("Error: the definition has the ID %s but the query has the ID %s",
+			query.ID, existing.ID))
+		return 1
+	}
+	query.ID = existing.ID
+
+	if _, err := client.PreparedQuery().Update(query, nil); err != nil {
+		c.UI.Error(fmt.Sprintf("Error updating the query: %s", err))
+		return 1
+	}
+
+	c.UI.Output(fmt.Sprintf("Updated query with ID %s", query.ID))
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Update a prepared query"
+const help = `
+Usage: consul query update [options] QUERY FILE
+
+  Replace the definition of the prepared query with the given ID or name by
+  a definition in HCL or JSON format, like the one for "consul query create".
+  Use "-" as the file to read the definition from stdin. The definition may
+  contain the ID of the query, like the output of "consul query read", but it
+  must match.
+
+      $ consul query update db db.hcl
+
+`
@@ -0,0 +1,103 @@
+package update
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	require.Equal(t, 1, c.Run([]string{"db"}))
+	require.Contains(t, ui.ErrorWriter.String(), "requires exactly two")
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	client := a.Client()
+
+	id, _, err := client.PreparedQuery().Create(&api.PreparedQueryDefinition{
+		Name:    "db",
+		Service: api.ServiceQuery{Service: "db"},
+	}, nil)
+	require.NoError(err)
+
+	// Update the query by name.
+	{
+		ui := cli.NewMockUi()
+		c := New(ui)
+		c.testStdin = strings.NewReader(`
+Name = "db"
+Service {
+  Service     = "db"
+  OnlyPassing = true
+}
+`)
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"db",
+			"-",
+		}
+		require.Equal(0, c.Run(args), ui.ErrorWriter.String())
+		require.Contains(ui.OutputWriter.String(), id)
+
+		queries, _, err := client.PreparedQuery().Get(id, nil)
+		require.NoError(err)
+		require.Len(queries, 1)
+		require.True(queries[0].Service.OnlyPassing)
+	}
+
+	// A definition with another ID is rejected.
+	{
+		ui := cli.NewMockUi()
+		c := New(ui)
+		c.testStdin = strings.NewReader(`
+ID = "a7a4f1f4-7c2e-4c7c-9e4b-5b0f7e5d2a35"
+Service {
+  Service = "db"
+}
+`)
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			id,
+			"-",
+		}
+		require.Equal(1, c.Run(args))
+		require.Contains(ui.ErrorWriter.String(), "has the ID")
+	}
+
+	// A missing query is an error.
+	{
+		ui := cli.NewMockUi()
+		c := New(ui)
+		c.testStdin = strings.NewReader(`Service { Service = "db" }`)
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"nope",
+			"-",
+		}
+		require.Equal(1, c.Run(args))
+		require.Contains(ui.ErrorWriter.String(), "not found")
+	}
+}
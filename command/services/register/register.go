@@ -95,8 +95,8 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	// Create all the services
-	for _, svc := range svcs {
+	if len(svcs) == 1 {
+		svc := svcs[0]
 		if err := client.Agent().ServiceRegister(svc); err != nil {
 			c.UI.Error(fmt.Sprintf("Error registering service %q: %s",
 				svc.Name, err))
@@ -104,6 +104,29 @@ func (c *cmd) Run(args []string) int {
 		}
 
 		c.UI.Output(fmt.Sprintf("Registered service: %s", svc.Name))
+		return 0
+	}
+
+	// Create all the services at once so that they are either all
+	// registered or none of them is.
+	results, err := client.Agent().ServiceRegisterBatch(svcs)
+	if err != nil {
+		if results == nil {
+			c.UI.Error(fmt.Sprintf("Error registering services: %s", err))
+			return 1
+		}
+		for i, result := range results {
+			if result.Error != "" {
+				c.UI.Error(fmt.Sprintf("Error registering service %q: %s",
+					svcs[i].Name, result.Error))
+			}
+		}
+		c.UI.Error("No services were registered")
+		return 1
+	}
+
+	for _, svc := range svcs {
+		c.UI.Output(fmt.Sprintf("Registered service: %s", svc.Name))
 	}
 
 	return 0
@@ -123,7 +146,9 @@ Usage: consul services register [options] [FILE...]
 
   Register one or more services using the local agent API. Services can
   be registered from standard Consul configuration files (HCL or JSON) or
-  using flags. The service is registered and the command returns. The caller
+  using flags. When several services are given, they are validated and
+  registered together, and none of them is registered if any of them is
+  invalid. The service is registered and the command returns. The caller
   must remember to call "consul services deregister" or a similar API to
   deregister the service when complete.
 
@@ -92,6 +92,57 @@ func TestCommand_File(t *testing.T) {
 	require.NotNil(svc)
 }
 
+func TestCommand_FileMultiple(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	contents := `{ "services": [ { "name": "web" }, { "name": "db" } ] }`
+	f := testFile(t, "json")
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(contents); err != nil {
+		t.Fatalf("err: %#v", err)
+	}
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		f.Name(),
+	}
+	require.Equal(0, c.Run(args), ui.ErrorWriter.String())
+
+	svcs, err := client.Agent().Services()
+	require.NoError(err)
+	require.Len(svcs, 2)
+	require.NotNil(svcs["web"])
+	require.NotNil(svcs["db"])
+
+	// None of the services is registered if one of them is invalid.
+	contents = `{ "services": [ { "name": "api" }, { "name": "cache", "address": "0.0.0.0" } ] }`
+	f2 := testFile(t, "json")
+	defer os.Remove(f2.Name())
+	if _, err := f2.WriteString(contents); err != nil {
+		t.Fatalf("err: %#v", err)
+	}
+
+	ui = cli.NewMockUi()
+	c = New(ui)
+	args = []string{
+		"-http-addr=" + a.HTTPAddr(),
+		f2.Name(),
+	}
+	require.Equal(1, c.Run(args))
+	require.Contains(ui.ErrorWriter.String(), `"cache": Invalid service address`)
+
+	svcs, err = client.Agent().Services()
+	require.NoError(err)
+	require.Len(svcs, 2)
+}
+
 func TestCommand_Flags(t *testing.T) {
 	t.Parallel()
 
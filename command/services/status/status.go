@@ -0,0 +1,158 @@
+package status
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(c.flags.Args()) > 0 {
+		c.UI.Error("Too many arguments (expected 0)")
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	status, err := client.Agent().SyncStatus()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error retrieving sync status: %s", err))
+		return 1
+	}
+
+	summary := []string{
+		fmt.Sprintf("Node:|%s", syncState(status.NodeInSync, false, status.NodeSyncError)),
+		fmt.Sprintf("Last Full Sync:|%s", syncResult(status.LastFullSync, status.LastFullSyncError)),
+		fmt.Sprintf("Last Partial Sync:|%s", syncResult(status.LastPartialSync, status.LastPartialSyncError)),
+		fmt.Sprintf("Next Full Sync:|%s", formatTime(status.NextFullSync)),
+		fmt.Sprintf("Paused:|%t", status.Paused),
+	}
+	c.UI.Output(columnize.SimpleFormat(summary))
+
+	c.UI.Output("")
+	c.UI.Output(formatEntries("Service", status.Services))
+	c.UI.Output("")
+	c.UI.Output(formatEntries("Check", status.Checks))
+
+	if len(status.ServiceFileErrors) > 0 {
+		paths := make([]string, 0, len(status.ServiceFileErrors))
+		for path := range status.ServiceFileErrors {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		result := []string{"File\x1fError"}
+		for _, path := range paths {
+			result = append(result, fmt.Sprintf("%s\x1f%s", path, status.ServiceFileErrors[path]))
+		}
+		c.UI.Output("")
+		c.UI.Output(columnize.Format(result, &columnize.Config{Delim: string([]byte{0x1f})}))
+	}
+	return 0
+}
+
+// formatEntries returns a table of the sync states of services or checks,
+// sorted by ID.
+func formatEntries(kind string, entries map[string]*api.AgentSyncEntry) string {
+	ids := make([]string, 0, len(entries))
+	for id := range entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	result := []string{fmt.Sprintf("%s\x1fStatus", kind)}
+	for _, id := range ids {
+		e := entries[id]
+		result = append(result, fmt.Sprintf("%s\x1f%s", id, syncState(e.InSync, e.Deleted, e.Error)))
+	}
+	return columnize.Format(result, &columnize.Config{Delim: string([]byte{0x1f})})
+}
+
+func syncState(inSync, deleted bool, err string) string {
+	var state string
+	switch {
+	case err != "":
+		state = "failed: " + err
+	case inSync:
+		state = "in sync"
+	default:
+		state = "pending"
+	}
+	if deleted {
+		state = "deleting, " + state
+	}
+	return state
+}
+
+func syncResult(t time.Time, err string) string {
+	if t.IsZero() {
+		return "never"
+	}
+	if err != "" {
+		return fmt.Sprintf("%s (failed: %s)", formatTime(t), err)
+	}
+	return formatTime(t)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(time.RFC3339)
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Show how services are synced to the catalog"
+const help = `
+Usage: consul services status [options]
+
+  Show how the services and checks registered with the local agent are
+  synced to the catalog, including the errors of failed sync attempts such
+  as registrations blocked by ACLs.
+
+      $ consul services status
+
+  This command must be run against the agent the services are registered
+  with.
+`
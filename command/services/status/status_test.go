@@ -0,0 +1,57 @@
+package status
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	require.Equal(t, 1, c.Run([]string{"foo"}))
+	require.Contains(t, ui.ErrorWriter.String(), "Too many arguments")
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	client := a.Client()
+
+	require.NoError(t, client.Agent().ServiceRegister(&api.AgentServiceRegistration{
+		Name: "web"}))
+
+	retry.Run(t, func(r *retry.R) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		args := []string{"-http-addr=" + a.HTTPAddr()}
+		if code := c.Run(args); code != 0 {
+			r.Fatalf("bad: %d. %s", code, ui.ErrorWriter.String())
+		}
+
+		output := ui.OutputWriter.String()
+		if !strings.Contains(output, "web") || !strings.Contains(output, "in sync") {
+			r.Fatalf("bad: %s", output)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package create
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	name      string
+	node      string
+	ttl       string
+	behavior  string
+	lockDelay time.Duration
+	checks    []string
+	noChecks  bool
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.name, "name", "",
+		"Name of the session.")
+	c.flags.StringVar(&c.node, "node", "",
+		"Node to tie the session to. This defaults to the node of the agent "+
+			"servicing the request.")
+	c.flags.StringVar(&c.ttl, "ttl", "",
+		"TTL of the session, such as \"30s\". The session is invalidated if it "+
+			"isn't renewed within the TTL. By default sessions have no TTL.")
+	c.flags.StringVar(&c.behavior, "behavior", "",
+		"What happens to the locks of the session when it's invalidated. This "+
+			"is either \"release\", the default, or \"delete\" to delete the keys.")
+	c.flags.DurationVar(&c.lockDelay, "lock-delay", 0,
+		"How long the locks of the session can't be acquired again after the "+
+			"session is invalidated. This defaults to 15s.")
+	c.flags.Var((*flags.AppendSliceValue)(&c.checks), "check",
+		"ID of a health check of the node to tie the session to. This can be "+
+			"specified multiple times. By default sessions are tied to the "+
+			"serfHealth check.")
+	c.flags.BoolVar(&c.noChecks, "no-checks", false,
+		"Don't tie the session to any health checks, not even serfHealth.")
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(c.flags.Args()) != 0 {
+		c.UI.Error("Error: command takes no arguments")
+		return 1
+	}
+	if c.noChecks && len(c.checks) > 0 {
+		c.UI.Error("Error: -check and -no-checks can't be used together")
+		return 1
+	}
+	switch c.behavior {
+	case "", api.SessionBehaviorRelease, api.SessionBehaviorDelete:
+	default:
+		c.UI.Error(fmt.Sprintf("Error: -behavior must be %q or %q",
+			api.SessionBehaviorRelease, api.SessionBehaviorDelete))
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	entry := &api.SessionEntry{
+		Name:      c.name,
+		Node:      c.node,
+		TTL:       c.ttl,
+		Behavior:  c.behavior,
+		LockDelay: c.lockDelay,
+		Checks:    c.checks,
+	}
+	var id string
+	if c.noChecks {
+		id, _, err = client.Session().CreateNoChecks(entry, nil)
+	} else {
+		id, _, err = client.Session().Create(entry, nil)
+	}
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error creating session: %s", err))
+		return 1
+	}
+
+	c.UI.Output(id)
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Create a session"
+const help = `
+Usage: consul session create [options]
+
+  Create a session and print its ID. Sessions that don't have a TTL must be
+  destroyed explicitly, or they live as long as their node and checks are
+  healthy.
+
+  Create a session that must be renewed every 30 seconds:
+
+      $ consul session create -name=worker -ttl=30s
+
+  Create a session that deletes its keys when it's invalidated:
+
+      $ consul session create -behavior=delete -check=serfHealth -check=service:web1
+
+`
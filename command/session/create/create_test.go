@@ -0,0 +1,85 @@
+package create
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	require.Equal(t, 1, c.Run([]string{"-check=serfHealth", "-no-checks"}))
+	require.Contains(t, ui.ErrorWriter.String(), "can't be used together")
+}
+
+func TestCommand_behavior(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	require.Equal(t, 1, c.Run([]string{"-behavior=nope"}))
+	require.Contains(t, ui.ErrorWriter.String(), "-behavior must be")
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	client := a.Client()
+
+	cases := []struct {
+		args  []string
+		check func(*api.SessionEntry)
+	}{
+		{
+			[]string{"-name=worker", "-ttl=30s", "-behavior=delete", "-lock-delay=5s"},
+			func(session *api.SessionEntry) {
+				require.Equal("worker", session.Name)
+				require.Equal(a.Config.NodeName, session.Node)
+				require.Equal("30s", session.TTL)
+				require.Equal(api.SessionBehaviorDelete, session.Behavior)
+				require.Equal(5*time.Second, session.LockDelay)
+				require.Equal([]string{"serfHealth"}, session.Checks)
+			},
+		},
+		{
+			[]string{"-no-checks"},
+			func(session *api.SessionEntry) {
+				require.Empty(session.Checks)
+			},
+		},
+	}
+	for _, tc := range cases {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		args := append([]string{"-http-addr=" + a.HTTPAddr()}, tc.args...)
+		require.Equal(0, c.Run(args), ui.ErrorWriter.String())
+
+		id := strings.TrimSpace(ui.OutputWriter.String())
+		session, _, err := client.Session().Info(id, nil)
+		require.NoError(err)
+		require.NotNil(session)
+		tc.check(session)
+	}
+}
@@ -0,0 +1,89 @@
+package destroy
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("Error: command requires exactly one argument: session ID")
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	// Destroying a missing session isn't an error for the API, so look it
+	// up first to catch typos.
+	session, _, err := client.Session().Info(args[0], nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading session: %s", err))
+		return 1
+	}
+	if session == nil {
+		c.UI.Error(fmt.Sprintf("Error: session %q not found", args[0]))
+		return 1
+	}
+
+	if _, err := client.Session().Destroy(session.ID, nil); err != nil {
+		c.UI.Error(fmt.Sprintf("Error destroying session: %s", err))
+		return 1
+	}
+
+	c.UI.Output("Session destroyed.")
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Destroy a session"
+const help = `
+Usage: consul session destroy [options] ID
+
+  Destroy the session with the given ID. The locks that the session holds
+  are released, or the keys are deleted if the session has the "delete"
+  behavior.
+
+      $ consul session destroy 4ca8e74b-6350-7587-addf-a18084928f3c
+
+`
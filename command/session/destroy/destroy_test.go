@@ -0,0 +1,60 @@
+package destroy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	require.Equal(t, 1, c.Run(nil))
+	require.Contains(t, ui.ErrorWriter.String(), "requires exactly one")
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	client := a.Client()
+
+	id, _, err := client.Session().Create(nil, nil)
+	require.NoError(err)
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		id,
+	}
+	require.Equal(0, c.Run(args), ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "destroyed")
+
+	session, _, err := client.Session().Info(id, nil)
+	require.NoError(err)
+	require.Nil(session)
+
+	// Destroying it again fails.
+	ui = cli.NewMockUi()
+	c = New(ui)
+	require.Equal(1, c.Run(args))
+	require.Contains(ui.ErrorWriter.String(), "not found")
+}
@@ -0,0 +1,120 @@
+package list
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	node string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.node, "node", "",
+		"Only list the sessions of the node with the given name.")
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(c.flags.Args()) != 0 {
+		c.UI.Error("Error: command takes no arguments")
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	var sessions []*api.SessionEntry
+	if c.node != "" {
+		sessions, _, err = client.Session().Node(c.node, nil)
+	} else {
+		sessions, _, err = client.Session().List(nil)
+	}
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error listing sessions: %s", err))
+		return 1
+	}
+	if len(sessions) == 0 {
+		return 0
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].Node != sessions[j].Node {
+			return sessions[i].Node < sessions[j].Node
+		}
+		return sessions[i].ID < sessions[j].ID
+	})
+
+	result := []string{"ID\x1fName\x1fNode\x1fBehavior\x1fTTL\x1fChecks\x1fHeld Keys"}
+	for _, session := range sessions {
+		result = append(result, fmt.Sprintf("%s\x1f%s\x1f%s\x1f%s\x1f%s\x1f%s\x1f%d",
+			session.ID, orDash(session.Name), session.Node, session.Behavior,
+			orDash(session.TTL), orDash(strings.Join(session.Checks, ",")),
+			session.HeldKeys))
+	}
+
+	c.UI.Output(columnize.Format(result, &columnize.Config{Delim: string([]byte{0x1f})}))
+	return 0
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "List sessions"
+const help = `
+Usage: consul session list [options]
+
+  List the sessions that the token can read, along with the health checks
+  they're tied to and the number of keys they hold locks on. Use "consul
+  session read" to see which keys a session holds.
+
+      $ consul session list
+
+  Only list the sessions of a node:
+
+      $ consul session list -node=web1
+
+`
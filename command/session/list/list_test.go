@@ -0,0 +1,85 @@
+package list
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	require.Equal(t, 1, c.Run([]string{"foo"}))
+	require.Contains(t, ui.ErrorWriter.String(), "takes no arguments")
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	client := a.Client()
+
+	// Create a session on the agent's node that holds a lock, and one on
+	// another node.
+	id, _, err := client.Session().Create(&api.SessionEntry{Name: "locker"}, nil)
+	require.NoError(err)
+	ok, _, err := client.KV().Acquire(&api.KVPair{Key: "service/leader", Session: id}, nil)
+	require.NoError(err)
+	require.True(ok)
+
+	_, err = client.Catalog().Register(&api.CatalogRegistration{
+		Node:    "other",
+		Address: "127.0.0.2",
+	}, nil)
+	require.NoError(err)
+	other, _, err := client.Session().CreateNoChecks(&api.SessionEntry{Node: "other", TTL: "30s"}, nil)
+	require.NoError(err)
+
+	{
+		ui := cli.NewMockUi()
+		c := New(ui)
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+		}
+		require.Equal(0, c.Run(args), ui.ErrorWriter.String())
+
+		output := ui.OutputWriter.String()
+		require.Contains(output, id)
+		require.Contains(output, other)
+		require.Regexp(id+`\s+locker\s+`+a.Config.NodeName+`\s+release\s+-\s+serfHealth\s+1`, output)
+		require.Regexp(other+`\s+-\s+other\s+release\s+30s\s+-\s+0`, output)
+	}
+
+	// Filter by node.
+	{
+		ui := cli.NewMockUi()
+		c := New(ui)
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-node=other",
+		}
+		require.Equal(0, c.Run(args), ui.ErrorWriter.String())
+
+		output := ui.OutputWriter.String()
+		require.Contains(output, other)
+		require.NotContains(output, id)
+	}
+}
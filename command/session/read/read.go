@@ -0,0 +1,106 @@
+package read
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("Error: command requires exactly one argument: session ID")
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	session, _, err := client.Session().Info(args[0], nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading session: %s", err))
+		return 1
+	}
+	if session == nil {
+		c.UI.Error(fmt.Sprintf("Error: session %q not found", args[0]))
+		return 1
+	}
+
+	keys, _, err := client.Session().Keys(session.ID, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error listing the keys of the session: %s", err))
+		return 1
+	}
+
+	c.UI.Output(columnize.SimpleFormat([]string{
+		fmt.Sprintf("ID:|%s", session.ID),
+		fmt.Sprintf("Name:|%s", orNone(session.Name)),
+		fmt.Sprintf("Node:|%s", session.Node),
+		fmt.Sprintf("Checks:|%s", orNone(strings.Join(session.Checks, ", "))),
+		fmt.Sprintf("Behavior:|%s", session.Behavior),
+		fmt.Sprintf("Lock Delay:|%s", session.LockDelay),
+		fmt.Sprintf("TTL:|%s", orNone(session.TTL)),
+		fmt.Sprintf("Create Index:|%d", session.CreateIndex),
+		fmt.Sprintf("Keys:|%s", orNone(strings.Join(keys, ", "))),
+	}))
+	return 0
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Read a session"
+const help = `
+Usage: consul session read [options] ID
+
+  Show the details of the session with the given ID, including the health
+  checks it's tied to and the keys it holds locks on.
+
+      $ consul session read 4ca8e74b-6350-7587-addf-a18084928f3c
+
+`
@@ -0,0 +1,81 @@
+package read
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	require.Equal(t, 1, c.Run(nil))
+	require.Contains(t, ui.ErrorWriter.String(), "requires exactly one")
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	client := a.Client()
+
+	id, _, err := client.Session().Create(&api.SessionEntry{Name: "locker"}, nil)
+	require.NoError(err)
+	for _, key := range []string{"service/b", "service/a"} {
+		ok, _, err := client.KV().Acquire(&api.KVPair{Key: key, Session: id}, nil)
+		require.NoError(err)
+		require.True(ok)
+	}
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		id,
+	}
+	require.Equal(0, c.Run(args), ui.ErrorWriter.String())
+
+	output := ui.OutputWriter.String()
+	require.Regexp(`ID:\s+`+id, output)
+	require.Regexp(`Name:\s+locker`, output)
+	require.Regexp(`Checks:\s+serfHealth`, output)
+	require.Regexp(`Lock Delay:\s+15s`, output)
+	require.Regexp(`TTL:\s+\(none\)`, output)
+	require.Regexp(`Keys:\s+service/a, service/b`, output)
+}
+
+func TestCommand_notFound(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"a7a4f1f4-7c2e-4c7c-9e4b-5b0f7e5d2a35",
+	}
+	require.Equal(1, c.Run(args))
+	require.Contains(ui.ErrorWriter.String(), "not found")
+}
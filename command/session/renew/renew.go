@@ -0,0 +1,86 @@
+package renew
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("Error: command requires exactly one argument: session ID")
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	session, _, err := client.Session().Renew(args[0], nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error renewing session: %s", err))
+		return 1
+	}
+	if session == nil {
+		c.UI.Error(fmt.Sprintf("Error: session %q not found", args[0]))
+		return 1
+	}
+
+	if session.TTL == "" {
+		c.UI.Output("Session renewed, but it has no TTL.")
+	} else {
+		c.UI.Output(fmt.Sprintf("Session renewed with a TTL of %s.", session.TTL))
+	}
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Renew a session"
+const help = `
+Usage: consul session renew [options] ID
+
+  Renew the TTL of the session with the given ID. The servers may increase
+  the TTL to limit the rate of renewals, and the TTL that is in effect is
+  printed.
+
+      $ consul session renew 4ca8e74b-6350-7587-addf-a18084928f3c
+
+`
@@ -0,0 +1,52 @@
+package session
+
+import (
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New() *cmd {
+	return &cmd{}
+}
+
+type cmd struct{}
+
+func (c *cmd) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(help, nil)
+}
+
+const synopsis = "Interact with sessions"
+const help = `
+Usage: consul session <subcommand> [options] [args]
+
+  This command has subcommands for interacting with sessions, which are used
+  to build locks and leader election on top of the KV store. Here are some
+  simple examples, and more detailed examples are available in the
+  subcommands or the documentation.
+
+  List all sessions and the number of keys they hold locks on:
+
+      $ consul session list
+
+  List the sessions of a node:
+
+      $ consul session list -node=web1
+
+  Show the details of a session:
+
+      $ consul session read 4ca8e74b-6350-7587-addf-a18084928f3c
+
+  Destroy a session, which releases its locks:
+
+      $ consul session destroy 4ca8e74b-6350-7587-addf-a18084928f3c
+
+  For more examples, ask for subcommand help or view the documentation.
+`
@@ -40,6 +40,11 @@ type cmd struct {
 	passingOnly string
 	state       string
 	name        string
+	node        string
+	source      string
+	destination string
+	query       string
+	interval    string
 	shell       bool
 }
 
@@ -47,7 +52,8 @@ func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
 	c.flags.StringVar(&c.watchType, "type", "",
 		"Specifies the watch type. One of key, keyprefix, services, nodes, "+
-			"service, checks, or event.")
+			"service, checks, event, intentions, sessions, prepared_query, "+
+			"or coordinates.")
 	c.flags.StringVar(&c.key, "key", "",
 		"Specifies the key to watch. Only for 'key' type.")
 	c.flags.StringVar(&c.prefix, "prefix", "",
@@ -67,6 +73,20 @@ func (c *cmd) init() {
 		"Specifies the states to watch. Optional for 'checks' type.")
 	c.flags.StringVar(&c.name, "name", "",
 		"Specifies an event name to watch. Only for 'event' type.")
+	c.flags.StringVar(&c.node, "node", "",
+		"Specifies the node to watch. Optional for 'sessions' and 'coordinates' types.")
+	c.flags.StringVar(&c.source, "source", "",
+		"Specifies the source service to watch intentions for. Optional for "+
+			"'intentions' type.")
+	c.flags.StringVar(&c.destination, "destination", "",
+		"Specifies the destination service to watch intentions for. Optional "+
+			"for 'intentions' type.")
+	c.flags.StringVar(&c.query, "query", "",
+		"Specifies the ID or name of the prepared query to watch. Required for "+
+			"'prepared_query' type.")
+	c.flags.StringVar(&c.interval, "interval", "",
+		"Specifies how often to execute the prepared query, such as \"30s\". "+
+			"Optional for 'prepared_query' type. Defaults to 10s.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -119,6 +139,21 @@ func (c *cmd) Run(args []string) int {
 	if c.name != "" {
 		params["name"] = c.name
 	}
+	if c.node != "" {
+		params["node"] = c.node
+	}
+	if c.source != "" {
+		params["source"] = c.source
+	}
+	if c.destination != "" {
+		params["destination"] = c.destination
+	}
+	if c.query != "" {
+		params["query"] = c.query
+	}
+	if c.interval != "" {
+		params["interval"] = c.interval
+	}
 	if c.passingOnly != "" {
 		b, err := strconv.ParseBool(c.passingOnly)
 		if err != nil {
@@ -0,0 +1,329 @@
+package proxy
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/consul/connect"
+)
+
+const (
+	// BalancePolicyRandom picks a healthy instance at random for each
+	// connection. This is the default.
+	BalancePolicyRandom = "random"
+
+	// BalancePolicyRoundRobin cycles through the healthy instances.
+	BalancePolicyRoundRobin = "round_robin"
+
+	// BalancePolicyLeastConn picks the instance with the fewest connections
+	// currently open through this upstream.
+	BalancePolicyLeastConn = "least_conn"
+
+	// BalancePolicyRTTNearest picks the instance with the lowest estimated
+	// round trip time from the local agent, based on network coordinates.
+	BalancePolicyRTTNearest = "rtt_nearest"
+)
+
+// instanceCacheTTL is how long the instances resolved for an upstream in HTTP
+// mode are reused for before they're resolved again. Requests are balanced
+// individually, so without this every request would need a lookup through the
+// local agent.
+const instanceCacheTTL = 5 * time.Second
+
+// upstreamBalancer chooses which instance each upstream connection is made to
+// and passively tracks dial and handshake failures so that instances failing
+// repeatedly are ejected for a while.
+type upstreamBalancer struct {
+	policy       string
+	maxFailures  int
+	ejectionTime time.Duration
+
+	logger       *log.Logger
+	metricLabels []metrics.Label
+
+	// now is used in place of time.Now so tests can control ejection expiry.
+	now func() time.Time
+
+	lock      sync.Mutex
+	next      int
+	instances map[string]*instanceState
+
+	// cached holds the instances last resolved by resolveCached, which are
+	// reused until cachedUntil. refreshLock is held while they're being
+	// resolved again so that concurrent requests share a single lookup.
+	refreshLock sync.Mutex
+	cached      []*connect.StaticResolver
+	cachedUntil time.Time
+}
+
+// instanceState is what the balancer knows about a single instance, keyed by
+// its address.
+type instanceState struct {
+	activeConns  int
+	failures     int
+	ejectedUntil time.Time
+}
+
+func newUpstreamBalancer(cfg UpstreamConfig, logger *log.Logger,
+	metricLabels []metrics.Label) *upstreamBalancer {
+	return &upstreamBalancer{
+		policy:       cfg.BalancePolicy(),
+		maxFailures:  cfg.OutlierMaxFailures(),
+		ejectionTime: cfg.OutlierEjectionTime(),
+		logger:       logger,
+		metricLabels: metricLabels,
+		now:          time.Now,
+		instances:    make(map[string]*instanceState),
+	}
+}
+
+// dial connects to an instance chosen from the resolver.
+func (b *upstreamBalancer) dial(ctx context.Context, svc *connect.Service,
+	resolver connect.Resolver) (net.Conn, error) {
+	inst, err := b.resolve(ctx, resolver)
+	if err != nil {
+		return nil, err
+	}
+	return b.dialInstance(ctx, svc, inst)
+}
+
+// resolve chooses an instance from the resolver. Resolvers that can't list
+// their instances choose one themselves, but outlier detection is still
+// applied to the connections made to it.
+func (b *upstreamBalancer) resolve(ctx context.Context,
+	resolver connect.Resolver) (*connect.StaticResolver, error) {
+	ir, ok := resolver.(connect.InstanceResolver)
+	if !ok {
+		addr, certURI, err := resolver.Resolve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &connect.StaticResolver{Addr: addr, CertURI: certURI}, nil
+	}
+
+	candidates, err := ir.ResolveInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return b.pick(candidates), nil
+}
+
+// resolveCached chooses an instance like resolve does, but reuses the
+// instances from the last lookup until they expire or a failure is recorded.
+// Resolvers that can't list their instances choose one themselves each time,
+// so there is nothing to cache for them.
+func (b *upstreamBalancer) resolveCached(ctx context.Context,
+	resolver connect.Resolver) (*connect.StaticResolver, error) {
+	ir, ok := resolver.(connect.InstanceResolver)
+	if !ok {
+		return b.resolve(ctx, resolver)
+	}
+
+	candidates, err := b.cachedInstances(ctx, ir)
+	if err != nil {
+		return nil, err
+	}
+	return b.pick(candidates), nil
+}
+
+// cachedInstances returns the cached instances, resolving them again if they
+// have expired.
+func (b *upstreamBalancer) cachedInstances(ctx context.Context,
+	resolver connect.InstanceResolver) ([]*connect.StaticResolver, error) {
+	if candidates, ok := b.freshInstances(); ok {
+		return candidates, nil
+	}
+
+	b.refreshLock.Lock()
+	defer b.refreshLock.Unlock()
+
+	// Another request may have resolved them while this one was waiting.
+	if candidates, ok := b.freshInstances(); ok {
+		return candidates, nil
+	}
+
+	candidates, err := resolver.ResolveInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.cached = candidates
+	b.cachedUntil = b.now().Add(instanceCacheTTL)
+	return candidates, nil
+}
+
+// freshInstances returns the cached instances and whether they can still be
+// used.
+func (b *upstreamBalancer) freshInstances() ([]*connect.StaticResolver, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if len(b.cached) == 0 || !b.now().Before(b.cachedUntil) {
+		return nil, false
+	}
+	return b.cached, true
+}
+
+// invalidate makes the next call to resolveCached resolve the instances
+// again, so that a failing instance is replaced as soon as the catalog
+// reflects it.
+func (b *upstreamBalancer) invalidate() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.cachedUntil = time.Time{}
+}
+
+// dialInstance connects to the given instance, recording the outcome so that
+// failing instances are ejected and open connections are counted.
+func (b *upstreamBalancer) dialInstance(ctx context.Context, svc *connect.Service,
+	inst *connect.StaticResolver) (net.Conn, error) {
+	conn, err := svc.Dial(ctx, inst)
+	if err != nil {
+		b.recordFailure(inst.Addr)
+		return nil, err
+	}
+	return &balancedConn{Conn: conn, release: b.recordSuccess(inst.Addr)}, nil
+}
+
+// pick chooses one of the candidates according to the policy, skipping any
+// that are currently ejected. If every candidate is ejected they are all
+// considered anyway since trying something is better than failing outright.
+func (b *upstreamBalancer) pick(candidates []*connect.StaticResolver) *connect.StaticResolver {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.pruneLocked(candidates)
+
+	now := b.now()
+	healthy := make([]*connect.StaticResolver, 0, len(candidates))
+	for _, c := range candidates {
+		if s, ok := b.instances[c.Addr]; ok && now.Before(s.ejectedUntil) {
+			continue
+		}
+		healthy = append(healthy, c)
+	}
+	if len(healthy) == 0 {
+		healthy = candidates
+	}
+
+	switch b.policy {
+	case BalancePolicyRoundRobin:
+		idx := b.next % len(healthy)
+		b.next++
+		return healthy[idx]
+
+	case BalancePolicyLeastConn:
+		// Break ties at random so that idle instances share new connections.
+		var least []*connect.StaticResolver
+		min := -1
+		for _, c := range healthy {
+			n := 0
+			if s, ok := b.instances[c.Addr]; ok {
+				n = s.activeConns
+			}
+			switch {
+			case min == -1 || n < min:
+				min = n
+				least = []*connect.StaticResolver{c}
+			case n == min:
+				least = append(least, c)
+			}
+		}
+		return least[rand.Intn(len(least))]
+
+	case BalancePolicyRTTNearest:
+		// The resolver already returned the instances nearest first.
+		return healthy[0]
+
+	default:
+		return healthy[rand.Intn(len(healthy))]
+	}
+}
+
+// pruneLocked forgets instances that are no longer candidates and have no
+// open connections. The lock must be held.
+func (b *upstreamBalancer) pruneLocked(candidates []*connect.StaticResolver) {
+	current := make(map[string]struct{}, len(candidates))
+	for _, c := range candidates {
+		current[c.Addr] = struct{}{}
+	}
+	for addr, s := range b.instances {
+		if _, ok := current[addr]; !ok && s.activeConns == 0 {
+			delete(b.instances, addr)
+		}
+	}
+}
+
+// stateLocked returns the state for an instance, creating it if needed. The
+// lock must be held.
+func (b *upstreamBalancer) stateLocked(addr string) *instanceState {
+	s, ok := b.instances[addr]
+	if !ok {
+		s = &instanceState{}
+		b.instances[addr] = s
+	}
+	return s
+}
+
+// recordFailure counts a failed dial to an instance and ejects it once it has
+// failed too many times in a row.
+func (b *upstreamBalancer) recordFailure(addr string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	// Whatever was wrong with the instance may also have changed what the
+	// catalog returns, so don't keep using a cached lookup.
+	b.cachedUntil = time.Time{}
+
+	s := b.stateLocked(addr)
+	s.failures++
+	if b.maxFailures <= 0 || s.failures < b.maxFailures {
+		return
+	}
+
+	s.failures = 0
+	s.ejectedUntil = b.now().Add(b.ejectionTime)
+	b.logger.Printf("[WARN] ejecting upstream instance %s for %s after %d "+
+		"consecutive failures", addr, b.ejectionTime, b.maxFailures)
+	metrics.IncrCounterWithLabels([]string{upstreamMetricPrefix, "ejections"}, 1,
+		b.metricLabels)
+}
+
+// recordSuccess resets the failure count for an instance and tracks the new
+// connection. It returns a func that must be called when the connection
+// closes.
+func (b *upstreamBalancer) recordSuccess(addr string) func() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	s := b.stateLocked(addr)
+	s.failures = 0
+	s.ejectedUntil = time.Time{}
+	s.activeConns++
+
+	return func() {
+		b.lock.Lock()
+		defer b.lock.Unlock()
+		s.activeConns--
+	}
+}
+
+// balancedConn is a net.Conn that tells the balancer when it closes.
+type balancedConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+// Close closes the connection and releases it from the balancer.
+func (c *balancedConn) Close() error {
+	c.once.Do(c.release)
+	return c.Conn.Close()
+}
@@ -0,0 +1,238 @@
+package proxy
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	agConnect "github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/connect"
+	"github.com/hashicorp/consul/sdk/freeport"
+)
+
+// testInstanceResolver is an InstanceResolver that returns a fixed list of
+// instances.
+type testInstanceResolver []*connect.StaticResolver
+
+func (r testInstanceResolver) Resolve(ctx context.Context) (string, agConnect.CertURI, error) {
+	return r[0].Resolve(ctx)
+}
+
+func (r testInstanceResolver) ResolveInstances(ctx context.Context) ([]*connect.StaticResolver, error) {
+	return r, nil
+}
+
+// countingResolver is an InstanceResolver that counts how many times its
+// instances were resolved.
+type countingResolver struct {
+	testInstanceResolver
+	count int
+}
+
+func (r *countingResolver) ResolveInstances(ctx context.Context) ([]*connect.StaticResolver, error) {
+	r.count++
+	return r.testInstanceResolver, nil
+}
+
+func testBalancer(t *testing.T, config map[string]interface{}) *upstreamBalancer {
+	cfg := UpstreamConfig{Config: config}
+	return newUpstreamBalancer(cfg, log.New(os.Stderr, "", log.LstdFlags), nil)
+}
+
+func testInstances(addrs ...string) []*connect.StaticResolver {
+	var instances []*connect.StaticResolver
+	for _, addr := range addrs {
+		instances = append(instances, &connect.StaticResolver{Addr: addr})
+	}
+	return instances
+}
+
+func pickAddrs(b *upstreamBalancer, candidates []*connect.StaticResolver, n int) []string {
+	var addrs []string
+	for i := 0; i < n; i++ {
+		addrs = append(addrs, b.pick(candidates).Addr)
+	}
+	return addrs
+}
+
+func TestUpstreamBalancer_Pick(t *testing.T) {
+	t.Parallel()
+
+	candidates := testInstances("a:1", "b:1", "c:1")
+
+	t.Run("random", func(t *testing.T) {
+		b := testBalancer(t, nil)
+		for _, addr := range pickAddrs(b, candidates, 10) {
+			require.Contains(t, []string{"a:1", "b:1", "c:1"}, addr)
+		}
+	})
+
+	t.Run("round_robin", func(t *testing.T) {
+		b := testBalancer(t, map[string]interface{}{"balance_policy": "round_robin"})
+		require.Equal(t, []string{"a:1", "b:1", "c:1", "a:1"}, pickAddrs(b, candidates, 4))
+	})
+
+	t.Run("rtt_nearest", func(t *testing.T) {
+		b := testBalancer(t, map[string]interface{}{"balance_policy": "rtt_nearest"})
+		require.Equal(t, []string{"a:1", "a:1"}, pickAddrs(b, candidates, 2))
+	})
+
+	t.Run("least_conn", func(t *testing.T) {
+		b := testBalancer(t, map[string]interface{}{"balance_policy": "least_conn"})
+		releaseA := b.recordSuccess("a:1")
+		b.recordSuccess("b:1")
+		require.Equal(t, []string{"c:1", "c:1"}, pickAddrs(b, candidates, 2))
+
+		b.recordSuccess("c:1")
+		b.recordSuccess("c:1")
+		releaseA()
+		require.Equal(t, []string{"a:1"}, pickAddrs(b, candidates, 1))
+	})
+}
+
+func TestUpstreamBalancer_Outliers(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	b := testBalancer(t, map[string]interface{}{
+		"balance_policy":           "rtt_nearest",
+		"outlier_max_failures":     2,
+		"outlier_ejection_time_ms": 10000,
+	})
+	b.now = func() time.Time { return now }
+	candidates := testInstances("a:1", "b:1")
+
+	// A single failure isn't enough to eject.
+	b.recordFailure("a:1")
+	require.Equal(t, "a:1", b.pick(candidates).Addr)
+
+	// A success resets the count.
+	b.recordSuccess("a:1")()
+	b.recordFailure("a:1")
+	require.Equal(t, "a:1", b.pick(candidates).Addr)
+
+	// Consecutive failures eject the instance.
+	b.recordFailure("a:1")
+	require.Equal(t, "b:1", b.pick(candidates).Addr)
+
+	// With every instance ejected, they are all considered again.
+	b.recordFailure("b:1")
+	b.recordFailure("b:1")
+	require.Equal(t, "a:1", b.pick(candidates).Addr)
+
+	// Ejections expire.
+	b.recordSuccess("b:1")()
+	require.Equal(t, "b:1", b.pick(candidates).Addr)
+	now = now.Add(11 * time.Second)
+	require.Equal(t, "a:1", b.pick(candidates).Addr)
+}
+
+func TestUpstreamBalancer_OutliersDisabled(t *testing.T) {
+	t.Parallel()
+
+	b := testBalancer(t, map[string]interface{}{
+		"balance_policy":       "rtt_nearest",
+		"outlier_max_failures": 0,
+	})
+	candidates := testInstances("a:1", "b:1")
+	for i := 0; i < 10; i++ {
+		b.recordFailure("a:1")
+	}
+	require.Equal(t, "a:1", b.pick(candidates).Addr)
+}
+
+func TestUpstreamBalancer_ResolveCached(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	b := testBalancer(t, map[string]interface{}{
+		"balance_policy": "round_robin",
+	})
+	b.now = func() time.Time { return now }
+	resolver := &countingResolver{testInstanceResolver: testInstances("a:1", "b:1")}
+	ctx := context.Background()
+
+	// Requests are still balanced across instances from a single lookup.
+	var addrs []string
+	for i := 0; i < 4; i++ {
+		inst, err := b.resolveCached(ctx, resolver)
+		require.NoError(t, err)
+		addrs = append(addrs, inst.Addr)
+	}
+	require.Equal(t, []string{"a:1", "b:1", "a:1", "b:1"}, addrs)
+	require.Equal(t, 1, resolver.count)
+
+	// The instances are resolved again once they expire.
+	now = now.Add(instanceCacheTTL)
+	_, err := b.resolveCached(ctx, resolver)
+	require.NoError(t, err)
+	require.Equal(t, 2, resolver.count)
+
+	// A failure means they are resolved again straight away.
+	b.recordFailure("a:1")
+	_, err = b.resolveCached(ctx, resolver)
+	require.NoError(t, err)
+	require.Equal(t, 3, resolver.count)
+
+	b.invalidate()
+	_, err = b.resolveCached(ctx, resolver)
+	require.NoError(t, err)
+	require.Equal(t, 4, resolver.count)
+}
+
+func TestUpstreamBalancer_Dial(t *testing.T) {
+	t.Parallel()
+
+	ca := agConnect.TestCA(t, nil)
+	ports := freeport.GetT(t, 1)
+
+	testSvr := connect.NewTestServer(t, "db", ca)
+	go func() {
+		err := testSvr.Serve()
+		require.NoError(t, err)
+	}()
+	defer testSvr.Close()
+	<-testSvr.Listening
+
+	svc := connect.TestService(t, "web", ca)
+	defer svc.Close()
+
+	// Nothing listens on the first instance so dialing it fails.
+	deadAddr := TestLocalAddr(ports[0])
+	resolver := testInstanceResolver{
+		{Addr: deadAddr, CertURI: agConnect.TestSpiffeIDService(t, "db")},
+		{Addr: testSvr.Addr, CertURI: agConnect.TestSpiffeIDService(t, "db")},
+	}
+
+	b := testBalancer(t, map[string]interface{}{
+		"balance_policy":       "rtt_nearest",
+		"outlier_max_failures": 1,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := b.dial(ctx, svc, resolver)
+	require.Error(t, err)
+
+	// The dead instance is now ejected so the next dial goes to the live one.
+	conn, err := b.dial(ctx, svc, resolver)
+	require.NoError(t, err)
+	TestEchoConn(t, conn, "")
+
+	b.lock.Lock()
+	require.Equal(t, 1, b.instances[testSvr.Addr].activeConns)
+	b.lock.Unlock()
+
+	// Closing more than once must only release the conn once.
+	require.NoError(t, conn.Close())
+	conn.Close()
+
+	b.lock.Lock()
+	require.Equal(t, 0, b.instances[testSvr.Addr].activeConns)
+	b.lock.Unlock()
+}
@@ -69,6 +69,16 @@ type PublicListenerConfig struct {
 	// handshake. Setting this low avoids DOS by malicious clients holding
 	// resources open. Defaults to 10000 (10s).
 	HandshakeTimeoutMs int `json:"handshake_timeout_ms" hcl:"handshake_timeout_ms" mapstructure:"handshake_timeout_ms"`
+
+	// Protocol is the protocol spoken by the local application. "tcp" proxies
+	// raw bytes while "http" parses each request so that timeouts and request
+	// metrics can be applied. Defaults to "tcp".
+	Protocol string `json:"protocol" hcl:"protocol" mapstructure:"protocol"`
+
+	// LocalRequestTimeoutMs is the timeout for the local application to
+	// respond to a request when Protocol is "http". Defaults to 0 which means
+	// no timeout.
+	LocalRequestTimeoutMs int `json:"local_request_timeout_ms" hcl:"local_request_timeout_ms" mapstructure:"local_request_timeout_ms"`
 }
 
 // applyDefaults sets zero-valued params to a sane default.
@@ -97,6 +107,78 @@ func (uc *UpstreamConfig) ConnectTimeout() time.Duration {
 	return 10000 * time.Millisecond
 }
 
+// Protocol returns the protocol field of the nested config struct or the
+// default "tcp". Unknown protocols are also treated as "tcp".
+func (uc *UpstreamConfig) Protocol() string {
+	if p, _ := uc.Config["protocol"].(string); p == ProtocolHTTP {
+		return ProtocolHTTP
+	}
+	return ProtocolTCP
+}
+
+// RequestTimeout returns the per-request timeout field of the nested config
+// struct, only used for the "http" protocol. Zero means no timeout.
+func (uc *UpstreamConfig) RequestTimeout() time.Duration {
+	if ms, ok := configInt(uc.Config, "request_timeout_ms"); ok {
+		return time.Duration(ms) * time.Millisecond
+	}
+	return 0
+}
+
+// MaxRetries returns how many times a failed idempotent request is retried
+// against another instance, only used for the "http" protocol. Defaults to 0.
+func (uc *UpstreamConfig) MaxRetries() int {
+	if n, ok := configInt(uc.Config, "max_retries"); ok && n > 0 {
+		return n
+	}
+	return 0
+}
+
+// BalancePolicy returns the load balancing policy field of the nested config
+// struct or the default random policy. Unknown policies also fall back to
+// random.
+func (uc *UpstreamConfig) BalancePolicy() string {
+	policy, _ := uc.Config["balance_policy"].(string)
+	switch policy {
+	case BalancePolicyRoundRobin, BalancePolicyLeastConn, BalancePolicyRTTNearest:
+		return policy
+	default:
+		return BalancePolicyRandom
+	}
+}
+
+// OutlierMaxFailures returns the number of consecutive dial or handshake
+// failures after which an instance is ejected, from the nested config struct
+// or the default value. Zero or less disables outlier detection.
+func (uc *UpstreamConfig) OutlierMaxFailures() int {
+	if n, ok := configInt(uc.Config, "outlier_max_failures"); ok {
+		return n
+	}
+	return 5
+}
+
+// OutlierEjectionTime returns how long an outlier is ejected for, from the
+// nested config struct or the default value.
+func (uc *UpstreamConfig) OutlierEjectionTime() time.Duration {
+	if ms, ok := configInt(uc.Config, "outlier_ejection_time_ms"); ok {
+		return time.Duration(ms) * time.Millisecond
+	}
+	return 30000 * time.Millisecond
+}
+
+// configInt returns an integer from an opaque config map. Values that arrive
+// via JSON are decoded as float64 so those are accepted too.
+func configInt(m map[string]interface{}, key string) (int, bool) {
+	switch v := m[key].(type) {
+	case int:
+		return v, true
+	case float64:
+		return int(v), true
+	default:
+		return 0, false
+	}
+}
+
 // applyDefaults sets zero-valued params to a sane default.
 func (uc *UpstreamConfig) applyDefaults() {
 	if uc.DestinationType == "" {
@@ -130,13 +212,18 @@ func UpstreamResolverFuncFromClient(client *api.Client) func(cfg UpstreamConfig)
 		if cfg.DestinationType == "prepared_query" {
 			typ = connect.ConsulResolverTypePreparedQuery
 		}
-		return &connect.ConsulResolver{
+		r := &connect.ConsulResolver{
 			Client:     client,
 			Namespace:  cfg.DestinationNamespace,
 			Name:       cfg.DestinationName,
 			Type:       typ,
 			Datacenter: cfg.Datacenter,
-		}, nil
+		}
+		if cfg.BalancePolicy() == BalancePolicyRTTNearest {
+			// Have the catalog sort instances by round trip time from this agent.
+			r.Near = "_agent"
+		}
+		return r, nil
 	}
 }
 
@@ -52,6 +52,25 @@ func TestUpstreamResolverFuncFromClient(t *testing.T) {
 				Type:       connect.ConsulResolverTypePreparedQuery,
 			},
 		},
+		{
+			name: "rtt_nearest sorts by agent",
+			cfg: UpstreamConfig{
+				DestinationNamespace: "foo",
+				DestinationName:      "web",
+				Datacenter:           "ny1",
+				DestinationType:      "service",
+				Config: map[string]interface{}{
+					"balance_policy": "rtt_nearest",
+				},
+			},
+			want: &connect.ConsulResolver{
+				Namespace:  "foo",
+				Name:       "web",
+				Datacenter: "ny1",
+				Type:       connect.ConsulResolverTypeService,
+				Near:       "_agent",
+			},
+		},
 		{
 			name: "unknown behaves like service",
 			cfg: UpstreamConfig{
@@ -79,6 +98,64 @@ func TestUpstreamResolverFuncFromClient(t *testing.T) {
 	}
 }
 
+func TestUpstreamConfig_Balancing(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		config       map[string]interface{}
+		policy       string
+		maxFailures  int
+		ejectionTime time.Duration
+	}{
+		{
+			name:         "defaults",
+			policy:       BalancePolicyRandom,
+			maxFailures:  5,
+			ejectionTime: 30 * time.Second,
+		},
+		{
+			name: "configured",
+			config: map[string]interface{}{
+				"balance_policy":           "least_conn",
+				"outlier_max_failures":     2,
+				"outlier_ejection_time_ms": 500,
+			},
+			policy:       BalancePolicyLeastConn,
+			maxFailures:  2,
+			ejectionTime: 500 * time.Millisecond,
+		},
+		{
+			name: "decoded from JSON",
+			config: map[string]interface{}{
+				"balance_policy":           "round_robin",
+				"outlier_max_failures":     float64(0),
+				"outlier_ejection_time_ms": float64(1000),
+			},
+			policy:       BalancePolicyRoundRobin,
+			maxFailures:  0,
+			ejectionTime: time.Second,
+		},
+		{
+			name: "unknown policy",
+			config: map[string]interface{}{
+				"balance_policy": "fastest",
+			},
+			policy:       BalancePolicyRandom,
+			maxFailures:  5,
+			ejectionTime: 30 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := UpstreamConfig{Config: tt.config}
+			require.Equal(t, tt.policy, uc.BalancePolicy())
+			require.Equal(t, tt.maxFailures, uc.OutlierMaxFailures())
+			require.Equal(t, tt.ejectionTime, uc.OutlierEjectionTime())
+		})
+	}
+}
+
 func TestAgentConfigWatcherManagedProxy(t *testing.T) {
 	t.Parallel()
 
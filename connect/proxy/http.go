@@ -0,0 +1,363 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"strconv"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/consul/connect"
+)
+
+const (
+	// ProtocolTCP proxies connections as raw bytes. This is the default.
+	ProtocolTCP = "tcp"
+
+	// ProtocolHTTP parses HTTP/1.1 requests so that per-request timeouts,
+	// retries, load balancing and metrics can be applied.
+	ProtocolHTTP = "http"
+)
+
+const (
+	// httpMaxIdleConnsPerHost is the number of keep-alive connections kept
+	// open to each backend, whether that's the local application or a single
+	// upstream instance.
+	httpMaxIdleConnsPerHost = 32
+
+	// httpIdleConnTimeout is how long an idle keep-alive connection to a
+	// backend is kept open.
+	httpIdleConnTimeout = 90 * time.Second
+
+	// httpReadHeaderTimeout is how long a listener in HTTP mode waits for a
+	// client to send the headers of a request, so that slow or stalled clients
+	// can't hold connections open.
+	httpReadHeaderTimeout = 10 * time.Second
+
+	// httpServerIdleTimeout is how long a listener in HTTP mode keeps an idle
+	// keep-alive connection from a client open.
+	httpServerIdleTimeout = 90 * time.Second
+)
+
+// httpProxy is the http.Handler used by listeners in HTTP mode. It forwards
+// each request to its transport and records request metrics.
+type httpProxy struct {
+	proxy        *httputil.ReverseProxy
+	timeout      time.Duration
+	metricPrefix string
+	metricLabels []metrics.Label
+}
+
+// newHTTPProxy returns an httpProxy that sends requests to host using the
+// given transport. A zero timeout means requests never time out.
+func newHTTPProxy(scheme, host string, transport http.RoundTripper,
+	timeout time.Duration, logger *log.Logger, metricPrefix string,
+	metricLabels []metrics.Label) *httpProxy {
+	return &httpProxy{
+		proxy: &httputil.ReverseProxy{
+			Director: func(req *http.Request) {
+				req.URL.Scheme = scheme
+				req.URL.Host = host
+			},
+			Transport: transport,
+			ErrorLog:  logger,
+			ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+				code := http.StatusBadGateway
+				if req.Context().Err() == context.DeadlineExceeded {
+					code = http.StatusGatewayTimeout
+				}
+				logger.Printf("[ERR] failed to proxy request %s %s: %s",
+					req.Method, req.URL.Path, err)
+				w.WriteHeader(code)
+			},
+		},
+		timeout:      timeout,
+		metricPrefix: metricPrefix,
+		metricLabels: metricLabels,
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (p *httpProxy) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+	if p.timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), p.timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	rw := &statusResponseWriter{ResponseWriter: w, code: http.StatusOK}
+	p.proxy.ServeHTTP(rw, req)
+
+	labels := make([]metrics.Label, 0, len(p.metricLabels)+1)
+	labels = append(labels, p.metricLabels...)
+	labels = append(labels, metrics.Label{Name: "code", Value: strconv.Itoa(rw.code)})
+	metrics.IncrCounterWithLabels([]string{p.metricPrefix, "http", "requests"}, 1,
+		labels)
+	metrics.MeasureSinceWithLabels([]string{p.metricPrefix, "http", "request_time"},
+		start, labels)
+}
+
+// statusResponseWriter records the status code written to a response. It
+// passes through flushing and hijacking so that streamed responses and
+// protocol upgrades still work.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	code        int
+	wroteHeader bool
+}
+
+func (w *statusResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.code = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *statusResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	return h.Hijack()
+}
+
+// newHTTPTransport returns the transport used to pool keep-alive connections
+// to backends. HTTP/2 is deliberately disabled: Connect's TLS config offers h2
+// via ALPN even when the other end is a TCP mode proxy in front of an HTTP/1.1
+// application, so HTTP/1.1 is the only safe choice.
+func newHTTPTransport() *http.Transport {
+	return &http.Transport{
+		TLSNextProto:        map[string]func(string, *tls.Conn) http.RoundTripper{},
+		MaxIdleConnsPerHost: httpMaxIdleConnsPerHost,
+		IdleConnTimeout:     httpIdleConnTimeout,
+	}
+}
+
+// http1TLSConfig returns a copy of a Connect server TLS config that doesn't
+// take part in ALPN. Connect configs offer h2 so that Connect-native HTTP
+// servers get HTTP/2 for free, but a listener in HTTP mode only speaks
+// HTTP/1.1 and would otherwise drop clients that negotiated h2. Clients that
+// only offer h2 are still accepted since no protocol is negotiated at all.
+func http1TLSConfig(cfg *tls.Config) *tls.Config {
+	cfg = cfg.Clone()
+	cfg.NextProtos = nil
+	if getConfig := cfg.GetConfigForClient; getConfig != nil {
+		cfg.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			c, err := getConfig(hello)
+			if err != nil || c == nil {
+				return c, err
+			}
+			c = c.Clone()
+			c.NextProtos = nil
+			return c, nil
+		}
+	}
+	return cfg
+}
+
+// upstreamTransport is the http.RoundTripper for upstream listeners in HTTP
+// mode. It chooses an upstream instance for each request, rather than for each
+// connection, and retries failed idempotent requests. The instances to choose
+// from are cached by the balancer and only resolved again once they expire or
+// a request fails.
+type upstreamTransport struct {
+	svc          *connect.Service
+	cfg          UpstreamConfig
+	resolverFunc func(UpstreamConfig) (connect.Resolver, error)
+	balancer     *upstreamBalancer
+	metricLabels []metrics.Label
+
+	// base pools connections per instance since each request's URL host is
+	// set to the chosen instance's address.
+	base *http.Transport
+
+	// instances maps the addresses of in-flight requests back to the
+	// instance so that base can dial them. Entries are removed once no
+	// request is using them so that instances that are no longer resolved
+	// don't accumulate.
+	lock      sync.Mutex
+	instances map[string]*pendingInstance
+
+	// rf is the resolver made by resolverFunc, which is kept so that the
+	// balancer can reuse the instances it resolved.
+	rf connect.Resolver
+}
+
+// pendingInstance is an upstream instance along with the number of in-flight
+// requests that were sent to it.
+type pendingInstance struct {
+	inst *connect.StaticResolver
+	refs int
+}
+
+func newUpstreamTransport(svc *connect.Service, cfg UpstreamConfig,
+	resolverFunc func(UpstreamConfig) (connect.Resolver, error),
+	balancer *upstreamBalancer, metricLabels []metrics.Label) *upstreamTransport {
+	t := &upstreamTransport{
+		svc:          svc,
+		cfg:          cfg,
+		resolverFunc: resolverFunc,
+		balancer:     balancer,
+		metricLabels: metricLabels,
+		base:         newHTTPTransport(),
+		instances:    make(map[string]*pendingInstance),
+	}
+	// The connections returned are already TLS so DialTLS stops the transport
+	// from trying to do its own handshake.
+	t.base.DialTLS = t.dialTLS
+	return t
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *upstreamTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	retries := t.cfg.MaxRetries()
+	for attempt := 0; ; attempt++ {
+		resp, err := t.roundTripOnce(req)
+		if attempt >= retries || !retryable(req, resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		metrics.IncrCounterWithLabels([]string{upstreamMetricPrefix, "http", "retries"}, 1,
+			t.metricLabels)
+	}
+}
+
+func (t *upstreamTransport) roundTripOnce(req *http.Request) (*http.Response, error) {
+	rf, err := t.resolver()
+	if err != nil {
+		return nil, err
+	}
+	inst, err := t.balancer.resolveCached(req.Context(), rf)
+	if err != nil {
+		return nil, err
+	}
+
+	t.acquire(inst)
+	defer t.release(inst.Addr)
+
+	out := req.WithContext(req.Context())
+	u := *req.URL
+	u.Scheme = "https"
+	u.Host = inst.Addr
+	out.URL = &u
+	resp, err := t.base.RoundTrip(out)
+	if err != nil {
+		t.balancer.invalidate()
+	}
+	return resp, err
+}
+
+// resolver returns the resolver for the upstream, creating it on first use.
+func (t *upstreamTransport) resolver() (connect.Resolver, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.rf != nil {
+		return t.rf, nil
+	}
+	rf, err := t.resolverFunc(t.cfg)
+	if err != nil {
+		return nil, err
+	}
+	t.rf = rf
+	return rf, nil
+}
+
+// acquire makes inst dialable for the duration of a request. The latest
+// resolved instance wins so that a changed certificate URI is picked up.
+func (t *upstreamTransport) acquire(inst *connect.StaticResolver) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	p, ok := t.instances[inst.Addr]
+	if !ok {
+		p = &pendingInstance{}
+		t.instances[inst.Addr] = p
+	}
+	p.inst = inst
+	p.refs++
+}
+
+// release undoes acquire once a request is done, forgetting the instance if
+// no other request is using it.
+func (t *upstreamTransport) release(addr string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	p, ok := t.instances[addr]
+	if !ok {
+		return
+	}
+	p.refs--
+	if p.refs <= 0 {
+		delete(t.instances, addr)
+	}
+}
+
+func (t *upstreamTransport) dialTLS(network, addr string) (net.Conn, error) {
+	t.lock.Lock()
+	p, ok := t.instances[addr]
+	var inst *connect.StaticResolver
+	if ok {
+		inst = p.inst
+	}
+	t.lock.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown upstream instance %s", addr)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.cfg.ConnectTimeout())
+	defer cancel()
+	return t.balancer.dialInstance(ctx, t.svc, inst)
+}
+
+// CloseIdleConnections closes all the pooled connections that aren't in use.
+func (t *upstreamTransport) CloseIdleConnections() {
+	t.base.CloseIdleConnections()
+}
+
+// retryable returns whether a request that got the given response or error
+// can safely be sent again. Only idempotent requests without a body are
+// retried, and only on errors or responses that suggest the instance was
+// unable to handle the request.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+	default:
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,72 @@
+package proxy
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/connect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		code   int
+		err    error
+		want   bool
+	}{
+		{"get error", "GET", "", 0, errors.New("dial failed"), true},
+		{"get unavailable", "GET", "", http.StatusServiceUnavailable, nil, true},
+		{"get bad gateway", "GET", "", http.StatusBadGateway, nil, true},
+		{"get ok", "GET", "", http.StatusOK, nil, false},
+		{"get server error", "GET", "", http.StatusInternalServerError, nil, false},
+		{"delete unavailable", "DELETE", "", http.StatusServiceUnavailable, nil, true},
+		{"put with body", "PUT", "data", http.StatusServiceUnavailable, nil, false},
+		{"post error", "POST", "", 0, errors.New("dial failed"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, "http://db/", nil)
+			require.NoError(t, err)
+			if tt.body != "" {
+				req.Body = http.NoBody
+				req, err = http.NewRequest(tt.method, "http://db/", strings.NewReader(tt.body))
+				require.NoError(t, err)
+			}
+			var resp *http.Response
+			if tt.err == nil {
+				resp = &http.Response{StatusCode: tt.code}
+			}
+			require.Equal(t, tt.want, retryable(req, resp, tt.err))
+		})
+	}
+}
+
+func TestUpstreamTransport_PrunesInstances(t *testing.T) {
+	t.Parallel()
+
+	tr := newUpstreamTransport(nil, UpstreamConfig{}, nil, nil, nil)
+	a := &connect.StaticResolver{Addr: "10.0.0.1:443"}
+	b := &connect.StaticResolver{Addr: "10.0.0.2:443"}
+
+	tr.acquire(a)
+	tr.acquire(a)
+	tr.acquire(b)
+	require.Len(t, tr.instances, 2)
+
+	tr.release(a.Addr)
+	tr.release(b.Addr)
+	require.Len(t, tr.instances, 1)
+
+	_, err := tr.dialTLS("tcp", b.Addr)
+	require.Error(t, err)
+
+	tr.release(a.Addr)
+	require.Empty(t, tr.instances)
+}
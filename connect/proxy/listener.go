@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -33,6 +34,12 @@ type Listener struct {
 	dialFunc   func() (net.Conn, error)
 	bindAddr   string
 
+	// httpHandler is set by the constructors when the listener is in HTTP mode,
+	// in which case it serves each request rather than copying bytes between
+	// connections. httpTransport is closed along with the listener.
+	httpHandler   http.Handler
+	httpTransport interface{ CloseIdleConnections() }
+
 	stopFlag int32
 	stopChan chan struct{}
 
@@ -59,7 +66,7 @@ type Listener struct {
 func NewPublicListener(svc *connect.Service, cfg PublicListenerConfig,
 	logger *log.Logger) *Listener {
 	bindAddr := fmt.Sprintf("%s:%d", cfg.BindAddress, cfg.BindPort)
-	return &Listener{
+	l := &Listener{
 		Service: svc,
 		listenFunc: func() (net.Listener, error) {
 			return tls.Listen("tcp", bindAddr, svc.ServerTLSConfig())
@@ -83,6 +90,21 @@ func NewPublicListener(svc *connect.Service, cfg PublicListenerConfig,
 		// seems for the extra complication of tracking many gauges here.
 		metricLabels: []metrics.Label{{Name: "dst", Value: svc.Name()}},
 	}
+
+	if cfg.Protocol == ProtocolHTTP {
+		l.listenFunc = func() (net.Listener, error) {
+			return tls.Listen("tcp", bindAddr, http1TLSConfig(svc.ServerTLSConfig()))
+		}
+		transport := newHTTPTransport()
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return l.dialFunc()
+		}
+		l.httpTransport = transport
+		l.httpHandler = newHTTPProxy("http", cfg.LocalServiceAddress, transport,
+			time.Duration(cfg.LocalRequestTimeoutMs)*time.Millisecond, logger,
+			l.metricPrefix, l.metricLabels)
+	}
+	return l
 }
 
 // NewUpstreamListener returns a Listener setup to listen locally for TCP
@@ -97,7 +119,14 @@ func newUpstreamListenerWithResolver(svc *connect.Service, cfg UpstreamConfig,
 	resolverFunc func(UpstreamConfig) (connect.Resolver, error),
 	logger *log.Logger) *Listener {
 	bindAddr := fmt.Sprintf("%s:%d", cfg.LocalBindAddress, cfg.LocalBindPort)
-	return &Listener{
+	metricLabels := []metrics.Label{
+		{Name: "src", Value: svc.Name()},
+		// TODO(banks): namespace support
+		{Name: "dst_type", Value: string(cfg.DestinationType)},
+		{Name: "dst", Value: cfg.DestinationName},
+	}
+	balancer := newUpstreamBalancer(cfg, logger, metricLabels)
+	l := &Listener{
 		Service: svc,
 		listenFunc: func() (net.Listener, error) {
 			return net.Listen("tcp", bindAddr)
@@ -110,20 +139,24 @@ func newUpstreamListenerWithResolver(svc *connect.Service, cfg UpstreamConfig,
 			ctx, cancel := context.WithTimeout(context.Background(),
 				cfg.ConnectTimeout())
 			defer cancel()
-			return svc.Dial(ctx, rf)
+			return balancer.dial(ctx, svc, rf)
 		},
 		bindAddr:      bindAddr,
 		stopChan:      make(chan struct{}),
 		listeningChan: make(chan struct{}),
 		logger:        logger,
 		metricPrefix:  upstreamMetricPrefix,
-		metricLabels: []metrics.Label{
-			{Name: "src", Value: svc.Name()},
-			// TODO(banks): namespace support
-			{Name: "dst_type", Value: string(cfg.DestinationType)},
-			{Name: "dst", Value: cfg.DestinationName},
-		},
+		metricLabels:  metricLabels,
 	}
+
+	if cfg.Protocol() == ProtocolHTTP {
+		transport := newUpstreamTransport(svc, cfg, resolverFunc, balancer,
+			metricLabels)
+		l.httpTransport = transport
+		l.httpHandler = newHTTPProxy("https", cfg.DestinationName, transport,
+			cfg.RequestTimeout(), logger, l.metricPrefix, l.metricLabels)
+	}
+	return l
 }
 
 // Serve runs the listener until it is stopped. It is an error to call Serve
@@ -142,6 +175,10 @@ func (l *Listener) Serve() error {
 	}
 	close(l.listeningChan)
 
+	if l.httpHandler != nil {
+		return l.serveHTTP(listen)
+	}
+
 	for {
 		conn, err := listen.Accept()
 		if err != nil {
@@ -155,6 +192,37 @@ func (l *Listener) Serve() error {
 	}
 }
 
+// serveHTTP serves requests from the listener until it is stopped.
+func (l *Listener) serveHTTP(listen net.Listener) error {
+	srv := &http.Server{
+		Handler:           l.httpHandler,
+		ErrorLog:          l.logger,
+		ReadHeaderTimeout: httpReadHeaderTimeout,
+		IdleTimeout:       httpServerIdleTimeout,
+		// HTTP/2 is never negotiated, see http1TLSConfig.
+		TLSNextProto: map[string]func(*http.Server, *tls.Conn, http.Handler){},
+		ConnState: func(conn net.Conn, state http.ConnState) {
+			switch state {
+			case http.StateNew:
+				l.addConns(1)
+			case http.StateHijacked, http.StateClosed:
+				l.addConns(-1)
+			}
+		},
+	}
+
+	go func() {
+		<-l.stopChan
+		srv.Close()
+	}()
+
+	err := srv.Serve(listen)
+	if atomic.LoadInt32(&l.stopFlag) == 1 {
+		return nil
+	}
+	return err
+}
+
 // handleConn is the internal connection handler goroutine.
 func (l *Listener) handleConn(src net.Conn) {
 	defer src.Close()
@@ -228,17 +296,19 @@ func (l *Listener) handleConn(src net.Conn) {
 // trackConn increments the count of active conns and returns a func() that can
 // be deferred on to decrement the counter again on connection close.
 func (l *Listener) trackConn() func() {
-	c := atomic.AddInt32(&l.activeConns, 1)
-	metrics.SetGaugeWithLabels([]string{l.metricPrefix, "conns"}, float32(c),
-		l.metricLabels)
-
+	l.addConns(1)
 	return func() {
-		c := atomic.AddInt32(&l.activeConns, -1)
-		metrics.SetGaugeWithLabels([]string{l.metricPrefix, "conns"}, float32(c),
-			l.metricLabels)
+		l.addConns(-1)
 	}
 }
 
+// addConns adjusts the count of active conns and updates the gauge.
+func (l *Listener) addConns(delta int32) {
+	c := atomic.AddInt32(&l.activeConns, delta)
+	metrics.SetGaugeWithLabels([]string{l.metricPrefix, "conns"}, float32(c),
+		l.metricLabels)
+}
+
 // Close terminates the listener and all active connections.
 func (l *Listener) Close() error {
 	oldFlag := atomic.SwapInt32(&l.stopFlag, 1)
@@ -246,6 +316,9 @@ func (l *Listener) Close() error {
 		close(l.stopChan)
 		// Wait for all conns to close
 		l.connWG.Wait()
+		if l.httpTransport != nil {
+			l.httpTransport.CloseIdleConnections()
+		}
 	}
 	return nil
 }
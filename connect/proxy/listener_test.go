@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -219,3 +223,128 @@ func TestUpstreamListener(t *testing.T) {
 	assertAllTimeCounterValue(t, sink, "consul.proxy.test.upstream.tx_bytes;src=web;dst_type=service;dst=db", 11)
 	assertAllTimeCounterValue(t, sink, "consul.proxy.test.upstream.rx_bytes;src=web;dst_type=service;dst=db", 11)
 }
+
+func TestHTTPListeners(t *testing.T) {
+	// Can't enable t.Parallel since we rely on the global metrics instance.
+
+	ca := agConnect.TestCA(t, nil)
+	ports := freeport.GetT(t, 2)
+
+	// Run a local HTTP app that counts the connections made to it.
+	var appConns, flakyCalls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "hello")
+	})
+	mux.HandleFunc("/flaky", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&flakyCalls, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, "recovered")
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+	})
+	testApp := httptest.NewUnstartedServer(mux)
+	testApp.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&appConns, 1)
+		}
+	}
+	testApp.Start()
+	defer testApp.Close()
+
+	sink := testSetupMetrics(t)
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+
+	// Run a public listener for "db" in front of the app.
+	dbSvc := connect.TestService(t, "db", ca)
+	pub := NewPublicListener(dbSvc, PublicListenerConfig{
+		BindAddress:           "127.0.0.1",
+		BindPort:              ports[0],
+		LocalServiceAddress:   testApp.Listener.Addr().String(),
+		LocalConnectTimeoutMs: 100,
+		Protocol:              ProtocolHTTP,
+	}, logger)
+	go func() {
+		err := pub.Serve()
+		require.NoError(t, err)
+	}()
+	defer pub.Close()
+	pub.Wait()
+
+	// Run an upstream listener for "web" that talks to the public listener.
+	cfg := UpstreamConfig{
+		DestinationType:      "service",
+		DestinationNamespace: "default",
+		DestinationName:      "db",
+		Config: map[string]interface{}{
+			"connect_timeout_ms": 100,
+			"protocol":           "http",
+			"max_retries":        2,
+			"request_timeout_ms": 200,
+		},
+		LocalBindAddress: "127.0.0.1",
+		LocalBindPort:    ports[1],
+	}
+	webSvc := connect.TestService(t, "web", ca)
+	rf := TestStaticUpstreamResolverFunc(&connect.StaticResolver{
+		Addr:    TestLocalAddr(ports[0]),
+		CertURI: agConnect.TestSpiffeIDService(t, "db"),
+	})
+	up := newUpstreamListenerWithResolver(webSvc, cfg, rf, logger)
+	go func() {
+		err := up.Serve()
+		require.NoError(t, err)
+	}()
+	defer up.Close()
+	up.Wait()
+
+	client := &http.Client{}
+	get := func(method, path string) (int, string) {
+		req, err := http.NewRequest(method, "http://"+up.BindAddr()+path, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	// Requests are proxied and connections to the app are kept alive.
+	for i := 0; i < 3; i++ {
+		code, body := get("GET", "/ok")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "hello", body)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&appConns))
+
+	// Idempotent requests are retried.
+	code, body := get("GET", "/flaky")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "recovered", body)
+	require.Equal(t, int32(3), atomic.LoadInt32(&flakyCalls))
+
+	// Other requests are not.
+	atomic.StoreInt32(&flakyCalls, 0)
+	code, _ = get("POST", "/flaky")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, int32(1), atomic.LoadInt32(&flakyCalls))
+
+	// Slow requests time out.
+	code, _ = get("GET", "/slow")
+	require.Equal(t, http.StatusGatewayTimeout, code)
+
+	up.Close()
+	pub.Close()
+
+	labels := "src=web;dst_type=service;dst=db"
+	assertAllTimeCounterValue(t, sink, "consul.proxy.test.upstream.http.requests;"+labels+";code=200", 4)
+	assertAllTimeCounterValue(t, sink, "consul.proxy.test.upstream.http.requests;"+labels+";code=503", 1)
+	assertAllTimeCounterValue(t, sink, "consul.proxy.test.upstream.http.requests;"+labels+";code=504", 1)
+	assertAllTimeCounterValue(t, sink, "consul.proxy.test.upstream.http.retries;"+labels, 2)
+	assertAllTimeCounterValue(t, sink, "consul.proxy.test.inbound.http.requests;dst=db;code=200", 4)
+	assertAllTimeCounterValue(t, sink, "consul.proxy.test.inbound.http.requests;dst=db;code=503", 3)
+}
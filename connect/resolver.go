@@ -28,6 +28,19 @@ type Resolver interface {
 	Resolve(ctx context.Context) (addr string, certURI connect.CertURI, err error)
 }
 
+// InstanceResolver is implemented by Resolvers that can return every candidate
+// instance instead of choosing one themselves. This lets callers such as the
+// built-in proxy apply their own load balancing and outlier detection. Each
+// instance is returned as a StaticResolver so that it can be passed directly
+// to Service.Dial.
+type InstanceResolver interface {
+	Resolver
+
+	// ResolveInstances returns all the candidate instances in the order the
+	// implementation prefers them. An error is returned if there are none.
+	ResolveInstances(ctx context.Context) ([]*StaticResolver, error)
+}
+
 // StaticResolver is a statically defined resolver. This can be used to Dial a
 // known Connect endpoint without performing service discovery.
 type StaticResolver struct {
@@ -74,31 +87,47 @@ type ConsulResolver struct {
 
 	// Datacenter to resolve in, empty indicates agent's local DC.
 	Datacenter string
+
+	// Near sorts the instances returned by ResolveInstances by estimated round
+	// trip time from the given node, using network coordinates. "_agent" means
+	// the local agent's node. Empty leaves the order up to the catalog.
+	Near string
 }
 
 // Resolve performs service discovery against the local Consul agent and returns
 // the address and expected identity of a suitable service instance.
 func (cr *ConsulResolver) Resolve(ctx context.Context) (string, connect.CertURI, error) {
 	switch cr.Type {
-	case ConsulResolverTypeService:
+	case ConsulResolverTypeService, ConsulResolverTypePreparedQuery:
 		return cr.resolveService(ctx)
-	case ConsulResolverTypePreparedQuery:
-		return cr.resolveQuery(ctx)
 	default:
 		return "", nil, fmt.Errorf("unknown resolver type")
 	}
 }
 
-func (cr *ConsulResolver) resolveService(ctx context.Context) (string, connect.CertURI, error) {
-	health := cr.Client.Health()
-
-	svcs, _, err := health.Connect(cr.Name, "", true, cr.queryOptions(ctx))
+// ResolveInstances implements InstanceResolver by returning every healthy
+// instance of the target, sorted by round trip time if Near is set.
+func (cr *ConsulResolver) ResolveInstances(ctx context.Context) ([]*StaticResolver, error) {
+	svcs, err := cr.serviceEntries(ctx)
 	if err != nil {
-		return "", nil, err
+		return nil, err
 	}
 
-	if len(svcs) < 1 {
-		return "", nil, fmt.Errorf("no healthy instances found")
+	instances := make([]*StaticResolver, 0, len(svcs))
+	for _, svc := range svcs {
+		addr, certURI, err := cr.resolveServiceEntry(svc)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, &StaticResolver{Addr: addr, CertURI: certURI})
+	}
+	return instances, nil
+}
+
+func (cr *ConsulResolver) resolveService(ctx context.Context) (string, connect.CertURI, error) {
+	svcs, err := cr.serviceEntries(ctx)
+	if err != nil {
+		return "", nil, err
 	}
 
 	// Services are not shuffled by HTTP API, pick one at (pseudo) random.
@@ -110,24 +139,35 @@ func (cr *ConsulResolver) resolveService(ctx context.Context) (string, connect.C
 	return cr.resolveServiceEntry(svcs[idx])
 }
 
-func (cr *ConsulResolver) resolveQuery(ctx context.Context) (string, connect.CertURI, error) {
-	resp, _, err := cr.Client.PreparedQuery().Execute(cr.Name, cr.queryOptions(ctx))
-	if err != nil {
-		return "", nil, err
-	}
+// serviceEntries returns the healthy instances of the target, or an error if
+// there are none.
+func (cr *ConsulResolver) serviceEntries(ctx context.Context) ([]*api.ServiceEntry, error) {
+	var svcs []*api.ServiceEntry
+	switch cr.Type {
+	case ConsulResolverTypeService:
+		entries, _, err := cr.Client.Health().Connect(cr.Name, "", true, cr.queryOptions(ctx))
+		if err != nil {
+			return nil, err
+		}
+		svcs = entries
 
-	svcs := resp.Nodes
-	if len(svcs) < 1 {
-		return "", nil, fmt.Errorf("no healthy instances found")
-	}
+	case ConsulResolverTypePreparedQuery:
+		resp, _, err := cr.Client.PreparedQuery().Execute(cr.Name, cr.queryOptions(ctx))
+		if err != nil {
+			return nil, err
+		}
+		for i := range resp.Nodes {
+			svcs = append(svcs, &resp.Nodes[i])
+		}
 
-	// Services are not shuffled by HTTP API, pick one at (pseudo) random.
-	idx := 0
-	if len(svcs) > 1 {
-		idx = rand.Intn(len(svcs))
+	default:
+		return nil, fmt.Errorf("unknown resolver type")
 	}
 
-	return cr.resolveServiceEntry(&svcs[idx])
+	if len(svcs) < 1 {
+		return nil, fmt.Errorf("no healthy instances found")
+	}
+	return svcs, nil
 }
 
 func (cr *ConsulResolver) resolveServiceEntry(entry *api.ServiceEntry) (string, connect.CertURI, error) {
@@ -165,6 +205,7 @@ func (cr *ConsulResolver) queryOptions(ctx context.Context) *api.QueryOptions {
 		// caching which is even more stale so...
 		AllowStale: true,
 		Datacenter: cr.Datacenter,
+		Near:       cr.Near,
 
 		// For prepared queries
 		Connect: true,
@@ -213,8 +213,10 @@ func TestConsulResolver_Resolve(t *testing.T) {
 			}
 			defer cancel()
 			gotAddr, gotCertURI, err := cr.Resolve(ctx)
+			gotInstances, instancesErr := cr.ResolveInstances(ctx)
 			if tt.wantErr {
 				require.NotNil(err)
+				require.NotNil(instancesErr)
 				return
 			}
 
@@ -223,6 +225,16 @@ func TestConsulResolver_Resolve(t *testing.T) {
 			if len(tt.addrs) > 0 {
 				require.Contains(tt.addrs, gotAddr)
 			}
+
+			require.Nil(instancesErr)
+			var gotAddrs []string
+			for _, inst := range gotInstances {
+				require.Equal(tt.wantCertURI, inst.CertURI)
+				gotAddrs = append(gotAddrs, inst.Addr)
+			}
+			if len(tt.addrs) > 0 {
+				require.ElementsMatch(tt.addrs, gotAddrs)
+			}
 		})
 	}
 }
@@ -169,7 +169,7 @@ func TestService_ServerTLSConfig(t *testing.T) {
 	require.True(strings.HasSuffix(cert.URIs[0].String(), "/svc/web"))
 
 	// Verify it as a client would
-	err = clientSideVerifier(tlsCfg, leaf.Certificate)
+	err = clientSideVerifier(tlsCfg, leaf.Certificate, nil)
 	require.NoError(err)
 
 	// Now test that rotating the root updates
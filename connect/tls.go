@@ -50,8 +50,9 @@ func parseLeafX509Cert(leaf *tls.Certificate) error {
 // Implementations can use the roots provided in the cfg to verify the certs.
 //
 // The passed *tls.Config may have a nil VerifyPeerCertificates function but
-// will have correct roots, leaf and other fields.
-type verifierFunc func(cfg *tls.Config, rawCerts [][]byte) error
+// will have correct roots, leaf and other fields. remoteAddr is the address of
+// the peer if it's known, which is only the case for server connections.
+type verifierFunc func(cfg *tls.Config, rawCerts [][]byte, remoteAddr net.Addr) error
 
 // defaultTLSConfig returns the standard config with no peer verifier. It is
 // insecure to use it as-is.
@@ -206,7 +207,7 @@ func verifyServerCertMatchesURI(certs []*x509.Certificate,
 // the connection. The service name provided is used as the target service name
 // for the Authorization.
 func newServerSideVerifier(client *api.Client, serviceName string) verifierFunc {
-	return func(tlsCfg *tls.Config, rawCerts [][]byte) error {
+	return func(tlsCfg *tls.Config, rawCerts [][]byte, remoteAddr net.Addr) error {
 		leaf, err := verifyChain(tlsCfg, rawCerts, false)
 		if err != nil {
 			log.Printf("connect: failed TLS verification: %s", err)
@@ -236,6 +237,7 @@ func newServerSideVerifier(client *api.Client, serviceName string) verifierFunc
 			Target:           serviceName,
 			ClientCertURI:    certURI.URI().String(),
 			ClientCertSerial: connect.HexString(leaf.SerialNumber.Bytes()),
+			ClientAddr:       remoteIP(remoteAddr),
 		}
 		resp, err := client.Agent().ConnectAuthorize(req)
 		if err != nil {
@@ -255,11 +257,27 @@ func newServerSideVerifier(client *api.Client, serviceName string) verifierFunc
 // verification since the identity check needs additional state and becomes
 // clunky to customize the callback for every outgoing request. That is done
 // within Service.Dial for now.
-func clientSideVerifier(tlsCfg *tls.Config, rawCerts [][]byte) error {
+func clientSideVerifier(tlsCfg *tls.Config, rawCerts [][]byte, _ net.Addr) error {
 	_, err := verifyChain(tlsCfg, rawCerts, true)
 	return err
 }
 
+// remoteIP returns the IP address of a peer address, or an empty string if it
+// isn't known.
+func remoteIP(addr net.Addr) string {
+	switch a := addr.(type) {
+	case nil:
+		return ""
+	case *net.TCPAddr:
+		return a.IP.String()
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil || net.ParseIP(host) == nil {
+		return ""
+	}
+	return host
+}
+
 // verifyChain performs standard TLS verification without enforcing remote
 // hostname matching.
 func verifyChain(tlsCfg *tls.Config, rawCerts [][]byte, client bool) (*x509.Certificate, error) {
@@ -355,6 +373,13 @@ func newDynamicTLSConfig(base *tls.Config, logger *log.Logger) *dynamicTLSConfig
 // client can use this config for a long time and will still verify against the
 // latest roots even though the roots in the struct is has can't change.
 func (cfg *dynamicTLSConfig) Get(v verifierFunc) *tls.Config {
+	return cfg.get(v, nil)
+}
+
+// get is like Get but passes the given remote address to the verifierFunc.
+// Server connections get a config with their peer address from
+// GetConfigForClient.
+func (cfg *dynamicTLSConfig) get(v verifierFunc, remoteAddr net.Addr) *tls.Config {
 	cfg.RLock()
 	defer cfg.RUnlock()
 	copy := cfg.base.Clone()
@@ -362,7 +387,7 @@ func (cfg *dynamicTLSConfig) Get(v verifierFunc) *tls.Config {
 	copy.ClientCAs = cfg.roots
 	if v != nil {
 		copy.VerifyPeerCertificate = func(rawCerts [][]byte, chains [][]*x509.Certificate) error {
-			return v(cfg.Get(nil), rawCerts)
+			return v(cfg.Get(nil), rawCerts, remoteAddr)
 		}
 	}
 	copy.GetCertificate = func(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
@@ -379,8 +404,12 @@ func (cfg *dynamicTLSConfig) Get(v verifierFunc) *tls.Config {
 		}
 		return leaf, nil
 	}
-	copy.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
-		return cfg.Get(v), nil
+	copy.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		var addr net.Addr
+		if hello != nil && hello.Conn != nil {
+			addr = hello.Conn.RemoteAddr()
+		}
+		return cfg.get(v, addr), nil
 	}
 	return copy
 }
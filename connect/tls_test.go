@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
+	"net"
 	"testing"
 
 	"github.com/hashicorp/consul/testrpc"
@@ -122,7 +123,7 @@ func TestClientSideVerifier(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			require := require.New(t)
-			err := clientSideVerifier(tt.tlsCfg, tt.rawCerts)
+			err := clientSideVerifier(tt.tlsCfg, tt.rawCerts, nil)
 			if tt.wantErr == "" {
 				require.Nil(err)
 			} else {
@@ -242,7 +243,7 @@ func TestServerSideVerifier(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			v := newServerSideVerifier(client, tt.service)
-			err := v(tt.tlsCfg, tt.rawCerts)
+			err := v(tt.tlsCfg, tt.rawCerts, nil)
 			if tt.wantErr == "" {
 				require.Nil(t, err)
 			} else {
@@ -320,15 +321,15 @@ func TestDynamicTLSConfig(t *testing.T) {
 	v1Ch := make(chan *tls.Config, 1)
 	v2Ch := make(chan *tls.Config, 1)
 	v3Ch := make(chan *tls.Config, 1)
-	verify1 := func(cfg *tls.Config, rawCerts [][]byte) error {
+	verify1 := func(cfg *tls.Config, rawCerts [][]byte, _ net.Addr) error {
 		v1Ch <- cfg
 		return nil
 	}
-	verify2 := func(cfg *tls.Config, rawCerts [][]byte) error {
+	verify2 := func(cfg *tls.Config, rawCerts [][]byte, _ net.Addr) error {
 		v2Ch <- cfg
 		return nil
 	}
-	verify3 := func(cfg *tls.Config, rawCerts [][]byte) error {
+	verify3 := func(cfg *tls.Config, rawCerts [][]byte, _ net.Addr) error {
 		v3Ch <- cfg
 		return nil
 	}
@@ -14,7 +14,16 @@ func WriteAtomic(path string, contents []byte) error {
 	return WriteAtomicWithPerms(path, contents, 0700)
 }
 
+// WriteAtomicWithPerms is like WriteAtomic but creates any missing parent
+// directories with the given permissions.
 func WriteAtomicWithPerms(path string, contents []byte, permissions os.FileMode) error {
+	return WriteAtomicWithFilePerms(path, contents, permissions, 0600)
+}
+
+// WriteAtomicWithFilePerms is like WriteAtomicWithPerms but also sets the
+// permissions of the file before it's renamed into place, so the file is
+// never visible with other permissions.
+func WriteAtomicWithFilePerms(path string, contents []byte, dirPerms, filePerms os.FileMode) error {
 
 	uuid, err := uuid.GenerateUUID()
 	if err != nil {
@@ -22,13 +31,19 @@ func WriteAtomicWithPerms(path string, contents []byte, permissions os.FileMode)
 	}
 	tempPath := fmt.Sprintf("%s-%s.tmp", path, uuid)
 
-	if err := os.MkdirAll(filepath.Dir(path), permissions); err != nil {
+	if err := os.MkdirAll(filepath.Dir(path), dirPerms); err != nil {
 		return err
 	}
 	fh, err := os.OpenFile(tempPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
 	if err != nil {
 		return err
 	}
+	// Chmod explicitly since the mode given to OpenFile is subject to umask.
+	if err := fh.Chmod(filePerms); err != nil {
+		fh.Close()
+		os.Remove(tempPath)
+		return err
+	}
 	if _, err := fh.Write(contents); err != nil {
 		fh.Close()
 		os.Remove(tempPath)
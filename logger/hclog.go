@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// HCLogger wraps a *log.Logger and implements the hclog.Logger interface
+// allowing libraries like Raft to log to the standard Consul logger. Levels
+// are filtered by the log writer of the agent, so all of them are enabled.
+type HCLogger struct {
+	l    *log.Logger
+	name string
+	args []interface{}
+}
+
+// NewHCLogger creates a hclog.Logger that will output to the supplied logger
+// with the given name in front of every message, like "raft".
+func NewHCLogger(logger *log.Logger, name string) *HCLogger {
+	return &HCLogger{
+		l:    logger,
+		name: name,
+	}
+}
+
+func (h *HCLogger) log(level string, msg string, args []interface{}) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "[%s] ", level)
+	if h.name != "" {
+		fmt.Fprintf(&buf, "%s: ", h.name)
+	}
+	buf.WriteString(msg)
+
+	all := append(append([]interface{}{}, h.args...), args...)
+	if len(all)%2 != 0 {
+		all = append(all, "<missing>")
+	}
+	for i := 0; i < len(all); i += 2 {
+		if i == 0 {
+			buf.WriteString(":")
+		}
+		fmt.Fprintf(&buf, " %v=%v", all[i], all[i+1])
+	}
+	h.l.Print(buf.String())
+}
+
+// Trace implements hclog.Logger
+func (h *HCLogger) Trace(msg string, args ...interface{}) {
+	h.log("TRACE", msg, args)
+}
+
+// Debug implements hclog.Logger
+func (h *HCLogger) Debug(msg string, args ...interface{}) {
+	h.log("DEBUG", msg, args)
+}
+
+// Info implements hclog.Logger
+func (h *HCLogger) Info(msg string, args ...interface{}) {
+	h.log("INFO", msg, args)
+}
+
+// Warn implements hclog.Logger
+func (h *HCLogger) Warn(msg string, args ...interface{}) {
+	h.log("WARN", msg, args)
+}
+
+// Error implements hclog.Logger
+func (h *HCLogger) Error(msg string, args ...interface{}) {
+	h.log("ERR", msg, args)
+}
+
+// IsTrace implements hclog.Logger
+func (h *HCLogger) IsTrace() bool { return true }
+
+// IsDebug implements hclog.Logger
+func (h *HCLogger) IsDebug() bool { return true }
+
+// IsInfo implements hclog.Logger
+func (h *HCLogger) IsInfo() bool { return true }
+
+// IsWarn implements hclog.Logger
+func (h *HCLogger) IsWarn() bool { return true }
+
+// IsError implements hclog.Logger
+func (h *HCLogger) IsError() bool { return true }
+
+// With implements hclog.Logger
+func (h *HCLogger) With(args ...interface{}) hclog.Logger {
+	return &HCLogger{
+		l:    h.l,
+		name: h.name,
+		args: append(append([]interface{}{}, h.args...), args...),
+	}
+}
+
+// Named implements hclog.Logger
+func (h *HCLogger) Named(name string) hclog.Logger {
+	if h.name != "" {
+		name = h.name + "." + name
+	}
+	return h.ResetNamed(name)
+}
+
+// ResetNamed implements hclog.Logger
+func (h *HCLogger) ResetNamed(name string) hclog.Logger {
+	return &HCLogger{
+		l:    h.l,
+		name: name,
+		args: h.args,
+	}
+}
+
+// SetLevel implements hclog.Logger. Levels are filtered by the log writer of
+// the agent, so this does nothing.
+func (h *HCLogger) SetLevel(level hclog.Level) {}
+
+// StandardLogger implements hclog.Logger
+func (h *HCLogger) StandardLogger(opts *hclog.StandardLoggerOptions) *log.Logger {
+	return h.l
+}
+
+// StandardWriter implements hclog.Logger
+func (h *HCLogger) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer {
+	return h.l.Writer()
+}
@@ -0,0 +1,25 @@
+package logger
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHCLogger(t *testing.T) {
+	var out bytes.Buffer
+	l := NewHCLogger(log.New(&out, "", 0), "raft")
+
+	l.Info("entering follower state", "leader", "node1")
+	l.Named("snapshot").With("id", "1-2").Error("failed", "err", "boom")
+	l.Warn("odd", "key")
+	l.Debug("plain")
+
+	require.Equal(t, `[INFO] raft: entering follower state: leader=node1
+[ERR] raft.snapshot: failed: id=1-2 err=boom
+[WARN] raft: odd: key=<missing>
+[DEBUG] raft: plain
+`, out.String())
+}
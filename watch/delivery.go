@@ -0,0 +1,235 @@
+package watch
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	// DefaultDeliveryRetryInterval is the wait before the first retry of a
+	// failed handler invocation.
+	DefaultDeliveryRetryInterval = time.Second
+
+	// DefaultDeliveryMaxRetryInterval caps the exponential backoff between
+	// retries.
+	DefaultDeliveryMaxRetryInterval = time.Minute
+
+	// DefaultDeliveryQueueSize is the number of results that can be waiting
+	// for delivery before the oldest ones are dropped.
+	DefaultDeliveryQueueSize = 16
+)
+
+// DeliveryConfig controls how results are handed to a RetryableHandlerFunc.
+// It is set with the 'delivery_config' watch parameter.
+type DeliveryConfig struct {
+	// MaxRetries is the number of times a failed invocation is retried. A
+	// negative value retries until the invocation succeeds or a newer result
+	// replaces it.
+	MaxRetries int `mapstructure:"max_retries"`
+
+	// RetryInterval is the wait before the first retry. It doubles with
+	// every failed retry up to MaxRetryInterval.
+	RetryInterval    time.Duration `mapstructure:"-"`
+	RetryIntervalRaw string        `mapstructure:"retry_interval"`
+
+	MaxRetryInterval    time.Duration `mapstructure:"-"`
+	MaxRetryIntervalRaw string        `mapstructure:"max_retry_interval"`
+
+	// QueueSize bounds the number of results waiting for delivery. When the
+	// queue is full the oldest result is dropped.
+	QueueSize int `mapstructure:"queue_size"`
+
+	// Coalesce keeps only the latest result waiting for delivery, and stops
+	// retrying a failed result once a newer one arrives.
+	Coalesce bool `mapstructure:"coalesce"`
+}
+
+// Parse the 'delivery_config' parameters
+func parseDeliveryConfig(configParams interface{}) (*DeliveryConfig, error) {
+	var config DeliveryConfig
+	if err := mapstructure.Decode(configParams, &config); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if config.RetryInterval, err = parseDeliveryDuration(config.RetryIntervalRaw, DefaultDeliveryRetryInterval); err != nil {
+		return nil, fmt.Errorf("Failed to parse retry_interval: %v", err)
+	}
+	if config.MaxRetryInterval, err = parseDeliveryDuration(config.MaxRetryIntervalRaw, DefaultDeliveryMaxRetryInterval); err != nil {
+		return nil, fmt.Errorf("Failed to parse max_retry_interval: %v", err)
+	}
+	if config.MaxRetryInterval < config.RetryInterval {
+		return nil, fmt.Errorf("max_retry_interval must not be less than retry_interval")
+	}
+	if config.QueueSize < 0 {
+		return nil, fmt.Errorf("queue_size must not be negative")
+	}
+	if config.QueueSize == 0 {
+		config.QueueSize = DefaultDeliveryQueueSize
+	}
+
+	return &config, nil
+}
+
+func parseDeliveryDuration(raw string, def time.Duration) (time.Duration, error) {
+	if raw == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("must be positive")
+	}
+	return d, nil
+}
+
+// backoff returns the wait before the given retry, starting at zero.
+func (c *DeliveryConfig) backoff(retry int) time.Duration {
+	wait := c.RetryInterval
+	for i := 0; i < retry && wait < c.MaxRetryInterval; i++ {
+		wait *= 2
+	}
+	if wait > c.MaxRetryInterval {
+		wait = c.MaxRetryInterval
+	}
+	return wait
+}
+
+// deliveryItem is a result waiting to be delivered.
+type deliveryItem struct {
+	idx  uint64
+	data interface{}
+}
+
+// delivery hands results to a RetryableHandlerFunc from its own goroutine so
+// that a slow or failing handler doesn't hold up the watch. Results are
+// delivered in order and failed invocations are retried according to the
+// DeliveryConfig.
+type delivery struct {
+	config  *DeliveryConfig
+	handler RetryableHandlerFunc
+	logger  *log.Logger
+	stopCh  <-chan struct{}
+
+	lock     sync.Mutex
+	queue    []deliveryItem
+	notifyCh chan struct{}
+}
+
+func newDelivery(config *DeliveryConfig, handler RetryableHandlerFunc,
+	logger *log.Logger, stopCh <-chan struct{}) *delivery {
+	return &delivery{
+		config:   config,
+		handler:  handler,
+		logger:   logger,
+		stopCh:   stopCh,
+		notifyCh: make(chan struct{}, 1),
+	}
+}
+
+// enqueue adds a result to the queue, dropping older results if the queue is
+// full or results are coalesced.
+func (d *delivery) enqueue(idx uint64, data interface{}) {
+	d.lock.Lock()
+	if d.config.Coalesce {
+		d.queue = d.queue[:0]
+	} else if len(d.queue) >= d.config.QueueSize {
+		dropped := d.queue[0]
+		d.queue = d.queue[1:]
+		d.logger.Printf("[WARN] consul.watch: Delivery queue full, dropping result at index %d",
+			dropped.idx)
+	}
+	d.queue = append(d.queue, deliveryItem{idx: idx, data: data})
+	d.lock.Unlock()
+
+	select {
+	case d.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+// next pops the oldest queued result.
+func (d *delivery) next() (deliveryItem, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if len(d.queue) == 0 {
+		return deliveryItem{}, false
+	}
+	item := d.queue[0]
+	d.queue = d.queue[1:]
+	return item, true
+}
+
+// pending returns whether results are waiting for delivery.
+func (d *delivery) pending() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return len(d.queue) > 0
+}
+
+// run delivers queued results until the stop channel is closed.
+func (d *delivery) run() {
+	for {
+		select {
+		case <-d.notifyCh:
+		case <-d.stopCh:
+			return
+		}
+
+		for {
+			item, ok := d.next()
+			if !ok {
+				break
+			}
+			if !d.deliver(item) {
+				return
+			}
+		}
+	}
+}
+
+// deliver invokes the handler for a result, retrying on failure. It returns
+// false if the delivery was stopped.
+func (d *delivery) deliver(item deliveryItem) bool {
+	// A newer result cuts the wait short when coalescing since it replaces
+	// the one being retried.
+	var newerCh <-chan struct{}
+	if d.config.Coalesce {
+		newerCh = d.notifyCh
+	}
+
+	for retry := 0; ; retry++ {
+		err := d.handler(item.idx, item.data)
+		if err == nil {
+			return true
+		}
+
+		if d.config.MaxRetries >= 0 && retry >= d.config.MaxRetries {
+			d.logger.Printf("[ERR] consul.watch: Handler failed for index %d, giving up: %v",
+				item.idx, err)
+			return true
+		}
+
+		wait := d.config.backoff(retry)
+		d.logger.Printf("[WARN] consul.watch: Handler failed for index %d, retry in %v: %v",
+			item.idx, wait, err)
+		select {
+		case <-time.After(wait):
+		case <-newerCh:
+		case <-d.stopCh:
+			return false
+		}
+
+		if d.config.Coalesce && d.pending() {
+			d.logger.Printf("[DEBUG] consul.watch: Dropping failed result for index %d for a newer one",
+				item.idx)
+			return true
+		}
+	}
+}
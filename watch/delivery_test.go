@@ -0,0 +1,210 @@
+package watch
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHandler fails the first failures invocations and records the
+// indexes it was invoked with.
+type recordingHandler struct {
+	sync.Mutex
+	failures int
+	calls    []uint64
+	done     []uint64
+	blockCh  chan struct{}
+}
+
+func (h *recordingHandler) handle(idx uint64, data interface{}) error {
+	if h.blockCh != nil {
+		<-h.blockCh
+	}
+	h.Lock()
+	defer h.Unlock()
+	h.calls = append(h.calls, idx)
+	if h.failures > 0 {
+		h.failures--
+		return errors.New("failed")
+	}
+	h.done = append(h.done, idx)
+	return nil
+}
+
+func (h *recordingHandler) delivered() []uint64 {
+	h.Lock()
+	defer h.Unlock()
+	return append([]uint64(nil), h.done...)
+}
+
+func (h *recordingHandler) invocations() []uint64 {
+	h.Lock()
+	defer h.Unlock()
+	return append([]uint64(nil), h.calls...)
+}
+
+func testDelivery(t *testing.T, config *DeliveryConfig, h *recordingHandler) (*delivery, func()) {
+	stopCh := make(chan struct{})
+	d := newDelivery(config, h.handle, log.New(ioutil.Discard, "", 0), stopCh)
+	go d.run()
+	return d, func() { close(stopCh) }
+}
+
+func TestDeliveryConfig_backoff(t *testing.T) {
+	t.Parallel()
+	config := &DeliveryConfig{RetryInterval: time.Second, MaxRetryInterval: 5 * time.Second}
+	require.Equal(t, time.Second, config.backoff(0))
+	require.Equal(t, 2*time.Second, config.backoff(1))
+	require.Equal(t, 4*time.Second, config.backoff(2))
+	require.Equal(t, 5*time.Second, config.backoff(3))
+	require.Equal(t, 5*time.Second, config.backoff(100))
+}
+
+func TestDelivery_Retry(t *testing.T) {
+	t.Parallel()
+	h := &recordingHandler{failures: 2}
+	d, stop := testDelivery(t, &DeliveryConfig{
+		MaxRetries:       -1,
+		RetryInterval:    time.Millisecond,
+		MaxRetryInterval: 10 * time.Millisecond,
+		QueueSize:        DefaultDeliveryQueueSize,
+	}, h)
+	defer stop()
+
+	d.enqueue(1, "one")
+	d.enqueue(2, "two")
+	retry.Run(t, func(r *retry.R) {
+		if !(len(h.delivered()) == 2) {
+			r.Fatal("not yet")
+		}
+	})
+	require.Equal(t, []uint64{1, 1, 1, 2}, h.invocations())
+}
+
+func TestDelivery_MaxRetries(t *testing.T) {
+	t.Parallel()
+	h := &recordingHandler{failures: 5}
+	d, stop := testDelivery(t, &DeliveryConfig{
+		MaxRetries:       1,
+		RetryInterval:    time.Millisecond,
+		MaxRetryInterval: time.Millisecond,
+		QueueSize:        DefaultDeliveryQueueSize,
+	}, h)
+	defer stop()
+
+	d.enqueue(1, "one")
+	d.enqueue(2, "two")
+	retry.Run(t, func(r *retry.R) {
+		if !(len(h.invocations()) == 4) {
+			r.Fatal("not yet")
+		}
+	})
+	require.Equal(t, []uint64{1, 1, 2, 2}, h.invocations())
+	require.Empty(t, h.delivered())
+}
+
+func TestDelivery_QueueFull(t *testing.T) {
+	t.Parallel()
+	h := &recordingHandler{blockCh: make(chan struct{})}
+	d, stop := testDelivery(t, &DeliveryConfig{
+		RetryInterval:    time.Millisecond,
+		MaxRetryInterval: time.Millisecond,
+		QueueSize:        2,
+	}, h)
+	defer stop()
+
+	// The first result is picked up right away and blocks the handler, the
+	// rest queue up and the oldest of them is dropped.
+	d.enqueue(1, "one")
+	retry.Run(t, func(r *retry.R) {
+		if !(!d.pending()) {
+			r.Fatal("not yet")
+		}
+	})
+	d.enqueue(2, "two")
+	d.enqueue(3, "three")
+	d.enqueue(4, "four")
+	close(h.blockCh)
+
+	retry.Run(t, func(r *retry.R) {
+		if !(len(h.delivered()) == 3) {
+			r.Fatal("not yet")
+		}
+	})
+	require.Equal(t, []uint64{1, 3, 4}, h.delivered())
+}
+
+func TestDelivery_Coalesce(t *testing.T) {
+	t.Parallel()
+	h := &recordingHandler{failures: 1000}
+	d, stop := testDelivery(t, &DeliveryConfig{
+		MaxRetries:       -1,
+		RetryInterval:    time.Hour,
+		MaxRetryInterval: time.Hour,
+		QueueSize:        DefaultDeliveryQueueSize,
+		Coalesce:         true,
+	}, h)
+	defer stop()
+
+	// The first result fails and waits for a retry, which a newer result
+	// cuts short and replaces.
+	d.enqueue(1, "one")
+	retry.Run(t, func(r *retry.R) {
+		if !(len(h.invocations()) == 1) {
+			r.Fatal("not yet")
+		}
+	})
+	h.Lock()
+	h.failures = 0
+	h.Unlock()
+	d.enqueue(2, "two")
+	d.enqueue(3, "three")
+
+	retry.Run(t, func(r *retry.R) {
+		if !(len(h.delivered()) == 1) {
+			r.Fatal("not yet")
+		}
+	})
+	require.Equal(t, []uint64{3}, h.delivered())
+	require.Equal(t, []uint64{1, 3}, h.invocations())
+}
+
+func TestRun_RetryableHandler(t *testing.T) {
+	t.Parallel()
+	plan := mustParse(t, `{"type":"noop", "delivery_config": {"retry_interval": "1ms", "max_retries": 3}}`)
+
+	h := &recordingHandler{failures: 2}
+	plan.RetryableHandler = h.handle
+	plan.LogOutput = ioutil.Discard
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- plan.Run("127.0.0.1:8500")
+	}()
+
+	retry.Run(t, func(r *retry.R) {
+		if !(len(h.delivered()) > 0) {
+			r.Fatal("not yet")
+		}
+	})
+	plan.Stop()
+
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatalf("watcher didn't exit")
+	}
+
+	// The noop watch produces results faster than they're delivered so only
+	// check that the first delivered result was retried until it succeeded.
+	calls := h.invocations()
+	require.Equal(t, []uint64{calls[0], calls[0], calls[0]}, calls[:3])
+	require.Equal(t, calls[0], h.delivered()[0])
+}
@@ -3,8 +3,11 @@ package watch
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	consulapi "github.com/hashicorp/consul/api"
+	"github.com/mitchellh/hashstructure"
 )
 
 // watchFactory is a function that can create a new WatchFunc
@@ -27,6 +30,10 @@ func init() {
 		"connect_leaf":         connectLeafWatch,
 		"connect_proxy_config": connectProxyConfigWatch,
 		"agent_service":        agentServiceWatch,
+		"intentions":           intentionsWatch,
+		"sessions":             sessionsWatch,
+		"prepared_query":       preparedQueryWatch,
+		"coordinates":          coordinatesWatch,
 	}
 }
 
@@ -333,6 +340,221 @@ func agentServiceWatch(params map[string]interface{}) (WatcherFunc, error) {
 	return fn, nil
 }
 
+// intentionsWatch is used to watch the intentions that apply to a source or
+// destination service, or all intentions if neither is given.
+func intentionsWatch(params map[string]interface{}) (WatcherFunc, error) {
+	stale := false
+	if err := assignValueBool(params, "stale", &stale); err != nil {
+		return nil, err
+	}
+
+	var source, destination string
+	if err := assignValue(params, "source", &source); err != nil {
+		return nil, err
+	}
+	if err := assignValue(params, "destination", &destination); err != nil {
+		return nil, err
+	}
+	if source != "" && destination != "" {
+		return nil, fmt.Errorf("Cannot specify source and destination")
+	}
+
+	fn := func(p *Plan) (BlockingParamVal, interface{}, error) {
+		connect := p.client.Connect()
+		opts := makeQueryOptionsWithContext(p, stale)
+		defer p.cancelFunc()
+
+		if source == "" && destination == "" {
+			intentions, meta, err := connect.Intentions(&opts)
+			if err != nil {
+				return nil, nil, err
+			}
+			return WaitIndexVal(meta.LastIndex), intentions, err
+		}
+
+		match := &consulapi.IntentionMatch{
+			By:    consulapi.IntentionMatchDestination,
+			Names: []string{destination},
+		}
+		if source != "" {
+			match.By = consulapi.IntentionMatchSource
+			match.Names = []string{source}
+		}
+		matches, meta, err := connect.IntentionMatch(match, &opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return WaitIndexVal(meta.LastIndex), matches[match.Names[0]], err
+	}
+	return fn, nil
+}
+
+// sessionsWatch is used to watch the sessions of a node, or all sessions if
+// no node is given.
+func sessionsWatch(params map[string]interface{}) (WatcherFunc, error) {
+	stale := false
+	if err := assignValueBool(params, "stale", &stale); err != nil {
+		return nil, err
+	}
+
+	var node string
+	if err := assignValue(params, "node", &node); err != nil {
+		return nil, err
+	}
+
+	fn := func(p *Plan) (BlockingParamVal, interface{}, error) {
+		session := p.client.Session()
+		opts := makeQueryOptionsWithContext(p, stale)
+		defer p.cancelFunc()
+		var sessions []*consulapi.SessionEntry
+		var meta *consulapi.QueryMeta
+		var err error
+		if node != "" {
+			sessions, meta, err = session.Node(node, &opts)
+		} else {
+			sessions, meta, err = session.List(&opts)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		return WaitIndexVal(meta.LastIndex), sessions, err
+	}
+	return fn, nil
+}
+
+// DefaultPreparedQueryWatchInterval is how often prepared query watches
+// execute the query by default.
+const DefaultPreparedQueryWatchInterval = 10 * time.Second
+
+// preparedQueryWatch is used to watch the results of a prepared query.
+// Executing a prepared query doesn't support blocking so the query is polled
+// every interval instead.
+func preparedQueryWatch(params map[string]interface{}) (WatcherFunc, error) {
+	stale := false
+	if err := assignValueBool(params, "stale", &stale); err != nil {
+		return nil, err
+	}
+
+	var query, intervalRaw string
+	if err := assignValue(params, "query", &query); err != nil {
+		return nil, err
+	}
+	if query == "" {
+		return nil, fmt.Errorf("Must specify a prepared query ID or name to watch")
+	}
+	if err := assignValue(params, "interval", &intervalRaw); err != nil {
+		return nil, err
+	}
+	interval := DefaultPreparedQueryWatchInterval
+	if intervalRaw != "" {
+		var err error
+		if interval, err = time.ParseDuration(intervalRaw); err != nil {
+			return nil, fmt.Errorf("Failed to parse interval: %v", err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("interval must be positive")
+		}
+	}
+
+	var polled bool
+	var lastHash, lastIndex uint64
+	fn := func(p *Plan) (BlockingParamVal, interface{}, error) {
+		opts := makeQueryOptionsWithContext(p, stale)
+		defer p.cancelFunc()
+
+		// Wait out the interval between executions, stopping early if
+		// the plan is stopped.
+		if polled {
+			select {
+			case <-time.After(interval):
+			case <-opts.Context().Done():
+				return nil, nil, opts.Context().Err()
+			}
+		}
+		polled = true
+
+		opts.WaitIndex = 0
+		resp, meta, err := p.client.PreparedQuery().Execute(query, &opts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// The index doesn't reliably change with the results, for example
+		// when the query fails over to another datacenter, so only move it
+		// forward when the results change. Nodes are shuffled on every
+		// execution so their order is ignored.
+		hash, err := preparedQueryResultHash(resp)
+		if err != nil {
+			return nil, nil, err
+		}
+		if lastIndex == 0 || hash != lastHash {
+			lastHash = hash
+			if meta.LastIndex > lastIndex {
+				lastIndex = meta.LastIndex
+			} else {
+				lastIndex++
+			}
+		}
+		return WaitIndexVal(lastIndex), resp, err
+	}
+	return fn, nil
+}
+
+// preparedQueryResultHash hashes the results of a prepared query without
+// regard to the order of the nodes.
+func preparedQueryResultHash(resp *consulapi.PreparedQueryExecuteResponse) (uint64, error) {
+	nodes := make([]consulapi.ServiceEntry, len(resp.Nodes))
+	copy(nodes, resp.Nodes)
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Node.Node != nodes[j].Node.Node {
+			return nodes[i].Node.Node < nodes[j].Node.Node
+		}
+		return nodes[i].Service.ID < nodes[j].Service.ID
+	})
+	return hashstructure.Hash(struct {
+		Datacenter string
+		Nodes      []consulapi.ServiceEntry
+	}{resp.Datacenter, nodes}, nil)
+}
+
+// coordinatesWatch is used to watch the network coordinates of a node, or of
+// all nodes if no node is given.
+func coordinatesWatch(params map[string]interface{}) (WatcherFunc, error) {
+	stale := false
+	if err := assignValueBool(params, "stale", &stale); err != nil {
+		return nil, err
+	}
+
+	var node string
+	if err := assignValue(params, "node", &node); err != nil {
+		return nil, err
+	}
+
+	fn := func(p *Plan) (BlockingParamVal, interface{}, error) {
+		coordinate := p.client.Coordinate()
+		opts := makeQueryOptionsWithContext(p, stale)
+		defer p.cancelFunc()
+
+		// The single node endpoint returns an error until the node has a
+		// coordinate, so filter the full list instead.
+		coords, meta, err := coordinate.Nodes(&opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		if node != "" {
+			var filtered []*consulapi.CoordinateEntry
+			for _, c := range coords {
+				if c.Node == node {
+					filtered = append(filtered, c)
+				}
+			}
+			coords = filtered
+		}
+		return WaitIndexVal(meta.LastIndex), coords, err
+	}
+	return fn, nil
+}
+
 func makeQueryOptionsWithContext(p *Plan, stale bool) consulapi.QueryOptions {
 	ctx, cancel := context.WithCancel(context.Background())
 	p.setCancelFunc(cancel)
@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/consul/agent/connect"
 	consulapi "github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/watch"
+	"github.com/hashicorp/serf/coordinate"
 	"github.com/stretchr/testify/require"
 )
 
@@ -793,6 +794,234 @@ func TestAgentServiceWatch(t *testing.T) {
 	wg.Wait()
 }
 
+func TestIntentionsWatch(t *testing.T) {
+	t.Parallel()
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	invoke := makeInvokeCh()
+	plan := mustParse(t, `{"type":"intentions", "destination":"db"}`)
+	plan.Handler = func(idx uint64, raw interface{}) {
+		if raw == nil {
+			return // ignore
+		}
+		v, ok := raw.([]*consulapi.Intention)
+		if !ok || len(v) == 0 {
+			return // ignore
+		}
+		if v[0].SourceName != "web" || v[0].DestinationName != "db" {
+			invoke <- errBadContent
+			return
+		}
+		invoke <- nil
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		connect := a.Client().Connect()
+
+		time.Sleep(20 * time.Millisecond)
+		for _, ixn := range []*consulapi.Intention{
+			{SourceName: "web", DestinationName: "db", Action: consulapi.IntentionActionAllow},
+			{SourceName: "web", DestinationName: "cache", Action: consulapi.IntentionActionAllow},
+		} {
+			if _, _, err := connect.IntentionCreate(ixn, nil); err != nil {
+				invoke <- err
+				return
+			}
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := plan.Run(a.HTTPAddr()); err != nil {
+			t.Errorf("err: %v", err)
+		}
+	}()
+
+	if err := <-invoke; err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	plan.Stop()
+	wg.Wait()
+}
+
+func TestSessionsWatch(t *testing.T) {
+	t.Parallel()
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	invoke := makeInvokeCh()
+	plan := mustParse(t, `{"type":"sessions", "node":"`+a.Config.NodeName+`"}`)
+	plan.Handler = func(idx uint64, raw interface{}) {
+		if raw == nil {
+			return // ignore
+		}
+		v, ok := raw.([]*consulapi.SessionEntry)
+		if !ok || len(v) == 0 {
+			return // ignore
+		}
+		if v[0].Name != "lock" || v[0].Node != a.Config.NodeName {
+			invoke <- errBadContent
+			return
+		}
+		invoke <- nil
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		session := a.Client().Session()
+
+		time.Sleep(20 * time.Millisecond)
+		if _, _, err := session.Create(&consulapi.SessionEntry{Name: "lock"}, nil); err != nil {
+			invoke <- err
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := plan.Run(a.HTTPAddr()); err != nil {
+			t.Errorf("err: %v", err)
+		}
+	}()
+
+	if err := <-invoke; err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	plan.Stop()
+	wg.Wait()
+}
+
+func TestPreparedQueryWatch(t *testing.T) {
+	t.Parallel()
+	a := agent.NewTestAgent(t, t.Name(), ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	client := a.Client()
+	_, _, err := client.PreparedQuery().Create(&consulapi.PreparedQueryDefinition{
+		Name:    "web",
+		Service: consulapi.ServiceQuery{Service: "web"},
+	}, nil)
+	require.NoError(t, err)
+
+	invoke := makeInvokeCh()
+	plan := mustParse(t, `{"type":"prepared_query", "query":"web", "interval":"50ms"}`)
+	plan.Handler = func(idx uint64, raw interface{}) {
+		if raw == nil {
+			return // ignore
+		}
+		v, ok := raw.(*consulapi.PreparedQueryExecuteResponse)
+		if !ok || len(v.Nodes) == 0 {
+			return // ignore
+		}
+		if v.Nodes[0].Service.Port != 8080 {
+			invoke <- errBadContent
+			return
+		}
+		invoke <- nil
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		time.Sleep(20 * time.Millisecond)
+		reg := &consulapi.AgentServiceRegistration{
+			Name: "web",
+			Port: 8080,
+		}
+		if err := client.Agent().ServiceRegister(reg); err != nil {
+			invoke <- err
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := plan.Run(a.HTTPAddr()); err != nil {
+			t.Errorf("err: %v", err)
+		}
+	}()
+
+	if err := <-invoke; err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	plan.Stop()
+	wg.Wait()
+}
+
+func TestCoordinatesWatch(t *testing.T) {
+	t.Parallel()
+	a := agent.NewTestAgent(t, t.Name(), `
+		consul = {
+			coordinate = {
+				update_period = "50ms"
+			}
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	invoke := makeInvokeCh()
+	plan := mustParse(t, `{"type":"coordinates", "node":"`+a.Config.NodeName+`"}`)
+	plan.Handler = func(idx uint64, raw interface{}) {
+		if raw == nil {
+			return // ignore
+		}
+		v, ok := raw.([]*consulapi.CoordinateEntry)
+		if !ok || len(v) == 0 {
+			return // ignore
+		}
+		if v[0].Node != a.Config.NodeName || v[0].Coord.Height != 42 {
+			return // ignore coordinates sent by the agent itself
+		}
+		invoke <- nil
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		time.Sleep(20 * time.Millisecond)
+		coord := coordinate.NewCoordinate(coordinate.DefaultConfig())
+		coord.Height = 42
+		entry := &consulapi.CoordinateEntry{
+			Node:  a.Config.NodeName,
+			Coord: coord,
+		}
+		if _, err := a.Client().Coordinate().Update(entry, nil); err != nil {
+			invoke <- err
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := plan.Run(a.HTTPAddr()); err != nil {
+			t.Errorf("err: %v", err)
+		}
+	}()
+
+	if err := <-invoke; err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	plan.Stop()
+	wg.Wait()
+}
+
 func mustParse(t *testing.T, q string) *watch.Plan {
 	t.Helper()
 	var params map[string]interface{}
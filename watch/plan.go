@@ -58,6 +58,14 @@ func (p *Plan) RunWithClientAndLogger(client *consulapi.Client,
 
 	p.client = client
 
+	// Hand results to a retryable handler from a separate goroutine if
+	// delivery is configured.
+	var d *delivery
+	if p.RetryableHandler != nil && p.Delivery != nil {
+		d = newDelivery(p.Delivery, p.RetryableHandler, logger, p.stopCh)
+		go d.run()
+	}
+
 	// Loop until we are canceled
 	failures := 0
 OUTER:
@@ -114,13 +122,23 @@ OUTER:
 		// If a hybrid handler exists use that
 		if p.HybridHandler != nil {
 			p.HybridHandler(blockParamVal, result)
-		} else if p.Handler != nil {
+		} else if p.Handler != nil || p.RetryableHandler != nil {
 			idx, ok := blockParamVal.(WaitIndexVal)
 			if !ok {
 				logger.Printf("[ERR] consul.watch: Handler only supports index-based " +
 					" watches but non index-based watch run. Skipping Handler.")
 			}
-			p.Handler(uint64(idx), result)
+			switch {
+			case p.Handler != nil:
+				p.Handler(uint64(idx), result)
+			case d != nil:
+				d.enqueue(uint64(idx), result)
+			default:
+				if err := p.RetryableHandler(uint64(idx), result); err != nil {
+					logger.Printf("[ERR] consul.watch: Handler for watch (type: %s) failed: %v",
+						p.Type, err)
+				}
+			}
 		}
 	}
 	return nil
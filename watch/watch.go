@@ -2,9 +2,13 @@ package watch
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"strconv"
 	"sync"
+	"text/template"
 	"time"
 
 	consulapi "github.com/hashicorp/consul/api"
@@ -29,7 +33,12 @@ type Plan struct {
 	// on index param. To support hash based watches, set HybridHandler instead.
 	Handler       HandlerFunc
 	HybridHandler HybridHandlerFunc
-	LogOutput     io.Writer
+	// RetryableHandler is used in place of Handler for handlers that can
+	// fail. If Delivery is set, results are queued and delivered from a
+	// separate goroutine with retries, otherwise failures are only logged.
+	RetryableHandler RetryableHandlerFunc
+	Delivery         *DeliveryConfig
+	LogOutput        io.Writer
 
 	address      string
 	client       *consulapi.Client
@@ -51,6 +60,21 @@ type HttpHandlerConfig struct {
 	TLSSkipVerify bool                `mapstructure:"tls_skip_verify"`
 }
 
+// FileHandlerConfig configures the 'file' handler type, which writes every
+// result to a local file.
+type FileHandlerConfig struct {
+	// Path is the file the result is written to. It is replaced atomically.
+	Path string `mapstructure:"path"`
+
+	// Template is an optional text/template rendered with the result. The
+	// result is written as JSON when it's empty.
+	Template string `mapstructure:"template"`
+
+	// Perms are the file permissions as an octal string, 0644 by default.
+	Perms    os.FileMode `mapstructure:"-"`
+	PermsRaw string      `mapstructure:"perms"`
+}
+
 // BlockingParamVal is an interface representing the common operations needed for
 // different styles of blocking. It's used to abstract the core watch plan from
 // whether we are performing index-based or hash-based blocking.
@@ -124,6 +148,10 @@ type HandlerFunc func(uint64, interface{})
 // index-based or hash-based watches via the BlockingParamVal.
 type HybridHandlerFunc func(BlockingParamVal, interface{})
 
+// RetryableHandlerFunc is used to handle new data like HandlerFunc, but
+// returns an error if the data couldn't be handled so it can be retried.
+type RetryableHandlerFunc func(uint64, interface{}) error
+
 // Parse takes a watch query and compiles it into a WatchPlan or an error
 func Parse(params map[string]interface{}) (*Plan, error) {
 	return ParseExempt(params, nil)
@@ -168,10 +196,31 @@ func ParseExempt(params map[string]interface{}, exempt []string) (*Plan, error)
 		plan.Exempt["http_handler_config"] = config
 		delete(params, "http_handler_config")
 
+	case "file":
+		if _, ok := params["file_handler_config"]; !ok {
+			return nil, fmt.Errorf("Handler type 'file' requires 'file_handler_config' to be set")
+		}
+		config, err := parseFileHandlerConfig(params["file_handler_config"])
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse 'file_handler_config': %v", err)
+		}
+		plan.Exempt["file_handler_config"] = config
+		delete(params, "file_handler_config")
+
 	case "script":
 		// Let the caller check for configuration in exempt parameters
 	}
 
+	// Get the delivery options
+	if raw, ok := params["delivery_config"]; ok {
+		config, err := parseDeliveryConfig(raw)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse 'delivery_config': %v", err)
+		}
+		plan.Delivery = config
+		delete(params, "delivery_config")
+	}
+
 	// Look for a factory function
 	factory := watchFuncFactory[plan.Type]
 	if factory == nil {
@@ -256,3 +305,38 @@ func parseHttpHandlerConfig(configParams interface{}) (*HttpHandlerConfig, error
 
 	return &config, nil
 }
+
+// Parse the 'file_handler_config' parameters
+func parseFileHandlerConfig(configParams interface{}) (*FileHandlerConfig, error) {
+	var config FileHandlerConfig
+	if err := mapstructure.Decode(configParams, &config); err != nil {
+		return nil, err
+	}
+
+	if config.Path == "" {
+		return nil, fmt.Errorf("Requires 'path' to be set")
+	}
+	if config.Template != "" {
+		if _, err := template.New("watch").Funcs(FileHandlerTemplateFuncs).Parse(config.Template); err != nil {
+			return nil, fmt.Errorf("Failed to parse template: %v", err)
+		}
+	}
+	if config.PermsRaw == "" {
+		config.Perms = 0644
+	} else if perms, err := strconv.ParseUint(config.PermsRaw, 8, 32); err != nil {
+		return nil, fmt.Errorf("Failed to parse perms: %v", err)
+	} else {
+		config.Perms = os.FileMode(perms)
+	}
+
+	return &config, nil
+}
+
+// FileHandlerTemplateFuncs are the functions available to file handler
+// templates in addition to the text/template builtins.
+var FileHandlerTemplateFuncs = template.FuncMap{
+	"toJSON": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
@@ -3,7 +3,9 @@ package watch
 import (
 	"bytes"
 	"encoding/json"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestParseBasic(t *testing.T) {
@@ -40,6 +42,65 @@ func TestParse_exempt(t *testing.T) {
 	}
 }
 
+func TestParse_fileHandler(t *testing.T) {
+	t.Parallel()
+	params := makeParams(t, `{"type":"key", "key":"foo", "handler_type": "file",
+		"file_handler_config": {"path": "/tmp/foo", "template": "{{ .Key }}", "perms": "0600"}}`)
+	p, err := Parse(params)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	config, ok := p.Exempt["file_handler_config"].(*FileHandlerConfig)
+	if !ok {
+		t.Fatalf("bad: %#v", p.Exempt)
+	}
+	if config.Path != "/tmp/foo" || config.Template != "{{ .Key }}" || config.Perms != 0600 {
+		t.Fatalf("bad: %#v", config)
+	}
+
+	for _, bad := range []string{
+		`{"type":"key", "key":"foo", "handler_type": "file"}`,
+		`{"type":"key", "key":"foo", "handler_type": "file", "file_handler_config": {}}`,
+		`{"type":"key", "key":"foo", "handler_type": "file", "file_handler_config": {"path": "a", "template": "{{"}}`,
+		`{"type":"key", "key":"foo", "handler_type": "file", "file_handler_config": {"path": "a", "perms": "rw"}}`,
+	} {
+		if _, err := Parse(makeParams(t, bad)); err == nil {
+			t.Fatalf("expected error for %s", bad)
+		}
+	}
+}
+
+func TestParse_deliveryConfig(t *testing.T) {
+	t.Parallel()
+	params := makeParams(t, `{"type":"key", "key":"foo",
+		"delivery_config": {"max_retries": -1, "retry_interval": "2s", "coalesce": true}}`)
+	p, err := Parse(params)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	expect := &DeliveryConfig{
+		MaxRetries:       -1,
+		RetryInterval:    2 * time.Second,
+		RetryIntervalRaw: "2s",
+		MaxRetryInterval: DefaultDeliveryMaxRetryInterval,
+		QueueSize:        DefaultDeliveryQueueSize,
+		Coalesce:         true,
+	}
+	if !reflect.DeepEqual(p.Delivery, expect) {
+		t.Fatalf("bad: %#v", p.Delivery)
+	}
+
+	for _, bad := range []string{
+		`{"type":"key", "key":"foo", "delivery_config": {"retry_interval": "soon"}}`,
+		`{"type":"key", "key":"foo", "delivery_config": {"retry_interval": "1m", "max_retry_interval": "1s"}}`,
+		`{"type":"key", "key":"foo", "delivery_config": {"queue_size": -1}}`,
+	} {
+		if _, err := Parse(makeParams(t, bad)); err == nil {
+			t.Fatalf("expected error for %s", bad)
+		}
+	}
+}
+
 func makeParams(t *testing.T, s string) map[string]interface{} {
 	var out map[string]interface{}
 	dec := json.NewDecoder(bytes.NewReader([]byte(s)))